@@ -0,0 +1,58 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Rainminds/gantral/internal/artifact"
+)
+
+// HandleListInstanceArtifacts handles GET /instances/{id}/artifacts.
+// Artifacts are returned in write order; auditors rebuild chain order from hash linkage.
+func (h *Handler) HandleListInstanceArtifacts(w http.ResponseWriter, r *http.Request) {
+	instanceID := r.PathValue("id")
+	if instanceID == "" {
+		http.Error(w, "instance id required", http.StatusBadRequest)
+		return
+	}
+
+	arts, err := h.Artifacts.ListByInstance(r.Context(), instanceID)
+	if err != nil {
+		writeArtifactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"instance_id": instanceID,
+		"artifacts":   arts,
+	})
+}
+
+// HandleGetArtifact handles GET /artifacts/{id}.
+// The body is the stored artifact exactly as gantral-verify expects it.
+func (h *Handler) HandleGetArtifact(w http.ResponseWriter, r *http.Request) {
+	art, err := h.Artifacts.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeArtifactError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(art)
+}
+
+// writeArtifactError maps artifact store errors to HTTP status codes.
+func writeArtifactError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, artifact.ErrInvalidID):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, artifact.ErrArtifactNotFound):
+		http.Error(w, "artifact not found", http.StatusNotFound)
+	default:
+		slog.Error("artifact store failure", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
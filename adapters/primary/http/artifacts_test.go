@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+)
+
+func newArtifactServer(t *testing.T) (*stdhttp.ServeMux, []*models.CommitmentArtifact) {
+	t.Helper()
+	store, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+
+	var chain []*models.CommitmentArtifact
+	prev := models.GenesisHash
+	for _, state := range []string{"WAITING_FOR_HUMAN", "APPROVED"} {
+		art := models.NewCommitmentArtifact("inst-1", prev, state, "pv", "ctx", "user")
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(context.Background(), art); err != nil {
+			t.Fatal(err)
+		}
+		chain = append(chain, art)
+		prev = art.ArtifactID
+	}
+
+	return NewServer("8080", nil, "queue", nil, nil, store).Routes(), chain
+}
+
+func TestListInstanceArtifacts(t *testing.T) {
+	mux, chain := newArtifactServer(t)
+
+	t.Run("Chain", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/instances/inst-1/artifacts", nil))
+		assert.Equal(t, stdhttp.StatusOK, w.Code)
+
+		var resp struct {
+			InstanceID string                      `json:"instance_id"`
+			Artifacts  []models.CommitmentArtifact `json:"artifacts"`
+		}
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "inst-1", resp.InstanceID)
+		if assert.Len(t, resp.Artifacts, len(chain)) {
+			assert.Equal(t, chain[0].ArtifactID, resp.Artifacts[0].ArtifactID)
+			assert.Equal(t, chain[1].ArtifactID, resp.Artifacts[1].ArtifactID)
+		}
+		// What the API returns must verify offline as-is.
+		assert.True(t, verifier.VerifyChain(resp.Artifacts).Valid)
+	})
+
+	t.Run("Unknown Instance", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/instances/inst-none/artifacts", nil))
+		assert.Equal(t, stdhttp.StatusOK, w.Code)
+		assert.JSONEq(t, `{"instance_id":"inst-none","artifacts":[]}`, w.Body.String())
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/instances/inst.1/artifacts", nil))
+		assert.Equal(t, stdhttp.StatusBadRequest, w.Code)
+	})
+}
+
+func TestGetArtifact(t *testing.T) {
+	mux, chain := newArtifactServer(t)
+
+	t.Run("Found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/artifacts/"+chain[1].ArtifactID, nil))
+		assert.Equal(t, stdhttp.StatusOK, w.Code)
+
+		res, err := verifier.VerifyArtifact(w.Body.Bytes())
+		assert.NoError(t, err)
+		assert.True(t, res.Valid, res.Error)
+		assert.Equal(t, chain[1].ArtifactID, res.ArtifactID)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/artifacts/deadbeef", nil))
+		assert.Equal(t, stdhttp.StatusNotFound, w.Code)
+	})
+
+	t.Run("Invalid ID", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/artifacts/a.b", nil))
+		assert.Equal(t, stdhttp.StatusBadRequest, w.Code)
+	})
+}
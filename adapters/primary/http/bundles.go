@@ -0,0 +1,113 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/pkg/bundle"
+	"github.com/Rainminds/gantral/pkg/models"
+)
+
+// maxBundleInstances bounds a single export request.
+const maxBundleInstances = 100
+
+// EvidenceSource lists the ExecutionEvidence captured for an instance.
+type EvidenceSource interface {
+	ListByInstance(ctx context.Context, instanceID string) ([]*models.ExecutionEvidence, error)
+}
+
+// HandleExportBundle handles GET /bundles?instance=<id>[&instance=<id>...].
+// The response is a tar bundle (see pkg/bundle) that `gantral-verify bundle` checks offline.
+// Policy versions that are no longer in the registry are left out; the verifier reports them.
+func (h *Handler) HandleExportBundle(w http.ResponseWriter, r *http.Request) {
+	instances := dedupe(r.URL.Query()["instance"])
+	if len(instances) == 0 {
+		http.Error(w, "at least one instance parameter required", http.StatusBadRequest)
+		return
+	}
+	if len(instances) > maxBundleInstances {
+		http.Error(w, fmt.Sprintf("at most %d instances per bundle", maxBundleInstances), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	contents := bundle.Contents{
+		Instances: instances,
+		Policies:  make(map[string][]byte),
+		Keys:      h.PublicKeys,
+	}
+	for _, id := range instances {
+		arts, err := h.Artifacts.ListByInstance(ctx, id)
+		if err != nil {
+			writeArtifactError(w, err)
+			return
+		}
+		if len(arts) == 0 {
+			http.Error(w, fmt.Sprintf("no artifacts for instance %s", id), http.StatusNotFound)
+			return
+		}
+		contents.Artifacts = append(contents.Artifacts, arts...)
+
+		if h.Evidence != nil {
+			evidence, err := h.Evidence.ListByInstance(ctx, id)
+			if err != nil {
+				slog.Error("evidence listing failed", "instance_id", id, "error", err)
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+				return
+			}
+			contents.Evidence = append(contents.Evidence, evidence...)
+		}
+	}
+
+	if h.Policies != nil {
+		for _, art := range contents.Artifacts {
+			pv := art.PolicyVersionID
+			if _, done := contents.Policies[pv]; done || pv == "" {
+				continue
+			}
+			v, err := h.Policies.GetPolicyVersion(ctx, pv)
+			if errors.Is(err, policy.ErrPolicyNotFound) {
+				continue // Legacy or pruned version
+			}
+			if err != nil {
+				writePolicyError(w, err)
+				return
+			}
+			contents.Policies[pv] = v.Body
+		}
+	}
+
+	// Build in memory so that a failure can still be reported with a proper status.
+	var buf bytes.Buffer
+	manifest, err := bundle.Write(&buf, contents, time.Now())
+	if err != nil {
+		slog.Error("bundle export failed", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	slog.Info("Audit bundle exported", "instances", instances, "members", len(manifest.Members))
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	w.Header().Set("Content-Disposition", `attachment; filename="gantral-bundle.tar"`)
+	_, _ = buf.WriteTo(w)
+}
+
+// dedupe drops empty and repeated values, keeping the first occurrence.
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/bundle"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type stubEvidence map[string][]*models.ExecutionEvidence
+
+func (s stubEvidence) ListByInstance(ctx context.Context, instanceID string) ([]*models.ExecutionEvidence, error) {
+	return s[instanceID], nil
+}
+
+func TestExportBundle(t *testing.T) {
+	store, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	version, err := policy.NewVersion("refunds", policy.Policy{Materiality: policy.MaterialityHigh, RequiresHumanApproval: true})
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
+	}
+
+	prev := models.GenesisHash
+	for _, state := range []string{"WAITING_FOR_HUMAN", "APPROVED"} {
+		art := models.NewCommitmentArtifact("inst-1", prev, state, version.VersionID, "ctx", "user")
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(context.Background(), art); err != nil {
+			t.Fatal(err)
+		}
+		prev = art.ArtifactID
+	}
+	ev := models.NewExecutionEvidence("inst-1", "refund", []byte(`{}`), []byte(`{}`))
+	if err := ev.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+
+	mockPolicies := new(MockPolicyStore)
+	mockPolicies.On("GetPolicyVersion", mock.Anything, version.VersionID).Return(version, nil)
+	mux := NewServer("8080", nil, "queue", nil, mockPolicies, store).
+		WithAuditSources(stubEvidence{"inst-1": {ev}}, nil).
+		Routes()
+
+	t.Run("Verifies Offline", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/bundles?instance=inst-1&instance=inst-1", nil))
+		assert.Equal(t, stdhttp.StatusOK, w.Code)
+		assert.Equal(t, "application/x-tar", w.Header().Get("Content-Type"))
+
+		res := bundle.Verify(bytes.NewReader(w.Body.Bytes()), nil)
+		assert.Equal(t, verifier.OutcomeValid, res.Outcome, res.Findings)
+		assert.Equal(t, []string{"inst-1"}, res.Manifest.Instances)
+		assert.Equal(t, 2, res.Artifacts)
+		assert.Equal(t, 1, res.Evidence)
+		assert.Equal(t, 1, res.Policies)
+	})
+
+	t.Run("Missing Instance Parameter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/bundles", nil))
+		assert.Equal(t, stdhttp.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown Instance", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/bundles?instance=inst-1&instance=inst-none", nil))
+		assert.Equal(t, stdhttp.StatusNotFound, w.Code)
+	})
+}
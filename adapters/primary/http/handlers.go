@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/Rainminds/gantral/core/activities"
 	"github.com/Rainminds/gantral/core/engine"
-	"github.com/Rainminds/gantral/core/policy"
 	"github.com/Rainminds/gantral/core/ports"
 	"github.com/Rainminds/gantral/core/workflows"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/auth"
 	"github.com/Rainminds/gantral/internal/middleware"
+	"github.com/Rainminds/gantral/pkg/signing"
 	"github.com/google/uuid"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
@@ -23,19 +26,28 @@ type Handler struct {
 	TemporalClient client.Client
 	TaskQueue      string
 	ReadStore      ports.InstanceStore // CQRS Read Path
+	Policies       ports.PolicyStore   // Policy Registry
+	Artifacts      artifact.Store      // Commitment artifacts (authoritative evidence)
+	Evidence       EvidenceSource      // Execution evidence for audit bundles (optional)
+	PublicKeys     *signing.KeySetFile // Public signing keys shipped in audit bundles (optional)
+	Log            TransparencyLog     // Global artifact log (optional)
 }
 
 // CreateInstanceRequest defines the payload for creating an instance.
+// The policy is referenced by name and resolved server-side; clients cannot supply policy content.
 type CreateInstanceRequest struct {
 	WorkflowID     string                 `json:"workflow_id"`
 	TriggerContext map[string]interface{} `json:"trigger_context"`
-	Policy         policy.Policy          `json:"policy"`
+	PolicyName     string                 `json:"policy_name"`
+	PolicyVersion  string                 `json:"policy_version,omitempty"` // Optional pin; defaults to latest active
+	Policy         json.RawMessage        `json:"policy,omitempty"`         // Rejected: inline policies are not accepted
 }
 
 // CreateInstanceResponse defines the response.
 type CreateInstanceResponse struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID              string `json:"id"`
+	Status          string `json:"status"`
+	PolicyVersionID string `json:"policy_version_id"`
 }
 
 // CreateInstance handles POST /instances.
@@ -47,6 +59,22 @@ func (h *Handler) CreateInstance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(req.Policy) > 0 {
+		http.Error(w, "inline policies are not accepted; register via POST /policies and reference policy_name", http.StatusBadRequest)
+		return
+	}
+	if req.PolicyName == "" {
+		http.Error(w, "policy_name required", http.StatusBadRequest)
+		return
+	}
+
+	// Resolve and pin the exact policy version
+	version, err := h.resolvePolicy(r, req.PolicyName, req.PolicyVersion)
+	if err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
 	// Generate Instance ID (Execution ID)
 	instanceID := fmt.Sprintf("inst-%s", uuid.New().String())
 
@@ -58,7 +86,16 @@ func (h *Handler) CreateInstance(w http.ResponseWriter, r *http.Request) {
 	input := workflows.WorkflowInput{
 		WorkflowID:     req.WorkflowID,
 		TriggerContext: req.TriggerContext,
-		Policy:         req.Policy,
+		PolicyName:     version.Name,
+		Policy:         version.Policy, // Policy.ID is the version hash
+		// Decisions on API instances are always authenticated; pin that so a decision
+		// without an identity is refused rather than trusted.
+		RequireIdentity: true,
+	}
+	// Record who created the instance, for separation-of-duties rules
+	if identity, err := middleware.GetIdentity(r.Context()); err == nil {
+		input.CreatedBy = actorIdentity(identity).ActorID()
+		input.CreatorOrgID = identity.OrgID
 	}
 
 	we, err := h.TemporalClient.ExecuteWorkflow(r.Context(), workflowOptions, workflows.GantralExecutionWorkflow, input)
@@ -68,11 +105,12 @@ func (h *Handler) CreateInstance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	slog.Info("Workflow Started", "instance_id", instanceID, "run_id", we.GetRunID())
+	slog.Info("Workflow Started", "instance_id", instanceID, "run_id", we.GetRunID(), "policy_version_id", version.VersionID)
 
 	resp := CreateInstanceResponse{
-		ID:     instanceID,
-		Status: "PENDING",
+		ID:              instanceID,
+		Status:          "PENDING",
+		PolicyVersionID: version.VersionID,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -82,9 +120,13 @@ func (h *Handler) CreateInstance(w http.ResponseWriter, r *http.Request) {
 }
 
 // RecordDecisionRequest defines the payload for a human decision.
+// The actor is always the authenticated caller: ActorID, Provider and OrgID are optional
+// and, when given, must match the token's identity.
 type RecordDecisionRequest struct {
 	Type            string                 `json:"type"`
-	ActorID         string                 `json:"actor_id"`
+	ActorID         string                 `json:"actor_id,omitempty"`
+	Provider        string                 `json:"provider,omitempty"`
+	OrgID           string                 `json:"org_id,omitempty"`
 	Justification   string                 `json:"justification"`
 	PolicyVersionID string                 `json:"policy_version_id"`
 	ContextSnapshot map[string]interface{} `json:"context_snapshot"`
@@ -105,10 +147,20 @@ func (h *Handler) RecordDecision(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract Role from Context
+	// The decision is recorded as the authenticated identity, never as the body claims
+	identity, err := middleware.GetIdentity(r.Context())
+	if err != nil {
+		http.Error(w, "authenticated identity required", http.StatusUnauthorized)
+		return
+	}
+	actor := actorIdentity(identity)
+	if err := checkClaimedIdentity(req, actor); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	role := "unknown_via_api"
-	if identity, err := middleware.GetIdentity(r.Context()); err == nil && len(identity.Roles) > 0 {
-		role = identity.Roles[0] // Use primary role
+	if len(actor.Roles) > 0 {
+		role = actor.Roles[0] // Use primary role
 	}
 
 	// Map to Signal Input
@@ -128,14 +180,17 @@ func (h *Handler) RecordDecision(w http.ResponseWriter, r *http.Request) {
 	signalArg := activities.RecordDecisionInput{
 		InstanceID:      instanceID,
 		DecisionType:    dType,
-		ActorID:         req.ActorID,
+		ActorID:         actor.ActorID(),
 		Justification:   req.Justification,
 		Role:            role,
+		Roles:           actor.Roles, // Approver groups for multi-party decisions
+		Identity:        &actor,
+		ActorOrgID:      actor.OrgID, // Checked against the creator's org (separation of duties)
 		PolicyVersionID: req.PolicyVersionID,
 		ContextSnapshot: req.ContextSnapshot,
 	}
 
-	err := h.TemporalClient.SignalWorkflow(r.Context(), instanceID, "", workflows.SignalHumanDecision, signalArg)
+	err = h.TemporalClient.SignalWorkflow(r.Context(), instanceID, "", workflows.SignalHumanDecision, signalArg)
 	if err != nil {
 		if _, ok := err.(*serviceerror.NotFound); ok {
 			http.Error(w, "instance not found or completed", http.StatusNotFound)
@@ -150,6 +205,187 @@ func (h *Handler) RecordDecision(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "SIGNAL_SENT"})
 }
 
+// actorIdentity is the identity recorded with a decision for a verified token. The token
+// provider is its issuer.
+func actorIdentity(identity *auth.Identity) engine.ActorIdentity {
+	return engine.ActorIdentity{
+		Subject: identity.Subject,
+		Issuer:  identity.Provider,
+		Type:    string(identity.Type),
+		Roles:   identity.Roles,
+		OrgID:   identity.OrgID,
+	}
+}
+
+// checkClaimedIdentity rejects a decision whose body names someone other than the caller.
+// actor_id may be the subject or the full issuer|subject binding.
+func checkClaimedIdentity(req RecordDecisionRequest, actor engine.ActorIdentity) error {
+	if err := checkClaimedActor(req.ActorID, actor); err != nil {
+		return err
+	}
+	if req.Provider != "" && req.Provider != actor.Issuer {
+		return fmt.Errorf("provider %q does not match the authenticated identity", req.Provider)
+	}
+	if req.OrgID != "" && req.OrgID != actor.OrgID {
+		return fmt.Errorf("org_id %q does not match the authenticated identity", req.OrgID)
+	}
+	return nil
+}
+
+// HandleGetApprovals handles GET /instances/{id}/approvals.
+// It queries the workflow for the votes received and the approvals still pending.
+func (h *Handler) HandleGetApprovals(w http.ResponseWriter, r *http.Request) {
+	var status workflows.ApprovalStatus
+	h.serveDecisionQuery(w, r, workflows.QueryApprovals, "approvals", &status)
+}
+
+// HandleGetEscalation handles GET /instances/{id}/escalation.
+// It queries the workflow for the active escalation tier and its deadline.
+func (h *Handler) HandleGetEscalation(w http.ResponseWriter, r *http.Request) {
+	var status workflows.EscalationStatus
+	h.serveDecisionQuery(w, r, workflows.QueryEscalation, "escalation", &status)
+}
+
+// serveDecisionQuery runs a query that the instance's workflow registers once it waits for
+// a human decision, decodes the answer into status and writes it as JSON. what names the
+// answer in error responses.
+func (h *Handler) serveDecisionQuery(w http.ResponseWriter, r *http.Request, queryType, what string, status interface{}) {
+	instanceID := r.PathValue("id")
+	if instanceID == "" {
+		http.Error(w, "instance id required", http.StatusBadRequest)
+		return
+	}
+
+	value, err := h.TemporalClient.QueryWorkflow(r.Context(), instanceID, "", queryType)
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			http.Error(w, "instance not found", http.StatusNotFound)
+			return
+		}
+		if _, ok := err.(*serviceerror.QueryFailed); ok {
+			// The query is registered once the instance waits for a human decision.
+			http.Error(w, "instance has not requested a human decision", http.StatusConflict)
+			return
+		}
+		slog.Error("Failed to query workflow", "query", queryType, "error", err)
+		http.Error(w, "failed to query "+what, http.StatusInternalServerError)
+		return
+	}
+
+	if err := value.Get(status); err != nil {
+		slog.Error("Failed to decode query result", "query", queryType, "error", err)
+		http.Error(w, "failed to query "+what, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// QuarantineOperatorRoles are the roles allowed to resolve a quarantined instance.
+var QuarantineOperatorRoles = []string{"admin", "operator"}
+
+// ResolveQuarantineRequest defines the payload for an operator decision on a quarantined instance.
+type ResolveQuarantineRequest struct {
+	Action        string `json:"action"`             // RELEASE or ABANDON
+	ActorID       string `json:"actor_id,omitempty"` // Optional; must match the authenticated identity
+	Justification string `json:"justification"`
+}
+
+// HandleResolveQuarantine handles POST /instances/{id}/quarantine/resolution.
+// It sends the operator decision to the parked workflow. Only quarantined instances accept
+// it, so that a resolution can never be queued ahead of a future quarantine. The decision
+// is recorded as the authenticated human identity, under one of QuarantineOperatorRoles.
+func (h *Handler) HandleResolveQuarantine(w http.ResponseWriter, r *http.Request) {
+	instanceID := r.PathValue("id")
+	if instanceID == "" {
+		http.Error(w, "instance id required", http.StatusBadRequest)
+		return
+	}
+
+	var req ResolveQuarantineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	action := engine.QuarantineAction(req.Action)
+	if action != engine.QuarantineRelease && action != engine.QuarantineAbandon {
+		http.Error(w, "action must be RELEASE or ABANDON", http.StatusBadRequest)
+		return
+	}
+	if req.Justification == "" {
+		http.Error(w, "justification is required", http.StatusBadRequest)
+		return
+	}
+
+	// The resolution is recorded as the authenticated identity, never as the body claims
+	identity, err := middleware.GetIdentity(r.Context())
+	if err != nil {
+		http.Error(w, "authenticated identity required", http.StatusUnauthorized)
+		return
+	}
+	actor := actorIdentity(identity)
+	if err := checkClaimedActor(req.ActorID, actor); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	role := operatorRole(identity)
+	if identity.Type == auth.IdentityTypeMachine || role == "" {
+		http.Error(w, "quarantine resolution requires a human operator", http.StatusForbidden)
+		return
+	}
+
+	inst, err := h.ReadStore.GetInstance(r.Context(), instanceID)
+	if err != nil {
+		http.Error(w, "instance not found", http.StatusNotFound)
+		return
+	}
+	if inst.State != engine.StateQuarantined {
+		http.Error(w, fmt.Sprintf("instance is %s, not %s", inst.State, engine.StateQuarantined), http.StatusConflict)
+		return
+	}
+
+	signalArg := activities.ResolveQuarantineInput{
+		InstanceID:    instanceID,
+		Action:        action,
+		ActorID:       actor.ActorID(),
+		Justification: req.Justification,
+		Role:          role,
+	}
+	err = h.TemporalClient.SignalWorkflow(r.Context(), instanceID, "", workflows.SignalQuarantineResolution, signalArg)
+	if err != nil {
+		if _, ok := err.(*serviceerror.NotFound); ok {
+			http.Error(w, "instance not found or completed", http.StatusNotFound)
+			return
+		}
+		slog.Error("Failed to signal workflow", "error", err)
+		http.Error(w, "failed to resolve quarantine", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]string{"status": "SIGNAL_SENT"})
+}
+
+// checkClaimedActor rejects a claimed actor_id that is neither the caller's subject nor its
+// issuer|subject binding. An empty claim is accepted.
+func checkClaimedActor(actorID string, actor engine.ActorIdentity) error {
+	if actorID != "" && actorID != actor.Subject && actorID != actor.ActorID() {
+		return fmt.Errorf("actor_id %q does not match the authenticated identity", actorID)
+	}
+	return nil
+}
+
+// operatorRole returns the first of QuarantineOperatorRoles the identity holds, or "".
+func operatorRole(identity *auth.Identity) string {
+	for _, allowed := range QuarantineOperatorRoles {
+		if slices.Contains(identity.Roles, allowed) {
+			return allowed
+		}
+	}
+	return ""
+}
+
 // HandleGetAuditLogs retrieves audit logs for an instance.
 func (h *Handler) HandleGetAuditLogs(w http.ResponseWriter, r *http.Request) {
 	instanceID := r.PathValue("id")
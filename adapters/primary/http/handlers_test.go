@@ -9,10 +9,16 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Rainminds/gantral/core/activities"
 	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/core/workflows"
+	"github.com/Rainminds/gantral/internal/auth"
+	"github.com/Rainminds/gantral/internal/middleware"
 	"github.com/stretchr/testify/mock"
 	"go.temporal.io/api/serviceerror"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
 )
 
 // --- Mocks ---
@@ -36,6 +42,18 @@ func (m *MockTemporalClient) SignalWorkflow(ctx context.Context, workflowID stri
 	return args.Error(0)
 }
 
+func (m *MockTemporalClient) QueryWorkflow(ctx context.Context, workflowID string, runID string, queryType string, args ...interface{}) (converter.EncodedValue, error) {
+	called := m.Called(ctx, workflowID, runID, queryType)
+	if result := called.Get(0); result != nil {
+		payloads, err := converter.GetDefaultDataConverter().ToPayloads(result)
+		if err != nil {
+			return nil, err
+		}
+		return client.NewValue(payloads), called.Error(1)
+	}
+	return nil, called.Error(1)
+}
+
 type MockWorkflowRun struct {
 	mock.Mock
 }
@@ -68,23 +86,84 @@ func (m *MockReadStore) RecordDecision(ctx context.Context, cmd engine.RecordDec
 	args := m.Called(ctx, cmd, nextState)
 	return args.Get(0).(*engine.Instance), args.Error(1)
 }
+func (m *MockReadStore) TransitionInstance(ctx context.Context, cmd engine.TransitionCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+func (m *MockReadStore) RecordVote(ctx context.Context, cmd engine.RecordVoteCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+func (m *MockReadStore) RecordEscalation(ctx context.Context, cmd engine.RecordEscalationCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+func (m *MockReadStore) RecordRejectedDecision(ctx context.Context, cmd engine.RejectedDecisionCmd) error {
+	return m.Called(ctx, cmd).Error(0)
+}
+func (m *MockReadStore) QuarantineInstance(ctx context.Context, cmd engine.QuarantineCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+func (m *MockReadStore) ResolveQuarantine(ctx context.Context, cmd engine.ResolveQuarantineCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
 func (m *MockReadStore) GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error) {
 	args := m.Called(ctx, instanceID)
 	return args.Get(0).([]engine.AuditEvent), args.Error(1)
 }
 
+type MockPolicyStore struct {
+	mock.Mock
+}
+
+func (m *MockPolicyStore) CreatePolicyVersion(ctx context.Context, v *policy.Version) (*policy.Version, bool, error) {
+	args := m.Called(ctx, v)
+	stored, _ := args.Get(0).(*policy.Version)
+	return stored, args.Bool(1), args.Error(2)
+}
+func (m *MockPolicyStore) GetPolicyVersion(ctx context.Context, versionID string) (*policy.Version, error) {
+	args := m.Called(ctx, versionID)
+	v, _ := args.Get(0).(*policy.Version)
+	return v, args.Error(1)
+}
+func (m *MockPolicyStore) GetLatestPolicyVersion(ctx context.Context, name string) (*policy.Version, error) {
+	args := m.Called(ctx, name)
+	v, _ := args.Get(0).(*policy.Version)
+	return v, args.Error(1)
+}
+func (m *MockPolicyStore) ListPolicyVersions(ctx context.Context, name string) ([]*policy.Version, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]*policy.Version), args.Error(1)
+}
+func (m *MockPolicyStore) DeprecatePolicyVersion(ctx context.Context, versionID string) (*policy.Version, error) {
+	args := m.Called(ctx, versionID)
+	v, _ := args.Get(0).(*policy.Version)
+	return v, args.Error(1)
+}
+
 // --- Tests ---
 
 func TestCreateInstance(t *testing.T) {
 	mockTemporal := new(MockTemporalClient)
 	mockRun := new(MockWorkflowRun)
+	mockPolicies := new(MockPolicyStore)
 	handler := &Handler{
 		TemporalClient: mockTemporal,
 		TaskQueue:      "test-queue",
+		Policies:       mockPolicies,
+	}
+
+	version, err := policy.NewVersion("finance-high", policy.Policy{Materiality: policy.MaterialityHigh, RequiresHumanApproval: true})
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
 	}
+	mockPolicies.On("GetLatestPolicyVersion", mock.Anything, "finance-high").Return(version, nil)
+	mockPolicies.On("GetLatestPolicyVersion", mock.Anything, "missing").Return(nil, policy.ErrPolicyNotFound)
 
 	t.Run("Success", func(t *testing.T) {
-		reqBody := `{"workflow_id": "test-wf", "policy": {"id": "p1"}}`
+		reqBody := `{"workflow_id": "test-wf", "policy_name": "finance-high"}`
 		req := httptest.NewRequest("POST", "/instances", strings.NewReader(reqBody))
 		w := httptest.NewRecorder()
 
@@ -95,7 +174,12 @@ func TestCreateInstance(t *testing.T) {
 				return opts.TaskQueue == "test-queue" && strings.HasPrefix(opts.ID, "inst-")
 			}),
 			mock.Anything, // Workflow func
-			mock.Anything, // Args
+			mock.MatchedBy(func(args []interface{}) bool {
+				// The resolved version hash must be pinned into the workflow input
+				in, ok := args[0].(workflows.WorkflowInput)
+				return ok && in.WorkflowID == "test-wf" && in.Policy.ID == version.VersionID && in.PolicyName == "finance-high" &&
+					in.RequireIdentity
+			}),
 		).Return(mockRun, nil)
 
 		handler.CreateInstance(w, req)
@@ -109,6 +193,50 @@ func TestCreateInstance(t *testing.T) {
 		if resp.Status != "PENDING" {
 			t.Errorf("expected PENDING, got %s", resp.Status)
 		}
+		if resp.PolicyVersionID != version.VersionID {
+			t.Errorf("expected pinned version %s, got %s", version.VersionID, resp.PolicyVersionID)
+		}
+	})
+
+	t.Run("Records Creator Identity", func(t *testing.T) {
+		reqBody := `{"workflow_id": "creator-wf", "policy_name": "finance-high"}`
+		req := httptest.NewRequest("POST", "/instances", strings.NewReader(reqBody))
+		identity := &auth.Identity{Subject: "alice", Provider: "https://idp.example.com", OrgID: "payments", Type: auth.IdentityTypeHuman}
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, identity))
+		w := httptest.NewRecorder()
+
+		mockTemporal.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything,
+			mock.MatchedBy(func(args []interface{}) bool {
+				in, ok := args[0].(workflows.WorkflowInput)
+				return ok && in.WorkflowID == "creator-wf" && in.CreatedBy == "https://idp.example.com|alice" && in.CreatorOrgID == "payments"
+			}),
+		).Return(mockRun, nil).Once()
+
+		handler.CreateInstance(w, req)
+
+		if w.Code != stdhttp.StatusAccepted {
+			t.Errorf("expected 202, got %d", w.Code)
+		}
+	})
+
+	t.Run("Inline Policy Rejected", func(t *testing.T) {
+		reqBody := `{"workflow_id": "test-wf", "policy": {"id": "p1"}}`
+		req := httptest.NewRequest("POST", "/instances", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+		handler.CreateInstance(w, req)
+		if w.Code != stdhttp.StatusBadRequest {
+			t.Errorf("expected 400, got %d", w.Code)
+		}
+	})
+
+	t.Run("Unknown Policy", func(t *testing.T) {
+		reqBody := `{"workflow_id": "test-wf", "policy_name": "missing"}`
+		req := httptest.NewRequest("POST", "/instances", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+		handler.CreateInstance(w, req)
+		if w.Code != stdhttp.StatusNotFound {
+			t.Errorf("expected 404, got %d", w.Code)
+		}
 	})
 
 	t.Run("Invalid JSON", func(t *testing.T) {
@@ -127,9 +255,20 @@ func TestRecordDecision(t *testing.T) {
 		TemporalClient: mockTemporal,
 	}
 
+	identity := &auth.Identity{
+		Subject:  "user1",
+		Provider: "https://idp.example.com",
+		Type:     auth.IdentityTypeHuman,
+		Roles:    []string{"user", "group:engineering"},
+		OrgID:    "payments",
+	}
+	authenticated := func(req *stdhttp.Request) *stdhttp.Request {
+		return req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, identity))
+	}
+
 	t.Run("Success Approve", func(t *testing.T) {
 		reqBody := `{"type": "APPROVE", "actor_id": "user1", "justification": "LGTM"}`
-		req := httptest.NewRequest("POST", "/instances/inst-1/decisions", strings.NewReader(reqBody))
+		req := authenticated(httptest.NewRequest("POST", "/instances/inst-1/decisions", strings.NewReader(reqBody)))
 		req.SetPathValue("id", "inst-1")
 		w := httptest.NewRecorder()
 
@@ -138,10 +277,11 @@ func TestRecordDecision(t *testing.T) {
 			"inst-1",
 			"",
 			"HumanDecision",
-			mock.MatchedBy(func(arg interface{}) bool {
-				// We can't easily cast to RecordDecisionInput here due to internal type,
-				// but we verify the call happens.
-				return true
+			mock.MatchedBy(func(arg activities.RecordDecisionInput) bool {
+				// The actor is bound to the token's issuer and subject
+				return arg.ActorID == "https://idp.example.com|user1" &&
+					arg.Identity != nil && arg.Identity.Issuer == "https://idp.example.com" &&
+					arg.Role == "user" && len(arg.Roles) == 2 && arg.ActorOrgID == "payments"
 			}),
 		).Return(nil)
 
@@ -152,9 +292,40 @@ func TestRecordDecision(t *testing.T) {
 		}
 	})
 
+	t.Run("Actor Mismatch", func(t *testing.T) {
+		for _, reqBody := range []string{
+			`{"type": "APPROVE", "actor_id": "someone-else", "justification": "LGTM"}`,
+			`{"type": "APPROVE", "provider": "https://other-idp.example.com", "justification": "LGTM"}`,
+			`{"type": "APPROVE", "org_id": "risk", "justification": "LGTM"}`,
+		} {
+			req := authenticated(httptest.NewRequest("POST", "/instances/inst-1/decisions", strings.NewReader(reqBody)))
+			req.SetPathValue("id", "inst-1")
+			w := httptest.NewRecorder()
+
+			handler.RecordDecision(w, req)
+
+			if w.Code != stdhttp.StatusForbidden {
+				t.Errorf("%s: expected 403, got %d", reqBody, w.Code)
+			}
+		}
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		reqBody := `{"type": "APPROVE", "actor_id": "user1", "justification": "LGTM"}`
+		req := httptest.NewRequest("POST", "/instances/inst-1/decisions", strings.NewReader(reqBody))
+		req.SetPathValue("id", "inst-1")
+		w := httptest.NewRecorder()
+
+		handler.RecordDecision(w, req)
+
+		if w.Code != stdhttp.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", w.Code)
+		}
+	})
+
 	t.Run("Not Found", func(t *testing.T) {
 		reqBody := `{"type": "APPROVE"}`
-		req := httptest.NewRequest("POST", "/instances/missing/decisions", strings.NewReader(reqBody))
+		req := authenticated(httptest.NewRequest("POST", "/instances/missing/decisions", strings.NewReader(reqBody)))
 		req.SetPathValue("id", "missing")
 		w := httptest.NewRecorder()
 
@@ -172,6 +343,194 @@ func TestRecordDecision(t *testing.T) {
 	})
 }
 
+func TestGetApprovals(t *testing.T) {
+	mockTemporal := new(MockTemporalClient)
+	handler := &Handler{
+		TemporalClient: mockTemporal,
+	}
+
+	t.Run("Pending Votes", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/instances/inst-1/approvals", nil)
+		req.SetPathValue("id", "inst-1")
+		w := httptest.NewRecorder()
+
+		mockTemporal.On("QueryWorkflow", mock.Anything, "inst-1", "", workflows.QueryApprovals).Return(workflows.ApprovalStatus{
+			InstanceID:    "inst-1",
+			Approvers:     []string{"group:engineering", "group:compliance"},
+			Quorum:        2,
+			Votes:         []engine.Vote{{ActorID: "alice", Decision: engine.DecisionApprove, Group: "group:engineering", ArtifactID: "art-1"}},
+			PendingGroups: []string{"group:compliance"},
+			Outstanding:   1,
+		}, nil)
+
+		handler.HandleGetApprovals(w, req)
+
+		if w.Code != stdhttp.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var status workflows.ApprovalStatus
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		if status.Outstanding != 1 || len(status.Votes) != 1 || status.PendingGroups[0] != "group:compliance" {
+			t.Errorf("unexpected status: %+v", status)
+		}
+	})
+
+	t.Run("No Decision Requested", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/instances/inst-auto/approvals", nil)
+		req.SetPathValue("id", "inst-auto")
+		w := httptest.NewRecorder()
+
+		mockTemporal.On("QueryWorkflow", mock.Anything, "inst-auto", "", workflows.QueryApprovals).
+			Return(nil, &serviceerror.QueryFailed{Message: "unknown queryType approvals"})
+
+		handler.HandleGetApprovals(w, req)
+
+		if w.Code != stdhttp.StatusConflict {
+			t.Errorf("expected 409, got %d", w.Code)
+		}
+	})
+}
+
+func TestGetEscalation(t *testing.T) {
+	mockTemporal := new(MockTemporalClient)
+	handler := &Handler{
+		TemporalClient: mockTemporal,
+	}
+
+	t.Run("Escalated", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/instances/inst-1/escalation", nil)
+		req.SetPathValue("id", "inst-1")
+		w := httptest.NewRecorder()
+
+		deadline := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+		mockTemporal.On("QueryWorkflow", mock.Anything, "inst-1", "", workflows.QueryEscalation).Return(workflows.EscalationStatus{
+			InstanceID:    "inst-1",
+			Tier:          1,
+			Tiers:         2,
+			ApproverRoles: []string{"director"},
+			Deadline:      deadline,
+			FinalAction:   policy.TimeoutReject,
+		}, nil)
+
+		handler.HandleGetEscalation(w, req)
+
+		if w.Code != stdhttp.StatusOK {
+			t.Fatalf("expected 200, got %d", w.Code)
+		}
+		var status workflows.EscalationStatus
+		if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+			t.Fatal(err)
+		}
+		if status.Tier != 1 || !status.Deadline.Equal(deadline) || status.ApproverRoles[0] != "director" {
+			t.Errorf("unexpected status: %+v", status)
+		}
+	})
+
+	t.Run("Instance Not Found", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/instances/missing/escalation", nil)
+		req.SetPathValue("id", "missing")
+		w := httptest.NewRecorder()
+
+		mockTemporal.On("QueryWorkflow", mock.Anything, "missing", "", workflows.QueryEscalation).
+			Return(nil, &serviceerror.NotFound{Message: "workflow not found"})
+
+		handler.HandleGetEscalation(w, req)
+
+		if w.Code != stdhttp.StatusNotFound {
+			t.Errorf("expected 404, got %d", w.Code)
+		}
+	})
+}
+
+func TestResolveQuarantine(t *testing.T) {
+	mockTemporal := new(MockTemporalClient)
+	mockStore := new(MockReadStore)
+	handler := &Handler{
+		TemporalClient: mockTemporal,
+		ReadStore:      mockStore,
+	}
+	mockStore.On("GetInstance", mock.Anything, "inst-q").Return(&engine.Instance{ID: "inst-q", State: engine.StateQuarantined}, nil)
+	mockStore.On("GetInstance", mock.Anything, "inst-ok").Return(&engine.Instance{ID: "inst-ok", State: engine.StateWaitingForHuman}, nil)
+
+	operator := &auth.Identity{Subject: "ops-1", Provider: "https://idp.example.com", Type: auth.IdentityTypeHuman, Roles: []string{"user", "operator"}}
+	resolveAs := func(identity *auth.Identity, id, body string) int {
+		req := httptest.NewRequest("POST", "/instances/"+id+"/quarantine/resolution", strings.NewReader(body))
+		if identity != nil {
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, identity))
+		}
+		req.SetPathValue("id", id)
+		w := httptest.NewRecorder()
+		handler.HandleResolveQuarantine(w, req)
+		return w.Code
+	}
+	resolve := func(id, body string) int {
+		return resolveAs(operator, id, body)
+	}
+
+	t.Run("Release", func(t *testing.T) {
+		mockTemporal.On("SignalWorkflow", mock.Anything, "inst-q", "", "QuarantineResolution",
+			mock.MatchedBy(func(arg interface{}) bool {
+				in, ok := arg.(activities.ResolveQuarantineInput)
+				return ok && in.Action == engine.QuarantineRelease && in.ActorID == "https://idp.example.com|ops-1" &&
+					in.Role == "operator" && in.RestoreState == ""
+			}),
+		).Return(nil).Once()
+
+		code := resolve("inst-q", `{"action": "RELEASE", "actor_id": "ops-1", "justification": "head repaired", "restore_state": "APPROVED"}`)
+		if code != stdhttp.StatusAccepted {
+			t.Errorf("expected 202, got %d", code)
+		}
+	})
+
+	t.Run("Not Quarantined", func(t *testing.T) {
+		code := resolve("inst-ok", `{"action": "RELEASE", "actor_id": "ops-1", "justification": "x"}`)
+		if code != stdhttp.StatusConflict {
+			t.Errorf("expected 409, got %d", code)
+		}
+	})
+
+	t.Run("Invalid Action", func(t *testing.T) {
+		code := resolve("inst-q", `{"action": "APPROVE", "actor_id": "ops-1", "justification": "x"}`)
+		if code != stdhttp.StatusBadRequest {
+			t.Errorf("expected 400, got %d", code)
+		}
+	})
+
+	t.Run("Missing Justification", func(t *testing.T) {
+		code := resolve("inst-q", `{"action": "ABANDON", "actor_id": "ops-1"}`)
+		if code != stdhttp.StatusBadRequest {
+			t.Errorf("expected 400, got %d", code)
+		}
+	})
+
+	t.Run("Unauthenticated", func(t *testing.T) {
+		code := resolveAs(nil, "inst-q", `{"action": "ABANDON", "justification": "x"}`)
+		if code != stdhttp.StatusUnauthorized {
+			t.Errorf("expected 401, got %d", code)
+		}
+	})
+
+	t.Run("Actor Mismatch", func(t *testing.T) {
+		code := resolve("inst-q", `{"action": "ABANDON", "actor_id": "someone-else", "justification": "x"}`)
+		if code != stdhttp.StatusForbidden {
+			t.Errorf("expected 403, got %d", code)
+		}
+	})
+
+	t.Run("Not An Operator", func(t *testing.T) {
+		user := &auth.Identity{Subject: "user1", Provider: "dev-mode", Type: auth.IdentityTypeHuman, Roles: []string{"user"}}
+		if code := resolveAs(user, "inst-q", `{"action": "ABANDON", "justification": "x"}`); code != stdhttp.StatusForbidden {
+			t.Errorf("expected 403 for a user, got %d", code)
+		}
+		runner := &auth.Identity{Subject: "runner-1", Provider: "dev-mode", Type: auth.IdentityTypeMachine, Roles: []string{"operator"}}
+		if code := resolveAs(runner, "inst-q", `{"action": "ABANDON", "justification": "x"}`); code != stdhttp.StatusForbidden {
+			t.Errorf("expected 403 for a machine, got %d", code)
+		}
+	})
+}
+
 func TestGetAuditLogs(t *testing.T) {
 	mockStore := new(MockReadStore)
 	handler := &Handler{
@@ -0,0 +1,159 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/pkg/translog"
+)
+
+// TransparencyLog serves checkpoints and proofs of the global artifact log.
+type TransparencyLog interface {
+	LatestCheckpoint(ctx context.Context) (*translog.Checkpoint, error)
+	GetCheckpoint(ctx context.Context, size uint64) (*translog.Checkpoint, error)
+	Receipts(ctx context.Context, size uint64) ([]*translog.Receipt, error)
+	InclusionProof(ctx context.Context, artifactID string, treeSize uint64) (*translog.InclusionProof, error)
+	ConsistencyProof(ctx context.Context, first, second uint64) (*translog.ConsistencyProof, error)
+}
+
+// HandleGetLatestCheckpoint handles GET /log/checkpoint.
+func (h *Handler) HandleGetLatestCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLog(w) {
+		return
+	}
+	cp, err := h.Log.LatestCheckpoint(r.Context())
+	if err != nil {
+		writeLogError(w, err)
+		return
+	}
+	writeJSON(w, cp)
+}
+
+// HandleGetCheckpoint handles GET /log/checkpoints/{size}.
+func (h *Handler) HandleGetCheckpoint(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLog(w) {
+		return
+	}
+	size, err := strconv.ParseUint(r.PathValue("size"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid tree size", http.StatusBadRequest)
+		return
+	}
+	cp, err := h.Log.GetCheckpoint(r.Context(), size)
+	if err != nil {
+		writeLogError(w, err)
+		return
+	}
+	writeJSON(w, cp)
+}
+
+// HandleGetReceipts handles GET /log/checkpoints/{size}/receipts: the anchor receipts
+// of a checkpoint (an empty list if it has not been anchored yet).
+func (h *Handler) HandleGetReceipts(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLog(w) {
+		return
+	}
+	size, err := strconv.ParseUint(r.PathValue("size"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid tree size", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.Log.GetCheckpoint(r.Context(), size); err != nil {
+		writeLogError(w, err)
+		return
+	}
+	receipts, err := h.Log.Receipts(r.Context(), size)
+	if err != nil {
+		writeLogError(w, err)
+		return
+	}
+	writeJSON(w, receipts)
+}
+
+// HandleGetInclusionProof handles GET /log/inclusion?artifact_id={id}[&tree_size={n}].
+// Without tree_size the proof is against the latest checkpoint.
+func (h *Handler) HandleGetInclusionProof(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLog(w) {
+		return
+	}
+	artifactID := r.URL.Query().Get("artifact_id")
+	if artifactID == "" {
+		http.Error(w, "artifact_id required", http.StatusBadRequest)
+		return
+	}
+
+	var treeSize uint64
+	if v := r.URL.Query().Get("tree_size"); v != "" {
+		size, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid tree_size", http.StatusBadRequest)
+			return
+		}
+		treeSize = size
+	} else {
+		cp, err := h.Log.LatestCheckpoint(r.Context())
+		if err != nil {
+			writeLogError(w, err)
+			return
+		}
+		treeSize = cp.TreeSize
+	}
+
+	proof, err := h.Log.InclusionProof(r.Context(), artifactID, treeSize)
+	if err != nil {
+		writeLogError(w, err)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+// HandleGetConsistencyProof handles GET /log/consistency?first={m}&second={n}.
+func (h *Handler) HandleGetConsistencyProof(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLog(w) {
+		return
+	}
+	first, err1 := strconv.ParseUint(r.URL.Query().Get("first"), 10, 64)
+	second, err2 := strconv.ParseUint(r.URL.Query().Get("second"), 10, 64)
+	if err1 != nil || err2 != nil {
+		http.Error(w, "first and second tree sizes required", http.StatusBadRequest)
+		return
+	}
+
+	proof, err := h.Log.ConsistencyProof(r.Context(), first, second)
+	if err != nil {
+		writeLogError(w, err)
+		return
+	}
+	writeJSON(w, proof)
+}
+
+func (h *Handler) requireLog(w http.ResponseWriter) bool {
+	if h.Log == nil {
+		http.Error(w, "artifact log not configured", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeLogError maps artifact log errors to HTTP status codes.
+func writeLogError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, artifactlog.ErrInvalidTreeSize):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, artifactlog.ErrNotInLog), errors.Is(err, artifactlog.ErrCheckpointNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		slog.Error("artifact log failure", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
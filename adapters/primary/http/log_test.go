@@ -0,0 +1,89 @@
+package http
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/translog"
+	"github.com/stretchr/testify/assert"
+	"gocloud.dev/blob/memblob"
+)
+
+func TestArtifactLogEndpoints(t *testing.T) {
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("log-key", priv)
+	keys := signing.KeySet{"log-key": pub}
+
+	log := artifactlog.New(bucket, "gantral.test")
+	for i := 0; i < 3; i++ {
+		_, _ = log.Append(ctx, fmt.Sprintf("art-%d", i))
+	}
+	_, _ = log.Checkpoint(ctx, signer)
+	for i := 3; i < 5; i++ {
+		_, _ = log.Append(ctx, fmt.Sprintf("art-%d", i))
+	}
+	_, _ = log.Checkpoint(ctx, signer)
+
+	mux := NewServer("8080", nil, "queue", nil, nil, nil).WithLog(log).Routes()
+	get := func(path string, v interface{}) int {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code == stdhttp.StatusOK && v != nil {
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(v))
+		}
+		return w.Code
+	}
+
+	var latest, first translog.Checkpoint
+	assert.Equal(t, stdhttp.StatusOK, get("/log/checkpoint", &latest))
+	assert.Equal(t, uint64(5), latest.TreeSize)
+	assert.NoError(t, translog.VerifyCheckpoint(&latest, keys))
+	assert.Equal(t, stdhttp.StatusOK, get("/log/checkpoints/3", &first))
+
+	t.Run("Inclusion Against Latest", func(t *testing.T) {
+		var proof translog.InclusionProof
+		assert.Equal(t, stdhttp.StatusOK, get("/log/inclusion?artifact_id=art-1", &proof))
+		assert.Equal(t, uint64(5), proof.TreeSize)
+		assert.NoError(t, translog.VerifyInclusion(&proof, &latest))
+	})
+
+	t.Run("Consistency", func(t *testing.T) {
+		var proof translog.ConsistencyProof
+		assert.Equal(t, stdhttp.StatusOK, get("/log/consistency?first=3&second=5", &proof))
+		assert.NoError(t, translog.VerifyConsistency(&proof, &first, &latest))
+	})
+
+	t.Run("Receipts", func(t *testing.T) {
+		_ = log.PutReceipt(ctx, &translog.Receipt{Witness: "witness", Kind: translog.ReceiptKindBucket, Origin: first.Origin, TreeSize: 3, RootHash: first.RootHash})
+		var receipts []translog.Receipt
+		assert.Equal(t, stdhttp.StatusOK, get("/log/checkpoints/3/receipts", &receipts))
+		assert.Len(t, receipts, 1)
+		assert.Equal(t, stdhttp.StatusOK, get("/log/checkpoints/5/receipts", &receipts))
+		assert.Empty(t, receipts)
+		assert.Equal(t, stdhttp.StatusNotFound, get("/log/checkpoints/4/receipts", nil))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		assert.Equal(t, stdhttp.StatusNotFound, get("/log/inclusion?artifact_id=art-4&tree_size=3", nil))
+		assert.Equal(t, stdhttp.StatusNotFound, get("/log/checkpoints/4", nil))
+		assert.Equal(t, stdhttp.StatusBadRequest, get("/log/consistency?first=3&second=9", nil))
+		assert.Equal(t, stdhttp.StatusBadRequest, get("/log/inclusion", nil))
+	})
+
+	t.Run("Not Configured", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewServer("8080", nil, "queue", nil, nil, nil).Routes().ServeHTTP(w, httptest.NewRequest("GET", "/log/checkpoint", nil))
+		assert.Equal(t, stdhttp.StatusNotFound, w.Code)
+	})
+}
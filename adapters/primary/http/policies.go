@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Rainminds/gantral/core/policy"
+)
+
+// CreatePolicyRequest defines the payload for registering a policy version.
+type CreatePolicyRequest struct {
+	Name                   string                  `json:"name"`
+	Materiality            policy.MaterialityLevel `json:"materiality"`
+	RequiresHumanApproval  bool                    `json:"requires_human_approval"`
+	ApprovalTimeoutSeconds int64                   `json:"approval_timeout_seconds"`
+	Approvers              []string                `json:"approvers,omitempty"`
+	Quorum                 int                     `json:"quorum,omitempty"`
+	ApproverRoles          []string                `json:"approver_roles,omitempty"`
+	OverrideRoles          []string                `json:"override_roles,omitempty"`
+
+	SeparationOfDuties *policy.SeparationOfDuties `json:"separation_of_duties,omitempty"`
+	Escalation         *policy.Escalation         `json:"escalation,omitempty"`
+	Bundle             string                     `json:"bundle,omitempty"` // Rego bundle hash the version must run with
+}
+
+// HandleCreatePolicy handles POST /policies.
+// Versions are content-addressed: registering identical content again returns the existing version (200).
+func (h *Handler) HandleCreatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req CreatePolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	version, err := policy.NewVersion(req.Name, policy.Policy{
+		Materiality:            req.Materiality,
+		RequiresHumanApproval:  req.RequiresHumanApproval,
+		ApprovalTimeoutSeconds: req.ApprovalTimeoutSeconds,
+		Approvers:              req.Approvers,
+		Quorum:                 req.Quorum,
+		ApproverRoles:          req.ApproverRoles,
+		OverrideRoles:          req.OverrideRoles,
+		SeparationOfDuties:     req.SeparationOfDuties,
+		Escalation:             req.Escalation,
+		Bundle:                 req.Bundle,
+	})
+	if err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	stored, created, err := h.Policies.CreatePolicyVersion(r.Context(), version)
+	if err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+		slog.Info("Policy version registered", "name", stored.Name, "version_id", stored.VersionID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprintf("/policies/%s", stored.VersionID))
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(stored)
+}
+
+// HandleListPolicies handles GET /policies, optionally filtered by ?name=.
+func (h *Handler) HandleListPolicies(w http.ResponseWriter, r *http.Request) {
+	versions, err := h.Policies.ListPolicyVersions(r.Context(), r.URL.Query().Get("name"))
+	if err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"policies": versions,
+	})
+}
+
+// HandleGetPolicy handles GET /policies/{id}.
+func (h *Handler) HandleGetPolicy(w http.ResponseWriter, r *http.Request) {
+	version, err := h.Policies.GetPolicyVersion(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(version)
+}
+
+// HandleDeprecatePolicy handles POST /policies/{id}/deprecate.
+// Deprecated versions stay readable (for verification) but cannot start new executions.
+func (h *Handler) HandleDeprecatePolicy(w http.ResponseWriter, r *http.Request) {
+	version, err := h.Policies.DeprecatePolicyVersion(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writePolicyError(w, err)
+		return
+	}
+
+	slog.Info("Policy version deprecated", "name", version.Name, "version_id", version.VersionID)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(version)
+}
+
+// resolvePolicy returns the version a new execution must be pinned to.
+// Without an explicit pin, the latest non-deprecated version of the named policy is used.
+func (h *Handler) resolvePolicy(r *http.Request, name, versionID string) (*policy.Version, error) {
+	if versionID == "" {
+		return h.Policies.GetLatestPolicyVersion(r.Context(), name)
+	}
+
+	version, err := h.Policies.GetPolicyVersion(r.Context(), versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version.Name != name {
+		return nil, fmt.Errorf("%w: version %s does not belong to policy %q", policy.ErrPolicyNotFound, versionID, name)
+	}
+	if version.Deprecated {
+		return nil, fmt.Errorf("%w: %s", policy.ErrPolicyDeprecated, versionID)
+	}
+	return version, nil
+}
+
+// writePolicyError maps registry errors to HTTP status codes.
+func writePolicyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, policy.ErrInvalidPolicy):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, policy.ErrPolicyNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, policy.ErrPolicyDeprecated):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		// Includes ErrPolicyTampered: never serve or execute a policy whose hash does not verify.
+		slog.Error("policy registry error", "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"encoding/json"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePolicy(t *testing.T) {
+	mockPolicies := new(MockPolicyStore)
+	handler := &Handler{Policies: mockPolicies}
+
+	reqBody := `{"name": "finance-high", "materiality": "HIGH", "requires_human_approval": true}`
+	expected, err := policy.NewVersion("finance-high", policy.Policy{Materiality: policy.MaterialityHigh, RequiresHumanApproval: true})
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		mockPolicies.On("CreatePolicyVersion", mock.Anything, mock.MatchedBy(func(v *policy.Version) bool {
+			return v.VersionID == expected.VersionID
+		})).Return(expected, true, nil).Once()
+
+		w := httptest.NewRecorder()
+		handler.HandleCreatePolicy(w, httptest.NewRequest("POST", "/policies", strings.NewReader(reqBody)))
+
+		assert.Equal(t, stdhttp.StatusCreated, w.Code)
+		assert.Equal(t, "/policies/"+expected.VersionID, w.Header().Get("Location"))
+
+		var resp policy.Version
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		assert.Equal(t, expected.VersionID, resp.VersionID)
+		assert.Equal(t, expected.VersionID, resp.Policy.ID)
+	})
+
+	t.Run("Idempotent", func(t *testing.T) {
+		mockPolicies.On("CreatePolicyVersion", mock.Anything, mock.Anything).Return(expected, false, nil).Once()
+
+		w := httptest.NewRecorder()
+		handler.HandleCreatePolicy(w, httptest.NewRequest("POST", "/policies", strings.NewReader(reqBody)))
+
+		assert.Equal(t, stdhttp.StatusOK, w.Code)
+	})
+
+	t.Run("Invalid Definition", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.HandleCreatePolicy(w, httptest.NewRequest("POST", "/policies", strings.NewReader(`{"name": "bad name!", "materiality": "HIGH"}`)))
+
+		assert.Equal(t, stdhttp.StatusBadRequest, w.Code)
+	})
+}
+
+func TestDeprecatePolicy(t *testing.T) {
+	mockPolicies := new(MockPolicyStore)
+	handler := &Handler{Policies: mockPolicies}
+
+	mockPolicies.On("DeprecatePolicyVersion", mock.Anything, "unknown").Return(nil, policy.ErrPolicyNotFound)
+
+	req := httptest.NewRequest("POST", "/policies/unknown/deprecate", nil)
+	req.SetPathValue("id", "unknown")
+	w := httptest.NewRecorder()
+	handler.HandleDeprecatePolicy(w, req)
+
+	assert.Equal(t, stdhttp.StatusNotFound, w.Code)
+}
+
+func TestResolvePolicy_Pinned(t *testing.T) {
+	mockPolicies := new(MockPolicyStore)
+	handler := &Handler{Policies: mockPolicies}
+
+	active, _ := policy.NewVersion("ops", policy.Policy{Materiality: policy.MaterialityLow})
+	deprecated, _ := policy.NewVersion("ops", policy.Policy{Materiality: policy.MaterialityMedium})
+	deprecated.Deprecated = true
+
+	mockPolicies.On("GetPolicyVersion", mock.Anything, active.VersionID).Return(active, nil)
+	mockPolicies.On("GetPolicyVersion", mock.Anything, deprecated.VersionID).Return(deprecated, nil)
+
+	req := httptest.NewRequest("POST", "/instances", nil)
+
+	v, err := handler.resolvePolicy(req, "ops", active.VersionID)
+	assert.NoError(t, err)
+	assert.Equal(t, active.VersionID, v.VersionID)
+
+	_, err = handler.resolvePolicy(req, "other", active.VersionID)
+	assert.ErrorIs(t, err, policy.ErrPolicyNotFound, "a version must not be resolvable under another policy's name")
+
+	_, err = handler.resolvePolicy(req, "ops", deprecated.VersionID)
+	assert.ErrorIs(t, err, policy.ErrPolicyDeprecated)
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/Rainminds/gantral/core/ports"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/signing"
 	"github.com/Rainminds/gantral/web"
 	"go.temporal.io/sdk/client"
 )
@@ -17,16 +19,32 @@ type Server struct {
 }
 
 // NewServer creates a new API server.
-func NewServer(port string, temporalClient client.Client, taskQueue string, readStore ports.InstanceStore) *Server {
+func NewServer(port string, temporalClient client.Client, taskQueue string, readStore ports.InstanceStore, policies ports.PolicyStore, artifacts artifact.Store) *Server {
 	return &Server{
 		handler: &Handler{
 			TemporalClient: temporalClient,
 			TaskQueue:      taskQueue,
 			ReadStore:      readStore,
+			Policies:       policies,
+			Artifacts:      artifacts,
 		},
 	}
 }
 
+// WithAuditSources sets the optional evidence source and public keys included in
+// audit bundles served by GET /bundles.
+func (s *Server) WithAuditSources(evidence EvidenceSource, keys *signing.KeySetFile) *Server {
+	s.handler.Evidence = evidence
+	s.handler.PublicKeys = keys
+	return s
+}
+
+// WithLog sets the global artifact log served under /log.
+func (s *Server) WithLog(log TransparencyLog) *Server {
+	s.handler.Log = log
+	return s
+}
+
 // Routes returns the http.ServeMux with all registered routes.
 func (s *Server) Routes() *http.ServeMux {
 	mux := http.NewServeMux()
@@ -34,9 +52,24 @@ func (s *Server) Routes() *http.ServeMux {
 	// Register routes using Go 1.22 method + path pattern
 	mux.HandleFunc("POST /instances", s.handler.CreateInstance)
 	mux.HandleFunc("POST /instances/{id}/decisions", s.handler.RecordDecision)
+	mux.HandleFunc("POST /instances/{id}/quarantine/resolution", s.handler.HandleResolveQuarantine)
+	mux.HandleFunc("GET /instances/{id}/approvals", s.handler.HandleGetApprovals)
+	mux.HandleFunc("GET /instances/{id}/escalation", s.handler.HandleGetEscalation)
 	mux.HandleFunc("GET /instances/{id}/audit", s.handler.HandleGetAuditLogs)
+	mux.HandleFunc("GET /instances/{id}/artifacts", s.handler.HandleListInstanceArtifacts)
 	mux.HandleFunc("GET /instances/{id}", s.handler.HandleGetInstance)
 	mux.HandleFunc("GET /instances", s.handler.HandleListInstances)
+	mux.HandleFunc("POST /policies", s.handler.HandleCreatePolicy)
+	mux.HandleFunc("GET /policies", s.handler.HandleListPolicies)
+	mux.HandleFunc("GET /policies/{id}", s.handler.HandleGetPolicy)
+	mux.HandleFunc("POST /policies/{id}/deprecate", s.handler.HandleDeprecatePolicy)
+	mux.HandleFunc("GET /artifacts/{id}", s.handler.HandleGetArtifact)
+	mux.HandleFunc("GET /bundles", s.handler.HandleExportBundle)
+	mux.HandleFunc("GET /log/checkpoint", s.handler.HandleGetLatestCheckpoint)
+	mux.HandleFunc("GET /log/checkpoints/{size}", s.handler.HandleGetCheckpoint)
+	mux.HandleFunc("GET /log/checkpoints/{size}/receipts", s.handler.HandleGetReceipts)
+	mux.HandleFunc("GET /log/inclusion", s.handler.HandleGetInclusionProof)
+	mux.HandleFunc("GET /log/consistency", s.handler.HandleGetConsistencyProof)
 	mux.HandleFunc("GET /healthz", s.handler.HealthCheck)
 
 	// Serve Static Files
@@ -10,7 +10,7 @@ func TestRoutes(t *testing.T) {
 	// Use nil dependencies for route registration check.
 	// NewServer constructs the Handler; we verifies Routes() registers paths correctly.
 
-	srv := NewServer("8080", nil, "queue", nil)
+	srv := NewServer("8080", nil, "queue", nil, nil, nil)
 	// Routes() registers handlers but doesn't execute them, so nil dependencies are safe here.
 	mux := srv.Routes()
 
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/core/ports"
+	"github.com/Rainminds/gantral/infra/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// Ensure Store implements PolicyStore
+var _ ports.PolicyStore = (*Store)(nil)
+
+func (s *Store) CreatePolicyVersion(ctx context.Context, v *policy.Version) (*policy.Version, bool, error) {
+	// Never trust the caller's ID: re-derive it from the exact bytes being stored.
+	if _, err := policy.VerifyVersion(v.VersionID, v.Body); err != nil {
+		return nil, false, err
+	}
+
+	row, err := s.Queries.CreatePolicyVersion(ctx, db.CreatePolicyVersionParams{
+		VersionID: v.VersionID,
+		Name:      v.Name,
+		Body:      string(v.Body),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// ON CONFLICT DO NOTHING: identical content is already registered.
+		existing, err := s.GetPolicyVersion(ctx, v.VersionID)
+		if err != nil {
+			return nil, false, err
+		}
+		return existing, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error creating policy version: %w", err)
+	}
+
+	stored, err := mapDBPolicyVersion(row)
+	if err != nil {
+		return nil, false, err
+	}
+	return stored, true, nil
+}
+
+func (s *Store) GetPolicyVersion(ctx context.Context, versionID string) (*policy.Version, error) {
+	row, err := s.Queries.GetPolicyVersion(ctx, versionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", policy.ErrPolicyNotFound, versionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting policy version: %w", err)
+	}
+	return mapDBPolicyVersion(row)
+}
+
+func (s *Store) GetLatestPolicyVersion(ctx context.Context, name string) (*policy.Version, error) {
+	row, err := s.Queries.GetLatestPolicyVersion(ctx, name)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: no active version of %q", policy.ErrPolicyNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest policy version: %w", err)
+	}
+	return mapDBPolicyVersion(row)
+}
+
+func (s *Store) ListPolicyVersions(ctx context.Context, name string) ([]*policy.Version, error) {
+	var (
+		rows []db.PolicyVersion
+		err  error
+	)
+	if name == "" {
+		rows, err = s.Queries.ListPolicyVersions(ctx)
+	} else {
+		rows, err = s.Queries.ListPolicyVersionsByName(ctx, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error listing policy versions: %w", err)
+	}
+
+	result := make([]*policy.Version, 0, len(rows))
+	for _, r := range rows {
+		v, err := mapDBPolicyVersion(r)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+func (s *Store) DeprecatePolicyVersion(ctx context.Context, versionID string) (*policy.Version, error) {
+	row, err := s.Queries.DeprecatePolicyVersion(ctx, versionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", policy.ErrPolicyNotFound, versionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error deprecating policy version: %w", err)
+	}
+	return mapDBPolicyVersion(row)
+}
+
+// mapDBPolicyVersion re-hashes the stored body so that tampering at rest fails closed.
+func mapDBPolicyVersion(row db.PolicyVersion) (*policy.Version, error) {
+	v, err := policy.VerifyVersion(row.VersionID, []byte(row.Body))
+	if err != nil {
+		return nil, err
+	}
+	v.Deprecated = row.Deprecated
+	v.CreatedAt = row.CreatedAt.Time
+	if row.DeprecatedAt.Valid {
+		t := row.DeprecatedAt.Time
+		v.DeprecatedAt = &t
+	}
+	return v, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/Rainminds/gantral/core/engine"
@@ -62,6 +63,8 @@ func (s *Store) CreateInstance(ctx context.Context, inst *engine.Instance) error
 		PolicyContext:    policyBytes,
 		PolicyVersionID:  inst.PolicyVersionID,
 		LastArtifactHash: inst.LastArtifactHash,
+		CreatedBy:        inst.CreatedBy,
+		CreatorOrgID:     inst.CreatorOrgID,
 	})
 	if err != nil {
 		return err
@@ -71,6 +74,7 @@ func (s *Store) CreateInstance(ctx context.Context, inst *engine.Instance) error
 	eventPayload := map[string]interface{}{
 		"workflow_id": inst.WorkflowID,
 		"state":       inst.State,
+		"created_by":  inst.CreatedBy,
 	}
 	payloadBytes, _ := json.Marshal(eventPayload)
 
@@ -144,15 +148,21 @@ func (s *Store) RecordDecision(ctx context.Context, cmd engine.RecordDecisionCmd
 		return nil, fmt.Errorf("failed to fetch current instance state: %w", err)
 	}
 
-	// 2. Update Instance State
-	err = qtx.UpdateInstanceState(ctx, db.UpdateInstanceStateParams{
-		ID:               cmd.InstanceID,
-		State:            string(nextState),
-		LastArtifactHash: cmd.NewArtifactHash,
+	// 2. Compare-and-set: the decision's artifact was chained from (WAITING_FOR_HUMAN,
+	// PrevArtifactHash). If either moved, a concurrent writer got there first.
+	rows, err := qtx.TransitionInstanceState(ctx, db.TransitionInstanceStateParams{
+		ID:                 cmd.InstanceID,
+		State:              string(nextState),
+		LastArtifactHash:   cmd.NewArtifactHash,
+		State_2:            string(engine.StateWaitingForHuman),
+		LastArtifactHash_2: cmd.PrevArtifactHash,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update instance state: %w", err)
 	}
+	if rows == 0 {
+		return nil, fmt.Errorf("%w: %s", engine.ErrStaleInstance, cmd.InstanceID)
+	}
 
 	// 3. Create Audit Event (DECISION_RECORDED)
 	eventPayload := map[string]interface{}{
@@ -183,6 +193,263 @@ func (s *Store) RecordDecision(ctx context.Context, cmd engine.RecordDecisionCmd
 	return s.GetInstance(ctx, cmd.InstanceID)
 }
 
+func (s *Store) TransitionInstance(ctx context.Context, cmd engine.TransitionCmd) (*engine.Instance, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.WithTx(tx)
+
+	// 1. Compare-and-set: the artifact was chained from (From, PrevArtifactHash).
+	// If either moved, writing would fork the DB away from the evidence chain.
+	rows, err := qtx.TransitionInstanceState(ctx, db.TransitionInstanceStateParams{
+		ID:                 cmd.InstanceID,
+		State:              string(cmd.To),
+		LastArtifactHash:   cmd.NewArtifactHash,
+		State_2:            string(cmd.From),
+		LastArtifactHash_2: cmd.PrevArtifactHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update instance state: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("%w: %s", engine.ErrStaleInstance, cmd.InstanceID)
+	}
+
+	// 2. Create Audit Event (STATE_TRANSITIONED)
+	eventPayload := map[string]interface{}{
+		"actor_id":    cmd.ActorID,
+		"reason":      cmd.Reason,
+		"from_state":  cmd.From,
+		"to_state":    cmd.To,
+		"artifact_id": cmd.NewArtifactHash,
+	}
+	payloadBytes, _ := json.Marshal(eventPayload)
+
+	_, err = qtx.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		ID:         fmt.Sprintf("evt-%d", time.Now().UnixNano()),
+		InstanceID: cmd.InstanceID,
+		EventType:  "STATE_TRANSITIONED",
+		Payload:    payloadBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetInstance(ctx, cmd.InstanceID)
+}
+
+func (s *Store) RecordVote(ctx context.Context, cmd engine.RecordVoteCmd) (*engine.Instance, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.WithTx(tx)
+
+	// 1. Compare-and-set on the head; the state stays WAITING_FOR_HUMAN.
+	waiting := string(engine.StateWaitingForHuman)
+	rows, err := qtx.TransitionInstanceState(ctx, db.TransitionInstanceStateParams{
+		ID:                 cmd.InstanceID,
+		State:              waiting,
+		LastArtifactHash:   cmd.Vote.ArtifactID,
+		State_2:            waiting,
+		LastArtifactHash_2: cmd.PrevArtifactHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update instance head: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("%w: %s", engine.ErrStaleInstance, cmd.InstanceID)
+	}
+
+	// 2. Create Audit Event (VOTE_RECORDED). Votes are not decisions: only the aggregate
+	// outcome is written to the decisions table.
+	eventPayload := map[string]interface{}{
+		"actor_id":      cmd.Vote.ActorID,
+		"decision_type": cmd.Vote.Decision,
+		"group":         cmd.Vote.Group,
+		"role":          cmd.Role,
+		"justification": cmd.Justification,
+		"artifact_id":   cmd.Vote.ArtifactID,
+	}
+	payloadBytes, _ := json.Marshal(eventPayload)
+
+	_, err = qtx.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		ID:         fmt.Sprintf("evt-%d", time.Now().UnixNano()),
+		InstanceID: cmd.InstanceID,
+		EventType:  "VOTE_RECORDED",
+		Payload:    payloadBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetInstance(ctx, cmd.InstanceID)
+}
+
+// RecordEscalation moves a waiting instance to its next escalation tier and writes an
+// APPROVAL_ESCALATED audit event in the same transaction.
+func (s *Store) RecordEscalation(ctx context.Context, cmd engine.RecordEscalationCmd) (*engine.Instance, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.WithTx(tx)
+
+	// 1. Compare-and-set on the head; the state stays WAITING_FOR_HUMAN.
+	rows, err := qtx.EscalateInstance(ctx, db.EscalateInstanceParams{
+		ID:                 cmd.InstanceID,
+		EscalationTier:     int32(cmd.Tier),
+		LastArtifactHash:   cmd.NewArtifactHash,
+		LastArtifactHash_2: cmd.PrevArtifactHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to escalate instance: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("%w: %s", engine.ErrStaleInstance, cmd.InstanceID)
+	}
+
+	// 2. Create Audit Event (APPROVAL_ESCALATED)
+	eventPayload := map[string]interface{}{
+		"tier":           cmd.Tier,
+		"approver_roles": cmd.ApproverRoles,
+		"deadline":       cmd.Deadline,
+		"artifact_id":    cmd.NewArtifactHash,
+	}
+	payloadBytes, _ := json.Marshal(eventPayload)
+
+	_, err = qtx.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		ID:         fmt.Sprintf("evt-%d", time.Now().UnixNano()),
+		InstanceID: cmd.InstanceID,
+		EventType:  "APPROVAL_ESCALATED",
+		Payload:    payloadBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetInstance(ctx, cmd.InstanceID)
+}
+
+// RecordRejectedDecision writes a DECISION_REJECTED audit event. The instance is not changed.
+func (s *Store) RecordRejectedDecision(ctx context.Context, cmd engine.RejectedDecisionCmd) error {
+	eventPayload := map[string]interface{}{
+		"actor_id":      cmd.ActorID,
+		"actor_org_id":  cmd.ActorOrgID,
+		"decision_type": cmd.DecisionType,
+		"reason":        cmd.Reason,
+	}
+	payloadBytes, _ := json.Marshal(eventPayload)
+
+	_, err := s.Queries.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		ID:         fmt.Sprintf("evt-%d", time.Now().UnixNano()),
+		InstanceID: cmd.InstanceID,
+		EventType:  "DECISION_REJECTED",
+		Payload:    payloadBytes,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) QuarantineInstance(ctx context.Context, cmd engine.QuarantineCmd) (*engine.Instance, error) {
+	eventPayload := map[string]interface{}{
+		"from_state":          cmd.From,
+		"claimed_artifact_id": cmd.ArtifactHash,
+		"reason":              cmd.Reason,
+	}
+	return s.setQuarantineState(ctx, cmd.InstanceID, cmd.From, engine.StateQuarantined, &cmd.ArtifactHash, "INSTANCE_QUARANTINED", eventPayload)
+}
+
+func (s *Store) ResolveQuarantine(ctx context.Context, cmd engine.ResolveQuarantineCmd) (*engine.Instance, error) {
+	eventPayload := map[string]interface{}{
+		"action":        cmd.Action,
+		"actor_id":      cmd.ActorID,
+		"role":          cmd.Role,
+		"justification": cmd.Justification,
+	}
+	// Abandoning keeps the instance parked; only the decision is recorded.
+	to := engine.StateQuarantined
+	if cmd.Action == engine.QuarantineRelease {
+		to = cmd.RestoreState
+		eventPayload["to_state"] = to
+	}
+	return s.setQuarantineState(ctx, cmd.InstanceID, engine.StateQuarantined, to, nil, "QUARANTINE_RESOLVED", eventPayload)
+}
+
+// setQuarantineState moves an instance between from and to without touching its chain head,
+// and records the audit event in the same transaction. A nil expectedHash accepts any head:
+// the operator may have repaired it while the instance was quarantined.
+func (s *Store) setQuarantineState(ctx context.Context, instanceID string, from, to engine.State, expectedHash *string, eventType string, eventPayload map[string]interface{}) (*engine.Instance, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	qtx := s.WithTx(tx)
+
+	current, err := qtx.GetInstance(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch current instance state: %w", err)
+	}
+	if expectedHash != nil && current.LastArtifactHash != *expectedHash {
+		return nil, fmt.Errorf("%w: %s", engine.ErrStaleInstance, instanceID)
+	}
+
+	// Compare-and-set on (state, head), as in TransitionInstance.
+	rows, err := qtx.TransitionInstanceState(ctx, db.TransitionInstanceStateParams{
+		ID:                 instanceID,
+		State:              string(to),
+		LastArtifactHash:   current.LastArtifactHash,
+		State_2:            string(from),
+		LastArtifactHash_2: current.LastArtifactHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update instance state: %w", err)
+	}
+	if rows == 0 {
+		return nil, fmt.Errorf("%w: %s", engine.ErrStaleInstance, instanceID)
+	}
+
+	payloadBytes, _ := json.Marshal(eventPayload)
+	_, err = qtx.CreateAuditEvent(ctx, db.CreateAuditEventParams{
+		ID:         fmt.Sprintf("evt-%d", time.Now().UnixNano()),
+		InstanceID: instanceID,
+		EventType:  eventType,
+		Payload:    payloadBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return s.GetInstance(ctx, instanceID)
+}
+
 func (s *Store) GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error) {
 	// Implements ports.InstanceStore.GetAuditEvents using generated SQLC code.
 	rows, err := s.Queries.GetAuditEvents(ctx, instanceID)
@@ -206,6 +473,29 @@ func (s *Store) GetAuditEvents(ctx context.Context, instanceID string) ([]engine
 	return events, nil
 }
 
+// ListDecisions returns the recorded decisions of an instance, oldest first.
+func (s *Store) ListDecisions(ctx context.Context, instanceID string) ([]engine.DecisionRecord, error) {
+	rows, err := s.Queries.GetDecisionsByInstance(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("query decisions: %w", err)
+	}
+
+	decisions := make([]engine.DecisionRecord, len(rows))
+	for i, r := range rows {
+		decisions[i] = engine.DecisionRecord{
+			ID:              r.ID,
+			InstanceID:      r.InstanceID,
+			Type:            engine.DecisionType(r.Type),
+			ActorID:         r.ActorID,
+			Role:            r.Role,
+			PolicyVersionID: r.PolicyVersionID,
+			CreatedAt:       r.CreatedAt.Time,
+		}
+	}
+	sort.SliceStable(decisions, func(i, j int) bool { return decisions[i].CreatedAt.Before(decisions[j].CreatedAt) })
+	return decisions, nil
+}
+
 func mapDBInstance(row db.Instance) *engine.Instance {
 	var trigger map[string]interface{}
 	var policy map[string]interface{}
@@ -220,6 +510,9 @@ func mapDBInstance(row db.Instance) *engine.Instance {
 		PolicyContext:    policy,
 		PolicyVersionID:  row.PolicyVersionID,
 		LastArtifactHash: row.LastArtifactHash,
+		CreatedBy:        row.CreatedBy,
+		CreatorOrgID:     row.CreatorOrgID,
+		EscalationTier:   int(row.EscalationTier),
 		CreatedAt:        row.CreatedAt.Time,
 		UpdatedAt:        row.UpdatedAt.Time,
 	}
@@ -1,7 +1,15 @@
 package main_test
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -9,7 +17,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Rainminds/gantral/internal/anchor"
+	"github.com/Rainminds/gantral/pkg/merkle"
 	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+	"github.com/Rainminds/gantral/pkg/rfc3161/rfc3161test"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/translog"
 )
 
 // Hostile Auditor Tests
@@ -114,7 +128,7 @@ func Test_Chain_Verification(t *testing.T) {
 	// Models sets timestamp to Now(). We sleep slightly to ensure order.
 
 	// A
-	artA := models.NewCommitmentArtifact("inst", models.GenesisHash, "RUNNING", "v1", "ctxA", "sys")
+	artA := models.NewCommitmentArtifact("inst", models.GenesisHash, "WAITING_FOR_HUMAN", "v1", "ctxA", "sys")
 	_ = artA.CalculateHashAndSetID()
 	_ = os.WriteFile(filepath.Join(tmpDir, "1_A.json"), mustMarshal(artA), 0644)
 	time.Sleep(10 * time.Millisecond)
@@ -126,7 +140,7 @@ func Test_Chain_Verification(t *testing.T) {
 	time.Sleep(10 * time.Millisecond)
 
 	// C
-	artC := models.NewCommitmentArtifact("inst", artB.ArtifactID, "COMPLETED", "v1", "ctxC", "sys")
+	artC := models.NewCommitmentArtifact("inst", artB.ArtifactID, "RESUMED", "v1", "ctxC", "sys")
 	_ = artC.CalculateHashAndSetID()
 	_ = os.WriteFile(filepath.Join(tmpDir, "3_C.json"), mustMarshal(artC), 0644)
 
@@ -143,6 +157,219 @@ func Test_Chain_Verification(t *testing.T) {
 	}
 }
 
+func Test_Chain_Outcome_ExitCodes(t *testing.T) {
+	binPath := buildVerifier(t)
+	defer os.Remove(binPath)
+
+	write := func(dir string, arts ...*models.CommitmentArtifact) {
+		for _, art := range arts {
+			_ = art.CalculateHashAndSetID()
+			_ = os.WriteFile(filepath.Join(dir, art.ArtifactID+".json"), mustMarshal(art), 0644)
+		}
+	}
+	run := func(dir string) (string, int) {
+		output, err := exec.Command(binPath, "chain", dir).CombinedOutput()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(output), exitErr.ExitCode()
+		}
+		return string(output), 0
+	}
+
+	// INVALID (1): APPROVED directly after COMPLETED
+	illegalDir, _ := os.MkdirTemp("", "audit-illegal-*")
+	defer os.RemoveAll(illegalDir)
+	running := models.NewCommitmentArtifact("inst", models.GenesisHash, "RUNNING", "v1", "ctxA", "sys")
+	_ = running.CalculateHashAndSetID()
+	completed := models.NewCommitmentArtifact("inst", running.ArtifactID, "COMPLETED", "v1", "ctxB", "sys")
+	_ = completed.CalculateHashAndSetID()
+	approved := models.NewCommitmentArtifact("inst", completed.ArtifactID, "APPROVED", "v1", "ctxC", "sys")
+	write(illegalDir, running, completed, approved)
+
+	out, code := run(illegalDir)
+	if code != 1 || !strings.Contains(out, "ILLEGAL_TRANSITION") {
+		t.Errorf("Expected exit 1 with ILLEGAL_TRANSITION, got %d:\n%s", code, out)
+	}
+
+	// INCONCLUSIVE (3): the middle artifact is missing
+	partialDir, _ := os.MkdirTemp("", "audit-partial-*")
+	defer os.RemoveAll(partialDir)
+	write(partialDir, running, approved)
+
+	out, code = run(partialDir)
+	if code != 3 || !strings.Contains(out, "INCONCLUSIVE") {
+		t.Errorf("Expected exit 3 with INCONCLUSIVE, got %d:\n%s", code, out)
+	}
+}
+
+func Test_Bundle_Export_And_Verify(t *testing.T) {
+	binPath := buildVerifier(t)
+	defer os.Remove(binPath)
+
+	root := t.TempDir()
+	artDir, policyDir := filepath.Join(root, "artifacts"), filepath.Join(root, "policies")
+	_ = os.Mkdir(artDir, 0755)
+	_ = os.Mkdir(policyDir, 0755)
+
+	body := []byte(`{"name":"refunds"}`)
+	sum := sha256.Sum256(body)
+	pv := hex.EncodeToString(sum[:])
+	_ = os.WriteFile(filepath.Join(policyDir, pv+".json"), body, 0644)
+
+	artA := models.NewCommitmentArtifact("inst", models.GenesisHash, "WAITING_FOR_HUMAN", pv, "ctxA", "sys")
+	_ = artA.CalculateHashAndSetID()
+	artB := models.NewCommitmentArtifact("inst", artA.ArtifactID, "APPROVED", pv, "ctxB", "sys")
+	_ = artB.CalculateHashAndSetID()
+	other := models.NewCommitmentArtifact("other", models.GenesisHash, "RUNNING", pv, "ctxC", "sys")
+	_ = other.CalculateHashAndSetID()
+	for _, art := range []*models.CommitmentArtifact{artA, artB, other} {
+		_ = os.WriteFile(filepath.Join(artDir, art.ArtifactID+".json"), mustMarshal(art), 0644)
+	}
+
+	bundlePath := filepath.Join(root, "bundle.tar")
+	out, err := exec.Command(binPath, "export", "--instance", "inst", "--dir", artDir, "--policies-dir", policyDir, "-o", bundlePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("Export failed: %v\nOutput: %s", err, out)
+	}
+
+	out, err = exec.Command(binPath, "bundle", bundlePath).CombinedOutput()
+	if err != nil || !strings.Contains(string(out), "BUNDLE VALID") || !strings.Contains(string(out), "Artifacts: 2") {
+		t.Fatalf("Expected a valid bundle with 2 artifacts, got %v:\n%s", err, out)
+	}
+
+	// Flip one decision inside the archive: the manifest no longer matches.
+	data, _ := os.ReadFile(bundlePath)
+	_ = os.WriteFile(bundlePath, []byte(strings.Replace(string(data), `"authority_state":"APPROVED"`, `"authority_state":"REJECTED"`, 1)), 0644)
+	out, err = exec.Command(binPath, "bundle", bundlePath).CombinedOutput()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 1 || !strings.Contains(string(out), "MANIFEST_MISMATCH") {
+		t.Errorf("Expected exit 1 with MANIFEST_MISMATCH, got %v:\n%s", err, out)
+	}
+}
+
+func Test_Log_Proofs(t *testing.T) {
+	binPath := buildVerifier(t)
+	defer os.Remove(binPath)
+
+	root := t.TempDir()
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("log-key", priv)
+	keysPath := filepath.Join(root, "keys.json")
+	_ = os.WriteFile(keysPath, mustMarshal(signing.KeySetFile{Keys: []signing.PublicKeyEntry{
+		{KeyID: "log-key", Algorithm: signing.AlgorithmEd25519, PublicKey: base64.StdEncoding.EncodeToString(pub)},
+	}}), 0644)
+
+	var leaves [][]byte
+	for i := 0; i < 7; i++ {
+		leaves = append(leaves, translog.LeafHash(fmt.Sprintf("art-%d", i)))
+	}
+	checkpoint := func(name string, size int) string {
+		cp := &translog.Checkpoint{
+			Origin:    "gantral.test/log",
+			TreeSize:  uint64(size),
+			RootHash:  hex.EncodeToString(merkle.Root(leaves[:size])),
+			Timestamp: "2026-01-01T00:00:00Z",
+		}
+		_ = cp.Sign(signer)
+		path := filepath.Join(root, name)
+		_ = os.WriteFile(path, mustMarshal(cp), 0644)
+		return path
+	}
+	cp3, cp7 := checkpoint("cp3.json", 3), checkpoint("cp7.json", 7)
+
+	hashes, _ := merkle.InclusionProof(leaves, 2)
+	inclusionPath := filepath.Join(root, "inclusion.json")
+	_ = os.WriteFile(inclusionPath, mustMarshal(translog.InclusionProof{ArtifactID: "art-2", LeafIndex: 2, TreeSize: 7, Hashes: translog.EncodeHashes(hashes)}), 0644)
+	hashes, _ = merkle.ConsistencyProof(leaves, 3)
+	consistencyPath := filepath.Join(root, "consistency.json")
+	_ = os.WriteFile(consistencyPath, mustMarshal(translog.ConsistencyProof{FirstSize: 3, SecondSize: 7, Hashes: translog.EncodeHashes(hashes)}), 0644)
+
+	run := func(args ...string) (string, int) {
+		output, err := exec.Command(binPath, args...).CombinedOutput()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(output), exitErr.ExitCode()
+		}
+		return string(output), 0
+	}
+
+	if out, code := run("checkpoint", cp7, "--keys", keysPath); code != 0 {
+		t.Errorf("Expected a valid checkpoint, got %d:\n%s", code, out)
+	}
+	if out, code := run("inclusion", "--checkpoint", cp7, "--proof", inclusionPath, "--keys", keysPath); code != 0 || !strings.Contains(out, "INCLUDED") {
+		t.Errorf("Expected inclusion to verify, got %d:\n%s", code, out)
+	}
+	if out, code := run("consistency", "--old", cp3, "--new", cp7, "--proof", consistencyPath, "--keys", keysPath); code != 0 || !strings.Contains(out, "CONSISTENT") {
+		t.Errorf("Expected consistency to verify, got %d:\n%s", code, out)
+	}
+
+	// A proof against the wrong checkpoint is INVALID (1).
+	if out, code := run("inclusion", "--checkpoint", cp3, "--proof", inclusionPath); code != 1 {
+		t.Errorf("Expected exit 1 for a mismatched checkpoint, got %d:\n%s", code, out)
+	}
+
+	// A rewritten checkpoint no longer carries a valid signature.
+	data, _ := os.ReadFile(cp7)
+	_ = os.WriteFile(cp7, []byte(strings.Replace(string(data), `"tree_size":7`, `"tree_size":6`, 1)), 0644)
+	if out, code := run("checkpoint", cp7, "--keys", keysPath); code != 1 {
+		t.Errorf("Expected exit 1 for a forged checkpoint, got %d:\n%s", code, out)
+	}
+
+	if out, code := run("checkpoint", filepath.Join(root, "missing.json")); code != 2 {
+		t.Errorf("Expected exit 2 for unreadable input, got %d:\n%s", code, out)
+	}
+}
+
+func Test_Anchor_Receipts(t *testing.T) {
+	binPath := buildVerifier(t)
+	defer os.Remove(binPath)
+
+	root := t.TempDir()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("log-key", priv)
+	cp := &translog.Checkpoint{
+		Origin:    "gantral.test/log",
+		TreeSize:  1,
+		RootHash:  hex.EncodeToString(translog.LeafHash("art-0")),
+		Timestamp: "2026-01-01T00:00:00Z",
+	}
+	_ = cp.Sign(signer)
+	cpPath := filepath.Join(root, "cp.json")
+	_ = os.WriteFile(cpPath, mustMarshal(cp), 0644)
+
+	tsa, _ := rfc3161test.New()
+	srv := httptest.NewServer(tsa)
+	defer srv.Close()
+	rootsPath := filepath.Join(root, "tsa-roots.pem")
+	_ = os.WriteFile(rootsPath, tsa.RootPEM(), 0644)
+
+	tsaWitness := anchor.NewTSAWitness("tsa", &rfc3161.Client{URL: srv.URL})
+	receipt, err := tsaWitness.Anchor(context.Background(), cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receiptPath := filepath.Join(root, "receipt.json")
+	_ = os.WriteFile(receiptPath, mustMarshal(receipt), 0644)
+
+	run := func(args ...string) (string, int) {
+		output, err := exec.Command(binPath, args...).CombinedOutput()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return string(output), exitErr.ExitCode()
+		}
+		return string(output), 0
+	}
+
+	if out, code := run("anchor", "--checkpoint", cpPath, "--receipt", receiptPath, "--tsa-roots", rootsPath); code != 0 || !strings.Contains(out, "ANCHORED") {
+		t.Errorf("Expected the receipt to verify, got %d:\n%s", code, out)
+	}
+
+	// A re-signed checkpoint (new timestamp) is not what the TSA saw.
+	cp.Timestamp = "2026-06-01T00:00:00Z"
+	_ = cp.Sign(signer)
+	_ = os.WriteFile(cpPath, mustMarshal(cp), 0644)
+	if out, code := run("anchor", "--checkpoint", cpPath, "--receipt", receiptPath); code != 1 {
+		t.Errorf("Expected exit 1 for another checkpoint, got %d:\n%s", code, out)
+	}
+}
+
 func mustMarshal(v interface{}) []byte {
 	b, _ := json.Marshal(v)
 	return b
@@ -0,0 +1,248 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Rainminds/gantral/pkg/bundle"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/spf13/cobra"
+)
+
+// newExportCmd builds `gantral-verify export`, which writes an audit bundle either
+// by downloading it from a Gantral server or by packing local directories.
+func newExportCmd(keysPath *string) *cobra.Command {
+	var (
+		instances   []string
+		output      string
+		serverURL   string
+		token       string
+		artifactDir string
+		evidenceDir string
+		policyDir   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export an audit bundle (tar) for one or more instances",
+		Long: `Writes a self-contained audit bundle: artifacts, execution evidence,
+policy versions, a manifest with the SHA-256 of every member and, with --keys,
+the public signing keys. Verify it with "gantral-verify bundle".
+
+Either download it from a server (--server) or pack local directories (--dir).`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(instances) == 0 || (serverURL == "") == (artifactDir == "") {
+				fmt.Println("❌ ERROR: --instance and exactly one of --server or --dir are required")
+				os.Exit(exitError)
+			}
+
+			var data []byte
+			var err error
+			if serverURL != "" {
+				if token == "" {
+					token = os.Getenv("GANTRAL_TOKEN")
+				}
+				data, err = downloadBundle(serverURL, token, instances)
+			} else {
+				data, err = packBundle(instances, artifactDir, evidenceDir, policyDir, *keysPath)
+			}
+			if err != nil {
+				fmt.Printf("❌ ERROR: Export failed: %v\n", err)
+				os.Exit(exitError)
+			}
+
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				fmt.Printf("❌ ERROR: Failed to write bundle: %v\n", err)
+				os.Exit(exitError)
+			}
+			fmt.Printf("📦 Bundle written | %s | Instances: %d | Bytes: %d\n", output, len(instances), len(data))
+		},
+	}
+	cmd.Flags().StringArrayVar(&instances, "instance", nil, "Instance ID to include (repeatable)")
+	cmd.Flags().StringVarP(&output, "output", "o", "bundle.tar", "Bundle file to write")
+	cmd.Flags().StringVar(&serverURL, "server", "", "Gantral API base URL to download the bundle from")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token for --server (default $GANTRAL_TOKEN)")
+	cmd.Flags().StringVar(&artifactDir, "dir", "", "Local artifact directory (<artifact_id>.json files)")
+	cmd.Flags().StringVar(&evidenceDir, "evidence-dir", "", "Local evidence directory (<evidence_id>.json files), used with --dir")
+	cmd.Flags().StringVar(&policyDir, "policies-dir", "", "Local policy directory (<version_id>.json canonical bodies), used with --dir")
+	return cmd
+}
+
+// newBundleCmd builds `gantral-verify bundle`, which verifies a whole bundle offline.
+func newBundleCmd(verbose *bool, loadKeys func() signing.KeySet) *cobra.Command {
+	return &cobra.Command{
+		Use:   "bundle [file.tar]",
+		Short: "Verify an audit bundle (manifest, artifacts, chains, policies, evidence)",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := loadKeys()
+			f, err := os.Open(args[0])
+			if err != nil {
+				fmt.Printf("❌ ERROR: Failed to open bundle: %v\n", err)
+				os.Exit(exitError)
+			}
+			defer f.Close()
+
+			res := bundle.Verify(f, keys)
+
+			for _, finding := range res.Findings {
+				fmt.Printf("%s %s | Code: %s | %s\n", outcomeIcon(finding.Outcome), finding.Outcome, finding.Code, finding.Message)
+			}
+			for _, inst := range res.Instances {
+				fmt.Printf("%s INSTANCE %s | %s | Length: %d\n", outcomeIcon(inst.Outcome), inst.Outcome, inst.InstanceID, inst.Length)
+				for _, finding := range inst.Findings {
+					fmt.Printf("    - %s | Index: %d | Code: %s | %s\n", finding.Outcome, finding.Index, finding.Code, finding.Message)
+				}
+			}
+
+			switch res.Keys {
+			case bundle.KeysBundle:
+				fmt.Println("[!] Signatures checked against keys.json shipped in the bundle; pass --keys to pin trusted keys")
+			case bundle.KeysNone:
+				fmt.Println("[!] Signatures NOT CHECKED (no keys in the bundle; pass --keys to verify origin)")
+			}
+			if *verbose && res.Manifest != nil {
+				fmt.Println("\n--- BUNDLE VERIFICATION SUMMARY ---")
+				fmt.Printf("[✓] Manifest SHA-256: %s\n", res.ManifestSHA256)
+				fmt.Printf("[✓] Created At:       %s\n", res.Manifest.CreatedAt)
+				fmt.Printf("[✓] Members:          %d\n", len(res.Manifest.Members))
+				fmt.Printf("[✓] Keys:             %s\n", res.Keys)
+			}
+
+			fmt.Printf("%s BUNDLE %s | Instances: %d | Artifacts: %d | Evidence: %d | Policies: %d | Findings: %d\n",
+				outcomeIcon(res.Outcome), res.Outcome, len(res.Instances), res.Artifacts, res.Evidence, res.Policies, len(res.Findings))
+			os.Exit(exitCode(res.Outcome))
+		},
+	}
+}
+
+// downloadBundle fetches GET /bundles from a Gantral server.
+func downloadBundle(serverURL, token string, instances []string) ([]byte, error) {
+	q := url.Values{}
+	for _, id := range instances {
+		q.Add("instance", id)
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(serverURL, "/")+"/bundles?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// packBundle builds a bundle from local directories.
+// Files that do not parse are reported and skipped; the bundle verifier flags what is missing.
+func packBundle(instances []string, artifactDir, evidenceDir, policyDir, keysPath string) ([]byte, error) {
+	wanted := make(map[string]bool, len(instances))
+	for _, id := range instances {
+		wanted[id] = true
+	}
+	contents := bundle.Contents{Instances: instances, Policies: make(map[string][]byte)}
+
+	err := readJSONDir(artifactDir, func(name string, data []byte) {
+		var art models.CommitmentArtifact
+		if err := json.Unmarshal(data, &art); err != nil {
+			fmt.Printf("⚠️  Skipping %s: %v\n", name, err)
+			return
+		}
+		if wanted[art.InstanceID] {
+			contents.Artifacts = append(contents.Artifacts, &art)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if evidenceDir != "" {
+		err := readJSONDir(evidenceDir, func(name string, data []byte) {
+			var ev models.ExecutionEvidence
+			if err := json.Unmarshal(data, &ev); err != nil {
+				fmt.Printf("⚠️  Skipping %s: %v\n", name, err)
+				return
+			}
+			if wanted[ev.InstanceID] {
+				contents.Evidence = append(contents.Evidence, &ev)
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if policyDir != "" {
+		for _, art := range contents.Artifacts {
+			pv := art.PolicyVersionID
+			if _, done := contents.Policies[pv]; done || pv == "" || filepath.Base(pv) != pv {
+				continue
+			}
+			body, err := os.ReadFile(filepath.Join(policyDir, pv+".json"))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			contents.Policies[pv] = body
+		}
+	}
+
+	if keysPath != "" {
+		keys, err := signing.LoadKeySetFile(keysPath)
+		if err != nil {
+			return nil, err
+		}
+		contents.Keys = keys
+	}
+
+	var buf bytes.Buffer
+	if _, err := bundle.Write(&buf, contents, time.Now()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readJSONDir calls fn with every *.json file directly inside dir.
+func readJSONDir(dir string, fn func(name string, data []byte)) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		fn(e.Name(), data)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/translog"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/spf13/cobra"
+)
+
+// newLogCmds builds the offline checks of the global artifact log:
+// `checkpoint`, `inclusion`, `consistency` and `anchor`.
+func newLogCmds(loadKeys func() signing.KeySet) []*cobra.Command {
+	checkpointCmd := &cobra.Command{
+		Use:   "checkpoint [checkpoint.json]",
+		Short: "Verify a signed log checkpoint",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := loadKeys()
+			var cp translog.Checkpoint
+			mustReadJSON(args[0], &cp)
+
+			if err := translog.VerifyCheckpoint(&cp, keys); err != nil {
+				fail(err)
+			}
+			warnUnchecked(keys)
+			fmt.Printf("✅ CHECKPOINT VALID | Origin: %s | Size: %d | Root: %s\n", cp.Origin, cp.TreeSize, cp.RootHash)
+			os.Exit(exitValid)
+		},
+	}
+
+	var checkpointPath, proofPath, artifactPath string
+	inclusionCmd := &cobra.Command{
+		Use:   "inclusion",
+		Short: "Verify that an artifact is included in a log checkpoint",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := loadKeys()
+			var cp translog.Checkpoint
+			var proof translog.InclusionProof
+			mustReadJSON(checkpointPath, &cp)
+			mustReadJSON(proofPath, &proof)
+
+			// With --artifact the proof must be about that exact, intact artifact.
+			if artifactPath != "" {
+				data, err := os.ReadFile(artifactPath)
+				if err != nil {
+					fmt.Printf("❌ ERROR: Failed to read artifact: %v\n", err)
+					os.Exit(exitError)
+				}
+				res, _ := verifier.VerifyArtifactWithKeys(data, keys)
+				if !res.Valid {
+					fmt.Printf("%s %s | Artifact: %s | Code: %s | Error: %s\n", outcomeIcon(res.Outcome), res.Outcome, res.ArtifactID, res.Code, res.Error)
+					os.Exit(exitCode(res.Outcome))
+				}
+				if res.ArtifactID != proof.ArtifactID {
+					fail(fmt.Errorf("proof is for artifact %s, not %s", proof.ArtifactID, res.ArtifactID))
+				}
+			}
+
+			if err := translog.VerifyCheckpoint(&cp, keys); err != nil {
+				fail(err)
+			}
+			if err := translog.VerifyInclusion(&proof, &cp); err != nil {
+				fail(err)
+			}
+			warnUnchecked(keys)
+			fmt.Printf("✅ INCLUDED | Artifact: %s | Leaf: %d | Size: %d | Root: %s\n", proof.ArtifactID, proof.LeafIndex, cp.TreeSize, cp.RootHash)
+			os.Exit(exitValid)
+		},
+	}
+	inclusionCmd.Flags().StringVar(&checkpointPath, "checkpoint", "", "Checkpoint file (GET /log/checkpoints/{size})")
+	inclusionCmd.Flags().StringVar(&proofPath, "proof", "", "Inclusion proof file (GET /log/inclusion)")
+	inclusionCmd.Flags().StringVar(&artifactPath, "artifact", "", "Optional artifact file the proof must be about")
+	_ = inclusionCmd.MarkFlagRequired("checkpoint")
+	_ = inclusionCmd.MarkFlagRequired("proof")
+
+	var oldPath, newPath, consistencyPath string
+	consistencyCmd := &cobra.Command{
+		Use:   "consistency",
+		Short: "Verify that a newer log checkpoint extends an older one (append-only)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := loadKeys()
+			var oldCP, newCP translog.Checkpoint
+			var proof translog.ConsistencyProof
+			mustReadJSON(oldPath, &oldCP)
+			mustReadJSON(newPath, &newCP)
+			mustReadJSON(consistencyPath, &proof)
+
+			for _, cp := range []*translog.Checkpoint{&oldCP, &newCP} {
+				if err := translog.VerifyCheckpoint(cp, keys); err != nil {
+					fail(err)
+				}
+			}
+			if err := translog.VerifyConsistency(&proof, &oldCP, &newCP); err != nil {
+				fail(err)
+			}
+			warnUnchecked(keys)
+			fmt.Printf("✅ CONSISTENT | Origin: %s | Size: %d -> %d\n", newCP.Origin, oldCP.TreeSize, newCP.TreeSize)
+			os.Exit(exitValid)
+		},
+	}
+	consistencyCmd.Flags().StringVar(&oldPath, "old", "", "Older checkpoint file")
+	consistencyCmd.Flags().StringVar(&newPath, "new", "", "Newer checkpoint file")
+	consistencyCmd.Flags().StringVar(&consistencyPath, "proof", "", "Consistency proof file (GET /log/consistency)")
+	_ = consistencyCmd.MarkFlagRequired("old")
+	_ = consistencyCmd.MarkFlagRequired("new")
+	_ = consistencyCmd.MarkFlagRequired("proof")
+
+	var anchoredPath, receiptPath, tsaRootsPath, witnessCopyPath string
+	anchorCmd := &cobra.Command{
+		Use:   "anchor",
+		Short: "Verify an anchor receipt of a log checkpoint",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := loadKeys()
+			var cp translog.Checkpoint
+			var receipt translog.Receipt
+			mustReadJSON(anchoredPath, &cp)
+			mustReadJSON(receiptPath, &receipt)
+
+			var roots *x509.CertPool
+			if tsaRootsPath != "" {
+				pem, err := os.ReadFile(tsaRootsPath)
+				if err != nil {
+					fmt.Printf("❌ ERROR: Failed to read TSA roots: %v\n", err)
+					os.Exit(exitError)
+				}
+				roots = x509.NewCertPool()
+				if !roots.AppendCertsFromPEM(pem) {
+					fmt.Println("❌ ERROR: TSA roots file contains no certificates")
+					os.Exit(exitError)
+				}
+			}
+
+			if err := translog.VerifyCheckpoint(&cp, keys); err != nil {
+				fail(err)
+			}
+			if err := translog.VerifyReceipt(&receipt, &cp, roots); err != nil {
+				fail(err)
+			}
+			// The copy fetched from a file or bucket witness must be the anchored checkpoint.
+			if witnessCopyPath != "" {
+				data, err := os.ReadFile(witnessCopyPath)
+				if err != nil {
+					fmt.Printf("❌ ERROR: Failed to read witness copy: %v\n", err)
+					os.Exit(exitError)
+				}
+				canonical, _ := translog.CanonicalCheckpoint(&cp)
+				if !bytes.Equal(data, canonical) {
+					fail(fmt.Errorf("witness copy differs from the checkpoint"))
+				}
+			}
+
+			warnUnchecked(keys)
+			switch {
+			case receipt.Kind == translog.ReceiptKindRFC3161 && roots == nil:
+				fmt.Println("[!] TSA certificate NOT CHECKED against trusted roots (pass --tsa-roots)")
+			case receipt.Kind != translog.ReceiptKindRFC3161 && witnessCopyPath == "":
+				fmt.Printf("[!] Witness copy NOT CHECKED (fetch %s from the witness and pass --witness-copy)\n", receipt.Location)
+			}
+			fmt.Printf("✅ ANCHORED | Witness: %s (%s) | Size: %d | At: %s\n", receipt.Witness, receipt.Kind, cp.TreeSize, receipt.AnchoredAt)
+			os.Exit(exitValid)
+		},
+	}
+	anchorCmd.Flags().StringVar(&anchoredPath, "checkpoint", "", "Checkpoint file (GET /log/checkpoints/{size})")
+	anchorCmd.Flags().StringVar(&receiptPath, "receipt", "", "Receipt file (one entry of GET /log/checkpoints/{size}/receipts)")
+	anchorCmd.Flags().StringVar(&tsaRootsPath, "tsa-roots", "", "PEM file of trusted TSA root certificates (rfc3161 receipts)")
+	anchorCmd.Flags().StringVar(&witnessCopyPath, "witness-copy", "", "Checkpoint copy fetched from a file or bucket witness")
+	_ = anchorCmd.MarkFlagRequired("checkpoint")
+	_ = anchorCmd.MarkFlagRequired("receipt")
+
+	return []*cobra.Command{checkpointCmd, inclusionCmd, consistencyCmd, anchorCmd}
+}
+
+// mustReadJSON decodes a file or exits with exitError: nothing can be verified without it.
+func mustReadJSON(path string, v interface{}) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Printf("❌ ERROR: Failed to read %s: %v\n", path, err)
+		os.Exit(exitError)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		fmt.Printf("❌ ERROR: Invalid JSON in %s: %v\n", path, err)
+		os.Exit(exitError)
+	}
+}
+
+// fail reports a proof or signature failure as INVALID.
+func fail(err error) {
+	fmt.Printf("❌ INVALID | Error: %v\n", err)
+	os.Exit(exitInvalid)
+}
+
+func warnUnchecked(keys signing.KeySet) {
+	if keys == nil {
+		fmt.Println("[!] Checkpoint signature NOT CHECKED (pass --keys to verify origin)")
+	}
+}
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 
 	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
 	"github.com/Rainminds/gantral/pkg/verifier"
 	"github.com/spf13/cobra"
 )
@@ -15,6 +15,7 @@ import (
 func main() {
 	// Global flags
 	var verbose bool
+	var keysPath string
 
 	var rootCmd = &cobra.Command{
 		Use:   "gantral-verify",
@@ -24,6 +25,20 @@ the integrity and chain-of-custody of Gantral artifacts
 without requiring access to the operational database.`,
 	}
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose human-readable output")
+	rootCmd.PersistentFlags().StringVar(&keysPath, "keys", "", "Trusted public key set (keys.json); when set, every artifact must carry a valid signature")
+
+	// loadKeys returns the trusted key set, or nil when signatures are not being checked.
+	loadKeys := func() signing.KeySet {
+		if keysPath == "" {
+			return nil
+		}
+		keys, err := signing.LoadKeySet(keysPath)
+		if err != nil {
+			fmt.Printf("❌ ERROR: Failed to load key set: %v\n", err)
+			os.Exit(exitError)
+		}
+		return keys
+	}
 
 	// Subcommand: verify file
 	var fileCmd = &cobra.Command{
@@ -32,56 +47,58 @@ without requiring access to the operational database.`,
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			path := args[0]
+			keys := loadKeys()
 			data, err := os.ReadFile(path)
 			if err != nil {
 				fmt.Printf("❌ ERROR: Failed to read file: %v\n", err)
-				os.Exit(2)
+				os.Exit(exitError)
 			}
 
 			// Parse first to get details for verbose output
 			var art models.CommitmentArtifact
 			_ = json.Unmarshal(data, &art)
 
-			result, err := verifier.VerifyArtifact(data)
+			result, err := verifier.VerifyArtifactWithKeys(data, keys)
 			if err != nil {
 				fmt.Printf("❌ ERROR: Logic failure: %v\n", err)
-				os.Exit(2)
+				os.Exit(exitError)
 			}
 
 			// Verbose Output
 			if verbose {
-				printArtifactSummary(art, result.Valid)
+				printArtifactSummary(art, result.Valid, keys != nil)
 			}
 
 			// Final Result
-			if result.Valid {
-				if !verbose {
+			if !verbose {
+				if result.Valid {
 					fmt.Printf("✅ VALID | ID: %s | Hash: %s\n", result.ArtifactID, result.CalculatedHash)
+				} else {
+					fmt.Printf("%s %s | ID: %s | Code: %s | Error: %s\n", outcomeIcon(result.Outcome), result.Outcome, result.ArtifactID, result.Code, result.Error)
 				}
-				os.Exit(0)
-			} else {
-				if !verbose {
-					fmt.Printf("❌ INVALID | ID: %s | Error: %s\n", result.ArtifactID, result.Error)
-				}
-				os.Exit(1)
 			}
+			os.Exit(exitCode(result.Outcome))
 		},
 	}
 
 	// Subcommand: verify chain
 	var chainCmd = &cobra.Command{
 		Use:   "chain [directory]",
-		Short: "Verify a chain of artifacts in a directory",
+		Short: "Verify the artifact chains (one per instance) in a directory",
 		Args:  cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			dir := args[0]
+			keys := loadKeys()
 			files, err := os.ReadDir(dir)
 			if err != nil {
 				fmt.Printf("❌ ERROR: Failed to read directory: %v\n", err)
-				os.Exit(2)
+				os.Exit(exitError)
 			}
 
+			// Every file is checked; a bad file is reported and excluded from the chain,
+			// but never stops verification of the rest.
 			var artifacts []models.CommitmentArtifact
+			var fileOutcomes []verifier.Outcome
 			for _, f := range files {
 				if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
 					continue
@@ -90,69 +107,117 @@ without requiring access to the operational database.`,
 				path := filepath.Join(dir, f.Name())
 				data, err := os.ReadFile(path)
 				if err != nil {
-					fmt.Printf("⚠️  WARNING: Skipping unreadable file %s: %v\n", f.Name(), err)
+					fmt.Printf("⚠️  INCONCLUSIVE | %s | Code: UNREADABLE | Error: %v\n", f.Name(), err)
+					fileOutcomes = append(fileOutcomes, verifier.OutcomeInconclusive)
 					continue
 				}
 
 				// Verify individual integrity first
-				res, _ := verifier.VerifyArtifact(data)
-				if res != nil && res.Valid {
-					var art models.CommitmentArtifact
-					_ = json.Unmarshal(data, &art)
-					artifacts = append(artifacts, art)
-
-					if verbose {
-						fmt.Printf("  [+] Loaded Valid Artifact: %s\n", art.ArtifactID[:8])
-					}
-				} else {
-					fmt.Printf("❌ INVALID INDIVIDUAL ARTIFACT: %s\n", f.Name())
-					os.Exit(1)
+				res, _ := verifier.VerifyArtifactWithKeys(data, keys)
+				fileOutcomes = append(fileOutcomes, res.Outcome)
+				if !res.Valid {
+					fmt.Printf("%s %s | %s | Code: %s | Error: %s\n", outcomeIcon(res.Outcome), res.Outcome, f.Name(), res.Code, res.Error)
+					continue
+				}
+
+				var art models.CommitmentArtifact
+				_ = json.Unmarshal(data, &art)
+				artifacts = append(artifacts, art)
+				if verbose {
+					fmt.Printf("  [+] Loaded Valid Artifact: %s\n", art.ArtifactID[:8])
 				}
 			}
 
 			if len(artifacts) == 0 {
-				fmt.Println("⚠️  No valid artifacts found.")
-				os.Exit(0)
+				fmt.Println("⚠️  INCONCLUSIVE | No valid artifacts found.")
+				os.Exit(exitCode(verifier.WorstOutcome(append(fileOutcomes, verifier.OutcomeInconclusive)...)))
 			}
 
-			// Sort by Timestamp
-			sort.Slice(artifacts, func(i, j int) bool {
-				return artifacts[i].Timestamp < artifacts[j].Timestamp
-			})
-
-			// Verify Chain
-			chainRes := verifier.VerifyChain(artifacts)
+			// Rebuild each instance's chain from hash linkage (timestamps only have
+			// one-second resolution and are not trusted for ordering).
+			instances := verifier.VerifyInstances(artifacts)
+			outcomes := fileOutcomes
+			findings := 0
+			for _, inst := range instances {
+				outcomes = append(outcomes, inst.Outcome)
+				findings += len(inst.Findings)
+
+				fmt.Printf("%s INSTANCE %s | %s | Length: %d\n", outcomeIcon(inst.Outcome), inst.Outcome, inst.InstanceID, inst.Length)
+				for _, finding := range inst.Findings {
+					fmt.Printf("    - %s | Index: %d | Code: %s | %s\n", finding.Outcome, finding.Index, finding.Code, finding.Message)
+				}
+				if verbose && inst.Length > 0 {
+					fmt.Printf("    [✓] Start Time:   %s\n", inst.Chain[0].Timestamp)
+					fmt.Printf("    [✓] End Time:     %s (%s)\n", inst.Chain[inst.Length-1].Timestamp, inst.Chain[inst.Length-1].AuthorityState)
+				}
+			}
+			outcome := verifier.WorstOutcome(outcomes...)
 
 			if verbose {
 				fmt.Println("\n--- CHAIN VERIFICATION SUMMARY ---")
 				fmt.Printf("[✓] Total Blocks: %d\n", len(artifacts))
-				fmt.Printf("[✓] Start Time:   %s\n", artifacts[0].Timestamp)
-				fmt.Printf("[✓] End Time:     %s\n", artifacts[len(artifacts)-1].Timestamp)
-			}
-
-			if chainRes.Valid {
-				if verbose {
+				fmt.Printf("[✓] Instances:    %d\n", len(instances))
+				if outcome == verifier.OutcomeValid {
 					fmt.Println("RESULT: ADMISSIBLE")
+				} else {
+					fmt.Println("RESULT: INADMISSIBLE")
 				}
-				fmt.Printf("✅ CHAIN VALID | Count: %d\n", len(artifacts))
-				os.Exit(0)
+			}
+
+			if outcome == verifier.OutcomeValid {
+				fmt.Printf("✅ CHAIN VALID | Instances: %d | Count: %d\n", len(instances), len(artifacts))
 			} else {
-				fmt.Printf("❌ CHAIN BROKEN | Index: %d | Reason: %s\n", chainRes.BrokenIndex, chainRes.BrokenReason)
-				os.Exit(1)
+				rejected := len(fileOutcomes) - len(artifacts)
+				fmt.Printf("%s CHAIN %s | Instances: %d | Count: %d | Rejected Files: %d | Chain Findings: %d\n", outcomeIcon(outcome), outcome, len(instances), len(artifacts), rejected, findings)
 			}
+			os.Exit(exitCode(outcome))
 		},
 	}
 
 	rootCmd.AddCommand(fileCmd)
 	rootCmd.AddCommand(chainCmd)
+	rootCmd.AddCommand(newExportCmd(&keysPath))
+	rootCmd.AddCommand(newBundleCmd(&verbose, loadKeys))
+	rootCmd.AddCommand(newLogCmds(loadKeys)...)
+	rootCmd.AddCommand(newReplayCmd(&verbose, loadKeys))
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(2)
+		os.Exit(exitError)
+	}
+}
+
+// Exit codes. Scripts must treat anything but 0 as "not proven".
+const (
+	exitValid        = 0
+	exitInvalid      = 1
+	exitError        = 2 // Usage or I/O error: nothing was verified
+	exitInconclusive = 3
+)
+
+func exitCode(outcome verifier.Outcome) int {
+	switch outcome {
+	case verifier.OutcomeValid:
+		return exitValid
+	case verifier.OutcomeInconclusive:
+		return exitInconclusive
+	default:
+		return exitInvalid
 	}
 }
 
-func printArtifactSummary(art models.CommitmentArtifact, valid bool) {
+func outcomeIcon(outcome verifier.Outcome) string {
+	switch outcome {
+	case verifier.OutcomeValid:
+		return "✅"
+	case verifier.OutcomeInconclusive:
+		return "⚠️ "
+	default:
+		return "❌"
+	}
+}
+
+func printArtifactSummary(art models.CommitmentArtifact, valid bool, signaturesChecked bool) {
 	fmt.Println("\n--- ARTIFACT VERIFICATION SUMMARY ---")
 
 	icon := "[✓]"
@@ -170,6 +235,11 @@ func printArtifactSummary(art models.CommitmentArtifact, valid bool) {
 		hashDisp = hashDisp[:10] + "..."
 	}
 	fmt.Printf("%s Evidence:     Verified (Hash: %s)\n", icon, hashDisp)
+	if signaturesChecked {
+		fmt.Printf("%s Signing Key:  %s\n", icon, art.KeyID)
+	} else {
+		fmt.Println("[!] Signature:    NOT CHECKED (pass --keys to verify origin)")
+	}
 
 	if valid {
 		fmt.Println("RESULT: ADMISSIBLE")
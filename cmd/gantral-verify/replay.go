@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Rainminds/gantral/core/workflows"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/policy"
+	"github.com/Rainminds/gantral/internal/policy/opa"
+	"github.com/Rainminds/gantral/internal/replay"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	gw "github.com/Rainminds/gantral/internal/workflow"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/spf13/cobra"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/interceptor"
+	tlog "go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/worker"
+)
+
+// Replay outcome codes.
+const (
+	codeNondeterministic = "NONDETERMINISTIC"
+	codeArtifactInvalid  = "ARTIFACT_INVALID"
+	codeReplayFailed     = "REPLAY_FAILED"
+)
+
+// newReplayCmd builds `gantral-verify replay`, which re-executes a recorded Temporal
+// workflow history with the worker's replay guard against a directory of artifacts.
+// It needs no Temporal server and no database.
+func newReplayCmd(verbose *bool, loadKeys func() signing.KeySet) *cobra.Command {
+	var historyPath, artifactsDir, policyPath string
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay a workflow history against the artifact store",
+		Long: `Replays a workflow history (temporal workflow show --output json) with the
+same replay guard the worker runs. The history is VALID when it replays
+deterministically and every artifact it carries exists, unaltered, in --artifacts.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			keys := loadKeys()
+			if !*verbose {
+				// The guard logs every violation; the verdict below already reports it.
+				slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			}
+
+			f, err := os.Open(historyPath)
+			if err != nil {
+				fmt.Printf("❌ ERROR: Failed to read history: %v\n", err)
+				os.Exit(exitError)
+			}
+			history, err := client.HistoryFromJSON(f, client.HistoryJSONOptions{})
+			f.Close()
+			if err != nil {
+				fmt.Printf("❌ ERROR: Invalid history %s: %v\n", historyPath, err)
+				os.Exit(exitError)
+			}
+
+			// Never create the directory: a mistyped path must not replay against an empty store.
+			if info, err := os.Stat(artifactsDir); err != nil || !info.IsDir() {
+				fmt.Printf("❌ ERROR: Artifact directory %s not found\n", artifactsDir)
+				os.Exit(exitError)
+			}
+			dir, err := local.NewStore(artifactsDir)
+			if err != nil {
+				fmt.Printf("❌ ERROR: Failed to open artifact directory: %v\n", err)
+				os.Exit(exitError)
+			}
+			store := &verifiedStore{Store: dir, keys: keys, checked: map[string]bool{}}
+
+			// Backend evaluations are recorded in history. Older histories evaluated the policy
+			// inline and replay it again; it must then be the backend the worker ran.
+			if policyPath != "" {
+				evaluator, err := opa.NewEvaluator(context.Background(), policyPath)
+				if err != nil {
+					fmt.Printf("❌ ERROR: Failed to load policies: %v\n", err)
+					os.Exit(exitError)
+				}
+				workflows.SetPolicyBackend(policy.NewFailClosedEngine(evaluator))
+			}
+
+			replayer, err := worker.NewWorkflowReplayerWithOptions(worker.WorkflowReplayerOptions{
+				DataConverter: converter.GetDefaultDataConverter(),
+				Interceptors: []interceptor.WorkerInterceptor{
+					gw.NewReplayInterceptor(replay.NewReplayGuard(store)),
+				},
+			})
+			if err != nil {
+				fmt.Printf("❌ ERROR: %v\n", err)
+				os.Exit(exitError)
+			}
+			replayer.RegisterWorkflow(workflows.GantralExecutionWorkflow)
+
+			events := len(history.GetEvents())
+			replayErr := replayer.ReplayWorkflowHistory(tlog.NewStructuredLogger(slog.Default()), history)
+			outcome, code := classifyReplay(replayErr, store)
+
+			if *verbose {
+				fmt.Println("\n--- REPLAY VERIFICATION SUMMARY ---")
+				fmt.Printf("[✓] History Events:     %d\n", events)
+				fmt.Printf("[✓] Artifacts Checked:  %d\n", len(store.checked))
+				for _, finding := range store.findings {
+					fmt.Printf("[❌] %s\n", finding)
+				}
+				if keys == nil {
+					fmt.Println("[!] Signatures:         NOT CHECKED (pass --keys to verify origin)")
+				}
+				if outcome == verifier.OutcomeValid {
+					fmt.Println("RESULT: ADMISSIBLE")
+				} else {
+					fmt.Println("RESULT: INADMISSIBLE")
+				}
+			}
+
+			if outcome == verifier.OutcomeValid {
+				fmt.Printf("✅ REPLAY VALID | Events: %d | Artifacts: %d\n", events, len(store.checked))
+			} else {
+				fmt.Printf("%s REPLAY %s | Events: %d | Code: %s | Error: %v\n", outcomeIcon(outcome), outcome, events, code, replayErr)
+			}
+			os.Exit(exitCode(outcome))
+		},
+	}
+	cmd.Flags().StringVar(&historyPath, "history", "", "Workflow history JSON (temporal workflow show --output json)")
+	cmd.Flags().StringVar(&artifactsDir, "artifacts", "", "Directory of artifact files named <artifact_id>.json")
+	cmd.Flags().StringVar(&policyPath, "policy", "", "Rego policies the worker evaluated (POLICY_PATH), for histories that did not record the evaluation; defaults to the built-in rules")
+	_ = cmd.MarkFlagRequired("history")
+	_ = cmd.MarkFlagRequired("artifacts")
+	return cmd
+}
+
+// classifyReplay maps the replayer's result onto a verification outcome. A guard
+// violation or a nondeterministic history proves tampering or a code mismatch (INVALID);
+// any other failure means the history could not be judged (INCONCLUSIVE).
+func classifyReplay(err error, store *verifiedStore) (verifier.Outcome, string) {
+	if err == nil {
+		return verifier.OutcomeValid, ""
+	}
+	if len(store.findings) > 0 {
+		return verifier.OutcomeInvalid, codeArtifactInvalid
+	}
+	var v *replay.Violation
+	if errors.As(err, &v) {
+		return verifier.OutcomeInvalid, string(v.Kind)
+	}
+	// The guard's panic reaches the replayer as text only.
+	msg := err.Error()
+	for _, kind := range []replay.ViolationKind{replay.ViolationIntegrity, replay.ViolationMissing, replay.ViolationFieldMismatch, replay.ViolationSignalMismatch} {
+		if strings.Contains(msg, replay.ErrReplayTampered.Error()+": "+string(kind)) {
+			return verifier.OutcomeInvalid, string(kind)
+		}
+	}
+	if strings.Contains(msg, "nondeterministic") {
+		return verifier.OutcomeInvalid, codeNondeterministic
+	}
+	return verifier.OutcomeInconclusive, codeReplayFailed
+}
+
+// verifiedStore serves artifacts to the replay guard only after the offline verifier
+// accepted them (hash, schema and, with --keys, signature). It records every artifact served.
+type verifiedStore struct {
+	artifact.Store
+	keys signing.KeySet
+
+	mu       sync.Mutex
+	checked  map[string]bool
+	findings []string
+}
+
+// Get returns the stored artifact, or an error if it fails verification.
+func (s *verifiedStore) Get(ctx context.Context, artifactID string) (*models.CommitmentArtifact, error) {
+	art, err := s.Store.Get(ctx, artifactID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(art)
+	if err != nil {
+		return nil, err
+	}
+	res, _ := verifier.VerifyArtifactWithKeys(data, s.keys)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checked[artifactID] = true
+	if !res.Valid {
+		finding := fmt.Sprintf("%s | Code: %s | %s", artifactID, res.Code, res.Error)
+		s.findings = append(s.findings, finding)
+		return nil, fmt.Errorf("stored artifact %s failed verification: %s", artifactID, res.Error)
+	}
+	return art, nil
+}
@@ -0,0 +1,322 @@
+package main_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rainminds/gantral/core/activities"
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/core/workflows"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/models"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/temporalproto"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// historyBuilder writes the event history a worker produces for GantralExecutionWorkflow,
+// as exported by `temporal workflow show --output json`.
+type historyBuilder struct {
+	t      *testing.T
+	events []*historypb.HistoryEvent
+}
+
+func (b *historyBuilder) add(typ enumspb.EventType, set func(*historypb.HistoryEvent)) int64 {
+	e := &historypb.HistoryEvent{
+		EventId:   int64(len(b.events) + 1),
+		EventTime: timestamppb.New(time.Date(2026, 1, 1, 0, 0, len(b.events), 0, time.UTC)),
+		EventType: typ,
+	}
+	set(e)
+	b.events = append(b.events, e)
+	return e.EventId
+}
+
+func (b *historyBuilder) payloads(v interface{}) *commonpb.Payloads {
+	p, err := converter.GetDefaultDataConverter().ToPayloads(v)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return p
+}
+
+// workflowTask appends a completed workflow task and returns its completed event ID.
+func (b *historyBuilder) workflowTask() int64 {
+	scheduled := b.add(enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_WorkflowTaskScheduledEventAttributes{WorkflowTaskScheduledEventAttributes: &historypb.WorkflowTaskScheduledEventAttributes{
+			TaskQueue: &taskqueuepb.TaskQueue{Name: workflows.TaskQueue},
+		}}
+	})
+	started := b.add(enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_WorkflowTaskStartedEventAttributes{WorkflowTaskStartedEventAttributes: &historypb.WorkflowTaskStartedEventAttributes{
+			ScheduledEventId: scheduled,
+		}}
+	})
+	return b.add(enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_WorkflowTaskCompletedEventAttributes{WorkflowTaskCompletedEventAttributes: &historypb.WorkflowTaskCompletedEventAttributes{
+			ScheduledEventId: scheduled,
+			StartedEventId:   started,
+		}}
+	})
+}
+
+// activity appends an activity scheduled by the given workflow task, and its result.
+func (b *historyBuilder) activity(task int64, name string, input, result interface{}) {
+	scheduled := b.add(enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_ActivityTaskScheduledEventAttributes{ActivityTaskScheduledEventAttributes: &historypb.ActivityTaskScheduledEventAttributes{
+			ActivityId:                   "",
+			ActivityType:                 &commonpb.ActivityType{Name: name},
+			TaskQueue:                    &taskqueuepb.TaskQueue{Name: workflows.TaskQueue},
+			Input:                        b.payloads(input),
+			StartToCloseTimeout:          durationpb.New(10 * time.Second),
+			WorkflowTaskCompletedEventId: task,
+		}}
+	})
+	b.events[scheduled-1].GetActivityTaskScheduledEventAttributes().ActivityId = strconv.FormatInt(scheduled, 10)
+	started := b.add(enumspb.EVENT_TYPE_ACTIVITY_TASK_STARTED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_ActivityTaskStartedEventAttributes{ActivityTaskStartedEventAttributes: &historypb.ActivityTaskStartedEventAttributes{
+			ScheduledEventId: scheduled,
+			Attempt:          1,
+		}}
+	})
+	b.add(enumspb.EVENT_TYPE_ACTIVITY_TASK_COMPLETED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_ActivityTaskCompletedEventAttributes{ActivityTaskCompletedEventAttributes: &historypb.ActivityTaskCompletedEventAttributes{
+			Result:           b.payloads(result),
+			ScheduledEventId: scheduled,
+			StartedEventId:   started,
+		}}
+	})
+}
+
+func (b *historyBuilder) write(path string) {
+	data, err := temporalproto.CustomJSONMarshalOptions{}.Marshal(&historypb.History{Events: b.events})
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		b.t.Fatal(err)
+	}
+}
+
+func (b *historyBuilder) started(input workflows.WorkflowInput) {
+	b.add(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+			WorkflowType:           &commonpb.WorkflowType{Name: "GantralExecutionWorkflow"},
+			TaskQueue:              &taskqueuepb.TaskQueue{Name: workflows.TaskQueue},
+			Input:                  b.payloads(input),
+			WorkflowTaskTimeout:    durationpb.New(10 * time.Second),
+			OriginalExecutionRunId: "run-1",
+			Attempt:                1,
+		}}
+	})
+}
+
+func (b *historyBuilder) completed(task int64, result workflows.WorkflowResult) {
+	b.add(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{
+			Result:                       b.payloads(result),
+			WorkflowTaskCompletedEventId: task,
+		}}
+	})
+}
+
+// chain seals artifacts in order, linking each to the previous one, and writes them to
+// store unless it is nil.
+func chain(t *testing.T, store artifact.Store, arts ...*models.CommitmentArtifact) {
+	prev := models.GenesisHash
+	for _, art := range arts {
+		art.PrevArtifactHash = prev
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		prev = art.ArtifactID
+		if store != nil {
+			if err := store.Write(t.Context(), art); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+const replayInstance = "inst-replay"
+
+// autoRunHistory records a low-materiality execution: the instance is persisted RUNNING
+// and completed by one Transition activity. firstActivity names the first activity
+// the history claims was scheduled.
+func autoRunHistory(t *testing.T, store artifact.Store, firstActivity string) *historyBuilder {
+	genesis := models.NewCommitmentArtifact(replayInstance, "", string(engine.StateRunning), "pol-low", "genesis", engine.ActorSystem)
+	completed := models.NewCommitmentArtifact(replayInstance, "", string(engine.StateCompleted), "pol-low", "transition", engine.ActorSystem)
+	chain(t, store, genesis, completed)
+
+	b := &historyBuilder{t: t}
+	b.started(workflows.WorkflowInput{WorkflowID: "wf-replay", Policy: policy.Policy{ID: "pol-low", Materiality: policy.MaterialityLow}})
+	task := b.workflowTask()
+	b.activity(task, firstActivity, activities.PersistInstanceInput{InstanceID: replayInstance}, &engine.Instance{ID: replayInstance, State: engine.StateRunning, LastArtifactHash: genesis.ArtifactID})
+	task = b.workflowTask()
+	b.activity(task, "Transition", activities.TransitionInput{InstanceID: replayInstance, TargetState: engine.StateCompleted}, completed)
+	b.completed(b.workflowTask(), workflows.WorkflowResult{InstanceID: replayInstance, FinalState: engine.StateCompleted})
+	return b
+}
+
+// rejectedHistory records a high-materiality execution rejected by alice through a
+// HumanDecision signal. recordedActor is the approver the decision artifact names.
+func rejectedHistory(t *testing.T, store artifact.Store, recordedActor string) *historyBuilder {
+	decision := activities.RecordDecisionInput{
+		InstanceID:      replayInstance,
+		DecisionType:    engine.DecisionReject,
+		ActorID:         "alice",
+		ContextSnapshot: map[string]interface{}{"ticket": "CHG-1"},
+	}
+	contextHash, err := artifact.HashContext(decision.ContextSnapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := models.NewCommitmentArtifact(replayInstance, "", string(engine.StateWaitingForHuman), "pol-high", "genesis", engine.ActorSystem)
+	rejected := models.NewCommitmentArtifact(replayInstance, "", string(engine.StateRejected), "pol-high", contextHash, recordedActor)
+	terminated := models.NewCommitmentArtifact(replayInstance, "", string(engine.StateTerminated), "pol-high", "transition", engine.ActorSystem)
+	chain(t, store, genesis, rejected, terminated)
+
+	b := &historyBuilder{t: t}
+	b.started(workflows.WorkflowInput{WorkflowID: "wf-replay", Policy: policy.Policy{ID: "pol-high", Materiality: policy.MaterialityHigh}})
+	task := b.workflowTask()
+	b.activity(task, "PersistInstance", activities.PersistInstanceInput{InstanceID: replayInstance}, &engine.Instance{ID: replayInstance, State: engine.StateWaitingForHuman, LastArtifactHash: genesis.ArtifactID})
+	task = b.workflowTask()
+	b.add(enumspb.EVENT_TYPE_TIMER_STARTED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_TimerStartedEventAttributes{TimerStartedEventAttributes: &historypb.TimerStartedEventAttributes{
+			TimerId:                      strconv.FormatInt(e.EventId, 10),
+			StartToFireTimeout:           durationpb.New(24 * time.Hour),
+			WorkflowTaskCompletedEventId: task,
+		}}
+	})
+	b.add(enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED, func(e *historypb.HistoryEvent) {
+		e.Attributes = &historypb.HistoryEvent_WorkflowExecutionSignaledEventAttributes{WorkflowExecutionSignaledEventAttributes: &historypb.WorkflowExecutionSignaledEventAttributes{
+			SignalName: workflows.SignalHumanDecision,
+			Input:      b.payloads(decision),
+		}}
+	})
+	task = b.workflowTask()
+	b.activity(task, "RecordDecision", decision, rejected)
+	task = b.workflowTask()
+	b.activity(task, "Transition", activities.TransitionInput{InstanceID: replayInstance, TargetState: engine.StateTerminated}, terminated)
+	b.completed(b.workflowTask(), workflows.WorkflowResult{InstanceID: replayInstance, FinalState: engine.StateTerminated})
+	return b
+}
+
+// runReplay records a history with build, whose artifacts go to a fresh directory, and
+// replays it with the verifier binary.
+func runReplay(t *testing.T, bin string, build func(store artifact.Store, dir string) *historyBuilder) (string, int) {
+	dir := t.TempDir()
+	artifacts := filepath.Join(dir, "artifacts")
+	store, err := local.NewStore(artifacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	historyPath := filepath.Join(dir, "history.json")
+	build(store, artifacts).write(historyPath)
+
+	out, err := exec.Command(bin, "replay", "--history", historyPath, "--artifacts", artifacts).CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return string(out), exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return string(out), 0
+}
+
+func Test_Offline_Replay(t *testing.T) {
+	binPath := buildVerifier(t)
+	defer os.Remove(binPath)
+
+	tests := []struct {
+		name     string
+		build    func(store artifact.Store, dir string) *historyBuilder
+		wantCode int
+		want     string
+	}{
+		{
+			name: "Honest decision",
+			build: func(store artifact.Store, dir string) *historyBuilder {
+				return rejectedHistory(t, store, "alice")
+			},
+			wantCode: 0,
+			want:     "REPLAY VALID | Events: 28 | Artifacts: 2",
+		},
+		{
+			name: "Decision artifact names another approver",
+			build: func(store artifact.Store, dir string) *historyBuilder {
+				return rejectedHistory(t, store, "mallory")
+			},
+			wantCode: 1,
+			want:     `Code: SIGNAL_MISMATCH | Error: REPLAY GUARD VIOLATION: SECURITY ALERT: replayed history does not match signed evidence: SIGNAL_MISMATCH: decision is not bound to the artifact it produced; human_actor_id claimed="alice" stored="mallory"`,
+		},
+		{
+			name: "Artifact missing from store",
+			build: func(store artifact.Store, dir string) *historyBuilder {
+				return autoRunHistory(t, nil, "PersistInstance")
+			},
+			wantCode: 1,
+			want:     "Code: ARTIFACT_MISSING",
+		},
+		{
+			name: "Stored artifact altered",
+			build: func(store artifact.Store, dir string) *historyBuilder {
+				b := autoRunHistory(t, store, "PersistInstance")
+				files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+				for _, f := range files {
+					data, _ := os.ReadFile(f)
+					_ = os.WriteFile(f, []byte(strings.Replace(string(data), `"transition"`, `"forged"`, 1)), 0600)
+				}
+				return b
+			},
+			wantCode: 1,
+			want:     "Code: ARTIFACT_INVALID",
+		},
+		{
+			name: "History from different workflow code",
+			build: func(store artifact.Store, dir string) *historyBuilder {
+				return autoRunHistory(t, store, "Transition")
+			},
+			wantCode: 1,
+			want:     "Code: NONDETERMINISTIC",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, code := runReplay(t, binPath, tt.build)
+			if code != tt.wantCode {
+				t.Errorf("Expected exit %d, got %d. Output: %s", tt.wantCode, code, out)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("Expected %q in output, got: %s", tt.want, out)
+			}
+		})
+	}
+
+	t.Run("Missing artifact directory", func(t *testing.T) {
+		dir := t.TempDir()
+		historyPath := filepath.Join(dir, "history.json")
+		autoRunHistory(t, nil, "PersistInstance").write(historyPath)
+
+		out, err := exec.Command(binPath, "replay", "--history", historyPath, "--artifacts", filepath.Join(dir, "nope")).CombinedOutput()
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok || exitErr.ExitCode() != 2 {
+			t.Errorf("Expected exit 2, got %v. Output: %s", err, out)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "nope")); !os.IsNotExist(err) {
+			t.Error("Replay created the artifact directory")
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Rainminds/gantral/adapters/secondary/postgres"
+	"github.com/Rainminds/gantral/internal/reconcile"
+	"github.com/Rainminds/gantral/internal/storage"
+	"github.com/Rainminds/gantral/pkg/config"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of a one-shot reconciliation.
+const (
+	exitClean    = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run executes the command line and returns the process exit code. Commands report
+// their code instead of calling os.Exit, so their deferred cleanup always runs.
+func run() int {
+	code := exitClean
+	rootCmd := &cobra.Command{
+		Use:   "gantral",
+		Short: "Operator tooling for a Gantral deployment",
+	}
+	rootCmd.AddCommand(newReconcileCmd(&code))
+
+	if err := rootCmd.Execute(); err != nil {
+		return exitError
+	}
+	return code
+}
+
+func newReconcileCmd(exitCode *int) *cobra.Command {
+	var outPath, metricsAddr string
+	var interval, grace time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Compare the database with the artifact store and emit a signed report",
+		Long: `Walks every instance and compares instances.last_artifact_hash, the decisions
+table and audit_events against the artifact chain (and the global artifact log,
+when configured). Divergences are classified as ORPHAN, MISSING or MISMATCH.
+
+Without --interval the command runs once and exits 0 when clean, 1 when there
+are findings and 2 on error. With --interval it runs as a background job and
+exports Prometheus gauges on --metrics-addr.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			*exitCode = runReconcile(outPath, metricsAddr, interval, grace)
+		},
+	}
+	cmd.Flags().StringVar(&outPath, "out", "-", "Report file (- for stdout)")
+	cmd.Flags().DurationVar(&interval, "interval", 0, "Run repeatedly at this interval instead of once")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus /metrics on this address (with --interval)")
+	cmd.Flags().DurationVar(&grace, "grace", time.Minute, "Ignore orphans younger than this (in-flight writes)")
+	return cmd
+}
+
+// runReconcile runs the reconciler once, or every interval until interrupted, and
+// returns the exit code.
+func runReconcile(outPath, metricsAddr string, interval, grace time.Duration) int {
+	logger := slog.New(slog.NewJSONHandler(os.Stderr, nil))
+	slog.SetDefault(logger)
+	_ = godotenv.Load()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	rec, signer, cleanup, err := setup(ctx, logger)
+	if err != nil {
+		logger.Error("Failed to initialize reconciler", "error", err)
+		return exitError
+	}
+	defer cleanup()
+	rec.Grace = grace
+
+	runOnce := func() (*reconcile.Report, error) {
+		report, err := rec.Run(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if signer != nil {
+			if err := report.Sign(signer); err != nil {
+				return nil, err
+			}
+		}
+		reconcile.RecordMetrics(report)
+		if err := writeReport(outPath, report); err != nil {
+			return nil, err
+		}
+		logger.Info("Reconciliation complete",
+			"instances", report.Instances,
+			"artifacts", report.Artifacts,
+			"orphan", report.Summary[reconcile.ClassOrphan],
+			"missing", report.Summary[reconcile.ClassMissing],
+			"mismatch", report.Summary[reconcile.ClassMismatch],
+		)
+		return report, nil
+	}
+
+	if interval <= 0 {
+		report, err := runOnce()
+		if err != nil {
+			logger.Error("Reconciliation failed", "error", err)
+			return exitError
+		}
+		if !report.Clean() {
+			return exitFindings
+		}
+		return exitClean
+	}
+
+	// Background job: keep the gauges fresh and let alerts fire on them.
+	if metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		srv := &http.Server{Addr: metricsAddr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Metrics server failed", "error", err)
+			}
+		}()
+		defer srv.Close()
+		logger.Info("Serving metrics", "addr", metricsAddr)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := runOnce(); err != nil {
+			logger.Error("Reconciliation failed", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return exitClean
+		case <-ticker.C:
+		}
+	}
+}
+
+// setup opens the database, the artifact store and, when configured, the global log,
+// using the same environment as the worker.
+func setup(ctx context.Context, logger *slog.Logger) (*reconcile.Reconciler, *signing.Signer, func(), error) {
+	// Not config.MustGetEnv: it exits with 1, which is the findings code.
+	dbURL := config.GetEnv("DATABASE_URL", "")
+	if dbURL == "" {
+		return nil, nil, nil, errors.New("DATABASE_URL is not set")
+	}
+	db, err := postgres.NewStore(ctx, dbURL)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	store, err := storage.OpenArtifactStore(ctx, logger)
+	if err != nil {
+		db.Close()
+		return nil, nil, nil, fmt.Errorf("failed to open artifact store: %w", err)
+	}
+	// Object storage holds an open bucket; the local store has nothing to close.
+	cleanup := func() {
+		if c, ok := store.(io.Closer); ok {
+			c.Close()
+		}
+		db.Close()
+	}
+
+	artifactLog, err := storage.OpenArtifactLog(ctx, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("failed to open artifact log: %w", err)
+	}
+
+	var rec *reconcile.Reconciler
+	if artifactLog != nil {
+		closeStore := cleanup
+		cleanup = func() { artifactLog.Close(); closeStore() }
+		rec = reconcile.New(db, store, artifactLog)
+	} else {
+		rec = reconcile.New(db, store, nil)
+	}
+
+	// Reports are signed with the artifact key so auditors verify them with the same keys.json.
+	var signer *signing.Signer
+	if keyPath := config.GetEnv("ARTIFACT_SIGNING_KEY", ""); keyPath != "" {
+		privateKey, err := signing.LoadPrivateKey(keyPath)
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, fmt.Errorf("failed to load signing key: %w", err)
+		}
+		signer, err = signing.NewSigner(config.GetEnv("ARTIFACT_SIGNING_KEY_ID", ""), privateKey)
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, fmt.Errorf("failed to initialize signer: %w", err)
+		}
+	} else {
+		logger.Warn("SECURITY ALERT: ARTIFACT_SIGNING_KEY not set; reconciliation reports will be unsigned")
+	}
+	return rec, signer, cleanup, nil
+}
+
+func writeReport(path string, report *reconcile.Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	// Write-then-rename so a scraper never reads a partial report.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
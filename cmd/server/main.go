@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	stdhttp "net/http" // Alias standard library
 	"os"
+	"strings"
 
 	"time"
 
@@ -12,7 +13,9 @@ import (
 	"github.com/Rainminds/gantral/adapters/secondary/postgres"
 	"github.com/Rainminds/gantral/internal/auth"
 	"github.com/Rainminds/gantral/internal/middleware"
+	"github.com/Rainminds/gantral/internal/storage"
 	"github.com/Rainminds/gantral/pkg/config"
+	"github.com/Rainminds/gantral/pkg/signing"
 	"github.com/joho/godotenv"
 	"go.temporal.io/sdk/client"
 )
@@ -56,6 +59,37 @@ func main() {
 	}
 	defer store.Close()
 
+	// 4b. Artifact Store (Read Path for /artifacts); same configuration as the worker
+	artifactStore, err := storage.OpenArtifactStore(context.Background(), logger)
+	if err != nil {
+		logger.Error("Unable to open artifact store", "error", err)
+		os.Exit(1)
+	}
+
+	// 4c. Audit bundle sources (GET /bundles): worker evidence and, optionally, the public keys
+	evidenceStore, err := storage.OpenEvidenceStore(context.Background(), logger)
+	if err != nil {
+		logger.Error("Unable to open evidence store", "error", err)
+		os.Exit(1)
+	}
+	defer evidenceStore.Close()
+
+	var publicKeys *signing.KeySetFile
+	if keysPath := config.GetEnv("ARTIFACT_PUBLIC_KEYS", ""); keysPath != "" {
+		publicKeys, err = signing.LoadKeySetFile(keysPath)
+		if err != nil {
+			logger.Error("Unable to load ARTIFACT_PUBLIC_KEYS", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// 4d. Global artifact log (GET /log/...), optional
+	artifactLog, err := storage.OpenArtifactLog(context.Background(), logger)
+	if err != nil {
+		logger.Error("Unable to open artifact log", "error", err)
+		os.Exit(1)
+	}
+
 	// 5. Setup Authentication
 	var verifiers []auth.TokenVerifier
 	devMode := config.GetEnv("DEV_MODE", "false") == "true"
@@ -111,7 +145,12 @@ func main() {
 
 	// 6. Start HTTP Server
 	// Note: API talks to Temporal for Writes, Postgres for Reads (CQRS).
-	srv := gantralhttp.NewServer(port, c, taskQueue, store)
+	srv := gantralhttp.NewServer(port, c, taskQueue, store, store, artifactStore).
+		WithAuditSources(evidenceStore, publicKeys)
+	if artifactLog != nil {
+		defer artifactLog.Close()
+		srv.WithLog(artifactLog)
+	}
 	mux := srv.Routes()
 
 	// 7. Manual RBAC implementation since we can't easily inject into the mux returned by adapters logic
@@ -132,6 +171,18 @@ func main() {
 			return
 		}
 
+		// Rule 3: Policy Registry writes -> Admin only
+		if strings.HasPrefix(path, "/policies") && method == "POST" {
+			middleware.RequireRole("admin")(mux).ServeHTTP(w, r)
+			return
+		}
+
+		// Rule 4: Quarantine resolution -> Operator/Admin only
+		if strings.HasPrefix(path, "/instances/") && strings.HasSuffix(path, "/quarantine/resolution") && method == "POST" {
+			middleware.RequireRole(gantralhttp.QuarantineOperatorRoles...)(mux).ServeHTTP(w, r)
+			return
+		}
+
 		// Default: Pass through (AuthMiddleware already validated identity exists)
 		mux.ServeHTTP(w, r)
 	})
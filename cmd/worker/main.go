@@ -2,17 +2,25 @@ package main
 
 import (
 	"context"
+	"io"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/Rainminds/gantral/adapters/secondary/postgres"
 	"github.com/Rainminds/gantral/core/activities"
 	"github.com/Rainminds/gantral/core/workflows"
+	"github.com/Rainminds/gantral/internal/anchor"
 	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/internal/authority"
+	"github.com/Rainminds/gantral/internal/policy"
+	"github.com/Rainminds/gantral/internal/policy/opa"
 	"github.com/Rainminds/gantral/internal/replay"
-	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/internal/storage"
 	gw "github.com/Rainminds/gantral/internal/workflow"
 	"github.com/Rainminds/gantral/pkg/config"
+	"github.com/Rainminds/gantral/pkg/signing"
 	"github.com/joho/godotenv"
 	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/interceptor"
@@ -49,18 +57,102 @@ func main() {
 	defer store.Close()
 
 	// 3b. Initialize Artifact Store (Evidence)
-	artifactDir := os.Getenv("ARTIFACT_STORAGE_PATH")
-	if artifactDir == "" {
-		artifactDir = "./gantral_artifacts"
-	}
-	artifactStore, err := local.NewStore(artifactDir)
+	// ARTIFACT_STORE_URL selects immutable object storage (s3://, file://, mem://);
+	// without it artifacts are written to the local filesystem (dev only).
+	artifactStore, err := storage.OpenArtifactStore(ctx, logger)
 	if err != nil {
 		logger.Error("Failed to initialize artifact store", "error", err)
 		os.Exit(1)
 	}
+	// 3b'. Global Artifact Log (Optional)
+	// Every written artifact ID is appended to one append-only log, so that deleting a
+	// whole instance chain is detectable from signed checkpoints.
+	artifactLog, err := storage.OpenArtifactLog(ctx, logger)
+	if err != nil {
+		logger.Error("Failed to open artifact log", "error", err)
+		os.Exit(1)
+	}
+	if artifactLog != nil {
+		defer artifactLog.Close()
+		artifactStore = artifactlog.WrapStore(artifactStore, artifactLog)
+	}
+
+	// Artifact Signing (Non-Repudiation)
+	// ARTIFACT_SIGNING_KEY is a PEM PKCS#8 Ed25519 private key (openssl genpkey -algorithm ed25519).
+	// Publish the logged public key in the keys.json handed to gantral-verify --keys.
 	artifactManager := artifact.NewManager(artifactStore)
+	var signer *signing.Signer
+	if keyPath := config.GetEnv("ARTIFACT_SIGNING_KEY", ""); keyPath != "" {
+		privateKey, err := signing.LoadPrivateKey(keyPath)
+		if err != nil {
+			logger.Error("Failed to load artifact signing key", "error", err)
+			os.Exit(1)
+		}
+		signer, err = signing.NewSigner(config.GetEnv("ARTIFACT_SIGNING_KEY_ID", ""), privateKey)
+		if err != nil {
+			logger.Error("Failed to initialize artifact signer", "error", err)
+			os.Exit(1)
+		}
+		artifactManager = artifact.NewSigningManager(artifactStore, signer)
+		entry := signing.Entry(signer.KeyID(), signer.PublicKey())
+		logger.Info("Artifact signing enabled", "key_id", entry.KeyID, "public_key", entry.PublicKey)
+	} else {
+		logger.Warn("SECURITY ALERT: ARTIFACT_SIGNING_KEY not set; artifacts will be unsigned and fail verification with --keys")
+	}
+
+	// Log checkpoints are signed with the artifact key; every worker may write them.
+	if artifactLog != nil {
+		if signer == nil {
+			logger.Warn("SECURITY ALERT: artifact log checkpoints disabled (no ARTIFACT_SIGNING_KEY)")
+		} else {
+			interval, err := time.ParseDuration(config.GetEnv("ARTIFACT_LOG_CHECKPOINT_INTERVAL", "1m"))
+			if err != nil || interval <= 0 {
+				logger.Error("Invalid ARTIFACT_LOG_CHECKPOINT_INTERVAL", "error", err)
+				os.Exit(1)
+			}
+			checkpointCtx, stopCheckpoints := context.WithCancel(ctx)
+			defer stopCheckpoints()
+			go artifactLog.RunCheckpointer(checkpointCtx, signer, interval, logger)
+
+			// External anchoring: checkpoints pinned where the log operator cannot rewrite them.
+			anchors, err := storage.OpenAnchors(ctx, logger)
+			if err != nil {
+				logger.Error("Failed to configure anchors", "error", err)
+				os.Exit(1)
+			}
+			if len(anchors) == 0 {
+				logger.Warn("SECURITY ALERT: no anchor witness configured; a rewritten artifact log is only detectable by auditors holding older checkpoints")
+			} else {
+				anchorInterval, err := time.ParseDuration(config.GetEnv("ANCHOR_INTERVAL", "10m"))
+				if err != nil || anchorInterval <= 0 {
+					logger.Error("Invalid ANCHOR_INTERVAL", "error", err)
+					os.Exit(1)
+				}
+				for _, a := range anchors {
+					if c, ok := a.(io.Closer); ok {
+						defer c.Close()
+					}
+				}
+				go anchor.Run(checkpointCtx, artifactLog, anchors, anchorInterval, logger)
+			}
+		}
+	}
 	replayGuard := replay.NewReplayGuard(artifactStore)
 
+	// 3c. Initialize Policy Backend (Optional)
+	// POLICY_ENGINE=opa loads Rego policies from POLICY_PATH (file, directory or bundle).
+	// Anything else keeps the built-in materiality rules.
+	if config.GetEnv("POLICY_ENGINE", "builtin") == "opa" {
+		policyPath := config.MustGetEnv("POLICY_PATH")
+		evaluator, err := opa.NewEvaluator(ctx, policyPath)
+		if err != nil {
+			logger.Error("Failed to load OPA policies", "path", policyPath, "error", err)
+			os.Exit(1)
+		}
+		workflows.SetPolicyBackend(policy.NewFailClosedEngine(evaluator))
+		logger.Info("Policy: Embedded OPA evaluator enabled", "path", policyPath, "bundle_hash", evaluator.BundleHash())
+	}
+
 	// 4. Connect to Temporal
 	c, err := client.Dial(client.Options{
 		HostPort: temporalHost,
@@ -82,9 +174,12 @@ func main() {
 	w.RegisterWorkflow(workflows.GantralExecutionWorkflow)
 
 	// Register Activities
+	// The guard verifies every chain head read from the database against the artifact
+	// store and quarantines instances whose head is missing, foreign or stale.
 	activityImpl := &activities.ExecutionActivities{
 		DB:              store,
 		ArtifactEmitter: artifactManager,
+		Guard:           authority.NewConsistencyGuard(artifactStore),
 	}
 	w.RegisterActivity(activityImpl)
 
@@ -0,0 +1,62 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// checkAuthorization re-checks a decision against the decision roles and the
+// separation-of-duties rules pinned on the instance before any evidence is written. The
+// workflow checks first and never schedules a refused decision, so a failure here is not
+// retried.
+func checkAuthorization(instance *engine.Instance, input RecordDecisionInput) error {
+	held := engine.HeldRoles(input.Identity, input.Roles)
+	err := engine.CheckRole(engine.RoleRulesFor(instance), input.DecisionType, input.ActorID, input.AuthorizedRole, held)
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), "RoleNotAuthorized", err)
+	}
+	err = engine.CheckSeparationOfDuties(engine.SeparationOfDutiesFor(instance), instance, input.ActorID, input.ActorOrgID, input.PriorActors)
+	if err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), "SeparationOfDuties", err)
+	}
+	return nil
+}
+
+// checkActor verifies that the decision's actor is the binding of its authenticated
+// identity, so the artifact can only ever name the caller.
+func checkActor(instance *engine.Instance, input RecordDecisionInput) error {
+	if err := engine.CheckActor(input.Identity, input.ActorID, engine.IdentityRequiredFor(instance)); err != nil {
+		return temporal.NewNonRetryableApplicationError(err.Error(), "ActorMismatch", err)
+	}
+	return nil
+}
+
+// RejectedDecisionInput is a decision attempt refused by separation of duties or by the
+// policy's decision roles, or whose actor is not its authenticated identity.
+type RejectedDecisionInput struct {
+	Decision RecordDecisionInput `json:"decision"`
+	Reason   string              `json:"reason"`
+}
+
+// RecordRejectedDecision audit-logs a refused decision attempt. It emits no artifact and
+// does not change the instance: the workflow keeps waiting for an eligible decision.
+func (a *ExecutionActivities) RecordRejectedDecision(ctx context.Context, input RejectedDecisionInput) error {
+	d := input.Decision
+	activity.GetLogger(ctx).Warn("Decision attempt rejected", "instance_id", d.InstanceID, "actor_id", d.ActorID, "reason", input.Reason)
+
+	cmd := engine.RejectedDecisionCmd{
+		InstanceID:   d.InstanceID,
+		DecisionType: d.DecisionType,
+		ActorID:      d.ActorID,
+		ActorOrgID:   d.ActorOrgID,
+		Reason:       input.Reason,
+	}
+	if err := a.DB.RecordRejectedDecision(ctx, cmd); err != nil {
+		return fmt.Errorf("failed to record rejected decision: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package activities
+
+import (
+	"testing"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestRecordDecision_RefusesSeparationOfDutiesViolation(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-sod").Return(&engine.Instance{
+		ID:               "inst-sod",
+		State:            engine.StateWaitingForHuman,
+		LastArtifactHash: "prev",
+		CreatorOrgID:     "payments",
+		PolicyContext: map[string]interface{}{
+			engine.SeparationOfDutiesKey: &policy.SeparationOfDuties{ExcludeCreatorOrg: true},
+		},
+	}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:   "inst-sod",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "bob",
+		ActorOrgID:   "payments",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "separation of duties")
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "RecordDecision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordRejectedDecision(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	activities := &ExecutionActivities{DB: mockDB}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("RecordRejectedDecision", mock.Anything, engine.RejectedDecisionCmd{
+		InstanceID:   "inst-sod",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "alice",
+		ActorOrgID:   "payments",
+		Reason:       "separation of duties violation: alice created the instance",
+	}).Return(nil)
+
+	_, err := env.ExecuteActivity(activities.RecordRejectedDecision, RejectedDecisionInput{
+		Decision: RecordDecisionInput{
+			InstanceID:   "inst-sod",
+			DecisionType: engine.DecisionApprove,
+			ActorID:      "alice",
+			ActorOrgID:   "payments",
+		},
+		Reason: "separation of duties violation: alice created the instance",
+	})
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+}
+
+func TestRecordDecision_RefusesActorMismatch(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:    "inst-1",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:   "inst-1",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "dev-mode|mallory",
+		Identity:     &engine.ActorIdentity{Subject: "alice", Issuer: "dev-mode"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the authenticated identity")
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordDecision_RefusesMissingIdentityWhenRequired(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:            "inst-1",
+		State:         engine.StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{engine.IdentityRequiredKey: true},
+	}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:   "inst-1",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "https://idp.example.com|alice",
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no authenticated identity")
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordDecision_BindsAuthorizedRole(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-roles").Return(&engine.Instance{
+		ID:               "inst-roles",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: "prev",
+		PolicyContext:    map[string]interface{}{engine.OverrideRolesKey: []interface{}{"break-glass"}},
+	}, nil)
+
+	input := RecordDecisionInput{
+		InstanceID:     "inst-roles",
+		DecisionType:   engine.DecisionOverride,
+		ActorID:        "bob",
+		Role:           "user",
+		Roles:          []string{"user", "break-glass"},
+		AuthorizedRole: "break-glass",
+		ContextDelta:   map[string]interface{}{"reason": "incident"},
+	}
+	// The checked role is bound into the artifact: the same decision without it hashes differently.
+	contextHash, _ := DecisionContextHash(input)
+	unbound := input
+	unbound.AuthorizedRole = ""
+	unboundHash, _ := DecisionContextHash(unbound)
+	assert.NotEqual(t, unboundHash, contextHash)
+
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-roles", "prev", "OVERRIDDEN", "pol-1", contextHash, "bob").
+		Return(&models.CommitmentArtifact{ArtifactID: "art-override"}, nil)
+	mockDB.On("RecordDecision", mock.Anything, mock.MatchedBy(func(cmd engine.RecordDecisionCmd) bool {
+		return cmd.Role == "break-glass"
+	}), engine.StateOverridden).Return(&engine.Instance{}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, input)
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+
+	// Without the break-glass role, nothing is emitted.
+	input.Roles = []string{"user"}
+	_, err = env.ExecuteActivity(activities.RecordDecision, input)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "role not authorized")
+	mockEmitter.AssertNumberOfCalls(t, "EmitArtifact", 1)
+}
+
+func TestRecordDecision_ChecksTokenRolesNotClaimedRoles(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-roles").Return(&engine.Instance{
+		ID:               "inst-roles",
+		State:            engine.StateWaitingForHuman,
+		LastArtifactHash: "prev",
+		PolicyContext:    map[string]interface{}{engine.OverrideRolesKey: []interface{}{"break-glass"}},
+	}, nil)
+
+	// The signal claims break-glass; the token behind it does not hold it.
+	identity := &engine.ActorIdentity{Subject: "bob", Issuer: "idp", Roles: []string{"user"}}
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:     "inst-roles",
+		DecisionType:   engine.DecisionOverride,
+		ActorID:        identity.ActorID(),
+		Identity:       identity,
+		Roles:          []string{"user", "break-glass"},
+		AuthorizedRole: "break-glass",
+		ContextDelta:   map[string]interface{}{"reason": "incident"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "role not authorized")
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
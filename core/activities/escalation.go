@@ -0,0 +1,86 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// EscalationInput defines one escalation step of an unanswered approval.
+type EscalationInput struct {
+	InstanceID    string    `json:"instance_id"`
+	Tier          int       `json:"tier"`           // 1-based tier of the policy's escalation
+	ApproverRoles []string  `json:"approver_roles"` // Roles that may approve while the tier is active
+	Deadline      time.Time `json:"deadline"`       // When the tier times out
+}
+
+// EscalationContextHash is the context hash of an escalation artifact. The artifact keeps
+// the instance WAITING_FOR_HUMAN, so the tier, its roles and its deadline are bound through
+// the context.
+func EscalationContextHash(input EscalationInput) (string, error) {
+	roles := make([]interface{}, len(input.ApproverRoles))
+	for i, r := range input.ApproverRoles {
+		roles[i] = r
+	}
+	return artifact.HashContext(map[string]interface{}{
+		"escalation_tier": input.Tier,
+		"approver_roles":  roles,
+		"deadline":        input.Deadline.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// RecordEscalation records an escalation step as a SYSTEM artifact chained on the instance
+// and moves the instance to the tier. The instance stays WAITING_FOR_HUMAN.
+func (a *ExecutionActivities) RecordEscalation(ctx context.Context, input EscalationInput) (*models.CommitmentArtifact, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Escalating approval", "instance_id", input.InstanceID, "tier", input.Tier, "approver_roles", input.ApproverRoles)
+
+	// 1. Fetch Current Instance State (chain head)
+	instance, err := a.DB.GetInstance(ctx, input.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance for chaining: %w", err)
+	}
+	if instance.State != engine.StateWaitingForHuman {
+		err := engine.ErrInvalidTransition{From: instance.State, To: engine.StateWaitingForHuman}
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "InvalidTransition", err)
+	}
+	prevHash := instance.LastArtifactHash
+
+	// 2. Emit Commitment Artifact (Evidence)
+	contextHash, err := EscalationContextHash(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash context: %w", err)
+	}
+	// As with votes, a retry whose earlier attempt committed finds its own escalation at
+	// the head: return it rather than applying the tier again.
+	attempt := engine.ChainAttempt{State: engine.StateWaitingForHuman, PolicyVersionID: instance.PolicyVersionID, ContextHash: contextHash, ActorID: engine.ActorSystem}
+	if recorded, err := a.recordedAttempt(ctx, instance, attempt); err != nil || recorded != nil {
+		return recorded, err
+	}
+	art, err := a.emitChained(ctx, instance, engine.StateWaitingForHuman, instance.PolicyVersionID, contextHash, engine.ActorSystem)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Persist to DB (evidence first, as in RecordDecision)
+	cmd := engine.RecordEscalationCmd{
+		InstanceID:       input.InstanceID,
+		Tier:             input.Tier,
+		ApproverRoles:    input.ApproverRoles,
+		Deadline:         input.Deadline,
+		PrevArtifactHash: prevHash,
+		NewArtifactHash:  art.ArtifactID,
+	}
+	if _, err := a.DB.RecordEscalation(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("failed to record escalation in DB: %w", err)
+	}
+
+	logger.Info("Escalation recorded", "instance_id", input.InstanceID, "tier", input.Tier, "artifact_id", art.ArtifactID)
+	return art, nil
+}
@@ -0,0 +1,108 @@
+package activities
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestRecordEscalation_ChainsSystemArtifact(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: "art-genesis",
+	}, nil)
+
+	deadline := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	input := EscalationInput{InstanceID: "inst-1", Tier: 1, ApproverRoles: []string{"director"}, Deadline: deadline}
+	contextHash, _ := EscalationContextHash(input)
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-1", "art-genesis", "WAITING_FOR_HUMAN", "pol-1", contextHash, engine.ActorSystem).
+		Return(&models.CommitmentArtifact{ArtifactID: "art-escalated", AuthorityState: "WAITING_FOR_HUMAN"}, nil)
+	mockDB.On("RecordEscalation", mock.Anything, mock.MatchedBy(func(cmd engine.RecordEscalationCmd) bool {
+		return cmd.InstanceID == "inst-1" && cmd.Tier == 1 && cmd.Deadline.Equal(deadline) &&
+			cmd.PrevArtifactHash == "art-genesis" && cmd.NewArtifactHash == "art-escalated"
+	})).Return(&engine.Instance{}, nil)
+
+	val, err := env.ExecuteActivity(activities.RecordEscalation, input)
+	assert.NoError(t, err)
+
+	var art models.CommitmentArtifact
+	assert.NoError(t, val.Get(&art))
+	assert.Equal(t, "art-escalated", art.ArtifactID)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+
+	// The artifact binds the tier's roles: escalating to other roles hashes differently.
+	other := input
+	other.ApproverRoles = []string{"cfo"}
+	otherHash, _ := EscalationContextHash(other)
+	assert.NotEqual(t, contextHash, otherHash)
+}
+
+func TestRecordEscalation_DecidedInstance(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateApproved,
+		LastArtifactHash: "art-approved",
+	}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordEscalation, EscalationInput{InstanceID: "inst-1", Tier: 1, ApproverRoles: []string{"director"}})
+	assert.Error(t, err)
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "RecordEscalation", mock.Anything, mock.Anything)
+}
+
+func TestRecordEscalation_RetryAfterCommitReturnsRecordedArtifact(t *testing.T) {
+	f := newStoreBacked(t)
+	f.waitingInstance(t)
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(f.activities)
+
+	input := EscalationInput{
+		InstanceID:    "inst-1",
+		Tier:          1,
+		ApproverRoles: []string{"manager"},
+		Deadline:      time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	future, err := env.ExecuteActivity(f.activities.RecordEscalation, input)
+	assert.NoError(t, err)
+	var first *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&first))
+
+	future, err = env.ExecuteActivity(f.activities.RecordEscalation, input)
+	assert.NoError(t, err)
+	var retried *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&retried))
+	assert.Equal(t, first.ArtifactID, retried.ArtifactID)
+	assert.Equal(t, 2, f.artifactCount(t), "the retry must not chain a second escalation")
+
+	inst, err := f.db.GetInstance(context.Background(), "inst-1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inst.EscalationTier)
+	assert.Equal(t, first.ArtifactID, inst.LastArtifactHash)
+}
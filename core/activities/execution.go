@@ -2,20 +2,44 @@ package activities
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/Rainminds/gantral/core/engine"
 	"github.com/Rainminds/gantral/core/ports"
 	"github.com/Rainminds/gantral/internal/artifact"
 	"github.com/Rainminds/gantral/pkg/models"
 	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
 )
 
+// ErrTypeStateAmbiguous is the application error type returned when an instance was
+// quarantined because its chain head failed verification. Workflows park on it.
+const ErrTypeStateAmbiguous = "StateAmbiguous"
+
+// ErrTypeStaleInstance is the application error type returned when a decision's database
+// write found the instance moved by another writer (engine.ErrStaleInstance). It is not
+// retried: the decision was made against a state that no longer exists.
+const ErrTypeStaleInstance = "StaleInstance"
+
+// ChainGuard verifies that the chain head read from the database is backed by the
+// artifact store (internal/authority.ConsistencyGuard). It returns the head's successor
+// when that successor is attempt's own artifact from an earlier try.
+type ChainGuard interface {
+	EnsureChainHead(ctx context.Context, instanceID, artifactID string, attempt engine.ChainAttempt) (*models.CommitmentArtifact, error)
+	// HeadArtifact returns the verified chain head artifact.
+	HeadArtifact(ctx context.Context, instanceID, artifactID string) (*models.CommitmentArtifact, error)
+}
+
 // ExecutionActivities provides activities for persisting execution state.
 type ExecutionActivities struct {
 	DB              ports.InstanceStore
 	ArtifactEmitter artifact.ArtifactEmitter
+	// Guard checks instance.LastArtifactHash before anything is chained from it.
+	// The database is not trusted; nil disables the check (unit tests only).
+	Guard ChainGuard
 }
 
 // PersistInstanceInput defines the input for PersistInstance activity.
@@ -25,6 +49,8 @@ type PersistInstanceInput struct {
 	TriggerContext  map[string]interface{}
 	Policy          map[string]interface{} // Using generic map to avoid circular deps if needed, but engine.Policy is fine usually.
 	PolicyVersionID string
+	CreatedBy       string // Authenticated identity that created the instance
+	CreatorOrgID    string
 	// Pre-evaluated policy result
 	InitialState engine.State
 	PolicyResult map[string]interface{}
@@ -43,13 +69,25 @@ func (a *ExecutionActivities) PersistInstance(ctx context.Context, input Persist
 	inst := &engine.Instance{
 		ID:              id,
 		WorkflowID:      input.WorkflowID,
-		State:           input.InitialState, // Should be RUNNING or WAITING_FOR_HUMAN
+		State:           input.InitialState, // RUNNING, WAITING_FOR_HUMAN or TERMINATED (policy DENY)
 		TriggerContext:  input.TriggerContext,
 		PolicyVersionID: input.PolicyVersionID,
 		PolicyContext:   input.PolicyResult,
+		CreatedBy:       input.CreatedBy,
+		CreatorOrgID:    input.CreatorOrgID,
 	}
-	err := a.DB.CreateInstance(ctx, inst)
+
+	// Genesis: every instance starts its evidence chain at creation, recording the policy outcome
+	// (including DENY, where the instance is born TERMINATED).
+	// Same consistency model as RecordDecision: emit the evidence first, then write to DB.
+	art, err := engine.EmitGenesis(ctx, a.ArtifactEmitter, inst)
 	if err != nil {
+		return nil, err
+	}
+	inst.LastArtifactHash = art.ArtifactID
+	logger.Info("Genesis artifact emitted", "instance_id", id, "state", inst.State, "artifact_id", art.ArtifactID)
+
+	if err := a.DB.CreateInstance(ctx, inst); err != nil {
 		slog.Error("Failed to persist instance", "error", err)
 		return nil, err
 	}
@@ -67,7 +105,52 @@ type RecordDecisionInput struct {
 	ContextSnapshot map[string]interface{} `json:"context_snapshot"`
 	ContextDelta    map[string]interface{} `json:"context_delta"`
 	PolicyVersionID string                 `json:"policy_version_id"`
-	EvidenceHash    string                 `json:"evidence_hash"` // Hash of tool execution evidence (Phase 5.5)
+	EvidenceHash    string                 `json:"evidence_hash"`   // Hash of tool execution evidence (Phase 5.5)
+	Roles           []string               `json:"roles,omitempty"` // Every role of the actor; matched against approver groups
+
+	// Identity is the authenticated identity behind the decision. When set, ActorID is its
+	// issuer|subject binding (engine.BindActorID), ActorOrgID comes from it, and only its
+	// roles are checked (engine.HeldRoles), never Roles.
+	Identity *engine.ActorIdentity `json:"identity,omitempty"`
+
+	// AuthorizedRole is set by the workflow to the role the policy admitted the decision
+	// under (engine.AuthorizeRole). It is bound into the artifact's context hash and
+	// recorded as the decision's role. Empty when the policy checks no role.
+	AuthorizedRole string `json:"authorized_role,omitempty"`
+
+	// Separation of duties (engine.CheckSeparationOfDuties)
+	ActorOrgID  string   `json:"actor_org_id,omitempty"` // The actor's org, from the authenticated identity
+	PriorActors []string `json:"prior_actors,omitempty"` // Set by the workflow: humans who already decided a stage
+}
+
+// DecisionContextHash is the context hash a decision binds into its artifact: the
+// evidence hash when one is provided (tool mediation), otherwise the hash of the
+// context snapshot (human decision). A decision admitted under a checked role binds
+// that role together with it.
+func DecisionContextHash(input RecordDecisionInput) (string, error) {
+	contextHash := input.EvidenceHash
+	if contextHash == "" {
+		var err error
+		if contextHash, err = artifact.HashContext(input.ContextSnapshot); err != nil {
+			return "", fmt.Errorf("failed to hash context: %w", err)
+		}
+	}
+	if input.AuthorizedRole == "" {
+		return contextHash, nil
+	}
+	return artifact.HashContext(map[string]interface{}{
+		"context_hash":    contextHash,
+		"authorized_role": input.AuthorizedRole,
+	})
+}
+
+// recordedRole is the role a decision is stored under: the role the policy checked, or
+// the actor's primary role when none was checked.
+func (input RecordDecisionInput) recordedRole() string {
+	if input.AuthorizedRole != "" {
+		return input.AuthorizedRole
+	}
+	return input.Role
 }
 
 // RecordDecision persists a human decision.
@@ -81,6 +164,12 @@ func (a *ExecutionActivities) RecordDecision(ctx context.Context, input RecordDe
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch instance for chaining: %w", err)
 	}
+	if err := checkActor(instance, input); err != nil {
+		return nil, err
+	}
+	if err := checkAuthorization(instance, input); err != nil {
+		return nil, err
+	}
 
 	// 2. Prepare Metadata
 	// Calculate next state using shared engine logic to ensure Artifact matches DB state.
@@ -89,45 +178,50 @@ func (a *ExecutionActivities) RecordDecision(ctx context.Context, input RecordDe
 		return nil, err
 	}
 
+	// The policy version is pinned at instance creation. The instance record is the
+	// source of truth; a caller-supplied version can never re-bind the decision.
+	policyVersionID := instance.PolicyVersionID
+	if policyVersionID == "" {
+		policyVersionID = input.PolicyVersionID // Legacy instances created before the registry
+	} else if input.PolicyVersionID != "" && input.PolicyVersionID != policyVersionID {
+		logger.Warn("Ignoring policy version supplied with decision", "instance_id", input.InstanceID, "supplied", input.PolicyVersionID, "pinned", policyVersionID)
+	}
+
 	// 3. Emit Commitment Artifact (Evidence)
-	// Compute Deterministic Context Hash
-	// If EvidenceHash is provided (Tool Mediation), use it.
-	// Otherwise, fallback to hashing the ContextSnapshot (Human Decision).
-	var contextHash string
-	if input.EvidenceHash != "" {
-		contextHash = input.EvidenceHash
-	} else {
-		var err error
-		contextHash, err = artifact.HashContext(input.ContextSnapshot)
-		if err != nil {
-			return nil, fmt.Errorf("failed to hash context: %w", err)
-		}
+	contextHash, err := DecisionContextHash(input)
+	if err != nil {
+		return nil, err
 	}
 
-	art, err := a.ArtifactEmitter.EmitArtifact(
-		ctx,
-		input.InstanceID,
-		instance.LastArtifactHash, // Chain Link
-		string(nextState),
-		input.PolicyVersionID,
-		contextHash,
-		input.ActorID,
-	)
+	// A retry whose earlier attempt committed (but whose response was lost) finds the
+	// instance already decided: return that attempt's artifact instead of chaining another.
+	attempt := engine.ChainAttempt{State: nextState, PolicyVersionID: policyVersionID, ContextHash: contextHash, ActorID: input.ActorID}
+	if instance.State != engine.StateWaitingForHuman {
+		return a.recordedDecision(ctx, instance, attempt)
+	}
+
+	// Chain Link: instance.LastArtifactHash. Bound to the policy evaluation when recorded (v2).
+	art, err := a.emitChained(ctx, instance, nextState, policyVersionID, contextHash, input.ActorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to emit artifact: %w", err)
+		return nil, err
 	}
 
 	// 4. Persist to DB (State + Chain Link)
 	cmd := engine.RecordDecisionCmd{
-		InstanceID:      input.InstanceID,
-		Type:            input.DecisionType,
-		ActorID:         input.ActorID,
-		Justification:   input.Justification,
-		Role:            input.Role,
-		ContextSnapshot: input.ContextSnapshot,
-		ContextDelta:    input.ContextDelta,
-		PolicyVersionID: input.PolicyVersionID,
-		NewArtifactHash: art.ArtifactID, // Persist the new link
+		InstanceID:       input.InstanceID,
+		Type:             input.DecisionType,
+		ActorID:          input.ActorID,
+		Justification:    input.Justification,
+		Role:             input.recordedRole(),
+		ContextSnapshot:  input.ContextSnapshot,
+		ContextDelta:     input.ContextDelta,
+		PolicyVersionID:  policyVersionID,
+		NewArtifactHash:  art.ArtifactID, // Persist the new link
+		PrevArtifactHash: instance.LastArtifactHash,
+		Roles:            input.Roles,
+		Identity:         input.Identity,
+		ActorOrgID:       input.ActorOrgID,
+		PriorActors:      input.PriorActors,
 	}
 
 	if _, err := a.DB.RecordDecision(ctx, cmd, nextState); err != nil {
@@ -137,8 +231,259 @@ func (a *ExecutionActivities) RecordDecision(ctx context.Context, input RecordDe
 		// that failed to operationalize.
 		// The reverse (DB updated, Artifact missing) is NEVER allowed.
 		// Therefore, we emit first, then write to DB.
-		return nil, fmt.Errorf("failed to record decision in DB: %w", err)
+		return nil, decisionWriteError(err)
+	}
+
+	return art, nil
+}
+
+// TransitionInput defines input for a non-decision state transition.
+type TransitionInput struct {
+	InstanceID  string       `json:"instance_id"`
+	TargetState engine.State `json:"target_state"`
+	ActorID     string       `json:"actor_id"` // Defaults to SYSTEM
+	Reason      string       `json:"reason"`
+}
+
+// Transition moves an instance along the canonical state machine (e.g. APPROVED -> RESUMED,
+// RUNNING -> COMPLETED, REJECTED -> TERMINATED) and records the move as a chained artifact.
+// Decisions (WAITING_FOR_HUMAN -> APPROVED/REJECTED/OVERRIDDEN) go through RecordDecision instead.
+func (a *ExecutionActivities) Transition(ctx context.Context, input TransitionInput) (*models.CommitmentArtifact, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Transitioning instance", "instance_id", input.InstanceID, "target", input.TargetState)
+
+	actorID := input.ActorID
+	if actorID == "" {
+		actorID = engine.ActorSystem
+	}
+
+	// 1. Fetch Current Instance State (source state + chain head)
+	instance, err := a.DB.GetInstance(ctx, input.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance for chaining: %w", err)
+	}
+	from := instance.State
+	prevHash := instance.LastArtifactHash
+
+	// 2. Enforce the state machine before any evidence is written. The move is checked on
+	// a copy: instance keeps the stored state, which a quarantine must compare against.
+	next := *instance
+	if err := engine.Transition(&next, input.TargetState); err != nil {
+		// A retry whose earlier attempt committed (but whose response was lost) finds the
+		// instance already moved: return that attempt's artifact instead of failing.
+		if from == input.TargetState {
+			recorded, rerr := a.recordedTransition(ctx, instance, input, actorID)
+			if rerr != nil || recorded != nil {
+				return recorded, rerr
+			}
+		}
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "InvalidTransition", err)
+	}
+
+	// 3. Emit Commitment Artifact (Evidence)
+	contextHash, err := transitionContextHash(from, input.TargetState, input.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chain Link: instance.LastArtifactHash.
+	art, err := a.emitChained(ctx, instance, input.TargetState, instance.PolicyVersionID, contextHash, actorID)
+	if err != nil {
+		return nil, err
 	}
 
+	// 4. Persist to DB (same consistency model as RecordDecision: evidence first)
+	cmd := engine.TransitionCmd{
+		InstanceID:       input.InstanceID,
+		From:             from,
+		To:               input.TargetState,
+		ActorID:          actorID,
+		Reason:           input.Reason,
+		PrevArtifactHash: prevHash,
+		NewArtifactHash:  art.ArtifactID,
+	}
+	if _, err := a.DB.TransitionInstance(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("failed to record transition in DB: %w", err)
+	}
+
+	logger.Info("Transition recorded", "instance_id", input.InstanceID, "from", from, "to", input.TargetState, "artifact_id", art.ArtifactID)
+	return art, nil
+}
+
+// recordedDecision answers a decision on an instance that is no longer waiting: the chain
+// head when it is attempt's artifact, otherwise a non-retryable InvalidTransition error.
+func (a *ExecutionActivities) recordedDecision(ctx context.Context, instance *engine.Instance, attempt engine.ChainAttempt) (*models.CommitmentArtifact, error) {
+	recorded, err := a.recordedAttempt(ctx, instance, attempt)
+	if err != nil || recorded != nil {
+		return recorded, err
+	}
+	invalid := engine.ErrInvalidTransition{From: instance.State, To: attempt.State}
+	return nil, temporal.NewNonRetryableApplicationError(invalid.Error(), "InvalidTransition", invalid)
+}
+
+// recordedAttempt returns the instance's chain head when it is attempt's artifact, or nil
+// when it is not (or cannot be read without a guard).
+func (a *ExecutionActivities) recordedAttempt(ctx context.Context, instance *engine.Instance, attempt engine.ChainAttempt) (*models.CommitmentArtifact, error) {
+	head, err := a.verifiedHead(ctx, instance)
+	if err != nil || head == nil || !attempt.Matches(instance.ID, head) {
+		return nil, err
+	}
+	activity.GetLogger(ctx).Info("Attempt already recorded", "instance_id", instance.ID, "state", attempt.State, "artifact_id", head.ArtifactID)
+	return head, nil
+}
+
+// verifiedHead returns the instance's chain head artifact, or nil when the head is not
+// backed by evidence (or cannot be read without a guard).
+func (a *ExecutionActivities) verifiedHead(ctx context.Context, instance *engine.Instance) (*models.CommitmentArtifact, error) {
+	if a.Guard == nil || instance.LastArtifactHash == "" {
+		return nil, nil
+	}
+	head, err := a.Guard.HeadArtifact(ctx, instance.ID, instance.LastArtifactHash)
+	if err != nil {
+		if errors.Is(err, engine.ErrStateAmbiguous) {
+			return nil, nil // Not ours; the caller's check stays as it was
+		}
+		return nil, fmt.Errorf("failed to verify chain head: %w", err)
+	}
+	return head, nil
+}
+
+// decisionWriteError wraps a failed decision write. A stale instance was moved by another
+// writer after it was read, so the decision is not retried against it.
+func decisionWriteError(err error) error {
+	err = fmt.Errorf("failed to record decision in DB: %w", err)
+	if errors.Is(err, engine.ErrStaleInstance) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeStaleInstance, err)
+	}
+	return err
+}
+
+// recordedTransition returns the instance's chain head when it is the artifact of this
+// transition, or nil when it is not (or cannot be read without a guard).
+func (a *ExecutionActivities) recordedTransition(ctx context.Context, instance *engine.Instance, input TransitionInput, actorID string) (*models.CommitmentArtifact, error) {
+	head, err := a.verifiedHead(ctx, instance)
+	if err != nil || head == nil {
+		return nil, err
+	}
+
+	// The artifact's context names the state it left; try every state that leads here.
+	for from, allowed := range engine.AllowedTransitions {
+		if !slices.Contains(allowed, input.TargetState) {
+			continue
+		}
+		contextHash, err := transitionContextHash(from, input.TargetState, input.Reason)
+		if err != nil {
+			return nil, err
+		}
+		attempt := engine.ChainAttempt{State: input.TargetState, PolicyVersionID: instance.PolicyVersionID, ContextHash: contextHash, ActorID: actorID}
+		if attempt.Matches(instance.ID, head) {
+			activity.GetLogger(ctx).Info("Transition already recorded", "instance_id", instance.ID, "from", from, "to", input.TargetState, "artifact_id", head.ArtifactID)
+			return head, nil
+		}
+	}
+	return nil, nil
+}
+
+// transitionContextHash is the context hash of a Transition artifact.
+func transitionContextHash(from, to engine.State, reason string) (string, error) {
+	contextHash, err := artifact.HashContext(map[string]interface{}{
+		"from_state": string(from),
+		"to_state":   string(to),
+		"reason":     reason,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash context: %w", err)
+	}
+	return contextHash, nil
+}
+
+// emitChained emits the instance's next artifact once its chain head is verified. When the
+// head already has this attempt's artifact (an earlier try emitted it, then failed to write
+// the database), that artifact is adopted instead of forking the chain.
+func (a *ExecutionActivities) emitChained(ctx context.Context, instance *engine.Instance, state engine.State, policyVersionID, contextHash, actorID string) (*models.CommitmentArtifact, error) {
+	attempt := engine.ChainAttempt{State: state, PolicyVersionID: policyVersionID, ContextHash: contextHash, ActorID: actorID}
+	orphan, err := a.verifyChainHead(ctx, instance, attempt)
+	if err != nil {
+		return nil, err
+	}
+	if orphan != nil {
+		activity.GetLogger(ctx).Warn("Adopting artifact from an earlier attempt", "instance_id", instance.ID, "artifact_id", orphan.ArtifactID, "state", state)
+		// The earlier emission may have failed after persisting (e.g. appending to the
+		// global log); finish it before the artifact is committed.
+		if adopter, ok := a.ArtifactEmitter.(artifact.Adopter); ok {
+			if err := adopter.Adopt(ctx, orphan); err != nil {
+				return nil, fmt.Errorf("failed to adopt artifact: %w", err)
+			}
+		}
+		return orphan, nil
+	}
+
+	art, err := engine.EmitInstanceArtifact(ctx, a.ArtifactEmitter, instance, state, policyVersionID, contextHash, actorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to emit artifact: %w", err)
+	}
 	return art, nil
 }
+
+// verifyChainHead fails closed when the instance's chain head is not backed by evidence:
+// the instance is quarantined and a non-retryable ErrTypeStateAmbiguous error is returned,
+// so no artifact is ever chained from a phantom or stale head. Errors reaching the store
+// are returned as-is and retried. A successor that is attempt's own artifact is returned.
+func (a *ExecutionActivities) verifyChainHead(ctx context.Context, instance *engine.Instance, attempt engine.ChainAttempt) (*models.CommitmentArtifact, error) {
+	if a.Guard == nil {
+		return nil, nil
+	}
+	orphan, err := a.Guard.EnsureChainHead(ctx, instance.ID, instance.LastArtifactHash, attempt)
+	if err == nil {
+		return orphan, nil
+	}
+	if !errors.Is(err, engine.ErrStateAmbiguous) {
+		return nil, fmt.Errorf("failed to verify chain head: %w", err)
+	}
+
+	logger := activity.GetLogger(ctx)
+	logger.Error("SECURITY ALERT: Quarantining instance", "instance_id", instance.ID, "state", instance.State, "claimed_artifact_id", instance.LastArtifactHash, "error", err)
+	_, qerr := a.DB.QuarantineInstance(ctx, engine.QuarantineCmd{
+		InstanceID:   instance.ID,
+		From:         instance.State,
+		ArtifactHash: instance.LastArtifactHash,
+		Reason:       err.Error(),
+	})
+	if qerr != nil {
+		// Still fail closed: nothing is emitted, and the retry re-runs the guard.
+		return nil, fmt.Errorf("failed to quarantine instance: %w (%v)", qerr, err)
+	}
+	return nil, temporal.NewNonRetryableApplicationError(err.Error(), ErrTypeStateAmbiguous, err)
+}
+
+// ResolveQuarantineInput defines an operator decision on a quarantined instance.
+type ResolveQuarantineInput struct {
+	InstanceID    string                  `json:"instance_id"`
+	Action        engine.QuarantineAction `json:"action"`
+	ActorID       string                  `json:"actor_id"`
+	Justification string                  `json:"justification"`
+	Role          string                  `json:"role"`
+	// RestoreState is the state the instance was quarantined from. It is set by the
+	// workflow, never by the operator.
+	RestoreState engine.State `json:"restore_state"`
+}
+
+// ResolveQuarantine records an operator decision on a quarantined instance.
+// No artifact is emitted: quarantine never enters the evidence chain.
+func (a *ExecutionActivities) ResolveQuarantine(ctx context.Context, input ResolveQuarantineInput) (*engine.Instance, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Resolving quarantine", "instance_id", input.InstanceID, "action", input.Action, "actor_id", input.ActorID)
+
+	inst, err := a.DB.ResolveQuarantine(ctx, engine.ResolveQuarantineCmd{
+		InstanceID:    input.InstanceID,
+		Action:        input.Action,
+		RestoreState:  input.RestoreState,
+		ActorID:       input.ActorID,
+		Role:          input.Role,
+		Justification: input.Justification,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve quarantine: %w", err)
+	}
+	return inst, nil
+}
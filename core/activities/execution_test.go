@@ -2,13 +2,18 @@ package activities
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/Rainminds/gantral/core/engine"
 	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/authority"
+	"github.com/Rainminds/gantral/internal/storage/local"
 	"github.com/Rainminds/gantral/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
 )
 
@@ -43,6 +48,35 @@ func (m *MockInstanceStore) RecordDecision(ctx context.Context, cmd engine.Recor
 	return args.Get(0).(*engine.Instance), args.Error(1)
 }
 
+func (m *MockInstanceStore) TransitionInstance(ctx context.Context, cmd engine.TransitionCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+
+func (m *MockInstanceStore) RecordVote(ctx context.Context, cmd engine.RecordVoteCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+
+func (m *MockInstanceStore) RecordEscalation(ctx context.Context, cmd engine.RecordEscalationCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+
+func (m *MockInstanceStore) RecordRejectedDecision(ctx context.Context, cmd engine.RejectedDecisionCmd) error {
+	return m.Called(ctx, cmd).Error(0)
+}
+
+func (m *MockInstanceStore) QuarantineInstance(ctx context.Context, cmd engine.QuarantineCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+
+func (m *MockInstanceStore) ResolveQuarantine(ctx context.Context, cmd engine.ResolveQuarantineCmd) (*engine.Instance, error) {
+	args := m.Called(ctx, cmd)
+	return args.Get(0).(*engine.Instance), args.Error(1)
+}
+
 type MockArtifactEmitter struct {
 	mock.Mock
 }
@@ -52,6 +86,11 @@ func (m *MockArtifactEmitter) EmitArtifact(ctx context.Context, instanceID, prev
 	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
 }
 
+func (m *MockArtifactEmitter) EmitBoundArtifact(ctx context.Context, instanceID, prevHash, state, policyVer, contextHash, actorID string, binding models.PolicyBinding) (*models.CommitmentArtifact, error) {
+	args := m.Called(ctx, instanceID, prevHash, state, policyVer, contextHash, actorID, binding)
+	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
+}
+
 func TestRecordDecision_Chaining(t *testing.T) {
 	// Setup
 	mockDB := new(MockInstanceStore)
@@ -78,6 +117,7 @@ func TestRecordDecision_Chaining(t *testing.T) {
 	mockDB.On("GetInstance", mock.Anything, instanceID).Return(&engine.Instance{
 		ID:               instanceID,
 		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  policyVer,
 		LastArtifactHash: prevHash,
 	}, nil)
 
@@ -90,14 +130,15 @@ func TestRecordDecision_Chaining(t *testing.T) {
 
 	// 3. Expect RecordDecision (DB) with NewArtifactHash
 	expectedCmd := engine.RecordDecisionCmd{
-		InstanceID:      instanceID,
-		Type:            engine.DecisionApprove,
-		ActorID:         "user-1",
-		Justification:   "Approved via test",
-		Role:            "admin",
-		ContextSnapshot: contextSnapshot,
-		PolicyVersionID: policyVer,
-		NewArtifactHash: "art-new", // Critical Check: Matches ID
+		InstanceID:       instanceID,
+		Type:             engine.DecisionApprove,
+		ActorID:          "user-1",
+		Justification:    "Approved via test",
+		Role:             "admin",
+		ContextSnapshot:  contextSnapshot,
+		PolicyVersionID:  policyVer,
+		NewArtifactHash:  "art-new", // Critical Check: Matches ID
+		PrevArtifactHash: prevHash,  // Compare-and-set on the head it was chained from
 	}
 	mockDB.On("RecordDecision", mock.Anything, expectedCmd, engine.StateApproved).Return(&engine.Instance{}, nil)
 
@@ -124,3 +165,653 @@ func TestRecordDecision_Chaining(t *testing.T) {
 	mockDB.AssertExpectations(t)
 	mockEmitter.AssertExpectations(t)
 }
+
+func TestRecordDecision_UsesPinnedPolicyVersion(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	pinned := "9f2c-pinned-version-hash"
+	mockDB.On("GetInstance", mock.Anything, "inst-pin").Return(&engine.Instance{
+		ID:               "inst-pin",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  pinned,
+		LastArtifactHash: "prev",
+	}, nil)
+
+	contextHash, _ := artifact.HashContext(map[string]interface{}(nil))
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-pin", "prev", "REJECTED", pinned, contextHash, "user-1").
+		Return(&models.CommitmentArtifact{ArtifactID: "art-pin"}, nil)
+	mockDB.On("RecordDecision", mock.Anything, mock.MatchedBy(func(cmd engine.RecordDecisionCmd) bool {
+		return cmd.PolicyVersionID == pinned
+	}), engine.StateRejected).Return(&engine.Instance{}, nil)
+
+	// The caller claims a different policy version; the pinned one must win.
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:      "inst-pin",
+		DecisionType:    engine.DecisionReject,
+		ActorID:         "user-1",
+		PolicyVersionID: "forged-version",
+	})
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+}
+
+func TestRecordDecision_BindsPolicyHashes(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	binding := models.PolicyBinding{InputHash: "in-hash", DecisionHash: "dec-hash"}
+	mockDB.On("GetInstance", mock.Anything, "inst-v2").Return(&engine.Instance{
+		ID:               "inst-v2",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  "pv",
+		LastArtifactHash: "prev",
+		PolicyContext: map[string]interface{}{
+			engine.PolicyInputHashKey:    binding.InputHash,
+			engine.PolicyDecisionHashKey: binding.DecisionHash,
+		},
+	}, nil)
+
+	contextHash, _ := artifact.HashContext(map[string]interface{}(nil))
+	mockEmitter.On("EmitBoundArtifact", mock.Anything, "inst-v2", "prev", "APPROVED", "pv", contextHash, "user-1", binding).
+		Return(&models.CommitmentArtifact{ArtifactID: "art-v2", ArtifactVersion: models.SchemaVersionV2}, nil)
+	mockDB.On("RecordDecision", mock.Anything, mock.Anything, engine.StateApproved).Return(&engine.Instance{}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:   "inst-v2",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "user-1",
+	})
+	assert.NoError(t, err)
+	mockEmitter.AssertExpectations(t)
+}
+
+func TestPersistInstance_PolicyDenyEmitsArtifact(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	trigger := map[string]interface{}{"op": "drop-table"}
+	contextHash, _ := artifact.HashContext(trigger)
+
+	// Evidence first: SYSTEM genesis artifact for the TERMINATED state
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-deny", models.GenesisHash, "TERMINATED", "pol-1", contextHash, engine.ActorSystem).
+		Return(&models.CommitmentArtifact{ArtifactID: "art-denial"}, nil)
+
+	// Then DB, carrying the chain link
+	mockDB.On("CreateInstance", mock.Anything, mock.MatchedBy(func(inst *engine.Instance) bool {
+		return inst.State == engine.StateTerminated && inst.LastArtifactHash == "art-denial"
+	})).Return(nil)
+
+	future, err := env.ExecuteActivity(activities.PersistInstance, PersistInstanceInput{
+		InstanceID:      "inst-deny",
+		WorkflowID:      "wf-1",
+		TriggerContext:  trigger,
+		PolicyVersionID: "pol-1",
+		InitialState:    engine.StateTerminated,
+	})
+	assert.NoError(t, err)
+
+	var inst *engine.Instance
+	assert.NoError(t, future.Get(&inst))
+	assert.Equal(t, "art-denial", inst.LastArtifactHash)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+}
+
+func TestPersistInstance_EmitsGenesisArtifact(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	binding := models.PolicyBinding{InputHash: "in-hash", DecisionHash: "dec-hash"}
+	contextHash, _ := artifact.HashContext(nil)
+
+	// Instances that never pause still get an artifact trail, anchored at GenesisHash
+	// and bound to the policy evaluation that chose the initial state.
+	mockEmitter.On("EmitBoundArtifact", mock.Anything, "inst-run", models.GenesisHash, "RUNNING", "pol-1", contextHash, engine.ActorSystem, binding).
+		Return(&models.CommitmentArtifact{ArtifactID: "art-genesis"}, nil)
+	mockDB.On("CreateInstance", mock.Anything, mock.MatchedBy(func(inst *engine.Instance) bool {
+		return inst.State == engine.StateRunning && inst.LastArtifactHash == "art-genesis"
+	})).Return(nil)
+
+	_, err := env.ExecuteActivity(activities.PersistInstance, PersistInstanceInput{
+		InstanceID:      "inst-run",
+		WorkflowID:      "wf-1",
+		PolicyVersionID: "pol-1",
+		InitialState:    engine.StateRunning,
+		PolicyResult: map[string]interface{}{
+			engine.PolicyInputHashKey:    binding.InputHash,
+			engine.PolicyDecisionHashKey: binding.DecisionHash,
+		},
+	})
+	assert.NoError(t, err)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+}
+
+func TestPersistInstance_EmitFailureSkipsDB(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockEmitter.On("EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return((*models.CommitmentArtifact)(nil), errors.New("worm unavailable"))
+
+	// No artifact, no operational state (DB must never be ahead of the evidence).
+	_, err := env.ExecuteActivity(activities.PersistInstance, PersistInstanceInput{
+		InstanceID:   "inst-fail",
+		InitialState: engine.StateRunning,
+	})
+	assert.Error(t, err)
+	mockDB.AssertNotCalled(t, "CreateInstance", mock.Anything, mock.Anything)
+}
+
+func TestTransition_ChainsArtifactAndUpdatesDB(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateApproved,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: "art-approved",
+	}, nil)
+
+	contextHash, _ := artifact.HashContext(map[string]interface{}{
+		"from_state": "APPROVED",
+		"to_state":   "RESUMED",
+		"reason":     "approved",
+	})
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-1", "art-approved", "RESUMED", "pol-1", contextHash, engine.ActorSystem).
+		Return(&models.CommitmentArtifact{ArtifactID: "art-resumed", AuthorityState: "RESUMED"}, nil)
+
+	mockDB.On("TransitionInstance", mock.Anything, engine.TransitionCmd{
+		InstanceID:       "inst-1",
+		From:             engine.StateApproved,
+		To:               engine.StateResumed,
+		ActorID:          engine.ActorSystem,
+		Reason:           "approved",
+		PrevArtifactHash: "art-approved",
+		NewArtifactHash:  "art-resumed",
+	}).Return(&engine.Instance{}, nil)
+
+	val, err := env.ExecuteActivity(activities.Transition, TransitionInput{
+		InstanceID:  "inst-1",
+		TargetState: engine.StateResumed,
+		Reason:      "approved",
+	})
+	assert.NoError(t, err)
+
+	var art models.CommitmentArtifact
+	assert.NoError(t, val.Get(&art))
+	assert.Equal(t, "art-resumed", art.ArtifactID)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+}
+
+func TestTransition_IllegalTransitionEmitsNothing(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:    "inst-1",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+
+	// Skipping the decision is not a legal move; no evidence may be written for it.
+	_, err := env.ExecuteActivity(activities.Transition, TransitionInput{
+		InstanceID:  "inst-1",
+		TargetState: engine.StateCompleted,
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid transition")
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "TransitionInstance", mock.Anything, mock.Anything)
+}
+
+type MockChainGuard struct {
+	mock.Mock
+}
+
+func (m *MockChainGuard) EnsureChainHead(ctx context.Context, instanceID, artifactID string, attempt engine.ChainAttempt) (*models.CommitmentArtifact, error) {
+	args := m.Called(ctx, instanceID, artifactID, attempt)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
+}
+
+func (m *MockChainGuard) HeadArtifact(ctx context.Context, instanceID, artifactID string) (*models.CommitmentArtifact, error) {
+	args := m.Called(ctx, instanceID, artifactID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
+}
+
+func TestRecordDecision_AmbiguousHeadQuarantines(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	mockGuard := new(MockChainGuard)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+		Guard:           mockGuard,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateWaitingForHuman,
+		LastArtifactHash: "phantom-1",
+	}, nil)
+	mockGuard.On("EnsureChainHead", mock.Anything, "inst-1", "phantom-1", mock.Anything).
+		Return(nil, fmt.Errorf("%w: artifact phantom-1 not found", engine.ErrStateAmbiguous))
+	mockDB.On("QuarantineInstance", mock.Anything, mock.MatchedBy(func(cmd engine.QuarantineCmd) bool {
+		return cmd.InstanceID == "inst-1" && cmd.From == engine.StateWaitingForHuman && cmd.ArtifactHash == "phantom-1"
+	})).Return(&engine.Instance{ID: "inst-1", State: engine.StateQuarantined}, nil)
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:   "inst-1",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "user-1",
+	})
+
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, ErrTypeStateAmbiguous, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "RecordDecision", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransition_StoreOutageIsRetried(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockGuard := new(MockChainGuard)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: new(MockArtifactEmitter),
+		Guard:           mockGuard,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateApproved,
+		LastArtifactHash: "art-1",
+	}, nil)
+	mockGuard.On("EnsureChainHead", mock.Anything, "inst-1", "art-1", mock.Anything).Return(nil, errors.New("bucket offline"))
+
+	_, err := env.ExecuteActivity(activities.Transition, TransitionInput{
+		InstanceID:  "inst-1",
+		TargetState: engine.StateResumed,
+	})
+
+	var appErr *temporal.ApplicationError
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &appErr) && appErr.NonRetryable(), "an outage must stay retryable")
+	mockDB.AssertNotCalled(t, "QuarantineInstance", mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "TransitionInstance", mock.Anything, mock.Anything)
+}
+
+// adoptingEmitter records the artifacts it is asked to adopt (artifact.Adopter).
+type adoptingEmitter struct {
+	*MockArtifactEmitter
+	adopted []string
+}
+
+func (e *adoptingEmitter) Adopt(ctx context.Context, art *models.CommitmentArtifact) error {
+	e.adopted = append(e.adopted, art.ArtifactID)
+	return nil
+}
+
+func TestRecordDecision_RetryAdoptsOrphanedArtifact(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	mockGuard := new(MockChainGuard)
+	emitter := &adoptingEmitter{MockArtifactEmitter: mockEmitter}
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: emitter,
+		Guard:           mockGuard,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	input := RecordDecisionInput{
+		InstanceID:    "inst-1",
+		DecisionType:  engine.DecisionApprove,
+		ActorID:       "user-1",
+		Justification: "ok",
+		Role:          "admin",
+	}
+	contextHash, err := DecisionContextHash(input)
+	assert.NoError(t, err)
+
+	// The first attempt emitted art-2, then its DB commit failed: the database head is
+	// still art-1 and the store holds art-2 chained from it.
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: "art-1",
+	}, nil)
+	attempt := engine.ChainAttempt{State: engine.StateApproved, PolicyVersionID: "pol-1", ContextHash: contextHash, ActorID: "user-1"}
+	orphan := &models.CommitmentArtifact{
+		ArtifactID:       "art-2",
+		InstanceID:       "inst-1",
+		PrevArtifactHash: "art-1",
+		AuthorityState:   "APPROVED",
+		PolicyVersionID:  "pol-1",
+		ContextHash:      contextHash,
+		HumanActorID:     "user-1",
+	}
+	mockGuard.On("EnsureChainHead", mock.Anything, "inst-1", "art-1", attempt).Return(orphan, nil)
+	mockDB.On("RecordDecision", mock.Anything, mock.MatchedBy(func(cmd engine.RecordDecisionCmd) bool {
+		return cmd.NewArtifactHash == "art-2"
+	}), engine.StateApproved).Return(&engine.Instance{ID: "inst-1", State: engine.StateApproved}, nil)
+
+	future, err := env.ExecuteActivity(activities.RecordDecision, input)
+	assert.NoError(t, err)
+
+	var art *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&art))
+	assert.Equal(t, "art-2", art.ArtifactID)
+	assert.Equal(t, []string{"art-2"}, emitter.adopted, "the adopted artifact must be finished (logged) first")
+	mockDB.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "QuarantineInstance", mock.Anything, mock.Anything)
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransition_RetryAfterCommitReturnsRecordedArtifact(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	mockGuard := new(MockChainGuard)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+		Guard:           mockGuard,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	// The first attempt committed APPROVED -> RESUMED as art-2, but its response was lost.
+	contextHash, err := transitionContextHash(engine.StateApproved, engine.StateResumed, "approved")
+	assert.NoError(t, err)
+	recorded := &models.CommitmentArtifact{
+		ArtifactID:       "art-2",
+		InstanceID:       "inst-1",
+		PrevArtifactHash: "art-1",
+		AuthorityState:   "RESUMED",
+		PolicyVersionID:  "pol-1",
+		ContextHash:      contextHash,
+		HumanActorID:     engine.ActorSystem,
+	}
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateResumed,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: "art-2",
+	}, nil)
+	mockGuard.On("HeadArtifact", mock.Anything, "inst-1", "art-2").Return(recorded, nil)
+
+	future, err := env.ExecuteActivity(activities.Transition, TransitionInput{
+		InstanceID:  "inst-1",
+		TargetState: engine.StateResumed,
+		Reason:      "approved",
+	})
+	assert.NoError(t, err)
+
+	var art *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&art))
+	assert.Equal(t, "art-2", art.ArtifactID)
+	mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "TransitionInstance", mock.Anything, mock.Anything)
+
+	// A head recorded by something else (another reason) leaves the move invalid.
+	_, err = env.ExecuteActivity(activities.Transition, TransitionInput{
+		InstanceID:  "inst-1",
+		TargetState: engine.StateResumed,
+		Reason:      "overridden",
+	})
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "InvalidTransition", appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
+
+// memoryInstanceStore adapts engine.MemoryStore, which keeps no audit log, to ports.InstanceStore.
+type memoryInstanceStore struct {
+	*engine.MemoryStore
+}
+
+func (memoryInstanceStore) GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error) {
+	return nil, nil
+}
+
+// storeBacked is the activities wired to an in-memory database, a local artifact store
+// and the real chain guard, so chain checks run against stored evidence.
+type storeBacked struct {
+	activities *ExecutionActivities
+	db         *engine.MemoryStore
+	store      *local.Store
+	manager    *artifact.Manager
+}
+
+func newStoreBacked(t *testing.T) storeBacked {
+	t.Helper()
+	store, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := engine.NewMemoryStore()
+	manager := artifact.NewManager(store)
+	return storeBacked{
+		activities: &ExecutionActivities{DB: memoryInstanceStore{db}, ArtifactEmitter: manager, Guard: authority.NewConsistencyGuard(store)},
+		db:         db,
+		store:      store,
+		manager:    manager,
+	}
+}
+
+// waitingInstance creates inst-1 waiting for a decision at a stored genesis artifact.
+func (f storeBacked) waitingInstance(t *testing.T) *engine.Instance {
+	t.Helper()
+	ctx := context.Background()
+	genesis, err := f.manager.EmitArtifact(ctx, "inst-1", models.GenesisHash, "WAITING_FOR_HUMAN", "pol-1", "ctx-0", engine.ActorSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inst := &engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: genesis.ArtifactID,
+	}
+	if err := f.db.CreateInstance(ctx, inst); err != nil {
+		t.Fatal(err)
+	}
+	return inst
+}
+
+// artifactCount is the number of artifacts stored for inst-1.
+func (f storeBacked) artifactCount(t *testing.T) int {
+	t.Helper()
+	arts, err := f.store.ListByInstance(context.Background(), "inst-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(arts)
+}
+
+func TestTransition_ForkedHeadQuarantinesFromStoredState(t *testing.T) {
+	f := newStoreBacked(t)
+	activities, db := f.activities, f.db
+	ctx := context.Background()
+
+	head, err := f.manager.EmitArtifact(ctx, "inst-1", models.GenesisHash, "APPROVED", "pol-1", "ctx-1", "user-1")
+	assert.NoError(t, err)
+	assert.NoError(t, db.CreateInstance(ctx, &engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateApproved,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: head.ArtifactID,
+	}))
+	// Another writer already chained a different artifact from the head the database holds.
+	_, err = f.manager.EmitArtifact(ctx, "inst-1", head.ArtifactID, "RESUMED", "pol-1", "ctx-other", engine.ActorSystem)
+	assert.NoError(t, err)
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	_, err = env.ExecuteActivity(activities.Transition, TransitionInput{
+		InstanceID:  "inst-1",
+		TargetState: engine.StateResumed,
+		Reason:      "approved",
+	})
+
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, ErrTypeStateAmbiguous, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+
+	inst, err := db.GetInstance(ctx, "inst-1")
+	assert.NoError(t, err)
+	assert.Equal(t, engine.StateQuarantined, inst.State)
+	assert.Equal(t, head.ArtifactID, inst.LastArtifactHash)
+}
+
+func TestRecordDecision_RetryAfterCommitReturnsRecordedArtifact(t *testing.T) {
+	f := newStoreBacked(t)
+	f.waitingInstance(t)
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(f.activities)
+
+	input := RecordDecisionInput{
+		InstanceID:    "inst-1",
+		DecisionType:  engine.DecisionApprove,
+		ActorID:       "user-1",
+		Justification: "ok",
+	}
+	future, err := env.ExecuteActivity(f.activities.RecordDecision, input)
+	assert.NoError(t, err)
+	var first *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&first))
+
+	// The first attempt committed but its response was lost: Temporal runs it again.
+	future, err = env.ExecuteActivity(f.activities.RecordDecision, input)
+	assert.NoError(t, err)
+	var retried *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&retried))
+	assert.Equal(t, first.ArtifactID, retried.ArtifactID)
+	assert.Equal(t, 2, f.artifactCount(t), "the retry must not chain a second decision")
+
+	// Any other decision on the decided instance is refused without evidence.
+	input.DecisionType = engine.DecisionReject
+	_, err = env.ExecuteActivity(f.activities.RecordDecision, input)
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, "InvalidTransition", appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+	assert.Equal(t, 2, f.artifactCount(t))
+}
+
+func TestRecordDecision_StaleWriteIsNotRetried(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateWaitingForHuman,
+		LastArtifactHash: "art-1",
+	}, nil)
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-1", "art-1", "APPROVED", mock.Anything, mock.Anything, "user-1").
+		Return(&models.CommitmentArtifact{ArtifactID: "art-2"}, nil)
+	mockDB.On("RecordDecision", mock.Anything, mock.Anything, engine.StateApproved).
+		Return((*engine.Instance)(nil), fmt.Errorf("%w: inst-1", engine.ErrStaleInstance))
+
+	_, err := env.ExecuteActivity(activities.RecordDecision, RecordDecisionInput{
+		InstanceID:   "inst-1",
+		DecisionType: engine.DecisionApprove,
+		ActorID:      "user-1",
+	})
+
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, ErrTypeStaleInstance, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
@@ -0,0 +1,183 @@
+package activities
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+)
+
+// RecordVoteInput defines one vote of a multi-party decision.
+type RecordVoteInput struct {
+	Decision RecordDecisionInput `json:"decision"`        // The HumanDecision signal carrying the vote
+	Group    string              `json:"group,omitempty"` // Approver group the vote counts for (engine.Tally.Admit)
+}
+
+// VoteContextHash is the context hash of a vote artifact. The artifact keeps the instance
+// WAITING_FOR_HUMAN, so the vote itself (decision and group) is bound through the context.
+func VoteContextHash(input RecordVoteInput) (string, error) {
+	decisionHash, err := DecisionContextHash(input.Decision)
+	if err != nil {
+		return "", err
+	}
+	return artifact.HashContext(map[string]interface{}{
+		"vote":         string(input.Decision.DecisionType),
+		"group":        input.Group,
+		"context_hash": decisionHash,
+	})
+}
+
+// RecordVote records one approver's vote as a chained artifact. The instance stays
+// WAITING_FOR_HUMAN until RecordQuorumDecision records the outcome.
+func (a *ExecutionActivities) RecordVote(ctx context.Context, input RecordVoteInput) (*models.CommitmentArtifact, error) {
+	logger := activity.GetLogger(ctx)
+	d := input.Decision
+	logger.Info("Recording vote", "instance_id", d.InstanceID, "actor_id", d.ActorID, "type", d.DecisionType, "group", input.Group)
+
+	// 1. Fetch Current Instance State (chain head)
+	instance, err := a.DB.GetInstance(ctx, d.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance for chaining: %w", err)
+	}
+	if err := checkActor(instance, d); err != nil {
+		return nil, err
+	}
+	if instance.State != engine.StateWaitingForHuman {
+		err := engine.ErrInvalidTransition{From: instance.State, To: engine.StateWaitingForHuman}
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "InvalidTransition", err)
+	}
+	if err := checkAuthorization(instance, d); err != nil {
+		return nil, err
+	}
+	prevHash := instance.LastArtifactHash
+
+	// 2. Emit Commitment Artifact (Evidence)
+	contextHash, err := VoteContextHash(input)
+	if err != nil {
+		return nil, err
+	}
+	// The instance keeps waiting after a vote, so a retry whose earlier attempt committed
+	// (but whose response was lost) finds its own vote at the head: return it.
+	attempt := engine.ChainAttempt{State: engine.StateWaitingForHuman, PolicyVersionID: instance.PolicyVersionID, ContextHash: contextHash, ActorID: d.ActorID}
+	if recorded, err := a.recordedAttempt(ctx, instance, attempt); err != nil || recorded != nil {
+		return recorded, err
+	}
+	art, err := a.emitChained(ctx, instance, engine.StateWaitingForHuman, instance.PolicyVersionID, contextHash, d.ActorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Persist to DB (evidence first, as in RecordDecision)
+	cmd := engine.RecordVoteCmd{
+		InstanceID: d.InstanceID,
+		Vote: engine.Vote{
+			ActorID:    d.ActorID,
+			Decision:   d.DecisionType,
+			Group:      input.Group,
+			ArtifactID: art.ArtifactID,
+		},
+		Role:             d.recordedRole(),
+		Justification:    d.Justification,
+		PrevArtifactHash: prevHash,
+	}
+	if _, err := a.DB.RecordVote(ctx, cmd); err != nil {
+		return nil, fmt.Errorf("failed to record vote in DB: %w", err)
+	}
+
+	logger.Info("Vote recorded", "instance_id", d.InstanceID, "actor_id", d.ActorID, "artifact_id", art.ArtifactID)
+	return art, nil
+}
+
+// QuorumDecisionInput defines the aggregate outcome of a multi-party decision.
+type QuorumDecisionInput struct {
+	InstanceID   string              `json:"instance_id"`
+	DecisionType engine.DecisionType `json:"decision_type"` // APPROVE (quorum met) or REJECT
+	Rule         engine.QuorumRule   `json:"rule"`
+	Votes        []engine.Vote       `json:"votes"` // In the order they were recorded
+}
+
+// QuorumContextHash is the context hash of an aggregate artifact: the rule and every
+// vote, each referencing its own vote artifact.
+func QuorumContextHash(input QuorumDecisionInput) (string, error) {
+	votes := make([]interface{}, len(input.Votes))
+	for i, v := range input.Votes {
+		votes[i] = map[string]interface{}{
+			"actor_id":    v.ActorID,
+			"decision":    string(v.Decision),
+			"group":       v.Group,
+			"artifact_id": v.ArtifactID,
+		}
+	}
+	approvers := make([]interface{}, len(input.Rule.Approvers))
+	for i, g := range input.Rule.Approvers {
+		approvers[i] = g
+	}
+	return artifact.HashContext(map[string]interface{}{
+		"decision":  string(input.DecisionType),
+		"approvers": approvers,
+		"quorum":    input.Rule.Quorum,
+		"votes":     votes,
+	})
+}
+
+// RecordQuorumDecision records the outcome of a multi-party decision as the instance's
+// decision (WAITING_FOR_HUMAN -> APPROVED/REJECTED). The artifact's actor is
+// engine.QuorumActorID of the deciding votes.
+func (a *ExecutionActivities) RecordQuorumDecision(ctx context.Context, input QuorumDecisionInput) (*models.CommitmentArtifact, error) {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Recording quorum decision", "instance_id", input.InstanceID, "type", input.DecisionType, "votes", len(input.Votes))
+
+	tally := engine.Tally{Rule: input.Rule, Votes: input.Votes}
+	if outcome, decided := tally.Outcome(); !decided || outcome != input.DecisionType {
+		err := fmt.Errorf("votes do not support decision %s", input.DecisionType)
+		return nil, temporal.NewNonRetryableApplicationError(err.Error(), "QuorumNotMet", err)
+	}
+
+	instance, err := a.DB.GetInstance(ctx, input.InstanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch instance for chaining: %w", err)
+	}
+	nextState, err := engine.CalculateNextState(input.DecisionType)
+	if err != nil {
+		return nil, err
+	}
+
+	contextHash, err := QuorumContextHash(input)
+	if err != nil {
+		return nil, err
+	}
+	actorID := engine.QuorumActorID(input.Votes, input.DecisionType)
+	// As in RecordDecision, a retry after a committed attempt returns that attempt's artifact.
+	if instance.State != engine.StateWaitingForHuman {
+		attempt := engine.ChainAttempt{State: nextState, PolicyVersionID: instance.PolicyVersionID, ContextHash: contextHash, ActorID: actorID}
+		return a.recordedDecision(ctx, instance, attempt)
+	}
+	art, err := a.emitChained(ctx, instance, nextState, instance.PolicyVersionID, contextHash, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	justification := fmt.Sprintf("Quorum met: %d of %d required approvals", len(input.Votes), input.Rule.Quorum)
+	if input.DecisionType == engine.DecisionReject {
+		justification = "Rejected by approver vote"
+	}
+	cmd := engine.RecordDecisionCmd{
+		InstanceID:       input.InstanceID,
+		Type:             input.DecisionType,
+		ActorID:          actorID,
+		Justification:    justification,
+		Role:             engine.RoleQuorum,
+		PolicyVersionID:  instance.PolicyVersionID,
+		NewArtifactHash:  art.ArtifactID,
+		PrevArtifactHash: instance.LastArtifactHash,
+	}
+	if _, err := a.DB.RecordDecision(ctx, cmd, nextState); err != nil {
+		return nil, decisionWriteError(err)
+	}
+
+	return art, nil
+}
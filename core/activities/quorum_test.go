@@ -0,0 +1,185 @@
+package activities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestRecordVote_ChainsArtifactWithoutDeciding(t *testing.T) {
+	mockDB := new(MockInstanceStore)
+	mockEmitter := new(MockArtifactEmitter)
+	activities := &ExecutionActivities{
+		DB:              mockDB,
+		ArtifactEmitter: mockEmitter,
+	}
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+		ID:               "inst-1",
+		State:            engine.StateWaitingForHuman,
+		PolicyVersionID:  "pol-1",
+		LastArtifactHash: "art-genesis",
+	}, nil)
+
+	input := RecordVoteInput{
+		Decision: RecordDecisionInput{
+			InstanceID:   "inst-1",
+			DecisionType: engine.DecisionApprove,
+			ActorID:      "alice",
+			Role:         "group:engineering",
+		},
+		Group: "group:engineering",
+	}
+	contextHash, _ := VoteContextHash(input)
+	mockEmitter.On("EmitArtifact", mock.Anything, "inst-1", "art-genesis", "WAITING_FOR_HUMAN", "pol-1", contextHash, "alice").
+		Return(&models.CommitmentArtifact{ArtifactID: "art-vote-1", AuthorityState: "WAITING_FOR_HUMAN"}, nil)
+	mockDB.On("RecordVote", mock.Anything, engine.RecordVoteCmd{
+		InstanceID: "inst-1",
+		Vote: engine.Vote{
+			ActorID:    "alice",
+			Decision:   engine.DecisionApprove,
+			Group:      "group:engineering",
+			ArtifactID: "art-vote-1",
+		},
+		Role:             "group:engineering",
+		PrevArtifactHash: "art-genesis",
+	}).Return(&engine.Instance{}, nil)
+
+	val, err := env.ExecuteActivity(activities.RecordVote, input)
+	assert.NoError(t, err)
+
+	var art models.CommitmentArtifact
+	assert.NoError(t, val.Get(&art))
+	assert.Equal(t, "art-vote-1", art.ArtifactID)
+	mockDB.AssertNotCalled(t, "RecordDecision", mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+	mockEmitter.AssertExpectations(t)
+
+	// The vote is bound to its group: the same approval for another group hashes differently.
+	other := input
+	other.Group = "group:compliance"
+	otherHash, _ := VoteContextHash(other)
+	assert.NotEqual(t, contextHash, otherHash)
+}
+
+func TestRecordQuorumDecision(t *testing.T) {
+	rule := engine.NewQuorumRule([]string{"group:engineering", "group:compliance"}, 2)
+	votes := []engine.Vote{
+		{ActorID: "alice", Decision: engine.DecisionApprove, Group: "group:engineering", ArtifactID: "art-vote-1"},
+		{ActorID: "carol", Decision: engine.DecisionApprove, Group: "group:compliance", ArtifactID: "art-vote-2"},
+	}
+
+	t.Run("quorum met records the aggregate decision", func(t *testing.T) {
+		mockDB := new(MockInstanceStore)
+		mockEmitter := new(MockArtifactEmitter)
+		activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+		s := &testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(activities)
+
+		mockDB.On("GetInstance", mock.Anything, "inst-1").Return(&engine.Instance{
+			ID:               "inst-1",
+			State:            engine.StateWaitingForHuman,
+			PolicyVersionID:  "pol-1",
+			LastArtifactHash: "art-vote-2",
+		}, nil)
+
+		input := QuorumDecisionInput{InstanceID: "inst-1", DecisionType: engine.DecisionApprove, Rule: rule, Votes: votes}
+		contextHash, _ := QuorumContextHash(input)
+		mockEmitter.On("EmitArtifact", mock.Anything, "inst-1", "art-vote-2", "APPROVED", "pol-1", contextHash, "quorum:alice,carol").
+			Return(&models.CommitmentArtifact{ArtifactID: "art-approved", AuthorityState: "APPROVED"}, nil)
+		mockDB.On("RecordDecision", mock.Anything, mock.MatchedBy(func(cmd engine.RecordDecisionCmd) bool {
+			return cmd.ActorID == "quorum:alice,carol" && cmd.Role == engine.RoleQuorum && cmd.NewArtifactHash == "art-approved"
+		}), engine.StateApproved).Return(&engine.Instance{}, nil)
+
+		_, err := env.ExecuteActivity(activities.RecordQuorumDecision, input)
+		assert.NoError(t, err)
+		mockDB.AssertExpectations(t)
+		mockEmitter.AssertExpectations(t)
+	})
+
+	t.Run("quorum not met emits nothing", func(t *testing.T) {
+		mockDB := new(MockInstanceStore)
+		mockEmitter := new(MockArtifactEmitter)
+		activities := &ExecutionActivities{DB: mockDB, ArtifactEmitter: mockEmitter}
+		s := &testsuite.WorkflowTestSuite{}
+		env := s.NewTestActivityEnvironment()
+		env.RegisterActivity(activities)
+
+		_, err := env.ExecuteActivity(activities.RecordQuorumDecision, QuorumDecisionInput{
+			InstanceID:   "inst-1",
+			DecisionType: engine.DecisionApprove,
+			Rule:         rule,
+			Votes:        votes[:1],
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "votes do not support decision")
+		mockDB.AssertNotCalled(t, "GetInstance", mock.Anything, mock.Anything)
+		mockEmitter.AssertNotCalled(t, "EmitArtifact", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestRecordQuorumDecision_RetryAfterCommitReturnsRecordedArtifact(t *testing.T) {
+	f := newStoreBacked(t)
+	f.waitingInstance(t)
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(f.activities)
+
+	input := QuorumDecisionInput{
+		InstanceID:   "inst-1",
+		DecisionType: engine.DecisionApprove,
+		Rule:         engine.NewQuorumRule([]string{"group:engineering"}, 1),
+		Votes:        []engine.Vote{{ActorID: "alice", Decision: engine.DecisionApprove, Group: "group:engineering", ArtifactID: "art-vote-1"}},
+	}
+	future, err := env.ExecuteActivity(f.activities.RecordQuorumDecision, input)
+	assert.NoError(t, err)
+	var first *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&first))
+
+	future, err = env.ExecuteActivity(f.activities.RecordQuorumDecision, input)
+	assert.NoError(t, err)
+	var retried *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&retried))
+	assert.Equal(t, first.ArtifactID, retried.ArtifactID)
+	assert.Equal(t, 2, f.artifactCount(t), "the retry must not chain a second decision")
+}
+
+func TestRecordVote_RetryAfterCommitReturnsRecordedArtifact(t *testing.T) {
+	f := newStoreBacked(t)
+	f.waitingInstance(t)
+
+	s := &testsuite.WorkflowTestSuite{}
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(f.activities)
+
+	input := RecordVoteInput{
+		Decision: RecordDecisionInput{InstanceID: "inst-1", DecisionType: engine.DecisionApprove, ActorID: "alice"},
+		Group:    "group:engineering",
+	}
+	future, err := env.ExecuteActivity(f.activities.RecordVote, input)
+	assert.NoError(t, err)
+	var first *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&first))
+
+	future, err = env.ExecuteActivity(f.activities.RecordVote, input)
+	assert.NoError(t, err)
+	var retried *models.CommitmentArtifact
+	assert.NoError(t, future.Get(&retried))
+	assert.Equal(t, first.ArtifactID, retried.ArtifactID)
+	assert.Equal(t, 2, f.artifactCount(t), "the retry must not chain a second vote")
+
+	inst, err := f.db.GetInstance(context.Background(), "inst-1")
+	assert.NoError(t, err)
+	assert.Equal(t, first.ArtifactID, inst.LastArtifactHash)
+}
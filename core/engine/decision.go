@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -15,6 +16,16 @@ const (
 	DecisionOverride DecisionType = "OVERRIDE"
 )
 
+// ActorSystem is the actor identity used for transitions authored by Gantral itself
+// (policy denials, approval timeouts).
+const ActorSystem = "SYSTEM"
+
+// PolicyContext keys holding the policy evaluation hashes bound into v2 artifacts.
+const (
+	PolicyInputHashKey    = "policy_input_hash"
+	PolicyDecisionHashKey = "policy_decision_hash"
+)
+
 // CalculateNextState determines the target state based on the decision type.
 // This logic is shared between the Engine (transactional update) and Activities (Artifact emission).
 func CalculateNextState(decisionType DecisionType) (State, error) {
@@ -41,6 +52,14 @@ type RecordDecisionCmd struct {
 	ContextDelta    map[string]interface{}
 	PolicyVersionID string
 	NewArtifactHash string // The hash of the artifact emitted for this decision (for chain linking)
+	// PrevArtifactHash is the chain head the artifact was chained from. Stores only record
+	// the decision while the instance is still WAITING_FOR_HUMAN at that head (ErrStaleInstance).
+	PrevArtifactHash string
+
+	Roles       []string       // Every role of the actor; Role must be one the policy allows
+	Identity    *ActorIdentity // The authenticated identity; ActorID must be its binding
+	ActorOrgID  string         // The actor's org (team), for separation of duties
+	PriorActors []string       // Humans who already decided a stage of the instance
 }
 
 // ValidateDecision enforces HITL invariants on a decision command against an instance state.
@@ -70,12 +89,19 @@ func ValidateDecision(instance *Instance, cmd RecordDecisionCmd) error {
 	if strings.TrimSpace(cmd.ActorID) == "" {
 		return fmt.Errorf("missing actor identity")
 	}
+	if err := CheckActor(cmd.Identity, cmd.ActorID, IdentityRequiredFor(instance)); err != nil {
+		return err
+	}
 
-	// Section B: Role mismatch (basic check, assuming role is required if present in cmd,
-	// though RBAC might be deeper. Enforcing non-empty role if system requires it).
-	// For Tier 1, we assert that if Role is provided, it's not empty string?
-	// The prompt says "Role mismatch -> reject".
-	// Without RBAC definitions, I will enforce ActorID presence as "Missing identity".
+	// Separation of duties, as pinned on the instance from its policy
+	if err := CheckSeparationOfDuties(SeparationOfDutiesFor(instance), instance, cmd.ActorID, cmd.ActorOrgID, cmd.PriorActors); err != nil {
+		return err
+	}
+
+	// Section B: Role mismatch -> reject, against the policy's approver and override roles
+	if err := CheckRole(RoleRulesFor(instance), cmd.Type, cmd.ActorID, cmd.Role, HeldRoles(cmd.Identity, cmd.Roles)); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -90,6 +116,9 @@ func (e *Engine) RecordDecision(ctx context.Context, cmd RecordDecisionCmd) (*In
 
 	// 2. Validate
 	if err := ValidateDecision(instance, cmd); err != nil {
+		if errors.Is(err, ErrSeparationOfDuties) || errors.Is(err, ErrRoleNotAuthorized) || errors.Is(err, ErrActorMismatch) {
+			e.recordRejection(ctx, cmd, err)
+		}
 		return nil, err
 	}
 
@@ -98,6 +127,7 @@ func (e *Engine) RecordDecision(ctx context.Context, cmd RecordDecisionCmd) (*In
 		return nil, err
 	}
 
-	// 3. Delegate to Store for Transactional Update
+	// 3. Delegate to Store for Transactional Update, against the instance just validated
+	cmd.PrevArtifactHash = instance.LastArtifactHash
 	return e.store.RecordDecision(ctx, cmd, nextState)
 }
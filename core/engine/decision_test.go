@@ -2,6 +2,7 @@ package engine
 
 import (
 	"context"
+	"errors"
 	"testing"
 )
 
@@ -135,3 +136,24 @@ func TestRecordDecision_MissingJustification(t *testing.T) {
 	// Let's enforce for all for consistency, or strictly follow user. User asked "If ANY of the above are false".
 	// Let's stick to Approve/Override for now.
 }
+
+func TestMemoryStore_RecordDecisionCompareAndSet(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	_ = store.CreateInstance(ctx, &Instance{ID: "inst-1", State: StateWaitingForHuman, LastArtifactHash: "art-1"})
+
+	// A concurrent writer chained art-2 from art-1 and committed first.
+	if _, err := store.RecordDecision(ctx, RecordDecisionCmd{InstanceID: "inst-1", PrevArtifactHash: "art-1", NewArtifactHash: "art-2"}, StateApproved); err != nil {
+		t.Fatalf("RecordDecision: %v", err)
+	}
+
+	// The losing writer also chained from art-1: recording it would fork the chain.
+	_, err := store.RecordDecision(ctx, RecordDecisionCmd{InstanceID: "inst-1", PrevArtifactHash: "art-1", NewArtifactHash: "art-3"}, StateRejected)
+	if !errors.Is(err, ErrStaleInstance) {
+		t.Errorf("expected ErrStaleInstance, got %v", err)
+	}
+	got, _ := store.GetInstance(ctx, "inst-1")
+	if got.State != StateApproved || got.LastArtifactHash != "art-2" {
+		t.Errorf("losing decision must not be applied: %+v", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
 )
 
 // InstanceStore defines the persistence layer requirements.
@@ -20,43 +22,69 @@ type InstanceStore interface {
 type Engine struct {
 	policyEngine *policy.Engine
 	store        InstanceStore
+	emitter      artifact.ArtifactEmitter // Optional: records system decisions (e.g. policy DENY)
 }
 
 // NewEngine creates a new instance of the Engine.
 func NewEngine(store InstanceStore) *Engine {
+	return NewEngineWithPolicy(store, policy.NewEngine())
+}
+
+// NewEngineWithPolicy creates an Engine that evaluates policies with the given Policy Engine.
+func NewEngineWithPolicy(store InstanceStore, policyEngine *policy.Engine) *Engine {
 	return &Engine{
-		policyEngine: policy.NewEngine(),
+		policyEngine: policyEngine,
 		store:        store,
 	}
 }
 
+// SetArtifactEmitter configures the emitter used to record system-authored transitions.
+func (e *Engine) SetArtifactEmitter(emitter artifact.ArtifactEmitter) {
+	e.emitter = emitter
+}
+
 // CreateInstance starts a new execution instance.
 func (e *Engine) CreateInstance(ctx context.Context, workflowID string, triggerContext map[string]interface{}, pol policy.Policy) (*Instance, error) {
 	// 1. Evaluate Policy
-	evalResult, err := e.policyEngine.Evaluate(ctx, pol)
+	policyInput := policy.NewInput(workflowID, pol, triggerContext)
+	evalResult, err := e.policyEngine.EvaluateInput(ctx, policyInput)
 	if err != nil {
 		return nil, fmt.Errorf("policy evaluation failed: %w", err)
 	}
+	policyContext, err := NewPolicyContext(pol.ID, policyInput, evalResult)
+	if err != nil {
+		return nil, err
+	}
 
 	// 2. Determine Initial State
+	// ADR-007: REQUIRE_HUMAN diverts to WAITING_FOR_HUMAN, DENY diverts to TERMINATED.
 	initialState := StateRunning
 	if evalResult.ShouldPause {
 		initialState = StateWaitingForHuman
 	}
+	if evalResult.Decision == policy.DecisionDeny {
+		initialState = StateTerminated
+	}
 
 	// 3. Create Instance Record
 	instance := &Instance{
-		ID:             fmt.Sprintf("inst-%d", time.Now().UnixNano()), // Simple unique ID
-		WorkflowID:     workflowID,
-		State:          initialState,
-		TriggerContext: triggerContext,
-		PolicyContext: map[string]interface{}{
-			"policy_id": pol.ID,
-			"decision":  evalResult.NextState,
-			"reason":    evalResult.Reason,
-		},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:              fmt.Sprintf("inst-%d", time.Now().UnixNano()), // Simple unique ID
+		WorkflowID:      workflowID,
+		State:           initialState,
+		TriggerContext:  triggerContext,
+		PolicyContext:   policyContext,
+		PolicyVersionID: pol.ID,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+
+	// 3b. Record the genesis artifact (policy outcome) before it becomes operational state.
+	if e.emitter != nil {
+		art, err := EmitGenesis(ctx, e.emitter, instance)
+		if err != nil {
+			return nil, err
+		}
+		instance.LastArtifactHash = art.ArtifactID
 	}
 
 	// 4. Store via Interface
@@ -67,6 +95,84 @@ func (e *Engine) CreateInstance(ctx context.Context, workflowID string, triggerC
 	return instance, nil
 }
 
+// NewPolicyContext records a policy evaluation on the instance, including the
+// input and decision hashes that later artifacts bind (artifact schema v2).
+func NewPolicyContext(policyID string, in policy.Input, result policy.EvaluationResult) (map[string]interface{}, error) {
+	inputHash, err := policy.HashInput(in)
+	if err != nil {
+		return nil, err
+	}
+	decisionHash, err := policy.HashResult(result)
+	if err != nil {
+		return nil, err
+	}
+	policyContext := map[string]interface{}{
+		"policy_id":           policyID,
+		"decision":            string(result.Decision),
+		"next_state":          result.NextState,
+		"reason":              result.Reason,
+		PolicyInputHashKey:    inputHash,
+		PolicyDecisionHashKey: decisionHash,
+	}
+	if in.Policy.SeparationOfDuties.Enabled() {
+		policyContext[SeparationOfDutiesKey] = in.Policy.SeparationOfDuties
+	}
+	approverRoles, overrideRoles := policy.AuthorizedRoles(in.Policy, result)
+	if len(approverRoles) > 0 {
+		policyContext[ApproverRolesKey] = approverRoles
+	}
+	if len(overrideRoles) > 0 {
+		policyContext[OverrideRolesKey] = overrideRoles
+	}
+	if in.Policy.Escalation != nil {
+		policyContext[EscalationKey] = in.Policy.Escalation
+	}
+	return policyContext, nil
+}
+
+// PolicyBindingFor returns the policy evaluation hashes recorded on the instance.
+// ok is false for instances created before hashes were recorded.
+func PolicyBindingFor(inst *Instance) (binding models.PolicyBinding, ok bool) {
+	binding.InputHash, _ = inst.PolicyContext[PolicyInputHashKey].(string)
+	binding.DecisionHash, _ = inst.PolicyContext[PolicyDecisionHashKey].(string)
+	return binding, binding.InputHash != "" && binding.DecisionHash != ""
+}
+
+// EmitInstanceArtifact emits the next artifact in the instance's chain.
+// An instance without artifacts chains from models.GenesisHash.
+// When the instance carries a policy binding the artifact is v2; otherwise it falls back to v1.
+func EmitInstanceArtifact(ctx context.Context, emitter artifact.ArtifactEmitter, inst *Instance, state State, policyVersionID, contextHash, actorID string) (*models.CommitmentArtifact, error) {
+	prevHash := inst.LastArtifactHash
+	if prevHash == "" {
+		prevHash = models.GenesisHash
+	}
+	if binding, ok := PolicyBindingFor(inst); ok {
+		return emitter.EmitBoundArtifact(ctx, inst.ID, prevHash, string(state), policyVersionID, contextHash, actorID, binding)
+	}
+	return emitter.EmitArtifact(ctx, inst.ID, prevHash, string(state), policyVersionID, contextHash, actorID)
+}
+
+// EmitGenesis emits the first, SYSTEM-authored artifact of an instance, chained from models.GenesisHash.
+// It records the policy evaluation outcome: the initial state (RUNNING, WAITING_FOR_HUMAN,
+// or TERMINATED on DENY) bound to the policy input and decision hashes.
+// It is shared between the Engine and Activities so both paths produce identical evidence.
+func EmitGenesis(ctx context.Context, emitter artifact.ArtifactEmitter, inst *Instance) (*models.CommitmentArtifact, error) {
+	if inst.LastArtifactHash != "" {
+		return nil, fmt.Errorf("genesis artifact already emitted for instance %s", inst.ID)
+	}
+
+	contextHash, err := artifact.HashContext(inst.TriggerContext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash context: %w", err)
+	}
+
+	art, err := EmitInstanceArtifact(ctx, emitter, inst, inst.State, inst.PolicyVersionID, contextHash, ActorSystem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to emit genesis artifact: %w", err)
+	}
+	return art, nil
+}
+
 // GetInstance retrieves an instance by ID.
 func (e *Engine) GetInstance(ctx context.Context, id string) (*Instance, error) {
 	return e.store.GetInstance(ctx, id)
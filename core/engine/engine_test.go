@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/pkg/models"
 )
 
 func TestNewEngine(t *testing.T) {
@@ -91,3 +92,52 @@ func TestRecordDecision_InvalidState(t *testing.T) {
 		t.Error("expected error for instance not in WAITING_FOR_HUMAN state")
 	}
 }
+
+type denyBackend struct{}
+
+func (denyBackend) EvaluateInput(ctx context.Context, in policy.Input) (policy.EvaluationResult, error) {
+	return policy.ResultForDecision(policy.DecisionDeny, "denied by test"), nil
+}
+
+type recordingEmitter struct {
+	states   []string
+	actors   []string
+	bindings []models.PolicyBinding
+}
+
+func (r *recordingEmitter) EmitArtifact(ctx context.Context, instanceID, prevHash, state, policyVer, contextHash, actorID string) (*models.CommitmentArtifact, error) {
+	r.states = append(r.states, state)
+	r.actors = append(r.actors, actorID)
+	return &models.CommitmentArtifact{ArtifactID: "art-denial", InstanceID: instanceID, AuthorityState: state}, nil
+}
+
+func (r *recordingEmitter) EmitBoundArtifact(ctx context.Context, instanceID, prevHash, state, policyVer, contextHash, actorID string, binding models.PolicyBinding) (*models.CommitmentArtifact, error) {
+	r.bindings = append(r.bindings, binding)
+	return r.EmitArtifact(ctx, instanceID, prevHash, state, policyVer, contextHash, actorID)
+}
+
+func TestCreateInstance_PolicyDeny(t *testing.T) {
+	store := NewMemoryStore()
+	e := NewEngineWithPolicy(store, policy.NewEngineWithBackend(denyBackend{}))
+	emitter := &recordingEmitter{}
+	e.SetArtifactEmitter(emitter)
+
+	inst, err := e.CreateInstance(context.Background(), "wf-deny", map[string]interface{}{"k": "v"}, policy.Policy{ID: "p-deny"})
+	if err != nil {
+		t.Fatalf("CreateInstance failed: %v", err)
+	}
+	if inst.State != StateTerminated {
+		t.Errorf("expected TERMINATED, got %s", inst.State)
+	}
+	if inst.LastArtifactHash != "art-denial" {
+		t.Errorf("expected denial artifact to be chained, got %q", inst.LastArtifactHash)
+	}
+	if len(emitter.states) != 1 || emitter.states[0] != string(StateTerminated) || emitter.actors[0] != ActorSystem {
+		t.Errorf("expected one SYSTEM TERMINATED artifact, got states=%v actors=%v", emitter.states, emitter.actors)
+	}
+
+	stored, _ := store.GetInstance(context.Background(), inst.ID)
+	if stored.State != StateTerminated {
+		t.Errorf("expected stored state TERMINATED, got %s", stored.State)
+	}
+}
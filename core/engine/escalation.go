@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Rainminds/gantral/core/policy"
+)
+
+// EscalationKey is the PolicyContext key pinning the policy's escalation tiers on an instance.
+const EscalationKey = "escalation"
+
+// EscalationFor returns the escalation tiers pinned on the instance, or nil when its policy
+// has none.
+func EscalationFor(inst *Instance) *policy.Escalation {
+	raw, ok := inst.PolicyContext[EscalationKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	// Stored contexts come back as generic maps; in-memory ones may hold the struct.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var e policy.Escalation
+	if err := json.Unmarshal(data, &e); err != nil || len(e.Tiers) == 0 {
+		return nil
+	}
+	return &e
+}
+
+// RecordEscalationCmd moves an instance waiting for a decision to escalation tier Tier.
+// The state does not change; the chain head moves from PrevArtifactHash to the escalation's
+// artifact, and stores must reject the update with ErrStaleInstance when the head has moved.
+type RecordEscalationCmd struct {
+	InstanceID       string
+	Tier             int
+	ApproverRoles    []string
+	Deadline         time.Time
+	PrevArtifactHash string
+	NewArtifactHash  string
+}
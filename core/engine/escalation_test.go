@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRoleRulesFor_EscalationTier(t *testing.T) {
+	// Tiers come back from storage as generic maps.
+	inst := &Instance{
+		ID:    "inst-1",
+		State: StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{
+			ApproverRolesKey: []interface{}{"approver"},
+			OverrideRolesKey: []interface{}{"break-glass"},
+			EscalationKey: map[string]interface{}{
+				"tiers": []interface{}{
+					map[string]interface{}{"timeout_seconds": 3600, "approver_roles": []interface{}{"director"}},
+				},
+				"final_action": "TERMINATE",
+			},
+		},
+	}
+	if got := RoleRulesFor(inst).ApproverRoles; len(got) != 1 || got[0] != "approver" {
+		t.Errorf("before escalation: approver roles = %v, want the policy's", got)
+	}
+
+	inst.EscalationTier = 1
+	rules := RoleRulesFor(inst)
+	if len(rules.ApproverRoles) != 1 || rules.ApproverRoles[0] != "director" {
+		t.Errorf("tier 1: approver roles = %v, want [director]", rules.ApproverRoles)
+	}
+	if len(rules.OverrideRoles) != 1 || rules.OverrideRoles[0] != "break-glass" {
+		t.Errorf("escalation must keep the override roles, got %v", rules.OverrideRoles)
+	}
+	if _, err := AuthorizeRole(rules, DecisionApprove, "alice", []string{"approver"}); !errors.Is(err, ErrRoleNotAuthorized) {
+		t.Errorf("escalated-away approver: expected ErrRoleNotAuthorized, got %v", err)
+	}
+}
+
+func TestMemoryStore_RecordEscalation(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	_ = store.CreateInstance(ctx, &Instance{ID: "inst-1", State: StateWaitingForHuman, LastArtifactHash: "art-1"})
+
+	got, err := store.RecordEscalation(ctx, RecordEscalationCmd{InstanceID: "inst-1", Tier: 1, PrevArtifactHash: "art-1", NewArtifactHash: "art-2"})
+	if err != nil {
+		t.Fatalf("RecordEscalation: %v", err)
+	}
+	if got.EscalationTier != 1 || got.LastArtifactHash != "art-2" || got.State != StateWaitingForHuman {
+		t.Errorf("unexpected instance after escalation: %+v", got)
+	}
+
+	// The head has moved: a replayed escalation is stale.
+	_, err = store.RecordEscalation(ctx, RecordEscalationCmd{InstanceID: "inst-1", Tier: 1, PrevArtifactHash: "art-1", NewArtifactHash: "art-3"})
+	if !errors.Is(err, ErrStaleInstance) {
+		t.Errorf("expected ErrStaleInstance, got %v", err)
+	}
+}
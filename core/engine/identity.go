@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrActorMismatch is returned when a claimed actor is not the authenticated identity.
+var ErrActorMismatch = errors.New("actor does not match the authenticated identity")
+
+// IdentityRequiredKey is the PolicyContext key pinning that an instance's human decisions
+// must carry an authenticated identity (WorkflowInput.RequireIdentity).
+const IdentityRequiredKey = "identity_required"
+
+// IdentityRequiredFor reports whether the instance only accepts authenticated decisions.
+// Instances created before the key was pinned accept decisions without an identity.
+func IdentityRequiredFor(inst *Instance) bool {
+	required, _ := inst.PolicyContext[IdentityRequiredKey].(bool)
+	return required
+}
+
+// ActorIdentity is the verified identity behind a decision, as authenticated by the API.
+// It travels with the decision so the recorded actor can always be traced to a token.
+type ActorIdentity struct {
+	Subject string   `json:"sub"`
+	Issuer  string   `json:"iss"` // Token issuer (OIDC issuer URL, or "dev-mode")
+	Type    string   `json:"type"`
+	Roles   []string `json:"roles,omitempty"`
+	OrgID   string   `json:"org_id,omitempty"`
+}
+
+// ActorID is the actor recorded on decisions and artifacts for this identity.
+func (a ActorIdentity) ActorID() string {
+	return BindActorID(a.Issuer, a.Subject)
+}
+
+// BindActorID returns the stable actor binding "issuer|subject". Subjects are only unique
+// per issuer, so the issuer is part of the actor.
+func BindActorID(issuer, subject string) string {
+	return issuer + "|" + subject
+}
+
+// CheckActor verifies that actorID is the binding of identity. A nil identity is an
+// unauthenticated decision: only SYSTEM may decide without one when required is set (see
+// IdentityRequiredFor); otherwise it is accepted as recorded before identities were bound.
+func CheckActor(identity *ActorIdentity, actorID string, required bool) error {
+	if identity == nil {
+		if required && actorID != ActorSystem {
+			return fmt.Errorf("%w: %s has no authenticated identity", ErrActorMismatch, actorID)
+		}
+		return nil
+	}
+	if actorID != identity.ActorID() {
+		return fmt.Errorf("%w: %s is not %s", ErrActorMismatch, actorID, identity.ActorID())
+	}
+	return nil
+}
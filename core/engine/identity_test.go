@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateDecision_BindsActorToIdentity(t *testing.T) {
+	inst := &Instance{ID: "inst-1", State: StateWaitingForHuman}
+	identity := &ActorIdentity{Subject: "alice", Issuer: "https://idp.example.com", Type: "human"}
+
+	if got := identity.ActorID(); got != "https://idp.example.com|alice" {
+		t.Fatalf("ActorID() = %s", got)
+	}
+
+	cmd := RecordDecisionCmd{
+		InstanceID:    inst.ID,
+		Type:          DecisionApprove,
+		ActorID:       identity.ActorID(),
+		Justification: "Reviewed",
+		Identity:      identity,
+	}
+	if err := ValidateDecision(inst, cmd); err != nil {
+		t.Fatalf("bound actor rejected: %v", err)
+	}
+
+	// The bare subject, or another issuer's subject, is not the authenticated actor.
+	for _, actorID := range []string{"alice", "https://other.example.com|alice", "mallory"} {
+		cmd.ActorID = actorID
+		if err := ValidateDecision(inst, cmd); !errors.Is(err, ErrActorMismatch) {
+			t.Errorf("%s: expected ErrActorMismatch, got %v", actorID, err)
+		}
+	}
+}
+
+func TestValidateDecision_RequiresIdentityWhenPinned(t *testing.T) {
+	inst := &Instance{
+		ID:            "inst-1",
+		State:         StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{IdentityRequiredKey: true},
+	}
+	cmd := RecordDecisionCmd{
+		InstanceID:    inst.ID,
+		Type:          DecisionApprove,
+		ActorID:       "https://idp.example.com|alice",
+		Justification: "Reviewed",
+	}
+	if err := ValidateDecision(inst, cmd); !errors.Is(err, ErrActorMismatch) {
+		t.Fatalf("expected ErrActorMismatch without an identity, got %v", err)
+	}
+
+	// The system actor never carries a token.
+	cmd.ActorID = ActorSystem
+	if err := ValidateDecision(inst, cmd); err != nil {
+		t.Errorf("system decision rejected: %v", err)
+	}
+
+	// Instances created before the requirement was pinned keep accepting them.
+	cmd.ActorID = "https://idp.example.com|alice"
+	inst.PolicyContext = nil
+	if err := ValidateDecision(inst, cmd); err != nil {
+		t.Errorf("legacy decision rejected: %v", err)
+	}
+}
@@ -1,8 +1,12 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/statemachine"
 )
 
 // ErrInvalidTransition is returned when a state transition is not allowed.
@@ -16,36 +20,19 @@ func (e ErrInvalidTransition) Error() string {
 }
 
 // AllowedTransitions defines the strict map of valid state transitions.
-// Based on specs/03-state-machine.md.
-var AllowedTransitions = map[State][]State{
-	StateCreated: {
-		StateRunning,
-	},
-	StateRunning: {
-		StateWaitingForHuman,
-		StateCompleted,
-		StateTerminated,
-	},
-	StateWaitingForHuman: {
-		StateApproved,
-		StateRejected,
-		StateOverridden,
-	},
-	StateApproved: {
-		StateResumed,
-	},
-	StateRejected: {
-		StateTerminated, // Or remediation path, but strictly TERMINATED for now based on simple model
-	},
-	StateOverridden: {
-		StateResumed,
-	},
-	StateResumed: {
-		StateRunning,
-	},
-	StateCompleted:  {}, // Terminal state
-	StateTerminated: {}, // Terminal state
-}
+// Based on specs/03-state-machine.md; the table itself lives in pkg/statemachine
+// so that the offline verifier enforces exactly the same rules.
+var AllowedTransitions = func() map[State][]State {
+	table := make(map[State][]State, len(statemachine.Transitions))
+	for from, tos := range statemachine.Transitions {
+		allowed := make([]State, len(tos))
+		for i, to := range tos {
+			allowed[i] = State(to)
+		}
+		table[State(from)] = allowed
+	}
+	return table
+}()
 
 // Transition attempts to move the instance to the target state.
 // It returns an error if the transition is invalid.
@@ -72,3 +59,74 @@ func Transition(instance *Instance, target State) error {
 	instance.UpdatedAt = time.Now()
 	return nil
 }
+
+// ErrStaleInstance is returned by stores when an instance changed between being read
+// and being transitioned (its state or chain head no longer matches).
+var ErrStaleInstance = errors.New("instance changed concurrently")
+
+// ErrStateAmbiguous indicates that the chain head recorded in the database is not backed
+// by evidence: the artifact is missing, belongs to another instance or already has a successor.
+var ErrStateAmbiguous = errors.New("CRITICAL: state ambiguity detected (phantom artifact)")
+
+// ChainAttempt is the artifact an activity is about to chain from an instance's head.
+// A successor of the head that matches it is the activity's own artifact from an earlier
+// attempt whose database write failed (a self-orphan), not a fork.
+type ChainAttempt struct {
+	State           State
+	PolicyVersionID string
+	ContextHash     string
+	ActorID         string
+}
+
+// Matches reports whether art is the artifact this attempt emits for the instance.
+func (c ChainAttempt) Matches(instanceID string, art *models.CommitmentArtifact) bool {
+	return art.InstanceID == instanceID &&
+		art.AuthorityState == string(c.State) &&
+		art.PolicyVersionID == c.PolicyVersionID &&
+		art.ContextHash == c.ContextHash &&
+		art.HumanActorID == c.ActorID
+}
+
+// QuarantineAction is an operator's decision on a quarantined instance.
+type QuarantineAction string
+
+const (
+	// QuarantineRelease restores the pre-quarantine state so the blocked step is retried.
+	// The operator is expected to have repaired the chain head first; the guard runs again.
+	QuarantineRelease QuarantineAction = "RELEASE"
+	// QuarantineAbandon leaves the instance quarantined for good and ends its execution.
+	QuarantineAbandon QuarantineAction = "ABANDON"
+)
+
+// QuarantineCmd parks an instance in StateQuarantined. From and ArtifactHash are the
+// expected current values, as in TransitionCmd; the chain head is left untouched.
+type QuarantineCmd struct {
+	InstanceID   string
+	From         State
+	ArtifactHash string
+	Reason       string
+}
+
+// ResolveQuarantineCmd records an operator decision on a quarantined instance.
+// On release the instance returns to RestoreState at whatever head it now has.
+type ResolveQuarantineCmd struct {
+	InstanceID    string
+	Action        QuarantineAction
+	RestoreState  State
+	ActorID       string
+	Role          string
+	Justification string
+}
+
+// TransitionCmd records a non-decision state transition (e.g. APPROVED -> RESUMED).
+// From and PrevArtifactHash are the expected current values; stores must reject the
+// update with ErrStaleInstance when they do not match.
+type TransitionCmd struct {
+	InstanceID       string
+	From             State
+	To               State
+	ActorID          string
+	Reason           string
+	PrevArtifactHash string
+	NewArtifactHash  string // The hash of the artifact emitted for this transition
+}
@@ -11,6 +11,7 @@ import (
 type MemoryStore struct {
 	mu        sync.RWMutex
 	instances map[string]*Instance
+	rejected  []RejectedDecisionCmd
 }
 
 func NewMemoryStore() *MemoryStore {
@@ -58,6 +59,9 @@ func (s *MemoryStore) RecordDecision(ctx context.Context, cmd RecordDecisionCmd,
 	if !ok {
 		return nil, fmt.Errorf("instance not found: %s", cmd.InstanceID)
 	}
+	if inst.State != StateWaitingForHuman || inst.LastArtifactHash != cmd.PrevArtifactHash {
+		return nil, fmt.Errorf("%w: %s", ErrStaleInstance, cmd.InstanceID)
+	}
 
 	inst.State = nextState
 	if cmd.NewArtifactHash != "" {
@@ -69,6 +73,108 @@ func (s *MemoryStore) RecordDecision(ctx context.Context, cmd RecordDecisionCmd,
 	return copyInstance(inst), nil
 }
 
+func (s *MemoryStore) TransitionInstance(ctx context.Context, cmd TransitionCmd) (*Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.instances[cmd.InstanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found: %s", cmd.InstanceID)
+	}
+	if inst.State != cmd.From || inst.LastArtifactHash != cmd.PrevArtifactHash {
+		return nil, fmt.Errorf("%w: %s", ErrStaleInstance, cmd.InstanceID)
+	}
+
+	inst.State = cmd.To
+	inst.LastArtifactHash = cmd.NewArtifactHash
+
+	s.instances[cmd.InstanceID] = copyInstance(inst)
+	return copyInstance(inst), nil
+}
+
+func (s *MemoryStore) RecordVote(ctx context.Context, cmd RecordVoteCmd) (*Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.instances[cmd.InstanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found: %s", cmd.InstanceID)
+	}
+	if inst.State != StateWaitingForHuman || inst.LastArtifactHash != cmd.PrevArtifactHash {
+		return nil, fmt.Errorf("%w: %s", ErrStaleInstance, cmd.InstanceID)
+	}
+
+	inst.LastArtifactHash = cmd.Vote.ArtifactID
+	return copyInstance(inst), nil
+}
+
+func (s *MemoryStore) RecordEscalation(ctx context.Context, cmd RecordEscalationCmd) (*Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.instances[cmd.InstanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found: %s", cmd.InstanceID)
+	}
+	if inst.State != StateWaitingForHuman || inst.LastArtifactHash != cmd.PrevArtifactHash {
+		return nil, fmt.Errorf("%w: %s", ErrStaleInstance, cmd.InstanceID)
+	}
+
+	inst.EscalationTier = cmd.Tier
+	inst.LastArtifactHash = cmd.NewArtifactHash
+	return copyInstance(inst), nil
+}
+
+func (s *MemoryStore) RecordRejectedDecision(ctx context.Context, cmd RejectedDecisionCmd) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rejected = append(s.rejected, cmd)
+	return nil
+}
+
+// RejectedDecisions returns the refused decision attempts recorded so far.
+func (s *MemoryStore) RejectedDecisions() []RejectedDecisionCmd {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]RejectedDecisionCmd(nil), s.rejected...)
+}
+
+func (s *MemoryStore) QuarantineInstance(ctx context.Context, cmd QuarantineCmd) (*Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.instances[cmd.InstanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found: %s", cmd.InstanceID)
+	}
+	if inst.State != cmd.From || inst.LastArtifactHash != cmd.ArtifactHash {
+		return nil, fmt.Errorf("%w: %s", ErrStaleInstance, cmd.InstanceID)
+	}
+
+	inst.State = StateQuarantined
+	return copyInstance(inst), nil
+}
+
+func (s *MemoryStore) ResolveQuarantine(ctx context.Context, cmd ResolveQuarantineCmd) (*Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inst, ok := s.instances[cmd.InstanceID]
+	if !ok {
+		return nil, fmt.Errorf("instance not found: %s", cmd.InstanceID)
+	}
+	if inst.State != StateQuarantined {
+		return nil, fmt.Errorf("%w: %s", ErrStaleInstance, cmd.InstanceID)
+	}
+
+	if cmd.Action == QuarantineRelease {
+		inst.State = cmd.RestoreState
+	}
+	return copyInstance(inst), nil
+}
+
 func copyInstance(src *Instance) *Instance {
 	dst := *src
 	// Helper to copy inner maps if needed, but for now shallow copy of maps is risky if tests mutate them
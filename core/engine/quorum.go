@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrDuplicateVote is returned when an actor votes twice on the same instance.
+	ErrDuplicateVote = errors.New("actor has already voted")
+	// ErrNotEligible is returned when an actor belongs to no approver group that still needs a vote.
+	ErrNotEligible = errors.New("actor is not an eligible approver")
+)
+
+// QuorumRule is the multi-party approval requirement of a policy: Quorum approvals out of
+// the Approvers groups. With groups, each group counts at most once and a voter must hold
+// the group as a role. Without groups, Quorum distinct actors must approve.
+type QuorumRule struct {
+	Approvers []string `json:"approvers,omitempty"`
+	Quorum    int      `json:"quorum"`
+}
+
+// NewQuorumRule normalizes a policy's approvers and quorum. Without a declared quorum the
+// approvers stay advisory, as before quorums existed: any one approval decides. A quorum
+// above the number of groups is capped, so a misconfigured policy can never become
+// unsatisfiable.
+func NewQuorumRule(approvers []string, quorum int) QuorumRule {
+	if quorum <= 0 {
+		return QuorumRule{Quorum: 1}
+	}
+	if len(approvers) > 0 && quorum > len(approvers) {
+		quorum = len(approvers)
+	}
+	return QuorumRule{Approvers: approvers, Quorum: quorum}
+}
+
+// MultiParty reports whether decisions must be collected as votes. A single approval from
+// anyone keeps the one-decision HITL path.
+func (r QuorumRule) MultiParty() bool {
+	return len(r.Approvers) > 0 || r.Quorum > 1
+}
+
+// Vote is one recorded approver vote on a multi-party decision.
+type Vote struct {
+	ActorID    string       `json:"actor_id"`
+	Decision   DecisionType `json:"decision"` // APPROVE or REJECT
+	Group      string       `json:"group,omitempty"`
+	ArtifactID string       `json:"artifact_id"`
+}
+
+// Tally collects the votes of one instance against its QuorumRule.
+// It is pure and deterministic, so workflows can keep it in their state.
+type Tally struct {
+	Rule  QuorumRule `json:"rule"`
+	Votes []Vote     `json:"votes"`
+}
+
+// Admit checks whether an actor holding roles may cast decision, and returns the approver
+// group the vote counts for. An approval counts for the first group, in policy order, that
+// the actor holds and that has not approved yet; a rejection for the first group held.
+func (t *Tally) Admit(actorID string, roles []string, decision DecisionType) (string, error) {
+	if strings.TrimSpace(actorID) == "" {
+		return "", fmt.Errorf("%w: missing actor identity", ErrNotEligible)
+	}
+	if decision != DecisionApprove && decision != DecisionReject {
+		return "", fmt.Errorf("invalid vote: %s", decision)
+	}
+	for _, v := range t.Votes {
+		if v.ActorID == actorID {
+			return "", fmt.Errorf("%w: %s", ErrDuplicateVote, actorID)
+		}
+	}
+	if len(t.Rule.Approvers) == 0 {
+		return "", nil
+	}
+
+	held := make(map[string]bool, len(roles))
+	for _, r := range roles {
+		held[r] = true
+	}
+	approved := t.approvedGroups()
+	for _, g := range t.Rule.Approvers {
+		if held[g] && (decision == DecisionReject || !approved[g]) {
+			return g, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s holds none of the pending groups %v", ErrNotEligible, actorID, t.Pending())
+}
+
+// Voters lists the actors who voted, in vote order.
+func (t *Tally) Voters() []string {
+	voters := make([]string, len(t.Votes))
+	for i, v := range t.Votes {
+		voters[i] = v.ActorID
+	}
+	return voters
+}
+
+// Add records an admitted vote.
+func (t *Tally) Add(v Vote) {
+	t.Votes = append(t.Votes, v)
+}
+
+// Outcome returns the decision once it is reached: REJECT as soon as any eligible voter
+// rejects, APPROVE once the quorum is met.
+func (t *Tally) Outcome() (DecisionType, bool) {
+	approvals := 0
+	for _, v := range t.Votes {
+		if v.Decision == DecisionReject {
+			return DecisionReject, true
+		}
+		approvals++
+	}
+	if approvals >= t.Rule.Quorum {
+		return DecisionApprove, true
+	}
+	return "", false
+}
+
+// Outstanding is the number of approvals still required.
+func (t *Tally) Outstanding() int {
+	n := t.Rule.Quorum - len(t.Votes)
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Pending lists the approver groups that have not approved yet, in policy order.
+func (t *Tally) Pending() []string {
+	approved := t.approvedGroups()
+	pending := []string{}
+	for _, g := range t.Rule.Approvers {
+		if !approved[g] {
+			pending = append(pending, g)
+		}
+	}
+	return pending
+}
+
+func (t *Tally) approvedGroups() map[string]bool {
+	approved := make(map[string]bool, len(t.Votes))
+	for _, v := range t.Votes {
+		if v.Decision == DecisionApprove && v.Group != "" {
+			approved[v.Group] = true
+		}
+	}
+	return approved
+}
+
+// RoleQuorum is the role recorded with the aggregate decision of a multi-party decision.
+const RoleQuorum = "QUORUM"
+
+// QuorumActorID is the actor recorded on the aggregate artifact of a multi-party decision:
+// the voters whose votes decided it, in vote order (e.g. "quorum:alice,bob").
+func QuorumActorID(votes []Vote, decision DecisionType) string {
+	var actors []string
+	for _, v := range votes {
+		if v.Decision == decision {
+			actors = append(actors, v.ActorID)
+		}
+	}
+	return "quorum:" + strings.Join(actors, ",")
+}
+
+// RecordVoteCmd records a vote on an instance waiting for a multi-party decision. The
+// state does not change; the chain head moves from PrevArtifactHash to the vote's artifact,
+// and stores must reject the update with ErrStaleInstance when the head has moved.
+type RecordVoteCmd struct {
+	InstanceID       string
+	Vote             Vote
+	Role             string
+	Justification    string
+	PrevArtifactHash string
+}
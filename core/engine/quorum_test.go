@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNewQuorumRule(t *testing.T) {
+	tests := []struct {
+		name       string
+		approvers  []string
+		quorum     int
+		want       int
+		multiParty bool
+	}{
+		{"single approval", nil, 0, 1, false},
+		{"n distinct actors", nil, 2, 2, true},
+		{"advisory approvers", []string{"group:eng", "group:compliance"}, 0, 1, false},
+		{"2 of 2 groups", []string{"group:eng", "group:compliance"}, 2, 2, true},
+		{"1 of 2 groups", []string{"group:eng", "group:compliance"}, 1, 1, true},
+		{"capped at group count", []string{"group:eng"}, 3, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewQuorumRule(tt.approvers, tt.quorum)
+			if r.Quorum != tt.want {
+				t.Errorf("Quorum = %d, want %d", r.Quorum, tt.want)
+			}
+			if r.MultiParty() != tt.multiParty {
+				t.Errorf("MultiParty() = %v, want %v", r.MultiParty(), tt.multiParty)
+			}
+		})
+	}
+}
+
+func TestTally_GroupQuorum(t *testing.T) {
+	tally := &Tally{Rule: NewQuorumRule([]string{"group:engineering", "group:compliance"}, 2)}
+
+	group, err := tally.Admit("alice", []string{"group:engineering"}, DecisionApprove)
+	if err != nil || group != "group:engineering" {
+		t.Fatalf("Admit(alice) = %q, %v", group, err)
+	}
+	tally.Add(Vote{ActorID: "alice", Decision: DecisionApprove, Group: group, ArtifactID: "a1"})
+
+	if _, decided := tally.Outcome(); decided {
+		t.Fatal("one of two groups must not decide")
+	}
+	if got := tally.Pending(); !reflect.DeepEqual(got, []string{"group:compliance"}) {
+		t.Errorf("Pending() = %v", got)
+	}
+
+	// The same actor cannot vote twice, even for another group it holds.
+	if _, err := tally.Admit("alice", []string{"group:compliance"}, DecisionApprove); !errors.Is(err, ErrDuplicateVote) {
+		t.Errorf("expected ErrDuplicateVote, got %v", err)
+	}
+	// A second engineer cannot fill the compliance seat.
+	if _, err := tally.Admit("bob", []string{"group:engineering"}, DecisionApprove); !errors.Is(err, ErrNotEligible) {
+		t.Errorf("expected ErrNotEligible, got %v", err)
+	}
+	if _, err := tally.Admit("", []string{"group:compliance"}, DecisionApprove); !errors.Is(err, ErrNotEligible) {
+		t.Errorf("missing actor: expected ErrNotEligible, got %v", err)
+	}
+	if _, err := tally.Admit("carol", []string{"group:compliance"}, DecisionOverride); err == nil {
+		t.Error("OVERRIDE is not a vote")
+	}
+
+	group, err = tally.Admit("carol", []string{"group:engineering", "group:compliance"}, DecisionApprove)
+	if err != nil || group != "group:compliance" {
+		t.Fatalf("Admit(carol) = %q, %v", group, err)
+	}
+	tally.Add(Vote{ActorID: "carol", Decision: DecisionApprove, Group: group, ArtifactID: "a2"})
+
+	if outcome, decided := tally.Outcome(); !decided || outcome != DecisionApprove {
+		t.Errorf("Outcome() = %s, %v; want APPROVE", outcome, decided)
+	}
+	if tally.Outstanding() != 0 || len(tally.Pending()) != 0 {
+		t.Errorf("nothing should be outstanding: %d %v", tally.Outstanding(), tally.Pending())
+	}
+	if got := QuorumActorID(tally.Votes, DecisionApprove); got != "quorum:alice,carol" {
+		t.Errorf("QuorumActorID = %s", got)
+	}
+}
+
+func TestTally_AnyRejectDecides(t *testing.T) {
+	tally := &Tally{Rule: NewQuorumRule(nil, 3)}
+	tally.Add(Vote{ActorID: "alice", Decision: DecisionApprove})
+	if tally.Outstanding() != 2 {
+		t.Errorf("Outstanding() = %d, want 2", tally.Outstanding())
+	}
+
+	if _, err := tally.Admit("bob", nil, DecisionReject); err != nil {
+		t.Fatalf("Admit(bob): %v", err)
+	}
+	tally.Add(Vote{ActorID: "bob", Decision: DecisionReject})
+
+	if outcome, decided := tally.Outcome(); !decided || outcome != DecisionReject {
+		t.Errorf("Outcome() = %s, %v; want REJECT", outcome, decided)
+	}
+	if got := QuorumActorID(tally.Votes, DecisionReject); got != "quorum:bob" {
+		t.Errorf("QuorumActorID = %s", got)
+	}
+}
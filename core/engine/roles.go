@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrRoleNotAuthorized is returned when the actor holds no role the policy allows for a decision.
+var ErrRoleNotAuthorized = errors.New("role not authorized for decision")
+
+// PolicyContext keys pinning the policy's decision roles on an instance.
+const (
+	ApproverRolesKey = "approver_roles"
+	OverrideRolesKey = "override_roles"
+)
+
+// RoleRules are the roles a policy allows to decide an instance.
+// Without any role, decisions are not role-checked.
+type RoleRules struct {
+	ApproverRoles []string // APPROVE and REJECT; empty allows any role
+	OverrideRoles []string // OVERRIDE (break-glass); empty allows no override once rules are set
+}
+
+// Enforced reports whether the policy declares any decision role.
+func (r RoleRules) Enforced() bool {
+	return len(r.ApproverRoles) > 0 || len(r.OverrideRoles) > 0
+}
+
+// allowed returns the roles that may cast decision, and whether any role may.
+func (r RoleRules) allowed(decision DecisionType) ([]string, bool) {
+	if decision == DecisionOverride {
+		return r.OverrideRoles, false
+	}
+	return r.ApproverRoles, len(r.ApproverRoles) == 0
+}
+
+// RoleRulesFor returns the decision roles pinned on the instance. Once the instance has
+// escalated, the active tier's approver roles replace the policy's.
+func RoleRulesFor(inst *Instance) RoleRules {
+	rules := RoleRules{
+		ApproverRoles: stringsFrom(inst.PolicyContext[ApproverRolesKey]),
+		OverrideRoles: stringsFrom(inst.PolicyContext[OverrideRolesKey]),
+	}
+	if inst.EscalationTier > 0 {
+		if e := EscalationFor(inst); e != nil && inst.EscalationTier <= len(e.Tiers) {
+			rules.ApproverRoles = e.Tiers[inst.EscalationTier-1].ApproverRoles
+		}
+	}
+	return rules
+}
+
+// stringsFrom reads a string list from a policy context, which holds []string in memory
+// and []interface{} once it has been stored.
+func stringsFrom(v interface{}) []string {
+	switch list := v.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// HeldRoles returns the roles a decision's actor holds. An authenticated decision holds
+// exactly its token's roles; the roles the caller listed count only without an identity.
+func HeldRoles(identity *ActorIdentity, claimed []string) []string {
+	if identity != nil {
+		return identity.Roles
+	}
+	return claimed
+}
+
+// AuthorizeRole returns the role, among those held by actorID, under which the policy
+// allows it to cast decision: the first allowed role in policy order. It returns "" when
+// no role is checked (no rules, any role may approve, or a SYSTEM decision).
+func AuthorizeRole(rules RoleRules, decision DecisionType, actorID string, held []string) (string, error) {
+	if !rules.Enforced() || actorID == ActorSystem {
+		return "", nil
+	}
+	allowed, unrestricted := rules.allowed(decision)
+	if unrestricted {
+		return "", nil
+	}
+	holds := make(map[string]bool, len(held))
+	for _, r := range held {
+		holds[r] = true
+	}
+	for _, r := range allowed {
+		if holds[r] {
+			return r, nil
+		}
+	}
+	if decision == DecisionOverride && len(allowed) == 0 {
+		return "", fmt.Errorf("%w: the policy declares no override role", ErrRoleNotAuthorized)
+	}
+	return "", fmt.Errorf("%w: %s holds none of %v for %s", ErrRoleNotAuthorized, actorID, allowed, decision)
+}
+
+// CheckRole verifies the role a decision was recorded under: when the policy restricts
+// the decision, role must be both allowed and held by the actor.
+func CheckRole(rules RoleRules, decision DecisionType, actorID, role string, held []string) error {
+	want, err := AuthorizeRole(rules, decision, actorID, held)
+	if err != nil || want == "" {
+		return err
+	}
+	allowed, _ := rules.allowed(decision)
+	if !contains(allowed, role) || !contains(held, role) {
+		return fmt.Errorf("%w: %s cannot decide %s as %q", ErrRoleNotAuthorized, actorID, decision, role)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
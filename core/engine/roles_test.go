@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAuthorizeRole(t *testing.T) {
+	rules := RoleRules{ApproverRoles: []string{"approver", "lead"}, OverrideRoles: []string{"break-glass"}}
+
+	tests := []struct {
+		name     string
+		rules    RoleRules
+		decision DecisionType
+		actor    string
+		held     []string
+		want     string
+		refuse   bool
+	}{
+		{"no rules", RoleRules{}, DecisionOverride, "alice", nil, "", false},
+		{"first allowed role in policy order", rules, DecisionApprove, "alice", []string{"user", "lead", "approver"}, "approver", false},
+		{"reject needs an approver role", rules, DecisionReject, "alice", []string{"user"}, "", true},
+		{"approver cannot override", rules, DecisionOverride, "alice", []string{"approver"}, "", true},
+		{"break-glass override", rules, DecisionOverride, "bob", []string{"user", "break-glass"}, "break-glass", false},
+		{"no override role declared", RoleRules{ApproverRoles: []string{"approver"}}, DecisionOverride, "bob", []string{"approver"}, "", true},
+		{"any role may approve", RoleRules{OverrideRoles: []string{"break-glass"}}, DecisionApprove, "alice", nil, "", false},
+		{"system timeout is exempt", rules, DecisionReject, ActorSystem, nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AuthorizeRole(tt.rules, tt.decision, tt.actor, tt.held)
+			if tt.refuse {
+				if !errors.Is(err, ErrRoleNotAuthorized) {
+					t.Errorf("expected ErrRoleNotAuthorized, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("AuthorizeRole() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateDecision_ChecksRole(t *testing.T) {
+	// Rules come back from storage as generic lists.
+	inst := &Instance{
+		ID:    "inst-1",
+		State: StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{
+			ApproverRolesKey: []interface{}{"approver"},
+			OverrideRolesKey: []interface{}{"break-glass"},
+		},
+	}
+	override := RecordDecisionCmd{
+		InstanceID:    inst.ID,
+		Type:          DecisionOverride,
+		ActorID:       "bob",
+		Justification: "Incident",
+		ContextDelta:  map[string]interface{}{"reason": "incident"},
+		Role:          "approver",
+		Roles:         []string{"approver", "break-glass"},
+	}
+	if err := ValidateDecision(inst, override); !errors.Is(err, ErrRoleNotAuthorized) {
+		t.Errorf("override recorded under an approver role: expected ErrRoleNotAuthorized, got %v", err)
+	}
+
+	override.Role = "break-glass"
+	if err := ValidateDecision(inst, override); err != nil {
+		t.Errorf("break-glass override rejected: %v", err)
+	}
+
+	// The role must be held, not just named.
+	override.Roles = []string{"approver"}
+	if err := ValidateDecision(inst, override); !errors.Is(err, ErrRoleNotAuthorized) {
+		t.Errorf("unheld role: expected ErrRoleNotAuthorized, got %v", err)
+	}
+
+	// An authenticated decision holds its token's roles, whatever the caller listed.
+	identity := &ActorIdentity{Subject: "bob", Issuer: "idp", Roles: []string{"approver"}}
+	override.Identity = identity
+	override.ActorID = identity.ActorID()
+	override.Roles = []string{"approver", "break-glass"}
+	if err := ValidateDecision(inst, override); !errors.Is(err, ErrRoleNotAuthorized) {
+		t.Errorf("role claimed beyond the token: expected ErrRoleNotAuthorized, got %v", err)
+	}
+	identity.Roles = []string{"break-glass"}
+	override.Roles = nil
+	if err := ValidateDecision(inst, override); err != nil {
+		t.Errorf("break-glass role on the token rejected: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Rainminds/gantral/core/policy"
+)
+
+// ErrSeparationOfDuties is returned when a decision violates the policy's separation-of-duties rules.
+var ErrSeparationOfDuties = errors.New("separation of duties violation")
+
+// SeparationOfDutiesKey is the PolicyContext key pinning the policy's separation-of-duties
+// rules on an instance.
+const SeparationOfDutiesKey = "separation_of_duties"
+
+// SeparationOfDutiesFor returns the separation-of-duties rules pinned on the instance,
+// or nil when its policy has none.
+func SeparationOfDutiesFor(inst *Instance) *policy.SeparationOfDuties {
+	raw, ok := inst.PolicyContext[SeparationOfDutiesKey]
+	if !ok || raw == nil {
+		return nil
+	}
+	// Stored contexts come back as generic maps; in-memory ones may hold the struct.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var rules policy.SeparationOfDuties
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil
+	}
+	return &rules
+}
+
+// CheckSeparationOfDuties checks a decision by actorID (of org actorOrgID) on inst against
+// rules. priorActors are the humans who already decided a stage of the instance.
+// An unknown creator or org cannot prove the duties separate, so the rule fails closed.
+// SYSTEM decisions (timeouts) are exempt.
+func CheckSeparationOfDuties(rules *policy.SeparationOfDuties, inst *Instance, actorID, actorOrgID string, priorActors []string) error {
+	if !rules.Enabled() || actorID == ActorSystem {
+		return nil
+	}
+	if rules.ExcludeCreator {
+		if inst.CreatedBy == "" {
+			return fmt.Errorf("%w: the instance creator was not recorded", ErrSeparationOfDuties)
+		}
+		if actorID == inst.CreatedBy {
+			return fmt.Errorf("%w: %s created the instance", ErrSeparationOfDuties, actorID)
+		}
+	}
+	if rules.ExcludeCreatorOrg {
+		if inst.CreatorOrgID == "" || actorOrgID == "" {
+			return fmt.Errorf("%w: the org of the creator or of %s is unknown", ErrSeparationOfDuties, actorID)
+		}
+		if actorOrgID == inst.CreatorOrgID {
+			return fmt.Errorf("%w: %s is in the creator's org %s", ErrSeparationOfDuties, actorID, actorOrgID)
+		}
+	}
+	if rules.DistinctApprovers {
+		for _, prior := range priorActors {
+			if prior == actorID {
+				return fmt.Errorf("%w: %s already decided a stage of this instance", ErrSeparationOfDuties, actorID)
+			}
+		}
+	}
+	return nil
+}
+
+// RejectedDecisionCmd records a decision attempt refused by separation of duties, by the
+// policy's decision roles, or because its actor is not its authenticated identity.
+// Nothing changes on the instance; the attempt is audit-logged only.
+type RejectedDecisionCmd struct {
+	InstanceID   string
+	DecisionType DecisionType
+	ActorID      string
+	ActorOrgID   string
+	Reason       string
+}
+
+// RejectionRecorder is implemented by stores that audit-log refused decision attempts.
+type RejectionRecorder interface {
+	RecordRejectedDecision(ctx context.Context, cmd RejectedDecisionCmd) error
+}
+
+// recordRejection audit-logs a refused decision when the store supports it. The decision
+// is refused either way, so a logging failure is only reported.
+func (e *Engine) recordRejection(ctx context.Context, cmd RecordDecisionCmd, reason error) {
+	recorder, ok := e.store.(RejectionRecorder)
+	if !ok {
+		return
+	}
+	err := recorder.RecordRejectedDecision(ctx, RejectedDecisionCmd{
+		InstanceID:   cmd.InstanceID,
+		DecisionType: cmd.Type,
+		ActorID:      cmd.ActorID,
+		ActorOrgID:   cmd.ActorOrgID,
+		Reason:       reason.Error(),
+	})
+	if err != nil {
+		slog.Error("Failed to audit rejected decision", "instance_id", cmd.InstanceID, "error", err)
+	}
+}
@@ -0,0 +1,94 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Rainminds/gantral/core/policy"
+)
+
+func TestCheckSeparationOfDuties(t *testing.T) {
+	inst := &Instance{ID: "inst-1", CreatedBy: "alice", CreatorOrgID: "payments"}
+	all := &policy.SeparationOfDuties{ExcludeCreator: true, ExcludeCreatorOrg: true, DistinctApprovers: true}
+
+	tests := []struct {
+		name   string
+		rules  *policy.SeparationOfDuties
+		inst   *Instance
+		actor  string
+		org    string
+		priors []string
+		refuse bool
+	}{
+		{"no rules", nil, inst, "alice", "payments", nil, false},
+		{"creator approves own instance", &policy.SeparationOfDuties{ExcludeCreator: true}, inst, "alice", "risk", nil, true},
+		{"other actor", &policy.SeparationOfDuties{ExcludeCreator: true}, inst, "bob", "payments", nil, false},
+		{"unknown creator fails closed", &policy.SeparationOfDuties{ExcludeCreator: true}, &Instance{ID: "inst-2"}, "bob", "risk", nil, true},
+		{"same org", &policy.SeparationOfDuties{ExcludeCreatorOrg: true}, inst, "bob", "payments", nil, true},
+		{"unknown actor org fails closed", &policy.SeparationOfDuties{ExcludeCreatorOrg: true}, inst, "bob", "", nil, true},
+		{"repeat approver", &policy.SeparationOfDuties{DistinctApprovers: true}, inst, "bob", "risk", []string{"bob"}, true},
+		{"all rules satisfied", all, inst, "carol", "risk", []string{"bob"}, false},
+		{"system timeout is exempt", all, inst, ActorSystem, "", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSeparationOfDuties(tt.rules, tt.inst, tt.actor, tt.org, tt.priors)
+			if tt.refuse && !errors.Is(err, ErrSeparationOfDuties) {
+				t.Errorf("expected ErrSeparationOfDuties, got %v", err)
+			}
+			if !tt.refuse && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecordDecision_SeparationOfDuties(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	e := NewEngine(store)
+
+	// Rules come back from storage as a generic map.
+	inst := &Instance{
+		ID:           "inst-sod",
+		State:        StateWaitingForHuman,
+		CreatedBy:    "alice",
+		CreatorOrgID: "payments",
+		PolicyContext: map[string]interface{}{
+			SeparationOfDutiesKey: map[string]interface{}{"exclude_creator": true},
+		},
+	}
+	_ = store.CreateInstance(ctx, inst)
+
+	_, err := e.RecordDecision(ctx, RecordDecisionCmd{
+		InstanceID:    inst.ID,
+		Type:          DecisionApprove,
+		ActorID:       "alice",
+		Justification: "Approving my own change",
+	})
+	if !errors.Is(err, ErrSeparationOfDuties) {
+		t.Fatalf("expected ErrSeparationOfDuties, got %v", err)
+	}
+
+	rejected := store.RejectedDecisions()
+	if len(rejected) != 1 || rejected[0].ActorID != "alice" || rejected[0].DecisionType != DecisionApprove {
+		t.Fatalf("expected the refused attempt to be audit-logged, got %+v", rejected)
+	}
+	if got, _ := store.GetInstance(ctx, inst.ID); got.State != StateWaitingForHuman {
+		t.Errorf("refused decision must not change state, got %s", got.State)
+	}
+
+	updated, err := e.RecordDecision(ctx, RecordDecisionCmd{
+		InstanceID:    inst.ID,
+		Type:          DecisionApprove,
+		ActorID:       "bob",
+		Justification: "Reviewed",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.State != StateApproved {
+		t.Errorf("expected APPROVED, got %s", updated.State)
+	}
+}
@@ -22,6 +22,11 @@ const (
 	StateTerminated      State = constants.StateTerminated
 )
 
+// StateQuarantined parks an instance whose chain head in the database is not backed by
+// the artifact store. It is operational only: no artifact ever records it, and only an
+// operator decision (ResolveQuarantineCmd) moves the instance out of it.
+const StateQuarantined State = "QUARANTINED"
+
 // Instance represents a concrete execution of a workflow.
 type Instance struct {
 	ID               string                 `json:"id"`
@@ -31,6 +36,9 @@ type Instance struct {
 	PolicyContext    map[string]interface{} `json:"policy_context"`
 	PolicyVersionID  string                 `json:"policy_version_id"`
 	LastArtifactHash string                 `json:"last_artifact_hash"`
+	CreatedBy        string                 `json:"created_by,omitempty"`      // Authenticated identity that created the instance
+	CreatorOrgID     string                 `json:"creator_org_id,omitempty"`  // The creator's org (team)
+	EscalationTier   int                    `json:"escalation_tier,omitempty"` // Active escalation tier; 0 before any escalation
 	CreatedAt        time.Time              `json:"created_at"`
 	UpdatedAt        time.Time              `json:"updated_at"`
 }
@@ -43,3 +51,14 @@ type AuditEvent struct {
 	Payload    map[string]interface{} `json:"payload"`
 	Timestamp  time.Time              `json:"timestamp"`
 }
+
+// DecisionRecord is a stored human decision (the decisions table).
+type DecisionRecord struct {
+	ID              string       `json:"id"`
+	InstanceID      string       `json:"instance_id"`
+	Type            DecisionType `json:"type"`
+	ActorID         string       `json:"actor_id"`
+	Role            string       `json:"role"`
+	PolicyVersionID string       `json:"policy_version_id"`
+	CreatedAt       time.Time    `json:"created_at"`
+}
@@ -8,23 +8,64 @@ import (
 	"github.com/Rainminds/gantral/pkg/constants"
 )
 
+// Backend is a pluggable policy evaluator (e.g. the embedded Rego evaluator).
+// Implementations MUST be deterministic: the same Input always yields the same result,
+// so that they are safe to call from inside a Temporal workflow.
+type Backend interface {
+	EvaluateInput(ctx context.Context, in Input) (EvaluationResult, error)
+}
+
+// Bundled is implemented by backends that evaluate a loaded policy bundle. BundleHash is
+// the SHA-256 of the bundle's content, so that evidence identifies the policy code that ran.
+type Bundled interface {
+	BundleHash() string
+}
+
+// BundleHashOf returns the bundle hash of backend, or "" when it has none.
+func BundleHashOf(backend Backend) string {
+	if b, ok := backend.(Bundled); ok {
+		return b.BundleHash()
+	}
+	return ""
+}
+
 // Engine is responsible for evaluating policies against execution requests.
-type Engine struct{}
+type Engine struct {
+	backend Backend
+}
 
-// NewEngine creates a new instance of the Policy Engine.
+// NewEngine creates a new instance of the Policy Engine using the built-in rules.
 func NewEngine() *Engine {
 	return &Engine{}
 }
 
+// NewEngineWithBackend creates a Policy Engine that delegates evaluation to backend.
+func NewEngineWithBackend(backend Backend) *Engine {
+	return &Engine{backend: backend}
+}
+
 // Evaluate checks the policy rules and determines the next execution state.
 func (e *Engine) Evaluate(ctx context.Context, p Policy) (EvaluationResult, error) {
-	// Delegate to pure function for core logic
-	result := EvaluatePure(p)
+	return e.EvaluateInput(ctx, NewInput("", p, nil))
+}
+
+// EvaluateInput evaluates the policy against the full evaluation input.
+func (e *Engine) EvaluateInput(ctx context.Context, in Input) (EvaluationResult, error) {
+	// Delegate to pure function for core logic unless a backend is configured
+	result := EvaluatePure(in.Policy)
+	if e.backend != nil {
+		var err error
+		result, err = e.backend.EvaluateInput(ctx, in)
+		if err != nil {
+			return EvaluationResult{}, err
+		}
+	}
 
 	// Observability (Side Effect)
 	slog.Info("policy evaluated",
-		"policy_id", p.ID,
-		"decision", result.NextState,
+		"policy_id", in.Policy.ID,
+		"decision", result.Decision,
+		"next_state", result.NextState,
 		"reason", result.Reason,
 	)
 
@@ -35,6 +76,7 @@ func (e *Engine) Evaluate(ctx context.Context, p Policy) (EvaluationResult, erro
 // It must have NO side effects (no logging, no I/O) to be safe for Replay.
 func EvaluatePure(p Policy) EvaluationResult {
 	result := EvaluationResult{
+		Decision:    DecisionAllow,
 		ShouldPause: false,
 		NextState:   constants.StateRunning,
 		Reason:      "Policy allows automatic execution",
@@ -42,10 +84,43 @@ func EvaluatePure(p Policy) EvaluationResult {
 
 	// Rule: HIGH materiality OR explicit human approval requirement pauses execution.
 	if p.Materiality == MaterialityHigh || p.RequiresHumanApproval {
+		result.Decision = DecisionRequireHuman
 		result.ShouldPause = true
 		result.NextState = constants.StateWaitingForHuman
 		result.Reason = fmt.Sprintf("Execution paused: Materiality=%s, RequiresApproval=%v", p.Materiality, p.RequiresHumanApproval)
+		result.Approvers = p.Approvers
+		result.Quorum = p.Quorum
+		result.ApproverRoles = p.ApproverRoles
+		result.OverrideRoles = p.OverrideRoles
 	}
 
 	return result
 }
+
+// ResultForDecision maps a policy Decision onto the execution state it diverts to (ADR-007).
+func ResultForDecision(d Decision, reason string) EvaluationResult {
+	switch d {
+	case DecisionAllow:
+		return EvaluationResult{Decision: DecisionAllow, NextState: constants.StateRunning, Reason: reason}
+	case DecisionDeny:
+		return EvaluationResult{Decision: DecisionDeny, NextState: constants.StateTerminated, Reason: reason}
+	default:
+		// REQUIRE_HUMAN and anything unrecognised park the execution (fail-closed).
+		return EvaluationResult{Decision: DecisionRequireHuman, ShouldPause: true, NextState: constants.StateWaitingForHuman, Reason: reason}
+	}
+}
+
+// DecisionForState infers the Decision of a legacy result that only carries NextState.
+// It returns false if the state does not correspond to any policy outcome.
+func DecisionForState(nextState string) (Decision, bool) {
+	switch nextState {
+	case constants.StateRunning:
+		return DecisionAllow, true
+	case constants.StateWaitingForHuman:
+		return DecisionRequireHuman, true
+	case constants.StateTerminated:
+		return DecisionDeny, true
+	default:
+		return "", false
+	}
+}
@@ -83,6 +83,13 @@ func TestEvaluatePure_Exhaustive(t *testing.T) {
 			if result.ShouldPause != tt.expected {
 				t.Errorf("expected pause %v, got %v", tt.expected, result.ShouldPause)
 			}
+			expectedDecision := DecisionAllow
+			if tt.expected {
+				expectedDecision = DecisionRequireHuman
+			}
+			if result.Decision != expectedDecision {
+				t.Errorf("expected decision %s, got %s", expectedDecision, result.Decision)
+			}
 		})
 	}
 }
@@ -0,0 +1,36 @@
+package policy
+
+import "fmt"
+
+// ValidateEscalation checks a policy's escalation tiers and final timeout action. Each tier
+// needs a timeout and approver roles that do not overlap the break-glass override roles.
+// An unanswered HIGH materiality approval may never approve itself.
+func ValidateEscalation(e *Escalation, materiality MaterialityLevel, overrideRoles []string) error {
+	if e == nil {
+		return nil
+	}
+	if len(e.Tiers) == 0 {
+		return fmt.Errorf("%w: escalation must declare at least one tier", ErrInvalidPolicy)
+	}
+	for i, tier := range e.Tiers {
+		if tier.TimeoutSeconds <= 0 {
+			return fmt.Errorf("%w: escalation tier %d needs a positive timeout_seconds", ErrInvalidPolicy, i+1)
+		}
+		if len(tier.ApproverRoles) == 0 {
+			return fmt.Errorf("%w: escalation tier %d needs approver_roles", ErrInvalidPolicy, i+1)
+		}
+		if err := ValidateRoles(tier.ApproverRoles, overrideRoles); err != nil {
+			return fmt.Errorf("escalation tier %d: %w", i+1, err)
+		}
+	}
+	switch e.Final() {
+	case TimeoutReject, TimeoutTerminate:
+	case TimeoutApprove:
+		if materiality == MaterialityHigh {
+			return fmt.Errorf("%w: HIGH materiality approvals cannot time out to APPROVE", ErrInvalidPolicy)
+		}
+	default:
+		return fmt.Errorf("%w: unknown final_action %q", ErrInvalidPolicy, e.FinalAction)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// HashInput returns the SHA-256 of the exact input document handed to the policy engine.
+// Struct field order is fixed and map keys are sorted by encoding/json, so the hash is deterministic.
+func HashInput(in Input) (string, error) {
+	return hashJSON("policy input", in)
+}
+
+// HashResult returns the SHA-256 of the exact decision returned by the policy engine.
+func HashResult(r EvaluationResult) (string, error) {
+	return hashJSON("policy decision", r)
+}
+
+func hashJSON(what string, v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", what, err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
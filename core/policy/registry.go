@@ -0,0 +1,175 @@
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidPolicy indicates a policy definition that cannot be registered.
+	ErrInvalidPolicy = errors.New("invalid policy definition")
+	// ErrPolicyNotFound indicates that no matching policy version is registered.
+	ErrPolicyNotFound = errors.New("policy version not found")
+	// ErrPolicyDeprecated indicates that a deprecated version was requested for new executions.
+	ErrPolicyDeprecated = errors.New("policy version is deprecated")
+	// ErrPolicyTampered indicates that a stored body no longer matches its content address.
+	ErrPolicyTampered = errors.New("SECURITY ALERT: policy body does not match its version hash")
+)
+
+// policyNameRegex restricts names to URL- and log-safe identifiers.
+var policyNameRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.\-]{0,127}$`)
+
+// bundleHashRegex matches a hex-encoded SHA-256 bundle hash.
+var bundleHashRegex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// Version is an immutable, content-addressed revision of a named policy.
+// VersionID is the SHA-256 of Body, so a version can never change without changing its ID.
+type Version struct {
+	VersionID    string     `json:"version_id"`
+	Name         string     `json:"name"`
+	Body         []byte     `json:"-"` // Canonical bytes that VersionID is computed over
+	Policy       Policy     `json:"policy"`
+	Deprecated   bool       `json:"deprecated"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
+}
+
+// definition is the canonical, hashed form of a policy version.
+// Struct field order is fixed, so encoding/json output is deterministic.
+type definition struct {
+	Name                   string           `json:"name"`
+	Materiality            MaterialityLevel `json:"materiality"`
+	RequiresHumanApproval  bool             `json:"requires_human_approval"`
+	ApprovalTimeoutSeconds int64            `json:"approval_timeout_seconds"`
+	// Added after v1 bodies were registered: omitted when unset, so existing version IDs are unchanged.
+	Approvers          []string            `json:"approvers,omitempty"`
+	Quorum             int                 `json:"quorum,omitempty"`
+	SeparationOfDuties *SeparationOfDuties `json:"separation_of_duties,omitempty"`
+	ApproverRoles      []string            `json:"approver_roles,omitempty"`
+	OverrideRoles      []string            `json:"override_roles,omitempty"`
+	Escalation         *Escalation         `json:"escalation,omitempty"`
+	Bundle             string              `json:"bundle,omitempty"`
+}
+
+// NewVersion validates a policy definition and seals it into a content-addressed Version.
+// Any ID carried by p is ignored: the version ID is always derived from the content.
+func NewVersion(name string, p Policy) (*Version, error) {
+	if !policyNameRegex.MatchString(name) {
+		return nil, fmt.Errorf("%w: name must match %s", ErrInvalidPolicy, policyNameRegex)
+	}
+	switch p.Materiality {
+	case MaterialityLow, MaterialityMedium, MaterialityHigh:
+	default:
+		return nil, fmt.Errorf("%w: unknown materiality %q", ErrInvalidPolicy, p.Materiality)
+	}
+	if p.ApprovalTimeoutSeconds < 0 {
+		return nil, fmt.Errorf("%w: approval_timeout_seconds must not be negative", ErrInvalidPolicy)
+	}
+	if err := validateQuorum(p.Approvers, p.Quorum); err != nil {
+		return nil, err
+	}
+	if err := ValidateRoles(p.ApproverRoles, p.OverrideRoles); err != nil {
+		return nil, err
+	}
+	if err := ValidateEscalation(p.Escalation, p.Materiality, p.OverrideRoles); err != nil {
+		return nil, err
+	}
+	if p.Bundle != "" && !bundleHashRegex.MatchString(p.Bundle) {
+		return nil, fmt.Errorf("%w: bundle must be a lowercase hex SHA-256", ErrInvalidPolicy)
+	}
+
+	sod := p.SeparationOfDuties
+	if !sod.Enabled() {
+		sod = nil // No rules hash like no field
+	}
+
+	body, err := json.Marshal(definition{
+		Name:                   name,
+		Materiality:            p.Materiality,
+		RequiresHumanApproval:  p.RequiresHumanApproval,
+		ApprovalTimeoutSeconds: p.ApprovalTimeoutSeconds,
+		Approvers:              p.Approvers,
+		Quorum:                 p.Quorum,
+		SeparationOfDuties:     sod,
+		ApproverRoles:          p.ApproverRoles,
+		OverrideRoles:          p.OverrideRoles,
+		Escalation:             p.Escalation,
+		Bundle:                 p.Bundle,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+
+	return VersionFromBody(body)
+}
+
+// VersionFromBody rebuilds a Version from its canonical body, recomputing the version ID.
+// Stores use it on read so that a body altered at rest is detected (see VerifyVersion).
+func VersionFromBody(body []byte) (*Version, error) {
+	var def definition
+	if err := json.Unmarshal(body, &def); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+
+	hash := sha256.Sum256(body)
+	id := hex.EncodeToString(hash[:])
+
+	return &Version{
+		VersionID: id,
+		Name:      def.Name,
+		Body:      body,
+		Policy: Policy{
+			ID:                     id, // Pinned: the policy ID IS the version hash
+			Materiality:            def.Materiality,
+			RequiresHumanApproval:  def.RequiresHumanApproval,
+			ApprovalTimeoutSeconds: def.ApprovalTimeoutSeconds,
+			Approvers:              def.Approvers,
+			Quorum:                 def.Quorum,
+			SeparationOfDuties:     def.SeparationOfDuties,
+			ApproverRoles:          def.ApproverRoles,
+			OverrideRoles:          def.OverrideRoles,
+			Escalation:             def.Escalation,
+			Bundle:                 def.Bundle,
+		},
+	}, nil
+}
+
+// validateQuorum checks the approver groups and the number of approvals required.
+// With groups, each group counts at most once, so the quorum cannot exceed them.
+func validateQuorum(approvers []string, quorum int) error {
+	seen := make(map[string]bool, len(approvers))
+	for _, a := range approvers {
+		if strings.TrimSpace(a) == "" {
+			return fmt.Errorf("%w: approvers must not be empty", ErrInvalidPolicy)
+		}
+		if seen[a] {
+			return fmt.Errorf("%w: duplicate approver %q", ErrInvalidPolicy, a)
+		}
+		seen[a] = true
+	}
+	if quorum < 0 {
+		return fmt.Errorf("%w: quorum must not be negative", ErrInvalidPolicy)
+	}
+	if len(approvers) > 0 && quorum > len(approvers) {
+		return fmt.Errorf("%w: quorum %d exceeds the %d approver groups", ErrInvalidPolicy, quorum, len(approvers))
+	}
+	return nil
+}
+
+// VerifyVersion checks that body hashes to the claimed versionID.
+func VerifyVersion(versionID string, body []byte) (*Version, error) {
+	v, err := VersionFromBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(v.VersionID, versionID) {
+		return nil, fmt.Errorf("%w: claimed %s, calculated %s", ErrPolicyTampered, versionID, v.VersionID)
+	}
+	return v, nil
+}
@@ -0,0 +1,147 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewVersion_ContentAddressed(t *testing.T) {
+	p := Policy{ID: "client-chosen", Materiality: MaterialityHigh, RequiresHumanApproval: true, ApprovalTimeoutSeconds: 60}
+
+	v1, err := NewVersion("finance", p)
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
+	}
+	v2, _ := NewVersion("finance", p)
+
+	if v1.VersionID != v2.VersionID {
+		t.Errorf("identical content must yield identical version IDs: %s != %s", v1.VersionID, v2.VersionID)
+	}
+	if v1.Policy.ID != v1.VersionID {
+		t.Errorf("policy ID must be pinned to the version hash, got %s", v1.Policy.ID)
+	}
+
+	p.ApprovalTimeoutSeconds = 120
+	v3, _ := NewVersion("finance", p)
+	if v3.VersionID == v1.VersionID {
+		t.Error("changed content must yield a new version ID")
+	}
+}
+
+func TestNewVersion_Invalid(t *testing.T) {
+	cases := map[string]struct {
+		name string
+		p    Policy
+	}{
+		"bad name":          {"../etc", Policy{Materiality: MaterialityLow}},
+		"empty name":        {"", Policy{Materiality: MaterialityLow}},
+		"bad materiality":   {"ok", Policy{Materiality: "EXTREME"}},
+		"negative timeout":  {"ok", Policy{Materiality: MaterialityLow, ApprovalTimeoutSeconds: -1}},
+		"quorum too high":   {"ok", Policy{Materiality: MaterialityHigh, Approvers: []string{"group:eng"}, Quorum: 2}},
+		"negative quorum":   {"ok", Policy{Materiality: MaterialityHigh, Quorum: -1}},
+		"dup approvers":     {"ok", Policy{Materiality: MaterialityHigh, Approvers: []string{"group:eng", "group:eng"}}},
+		"empty role":        {"ok", Policy{Materiality: MaterialityHigh, ApproverRoles: []string{""}}},
+		"override approver": {"ok", Policy{Materiality: MaterialityHigh, ApproverRoles: []string{"approver"}, OverrideRoles: []string{"approver"}}},
+		"no tiers":          {"ok", Policy{Materiality: MaterialityLow, Escalation: &Escalation{}}},
+		"tier no timeout":   {"ok", Policy{Materiality: MaterialityLow, Escalation: &Escalation{Tiers: []EscalationTier{{ApproverRoles: []string{"lead"}}}}}},
+		"tier no roles":     {"ok", Policy{Materiality: MaterialityLow, Escalation: &Escalation{Tiers: []EscalationTier{{TimeoutSeconds: 60}}}}},
+		"tier override":     {"ok", Policy{Materiality: MaterialityLow, OverrideRoles: []string{"cso"}, Escalation: &Escalation{Tiers: []EscalationTier{{TimeoutSeconds: 60, ApproverRoles: []string{"cso"}}}}}},
+		"bad final action":  {"ok", Policy{Materiality: MaterialityLow, Escalation: &Escalation{Tiers: []EscalationTier{{TimeoutSeconds: 60, ApproverRoles: []string{"lead"}}}, FinalAction: "IGNORE"}}},
+		"high auto-approve": {"ok", Policy{Materiality: MaterialityHigh, Escalation: &Escalation{Tiers: []EscalationTier{{TimeoutSeconds: 60, ApproverRoles: []string{"lead"}}}, FinalAction: TimeoutApprove}}},
+		"bad bundle":        {"ok", Policy{Materiality: MaterialityLow, Bundle: "policies.tar.gz"}},
+	}
+	for desc, tc := range cases {
+		if _, err := NewVersion(tc.name, tc.p); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("%s: expected ErrInvalidPolicy, got %v", desc, err)
+		}
+	}
+}
+
+func TestVerifyVersion_DetectsTampering(t *testing.T) {
+	v, _ := NewVersion("finance", Policy{Materiality: MaterialityHigh, RequiresHumanApproval: true})
+
+	if _, err := VerifyVersion(v.VersionID, v.Body); err != nil {
+		t.Fatalf("untampered body rejected: %v", err)
+	}
+
+	tampered := []byte(`{"name":"finance","materiality":"HIGH","requires_human_approval":false,"approval_timeout_seconds":0}`)
+	if _, err := VerifyVersion(v.VersionID, tampered); !errors.Is(err, ErrPolicyTampered) {
+		t.Errorf("expected ErrPolicyTampered, got %v", err)
+	}
+}
+
+func TestNewVersion_SeparationOfDuties(t *testing.T) {
+	p := Policy{Materiality: MaterialityHigh, RequiresHumanApproval: true}
+	base, _ := NewVersion("finance", p)
+
+	// Declaring no rules must not change the version of existing policies.
+	p.SeparationOfDuties = &SeparationOfDuties{}
+	empty, _ := NewVersion("finance", p)
+	if empty.VersionID != base.VersionID {
+		t.Errorf("empty separation of duties changed the version: %s != %s", empty.VersionID, base.VersionID)
+	}
+
+	p.SeparationOfDuties = &SeparationOfDuties{ExcludeCreator: true}
+	v, err := NewVersion("finance", p)
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
+	}
+	if v.VersionID == base.VersionID {
+		t.Error("separation-of-duties rules must yield a new version ID")
+	}
+	got, err := VerifyVersion(v.VersionID, v.Body)
+	if err != nil {
+		t.Fatalf("VerifyVersion: %v", err)
+	}
+	if got.Policy.SeparationOfDuties == nil || !got.Policy.SeparationOfDuties.ExcludeCreator {
+		t.Errorf("rules lost in round trip: %+v", got.Policy.SeparationOfDuties)
+	}
+}
+
+func TestNewVersion_Escalation(t *testing.T) {
+	p := Policy{
+		Materiality:           MaterialityMedium,
+		RequiresHumanApproval: true,
+		Escalation: &Escalation{
+			Tiers: []EscalationTier{
+				{TimeoutSeconds: 3600, ApproverRoles: []string{"team-lead"}},
+				{TimeoutSeconds: 7200, ApproverRoles: []string{"director"}},
+			},
+			FinalAction: TimeoutApprove,
+		},
+	}
+	v, err := NewVersion("payments", p)
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
+	}
+	got, err := VerifyVersion(v.VersionID, v.Body)
+	if err != nil {
+		t.Fatalf("VerifyVersion: %v", err)
+	}
+	e := got.Policy.Escalation
+	if e == nil || len(e.Tiers) != 2 || e.Tiers[1].ApproverRoles[0] != "director" || e.Final() != TimeoutApprove {
+		t.Errorf("escalation lost in round trip: %+v", e)
+	}
+
+	p.Escalation = nil
+	if v2, _ := NewVersion("payments", p); v2.VersionID == v.VersionID {
+		t.Error("escalation tiers must yield a new version ID")
+	}
+}
+
+func TestNewVersion_Bundle(t *testing.T) {
+	p := Policy{Materiality: MaterialityHigh, RequiresHumanApproval: true}
+	plain, _ := NewVersion("finance", p)
+
+	p.Bundle = "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+	bound, err := NewVersion("finance", p)
+	if err != nil {
+		t.Fatalf("NewVersion: %v", err)
+	}
+	if bound.VersionID == plain.VersionID {
+		t.Error("the bundle must be part of the version ID")
+	}
+	if bound.Policy.Bundle != p.Bundle {
+		t.Errorf("bundle not pinned: %q", bound.Policy.Bundle)
+	}
+}
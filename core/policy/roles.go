@@ -0,0 +1,37 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateRoles checks a policy's approver and override roles. Override is break-glass:
+// no role may both approve and override, so an override always needs a role of its own.
+func ValidateRoles(approverRoles, overrideRoles []string) error {
+	approvers := make(map[string]bool, len(approverRoles))
+	for _, r := range approverRoles {
+		if strings.TrimSpace(r) == "" {
+			return fmt.Errorf("%w: approver_roles must not be empty", ErrInvalidPolicy)
+		}
+		approvers[r] = true
+	}
+	for _, r := range overrideRoles {
+		if strings.TrimSpace(r) == "" {
+			return fmt.Errorf("%w: override_roles must not be empty", ErrInvalidPolicy)
+		}
+		if approvers[r] {
+			return fmt.Errorf("%w: %q cannot be both an approver and an override role", ErrInvalidPolicy, r)
+		}
+	}
+	return nil
+}
+
+// AuthorizedRoles returns the approver and override roles that govern decisions: those of
+// the evaluation when the backend returned any, otherwise those of the registered policy,
+// so that a backend unaware of roles never drops them.
+func AuthorizedRoles(p Policy, result EvaluationResult) (approverRoles, overrideRoles []string) {
+	if len(result.ApproverRoles) > 0 || len(result.OverrideRoles) > 0 {
+		return result.ApproverRoles, result.OverrideRoles
+	}
+	return p.ApproverRoles, p.OverrideRoles
+}
@@ -9,17 +9,117 @@ const (
 	MaterialityHigh   MaterialityLevel = "HIGH"
 )
 
+// Decision is the advisory outcome of a policy evaluation (specs/04-policy-engine.md).
+type Decision string
+
+const (
+	DecisionAllow        Decision = "ALLOW"
+	DecisionRequireHuman Decision = "REQUIRE_HUMAN"
+	DecisionDeny         Decision = "DENY"
+)
+
 // Policy defines the governance rules for execution.
 type Policy struct {
 	ID                     string           `json:"id"`
 	Materiality            MaterialityLevel `json:"materiality"`
 	RequiresHumanApproval  bool             `json:"requires_human_approval,omitempty"`
 	ApprovalTimeoutSeconds int64            `json:"approval_timeout_seconds,omitempty"` // Default 24h if 0
+	Approvers              []string         `json:"approvers,omitempty"`                // Approver groups (e.g. "group:compliance")
+	Quorum                 int              `json:"quorum,omitempty"`                   // Approvals required; 0 keeps approvers advisory (one decision)
+	ApproverRoles          []string         `json:"approver_roles,omitempty"`           // Roles allowed to approve or reject; empty allows any
+	OverrideRoles          []string         `json:"override_roles,omitempty"`           // Break-glass roles allowed to override
+
+	SeparationOfDuties *SeparationOfDuties `json:"separation_of_duties,omitempty"`
+	Escalation         *Escalation         `json:"escalation,omitempty"`
+
+	// Bundle is the hash of the Rego bundle (Bundled.BundleHash) the version must be evaluated
+	// with. Empty evaluates with whatever the worker loaded, or the built-in rules.
+	Bundle string `json:"bundle,omitempty"`
+}
+
+// SeparationOfDuties are the separation-of-duties rules a policy imposes on human decisions.
+type SeparationOfDuties struct {
+	ExcludeCreator    bool `json:"exclude_creator,omitempty"`     // The instance's creator may not decide it
+	ExcludeCreatorOrg bool `json:"exclude_creator_org,omitempty"` // Nor anyone in the creator's org (team)
+	DistinctApprovers bool `json:"distinct_approvers,omitempty"`  // No actor may decide more than one stage
+}
+
+// Enabled reports whether any rule is set. A nil receiver has no rules.
+func (s *SeparationOfDuties) Enabled() bool {
+	return s != nil && (s.ExcludeCreator || s.ExcludeCreatorOrg || s.DistinctApprovers)
+}
+
+// TimeoutAction is what happens when an approval times out after its last escalation tier.
+type TimeoutAction string
+
+const (
+	TimeoutReject    TimeoutAction = "REJECT"    // Default: SYSTEM rejects the instance
+	TimeoutTerminate TimeoutAction = "TERMINATE" // The instance is terminated without a decision
+	TimeoutApprove   TimeoutAction = "APPROVE"   // SYSTEM approves; not allowed for HIGH materiality
+)
+
+// Escalation is the chain of tiers an unanswered approval escalates through before the
+// final timeout action. The first tier starts when the approval timeout expires.
+type Escalation struct {
+	Tiers       []EscalationTier `json:"tiers"`
+	FinalAction TimeoutAction    `json:"final_action,omitempty"` // Empty means REJECT
+}
+
+// EscalationTier hands an approval to other approver roles for a time of its own.
+type EscalationTier struct {
+	TimeoutSeconds int64    `json:"timeout_seconds"`
+	ApproverRoles  []string `json:"approver_roles"` // Replace the policy's approver roles while the tier is active
+}
+
+// Final returns the action taken once the last tier times out. A nil receiver rejects.
+func (e *Escalation) Final() TimeoutAction {
+	if e == nil || e.FinalAction == "" {
+		return TimeoutReject
+	}
+	return e.FinalAction
+}
+
+// Input is the document handed to a policy backend for evaluation.
+// Its JSON shape is the `input` seen by Rego policies (see examples/policies).
+type Input struct {
+	InstanceID   string                 `json:"instance_id,omitempty"`
+	Workflow     WorkflowRef            `json:"workflow"`
+	Policy       Policy                 `json:"policy"`
+	CurrentState string                 `json:"state,omitempty"`
+	Context      map[string]interface{} `json:"context"`
+	BundleHash   string                 `json:"bundle_hash,omitempty"` // The Rego bundle evaluated; empty for the built-in rules
+}
+
+// WorkflowRef describes the workflow being governed.
+type WorkflowRef struct {
+	ID          string           `json:"id"`
+	Materiality MaterialityLevel `json:"materiality"`
+}
+
+// NewInput builds the evaluation input for a policy and its trigger context.
+func NewInput(workflowID string, p Policy, triggerContext map[string]interface{}) Input {
+	if triggerContext == nil {
+		triggerContext = map[string]interface{}{}
+	}
+	return Input{
+		Workflow: WorkflowRef{
+			ID:          workflowID,
+			Materiality: p.Materiality,
+		},
+		Policy:  p,
+		Context: triggerContext,
+	}
 }
 
 // EvaluationResult captures the decision made by the Policy Engine.
 type EvaluationResult struct {
-	ShouldPause bool   `json:"should_pause"`
-	NextState   string `json:"next_state"` // e.g., "RUNNING", "WAITING_FOR_HUMAN"
-	Reason      string `json:"reason"`
+	Decision       Decision `json:"decision"`
+	ShouldPause    bool     `json:"should_pause"`
+	NextState      string   `json:"next_state"` // e.g., "RUNNING", "WAITING_FOR_HUMAN"
+	Reason         string   `json:"reason"`
+	Approvers      []string `json:"approvers,omitempty"`
+	Quorum         int      `json:"quorum,omitempty"`          // Approvals required (engine.NewQuorumRule)
+	TimeoutSeconds int64    `json:"timeout_seconds,omitempty"` // Overrides Policy.ApprovalTimeoutSeconds if > 0
+	ApproverRoles  []string `json:"approver_roles,omitempty"`  // Roles allowed to approve or reject
+	OverrideRoles  []string `json:"override_roles,omitempty"`  // Break-glass roles allowed to override
 }
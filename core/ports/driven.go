@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/policy"
 )
 
 // InstanceStore defines the secondary port for persistence.
@@ -16,5 +17,42 @@ type InstanceStore interface {
 
 	// GetAuditEvents retrieves the immutable event log for an instance.
 	GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error)
+	// RecordDecision records a human decision and moves the instance to nextState. It returns
+	// engine.ErrStaleInstance if the instance is no longer waiting at cmd.PrevArtifactHash.
 	RecordDecision(ctx context.Context, cmd engine.RecordDecisionCmd, nextState engine.State) (*engine.Instance, error)
+	// TransitionInstance applies a non-decision transition (resume, completion, termination).
+	// It returns engine.ErrStaleInstance if the instance is no longer in cmd.From at cmd.PrevArtifactHash.
+	TransitionInstance(ctx context.Context, cmd engine.TransitionCmd) (*engine.Instance, error)
+	// RecordVote records one vote of a multi-party decision and advances the chain head to
+	// the vote's artifact. It returns engine.ErrStaleInstance if the instance is no longer
+	// waiting for a decision at cmd.PrevArtifactHash.
+	RecordVote(ctx context.Context, cmd engine.RecordVoteCmd) (*engine.Instance, error)
+	// RecordEscalation moves an instance waiting for a decision to its next escalation tier,
+	// audit-logs the step and advances the chain head to the escalation's artifact. It returns
+	// engine.ErrStaleInstance if the instance is no longer waiting at cmd.PrevArtifactHash.
+	RecordEscalation(ctx context.Context, cmd engine.RecordEscalationCmd) (*engine.Instance, error)
+	// RecordRejectedDecision audit-logs a decision attempt refused by separation of duties.
+	// The instance is not changed.
+	RecordRejectedDecision(ctx context.Context, cmd engine.RejectedDecisionCmd) error
+	// QuarantineInstance parks an instance whose chain head failed verification.
+	// It returns engine.ErrStaleInstance if the instance is no longer in cmd.From at cmd.ArtifactHash.
+	QuarantineInstance(ctx context.Context, cmd engine.QuarantineCmd) (*engine.Instance, error)
+	// ResolveQuarantine records an operator decision and, on release, restores cmd.RestoreState.
+	ResolveQuarantine(ctx context.Context, cmd engine.ResolveQuarantineCmd) (*engine.Instance, error)
+}
+
+// PolicyStore defines the secondary port for the policy registry.
+// Versions are immutable: the only permitted mutation is deprecation.
+type PolicyStore interface {
+	// CreatePolicyVersion registers a version. Registering identical content again is a no-op
+	// that returns the existing version and created=false.
+	CreatePolicyVersion(ctx context.Context, v *policy.Version) (stored *policy.Version, created bool, err error)
+	// GetPolicyVersion retrieves a version by its content hash.
+	GetPolicyVersion(ctx context.Context, versionID string) (*policy.Version, error)
+	// GetLatestPolicyVersion retrieves the newest non-deprecated version of a named policy.
+	GetLatestPolicyVersion(ctx context.Context, name string) (*policy.Version, error)
+	// ListPolicyVersions lists versions, newest first. An empty name lists every policy.
+	ListPolicyVersions(ctx context.Context, name string) ([]*policy.Version, error)
+	// DeprecatePolicyVersion marks a version as unusable for new executions.
+	DeprecatePolicyVersion(ctx context.Context, versionID string) (*policy.Version, error)
 }
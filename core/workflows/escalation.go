@@ -0,0 +1,132 @@
+package workflows
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Rainminds/gantral/core/activities"
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/pkg/models"
+	"go.temporal.io/sdk/workflow"
+)
+
+// errApprovalExpired ends the wait for a decision when the approval times out with the
+// TERMINATE final action: no decision is recorded and the instance is terminated.
+var errApprovalExpired = errors.New("approval expired without a decision")
+
+// EscalationStatus is the answer to QueryEscalation: the active escalation tier (0 before
+// the first escalation), the roles that may approve in it and when it times out.
+type EscalationStatus struct {
+	InstanceID    string               `json:"instance_id"`
+	Tier          int                  `json:"tier"`
+	Tiers         int                  `json:"tiers"` // Escalation tiers declared by the policy
+	ApproverRoles []string             `json:"approver_roles"`
+	Deadline      time.Time            `json:"deadline"`
+	FinalAction   policy.TimeoutAction `json:"final_action"` // Taken when the last tier times out
+}
+
+// approvalDeadline is the approval timer of a waiting instance. When it fires, the
+// approval escalates to the next tier of the policy's escalation, if any remains;
+// otherwise the final timeout action applies.
+type approvalDeadline struct {
+	inst       *engine.Instance
+	escalation *policy.Escalation // nil: the timeout applies the final action directly
+	timeout    time.Duration      // Of the active tier
+	deadline   time.Time
+	timer      workflow.Future
+	expired    bool
+}
+
+// newApprovalDeadline starts the approval timer of the policy's approval timeout.
+func newApprovalDeadline(ctx workflow.Context, inst *engine.Instance, approvalTimeout time.Duration) *approvalDeadline {
+	d := &approvalDeadline{inst: inst, escalation: engine.EscalationFor(inst)}
+	d.start(ctx, approvalTimeout)
+	return d
+}
+
+func (d *approvalDeadline) start(ctx workflow.Context, timeout time.Duration) {
+	d.timeout = timeout
+	d.deadline = workflow.Now(ctx).Add(timeout)
+	d.timer = workflow.NewTimer(ctx, timeout)
+}
+
+// watch adds the active timer to selector; expired is set once it fires.
+func (d *approvalDeadline) watch(selector workflow.Selector) {
+	selector.AddFuture(d.timer, func(f workflow.Future) {
+		d.expired = true
+	})
+}
+
+// expire handles a fired timer. While tiers remain, it records the escalation to the next
+// tier (activities.RecordEscalation), restarts the timer on selector and returns nil: the
+// wait goes on. After the last tier it returns the SYSTEM decision of the final action, or
+// errApprovalExpired for TERMINATE.
+func (d *approvalDeadline) expire(ctx workflow.Context, selector workflow.Selector) (*activities.RecordDecisionInput, error) {
+	d.expired = false
+	if d.escalation == nil || d.inst.EscalationTier >= len(d.escalation.Tiers) {
+		return d.finalAction()
+	}
+
+	next := d.inst.EscalationTier + 1
+	tier := d.escalation.Tiers[next-1]
+	timeout := time.Duration(tier.TimeoutSeconds) * time.Second
+
+	var a *activities.ExecutionActivities
+	var artifact models.CommitmentArtifact
+	input := activities.EscalationInput{
+		InstanceID:    d.inst.ID,
+		Tier:          next,
+		ApproverRoles: tier.ApproverRoles,
+		Deadline:      workflow.Now(ctx).Add(timeout),
+	}
+	if err := executeGuarded(ctx, d.inst, a.RecordEscalation, input, &artifact); err != nil {
+		return nil, err
+	}
+	d.inst.EscalationTier = next
+	workflow.GetLogger(ctx).Info("Approval Escalated", "instance_id", d.inst.ID, "tier", next, "approver_roles", tier.ApproverRoles, "artifact_id", artifact.ArtifactID)
+
+	d.start(ctx, timeout)
+	d.watch(selector)
+	return nil, nil
+}
+
+// finalAction is the SYSTEM decision taken when the approval times out for good.
+func (d *approvalDeadline) finalAction() (*activities.RecordDecisionInput, error) {
+	switch d.escalation.Final() {
+	case policy.TimeoutTerminate:
+		return nil, errApprovalExpired
+	case policy.TimeoutApprove:
+		approval := activities.RecordDecisionInput{
+			InstanceID:    d.inst.ID,
+			DecisionType:  engine.DecisionApprove,
+			ActorID:       engine.ActorSystem,
+			Justification: fmt.Sprintf("Approval Timeout (%s) Exceeded: approved by policy", d.timeout),
+			Role:          engine.ActorSystem,
+		}
+		return &approval, nil
+	}
+	rejection := timeoutRejection(d.inst.ID, d.timeout)
+	return &rejection, nil
+}
+
+// status answers QueryEscalation.
+func (d *approvalDeadline) status() EscalationStatus {
+	approverRoles := engine.RoleRulesFor(d.inst).ApproverRoles
+	if approverRoles == nil {
+		approverRoles = []string{}
+	}
+	tiers := 0
+	if d.escalation != nil {
+		tiers = len(d.escalation.Tiers)
+	}
+	return EscalationStatus{
+		InstanceID:    d.inst.ID,
+		Tier:          d.inst.EscalationTier,
+		Tiers:         tiers,
+		ApproverRoles: approverRoles,
+		Deadline:      d.deadline,
+		FinalAction:   d.escalation.Final(),
+	}
+}
@@ -1,6 +1,8 @@
 package workflows
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,15 +18,78 @@ const (
 	// SignalHumanDecision is the signal name for HITL decisions.
 	SignalHumanDecision = "HumanDecision"
 
+	// SignalQuarantineResolution is the signal name for operator decisions on a
+	// quarantined instance (activities.ResolveQuarantineInput).
+	SignalQuarantineResolution = "QuarantineResolution"
+
+	// QueryApprovals is the query name for the approval status of a waiting instance
+	// (ApprovalStatus).
+	QueryApprovals = "approvals"
+
+	// QueryEscalation is the query name for the escalation tier and deadline of a waiting
+	// instance (EscalationStatus).
+	QueryEscalation = "escalation"
+
 	// TaskQueue is the default task queue for Gantral.
 	TaskQueue = "gantral-core"
 )
 
+// policyBackend is the optional deterministic policy backend (e.g. embedded Rego).
+// It is configured once at worker start-up via SetPolicyBackend, before any workflow runs.
+// Its evaluations are recorded in history (workflow.SideEffect), so replay never depends
+// on the bundle a worker has loaded.
+var policyBackend policy.Backend
+
+// policySideEffectChange versions the move of backend evaluation into a side effect.
+// Histories recorded before it replay with an inline evaluation against the loaded backend.
+const policySideEffectChange = "policy-side-effect"
+
+// SetPolicyBackend configures the policy backend used by GantralExecutionWorkflow.
+// The backend MUST be deterministic and SHOULD be fail-closed (see internal/policy.FailClosedEngine).
+// Passing nil restores the built-in rules (policy.EvaluatePure).
+func SetPolicyBackend(backend policy.Backend) {
+	policyBackend = backend
+}
+
 // WorkflowInput defines strict inputs for the execution workflow.
 type WorkflowInput struct {
 	WorkflowID     string
 	TriggerContext map[string]interface{}
-	Policy         policy.Policy
+	PolicyName     string        // Registry name the policy was resolved from
+	Policy         policy.Policy // Pinned version: Policy.ID is the version hash
+	CreatedBy      string        // Authenticated identity that created the instance
+	CreatorOrgID   string        // The creator's org (team)
+	// RequireIdentity refuses human decisions that carry no authenticated identity.
+	RequireIdentity bool
+}
+
+// ApprovalStatus is the answer to QueryApprovals: the quorum rule, the votes recorded so
+// far and what is still missing. Single-decision instances report a quorum of 1 and no votes.
+type ApprovalStatus struct {
+	InstanceID    string              `json:"instance_id"`
+	Approvers     []string            `json:"approvers"`
+	Quorum        int                 `json:"quorum"`
+	Votes         []engine.Vote       `json:"votes"`
+	PendingGroups []string            `json:"pending_groups"`
+	Outstanding   int                 `json:"outstanding"`       // Approvals still required
+	Outcome       engine.DecisionType `json:"outcome,omitempty"` // Set once the decision is made
+}
+
+func newApprovalStatus(instanceID string, tally *engine.Tally, outcome engine.DecisionType) ApprovalStatus {
+	approvers := tally.Rule.Approvers
+	if approvers == nil {
+		approvers = []string{}
+	}
+	votes := append([]engine.Vote{}, tally.Votes...)
+	return ApprovalStatus{
+		InstanceID:    instanceID,
+		Approvers:     approvers,
+		Quorum:        tally.Rule.Quorum,
+		Votes:         votes,
+		PendingGroups: tally.Pending(),
+		Outstanding:   tally.Outstanding(),
+		Outcome:       outcome,
+	}
 }
 
 // WorkflowResult defines the output of the execution workflow.
@@ -34,7 +99,7 @@ type WorkflowResult struct {
 }
 
 // GantralExecutionWorkflow orchestrates the lifecycle of a Gantral Instance.
-// It is deterministic and handles: Creation -> Policy Eval -> HITL -> Completion.
+// It is deterministic and handles: Creation -> Policy Eval -> HITL -> Resume -> Completion/Termination.
 func GantralExecutionWorkflow(ctx workflow.Context, input WorkflowInput) (WorkflowResult, error) {
 	logger := workflow.GetLogger(ctx)
 	logger.Info("Starting Gantral Execution Workflow", "workflow_id", input.WorkflowID)
@@ -51,9 +116,12 @@ func GantralExecutionWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 	ctx = workflow.WithActivityOptions(ctx, ao)
 
 	// B. Policy Evaluation (Deterministic Logic)
-	// We call the shared, pure function from core/policy.
-	// This ensures logic parity with the Engine and is safe for Replay (pure function).
-	evalResult := policy.EvaluatePure(input.Policy)
+	// We call the shared, pure function from core/policy (or the configured backend, whose
+	// result is recorded in history). This ensures logic parity with the Engine and is safe for Replay.
+	policyInput, evalResult, err := evaluatePolicy(ctx, input)
+	if err != nil {
+		return WorkflowResult{}, err
+	}
 
 	shouldPause := evalResult.ShouldPause
 	// Convert policy string state to engine state if necessary, but string matches
@@ -61,10 +129,53 @@ func GantralExecutionWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 	reason := evalResult.Reason
 
 	policyResult := map[string]interface{}{
+		"decision":     string(evalResult.Decision),
 		"should_pause": shouldPause,
 		"reason":       reason,
 		"policy_id":    input.Policy.ID,
 	}
+	if input.PolicyName != "" {
+		policyResult["policy_name"] = input.PolicyName
+	}
+	if policyInput.BundleHash != "" {
+		policyResult["policy_bundle_hash"] = policyInput.BundleHash
+	}
+
+	// Bind the exact policy input and decision into the evidence (artifact schema v2).
+	// Hashing is pure, so this is replay-safe.
+	// The input carries the bundle hash, so the Rego that ran is bound as well.
+	inputHash, err := policy.HashInput(policyInput)
+	if err != nil {
+		return WorkflowResult{}, err
+	}
+	decisionHash, err := policy.HashResult(evalResult)
+	if err != nil {
+		return WorkflowResult{}, err
+	}
+	policyResult[engine.PolicyInputHashKey] = inputHash
+	policyResult[engine.PolicyDecisionHashKey] = decisionHash
+	if len(evalResult.Approvers) > 0 {
+		policyResult["approvers"] = evalResult.Approvers
+	}
+	if evalResult.Quorum > 0 {
+		policyResult["quorum"] = evalResult.Quorum
+	}
+	if input.Policy.SeparationOfDuties.Enabled() {
+		policyResult[engine.SeparationOfDutiesKey] = input.Policy.SeparationOfDuties
+	}
+	approverRoles, overrideRoles := policy.AuthorizedRoles(input.Policy, evalResult)
+	if len(approverRoles) > 0 {
+		policyResult[engine.ApproverRolesKey] = approverRoles
+	}
+	if len(overrideRoles) > 0 {
+		policyResult[engine.OverrideRolesKey] = overrideRoles
+	}
+	if input.Policy.Escalation != nil {
+		policyResult[engine.EscalationKey] = input.Policy.Escalation
+	}
+	if input.RequireIdentity {
+		policyResult[engine.IdentityRequiredKey] = true
+	}
 
 	// C. Persist Instance (Create)
 	var inst *engine.Instance
@@ -73,93 +184,135 @@ func GantralExecutionWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		WorkflowID:      input.WorkflowID,
 		TriggerContext:  input.TriggerContext,
 		Policy:          nil,             // Metadata
-		PolicyVersionID: input.Policy.ID, // Assuming ID implies version for now
+		PolicyVersionID: input.Policy.ID, // Pinned registry version hash
 		InitialState:    nextState,
 		PolicyResult:    policyResult,
+		CreatedBy:       input.CreatedBy,
+		CreatorOrgID:    input.CreatorOrgID,
 	}
 
 	var a *activities.ExecutionActivities // nil struct for name resolution
-	err := workflow.ExecuteActivity(ctx, a.PersistInstance, persistInput).Get(ctx, &inst)
+	err = workflow.ExecuteActivity(ctx, a.PersistInstance, persistInput).Get(ctx, &inst)
 	if err != nil {
 		logger.Error("Failed to persist instance", "error", err)
 		return WorkflowResult{}, err
 	}
 
+	// Policy DENY (ADR-007): the instance was persisted as TERMINATED together with
+	// a SYSTEM denial artifact. Nothing else may happen for this execution.
+	if evalResult.Decision == policy.DecisionDeny {
+		logger.Info("Execution denied by policy", "instance_id", inst.ID, "reason", reason)
+		return WorkflowResult{
+			InstanceID: inst.ID,
+			FinalState: engine.StateTerminated,
+		}, nil
+	}
+
 	// D. HITL Loop
 	if shouldPause {
 		logger.Info("Blocking for Human Decision", "instance_id", inst.ID)
 
 		// Defaults (Configurable Timeout)
 		approvalTimeout := 24 * time.Hour
-		if input.Policy.ApprovalTimeoutSeconds > 0 {
+		if evalResult.TimeoutSeconds > 0 {
+			approvalTimeout = time.Duration(evalResult.TimeoutSeconds) * time.Second
+		} else if input.Policy.ApprovalTimeoutSeconds > 0 {
 			approvalTimeout = time.Duration(input.Policy.ApprovalTimeoutSeconds) * time.Second
 		}
-		var decisionInput activities.RecordDecisionInput
+		// Multi-party policies collect one vote per approver until the quorum decides.
+		tally := &engine.Tally{Rule: engine.NewQuorumRule(evalResult.Approvers, evalResult.Quorum)}
+		var decided engine.DecisionType
+		err := workflow.SetQueryHandler(ctx, QueryApprovals, func() (ApprovalStatus, error) {
+			return newApprovalStatus(inst.ID, tally, decided), nil
+		})
+		if err != nil {
+			return WorkflowResult{}, err
+		}
 
-		// Setup Selector
-		msg := "HITL Decision Received"
-		selector := workflow.NewSelector(ctx)
 		signalChan := workflow.GetSignalChannel(ctx, SignalHumanDecision)
-		timerFuture := workflow.NewTimer(ctx, approvalTimeout)
-
-		// 1. Handle Signal
-		selector.AddReceive(signalChan, func(c workflow.ReceiveChannel, more bool) {
-			c.Receive(ctx, &decisionInput)
+		deadline := newApprovalDeadline(ctx, inst, approvalTimeout)
+		err = workflow.SetQueryHandler(ctx, QueryEscalation, func() (EscalationStatus, error) {
+			return deadline.status(), nil
 		})
+		if err != nil {
+			return WorkflowResult{}, err
+		}
 
-		// 2. Handle Timeout
-		selector.AddFuture(timerFuture, func(f workflow.Future) {
-			msg = "HITL Timeout Exceeded"
-			// Construct System Rejection
-			decisionInput = activities.RecordDecisionInput{
-				InstanceID:    inst.ID,
-				DecisionType:  engine.DecisionReject,
-				ActorID:       "SYSTEM",
-				Justification: fmt.Sprintf("Approval Timeout (%s) Exceeded", approvalTimeout),
-				Role:          "SYSTEM",
-			}
-		})
+		var decision *activities.RecordDecisionInput
+		if tally.Rule.MultiParty() {
+			decision, err = awaitQuorum(ctx, inst, tally, signalChan, deadline)
+		} else {
+			var single activities.RecordDecisionInput
+			single, err = awaitDecision(ctx, inst, signalChan, deadline)
+			decision = &single
+		}
+		if errors.Is(err, errQuarantineAbandoned) {
+			return WorkflowResult{InstanceID: inst.ID, FinalState: engine.StateQuarantined}, nil
+		}
 
-		// Wait for one (Loop for validation)
-		for {
-			selector.Select(ctx)
+		if errors.Is(err, errApprovalExpired) {
+			// No decision: the instance stays WAITING_FOR_HUMAN and is terminated below.
+			logger.Info("Approval expired, terminating", "instance_id", inst.ID, "tier", inst.EscalationTier)
+		} else {
+			if err != nil {
+				logger.Error("Failed while waiting for a decision", "error", err)
+				return WorkflowResult{}, err
+			}
 
-			// 3. Validate
-			// If it was a timeout (SYSTEM actor), it's valid.
-			if decisionInput.ActorID == "SYSTEM" {
-				break
+			// Record Decision via Activity: a single decision (or override, or timeout), or the
+			// aggregate outcome of the votes.
+			var artifact models.CommitmentArtifact
+			if decision != nil {
+				decided = decision.DecisionType
+				err = executeGuarded(ctx, inst, a.RecordDecision, *decision, &artifact)
+			} else {
+				decided, _ = tally.Outcome()
+				quorumInput := activities.QuorumDecisionInput{
+					InstanceID:   inst.ID,
+					DecisionType: decided,
+					Rule:         tally.Rule,
+					Votes:        tally.Votes,
+				}
+				err = executeGuarded(ctx, inst, a.RecordQuorumDecision, quorumInput, &artifact)
 			}
-			// If it was a signal, check InstanceID
-			if decisionInput.InstanceID == inst.ID {
-				break
+			if errors.Is(err, errQuarantineAbandoned) {
+				return WorkflowResult{InstanceID: inst.ID, FinalState: engine.StateQuarantined}, nil
 			}
+			if err != nil {
+				logger.Error("Failed to record decision", "error", err)
+				return WorkflowResult{}, err
+			}
+			logger.Info("Decision Recorded & Artifact Emitted", "artifact_id", artifact.ArtifactID, "state", artifact.AuthorityState)
 
-			// Invalid signal: Log and continue waiting
-			logger.Warn("Received signal for wrong instance or invalid payload", "expected", inst.ID, "got", decisionInput.InstanceID)
-		}
-		logger.Info(msg, "instance_id", inst.ID)
-
-		// Ensure InstanceID is set for Timeout case if it wasn't
-		if decisionInput.InstanceID == "" {
-			decisionInput.InstanceID = inst.ID
+			// Update local state based on decision (same mapping RecordDecision applied)
+			nextState, err := engine.CalculateNextState(decided)
+			if err != nil {
+				return WorkflowResult{}, err
+			}
+			inst.State = nextState
 		}
+	}
 
-		// Record Decision via Activity
+	// E. Drive the instance to a terminal state. Every hop goes through the Transition
+	// activity so the artifact chain covers the full lifecycle, not only the decision.
+	for _, target := range lifecyclePath(inst.State) {
 		var artifact models.CommitmentArtifact
-		err := workflow.ExecuteActivity(ctx, a.RecordDecision, decisionInput).Get(ctx, &artifact)
+		transitionInput := activities.TransitionInput{
+			InstanceID:  inst.ID,
+			TargetState: target,
+			ActorID:     engine.ActorSystem,
+			Reason:      fmt.Sprintf("%s -> %s", inst.State, target),
+		}
+		err := executeGuarded(ctx, inst, a.Transition, transitionInput, &artifact)
+		if errors.Is(err, errQuarantineAbandoned) {
+			return WorkflowResult{InstanceID: inst.ID, FinalState: engine.StateQuarantined}, nil
+		}
 		if err != nil {
-			logger.Error("Failed to record decision", "error", err)
+			logger.Error("Failed to record transition", "error", err, "target", target)
 			return WorkflowResult{}, err
 		}
-		logger.Info("Decision Recorded & Artifact Emitted", "artifact_id", artifact.ArtifactID, "state", artifact.AuthorityState)
-
-		// Update local state based on decision
-		switch decisionInput.DecisionType {
-		case engine.DecisionApprove, engine.DecisionOverride:
-			inst.State = engine.StateApproved
-		case engine.DecisionReject:
-			inst.State = engine.StateRejected
-		}
+		inst.State = target
+		logger.Info("Transition Recorded & Artifact Emitted", "artifact_id", artifact.ArtifactID, "state", artifact.AuthorityState)
 	}
 
 	logger.Info("Workflow Completed", "final_state", inst.State)
@@ -168,3 +321,311 @@ func GantralExecutionWorkflow(ctx workflow.Context, input WorkflowInput) (Workfl
 		FinalState: inst.State,
 	}, nil
 }
+
+// awaitDecision blocks until a single HITL decision for the instance arrives, or until the
+// approval times out after its last escalation tier (see approvalDeadline.expire). Refused
+// decisions (see refused) are audit-logged and do not end the wait.
+func awaitDecision(ctx workflow.Context, inst *engine.Instance, signalChan workflow.ReceiveChannel, deadline *approvalDeadline) (activities.RecordDecisionInput, error) {
+	logger := workflow.GetLogger(ctx)
+	var decisionInput activities.RecordDecisionInput
+
+	// Setup Selector
+	msg := "HITL Decision Received"
+	selector := workflow.NewSelector(ctx)
+
+	// 1. Handle Signal
+	selector.AddReceive(signalChan, func(c workflow.ReceiveChannel, more bool) {
+		// Decode each signal into a fresh value: fields a payload omits, or that refused
+		// set on an earlier signal, must not carry over.
+		decisionInput = activities.RecordDecisionInput{}
+		c.Receive(ctx, &decisionInput)
+	})
+
+	// 2. Handle Timeout
+	deadline.watch(selector)
+
+	// Wait for one (Loop for validation)
+	for {
+		selector.Select(ctx)
+
+		// 3. Validate
+		// A timeout escalates, or ends the wait with the final action (SYSTEM actor).
+		if deadline.expired {
+			final, err := deadline.expire(ctx, selector)
+			if err != nil {
+				return activities.RecordDecisionInput{}, err
+			}
+			if final == nil {
+				continue
+			}
+			msg = "HITL Timeout Exceeded"
+			decisionInput = *final
+			break
+		}
+		// If it was a signal, check InstanceID
+		if decisionInput.InstanceID != inst.ID {
+			// Invalid signal: Log and continue waiting
+			logger.Warn("Received signal for wrong instance or invalid payload", "expected", inst.ID, "got", decisionInput.InstanceID)
+			continue
+		}
+		// 4. Identity, role and separation of duties
+		isRefused, err := refused(ctx, inst, &decisionInput)
+		if err != nil {
+			return activities.RecordDecisionInput{}, err
+		}
+		if !isRefused {
+			break
+		}
+	}
+	logger.Info(msg, "instance_id", inst.ID)
+
+	// Ensure InstanceID is set for Timeout case if it wasn't
+	if decisionInput.InstanceID == "" {
+		decisionInput.InstanceID = inst.ID
+	}
+	return decisionInput, nil
+}
+
+// awaitQuorum records every admissible HumanDecision signal as a vote (activities.RecordVote)
+// until the tally reaches an outcome, and returns nil. An OVERRIDE, or the approval timing
+// out after its last escalation tier, ends the vote instead: the returned decision is then
+// recorded as a single decision.
+// Duplicate votes and votes from actors outside the pending approver groups are ignored;
+// refused votes and overrides (see refused) are audit-logged and ignored.
+func awaitQuorum(ctx workflow.Context, inst *engine.Instance, tally *engine.Tally, signalChan workflow.ReceiveChannel, deadline *approvalDeadline) (*activities.RecordDecisionInput, error) {
+	logger := workflow.GetLogger(ctx)
+	var a *activities.ExecutionActivities
+
+	var signal activities.RecordDecisionInput
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(signalChan, func(c workflow.ReceiveChannel, more bool) {
+		signal = activities.RecordDecisionInput{}
+		c.Receive(ctx, &signal)
+	})
+	deadline.watch(selector)
+
+	for {
+		selector.Select(ctx)
+		if deadline.expired {
+			final, err := deadline.expire(ctx, selector)
+			if err != nil {
+				return nil, err
+			}
+			if final == nil {
+				continue
+			}
+			logger.Info("HITL Timeout Exceeded", "instance_id", inst.ID, "votes", len(tally.Votes))
+			return final, nil
+		}
+		if signal.InstanceID != inst.ID {
+			logger.Warn("Received signal for wrong instance or invalid payload", "expected", inst.ID, "got", signal.InstanceID)
+			continue
+		}
+		// Every voter so far has decided a stage of this instance.
+		signal.PriorActors = tally.Voters()
+
+		if signal.DecisionType == engine.DecisionOverride {
+			logger.Info("HITL Override Received", "instance_id", inst.ID, "actor_id", signal.ActorID)
+			isRefused, err := refused(ctx, inst, &signal)
+			if err != nil {
+				return nil, err
+			}
+			if isRefused {
+				continue
+			}
+			override := signal
+			return &override, nil
+		}
+
+		group, err := tally.Admit(signal.ActorID, engine.HeldRoles(signal.Identity, signal.Roles), signal.DecisionType)
+		if err != nil {
+			logger.Warn("Ignoring vote", "instance_id", inst.ID, "actor_id", signal.ActorID, "error", err)
+			continue
+		}
+		isRefused, err := refused(ctx, inst, &signal)
+		if err != nil {
+			return nil, err
+		}
+		if isRefused {
+			continue
+		}
+
+		var artifact models.CommitmentArtifact
+		voteInput := activities.RecordVoteInput{Decision: signal, Group: group}
+		if err := executeGuarded(ctx, inst, a.RecordVote, voteInput, &artifact); err != nil {
+			return nil, err
+		}
+		tally.Add(engine.Vote{
+			ActorID:    signal.ActorID,
+			Decision:   signal.DecisionType,
+			Group:      group,
+			ArtifactID: artifact.ArtifactID,
+		})
+		logger.Info("Vote Recorded", "instance_id", inst.ID, "actor_id", signal.ActorID, "group", group, "outstanding", tally.Outstanding())
+
+		if _, done := tally.Outcome(); done {
+			return nil, nil
+		}
+	}
+}
+
+// refused checks a decision before it is recorded: its actor against its authenticated
+// identity, its roles against the policy's approver or override roles, and the
+// separation-of-duties rules pinned on the instance. The role the decision is admitted
+// under is set as its AuthorizedRole. A refused attempt is audit-logged
+// (activities.RecordRejectedDecision) and emits no artifact; the caller keeps waiting for
+// an eligible decision.
+func refused(ctx workflow.Context, inst *engine.Instance, decision *activities.RecordDecisionInput) (bool, error) {
+	err := engine.CheckActor(decision.Identity, decision.ActorID, engine.IdentityRequiredFor(inst))
+	if err == nil {
+		held := engine.HeldRoles(decision.Identity, decision.Roles)
+		decision.AuthorizedRole, err = engine.AuthorizeRole(engine.RoleRulesFor(inst), decision.DecisionType, decision.ActorID, held)
+	}
+	if err == nil {
+		err = engine.CheckSeparationOfDuties(engine.SeparationOfDutiesFor(inst), inst, decision.ActorID, decision.ActorOrgID, decision.PriorActors)
+	}
+	if err == nil {
+		return false, nil
+	}
+	workflow.GetLogger(ctx).Warn("Decision refused", "instance_id", inst.ID, "actor_id", decision.ActorID, "error", err)
+
+	var a *activities.ExecutionActivities
+	rejected := activities.RejectedDecisionInput{Decision: *decision, Reason: err.Error()}
+	return true, workflow.ExecuteActivity(ctx, a.RecordRejectedDecision, rejected).Get(ctx, nil)
+}
+
+// timeoutRejection is the SYSTEM rejection recorded when the approval timeout fires.
+func timeoutRejection(instanceID string, approvalTimeout time.Duration) activities.RecordDecisionInput {
+	return activities.RecordDecisionInput{
+		InstanceID:    instanceID,
+		DecisionType:  engine.DecisionReject,
+		ActorID:       engine.ActorSystem,
+		Justification: fmt.Sprintf("Approval Timeout (%s) Exceeded", approvalTimeout),
+		Role:          engine.ActorSystem,
+	}
+}
+
+// errQuarantineAbandoned ends the workflow when an operator abandons a quarantined instance.
+var errQuarantineAbandoned = errors.New("quarantined instance abandoned by operator")
+
+// executeGuarded runs an activity that chains a new artifact from the instance's head.
+// If the activity quarantined the instance (activities.ErrTypeStateAmbiguous), the workflow
+// parks until an operator resolves it: RELEASE retries the activity, which verifies the
+// head again; ABANDON returns errQuarantineAbandoned. There is no timeout: an ambiguous
+// chain must never be resolved by the system.
+func executeGuarded(ctx workflow.Context, inst *engine.Instance, activityFn interface{}, input interface{}, result interface{}) error {
+	logger := workflow.GetLogger(ctx)
+	var a *activities.ExecutionActivities
+	for {
+		err := workflow.ExecuteActivity(ctx, activityFn, input).Get(ctx, result)
+		var appErr *temporal.ApplicationError
+		if err == nil || !errors.As(err, &appErr) || appErr.Type() != activities.ErrTypeStateAmbiguous {
+			return err
+		}
+		logger.Error("Instance quarantined, waiting for operator decision", "instance_id", inst.ID, "state", inst.State, "error", err)
+
+		resolution := awaitQuarantineResolution(ctx, inst.ID)
+		resolution.RestoreState = inst.State
+		if err := workflow.ExecuteActivity(ctx, a.ResolveQuarantine, resolution).Get(ctx, nil); err != nil {
+			return err
+		}
+		logger.Info("Quarantine resolved", "instance_id", inst.ID, "action", resolution.Action, "actor_id", resolution.ActorID)
+		if resolution.Action == engine.QuarantineAbandon {
+			return errQuarantineAbandoned
+		}
+	}
+}
+
+// awaitQuarantineResolution blocks until a valid operator decision for the instance arrives.
+func awaitQuarantineResolution(ctx workflow.Context, instanceID string) activities.ResolveQuarantineInput {
+	logger := workflow.GetLogger(ctx)
+	signalChan := workflow.GetSignalChannel(ctx, SignalQuarantineResolution)
+	for {
+		var resolution activities.ResolveQuarantineInput
+		signalChan.Receive(ctx, &resolution)
+
+		validAction := resolution.Action == engine.QuarantineRelease || resolution.Action == engine.QuarantineAbandon
+		if resolution.InstanceID == instanceID && validAction && resolution.ActorID != "" {
+			return resolution
+		}
+		logger.Warn("Ignoring invalid quarantine resolution", "expected", instanceID, "got", resolution.InstanceID, "action", resolution.Action)
+	}
+}
+
+// lifecyclePath returns the transitions that take an instance from a post-decision
+// (or auto-run) state to its terminal state:
+//
+//	APPROVED | OVERRIDDEN -> RESUMED -> RUNNING -> COMPLETED
+//	REJECTED              -> TERMINATED
+//	WAITING_FOR_HUMAN     -> TERMINATED (approval expired with the TERMINATE final action)
+//	RUNNING               -> COMPLETED
+func lifecyclePath(from engine.State) []engine.State {
+	switch from {
+	case engine.StateApproved, engine.StateOverridden:
+		return []engine.State{engine.StateResumed, engine.StateRunning, engine.StateCompleted}
+	case engine.StateRejected, engine.StateWaitingForHuman:
+		return []engine.State{engine.StateTerminated}
+	case engine.StateRunning:
+		return []engine.State{engine.StateCompleted}
+	default:
+		return nil
+	}
+}
+
+// policyEvaluation is the recorded outcome of a backend evaluation.
+type policyEvaluation struct {
+	Input  policy.Input
+	Result policy.EvaluationResult
+}
+
+// evaluatePolicy runs the configured policy backend against the workflow input.
+// Without a backend it falls back to the built-in pure rules.
+// It returns the exact input document evaluated, so that it can be hashed into the evidence.
+func evaluatePolicy(ctx workflow.Context, input WorkflowInput) (policy.Input, policy.EvaluationResult, error) {
+	in := policy.NewInput(input.WorkflowID, input.Policy, input.TriggerContext)
+	in.InstanceID = workflow.GetInfo(ctx).WorkflowExecution.ID
+	in.CurrentState = string(engine.StateCreated)
+
+	if policyBackend == nil {
+		in, result := evaluateBackend(nil, in)
+		return in, result, nil
+	}
+
+	if workflow.GetVersion(ctx, policySideEffectChange, workflow.DefaultVersion, 1) == workflow.DefaultVersion {
+		return in, evaluateInput(policyBackend, in), nil
+	}
+	var recorded policyEvaluation
+	err := workflow.SideEffect(ctx, func(workflow.Context) interface{} {
+		in, result := evaluateBackend(policyBackend, in)
+		return policyEvaluation{Input: in, Result: result}
+	}).Get(&recorded)
+	if err != nil {
+		return policy.Input{}, policy.EvaluationResult{}, fmt.Errorf("failed to read policy evaluation: %w", err)
+	}
+	return recorded.Input, recorded.Result, nil
+}
+
+// evaluateBackend binds the backend's bundle hash into in and evaluates it, or applies the
+// built-in rules when backend is nil. A policy version pinned to a bundle other than the
+// backend's is never evaluated.
+func evaluateBackend(backend policy.Backend, in policy.Input) (policy.Input, policy.EvaluationResult) {
+	in.BundleHash = policy.BundleHashOf(backend)
+	if in.Policy.Bundle != "" && in.Policy.Bundle != in.BundleHash {
+		// Fail-Closed: the Rego this version was registered with is not the Rego loaded.
+		reason := fmt.Sprintf("Fail-Closed: Policy bundle %s is not loaded (loaded: %q)", in.Policy.Bundle, in.BundleHash)
+		return in, policy.ResultForDecision(policy.DecisionRequireHuman, reason)
+	}
+	if backend == nil {
+		return in, policy.EvaluatePure(in.Policy)
+	}
+	return in, evaluateInput(backend, in)
+}
+
+func evaluateInput(backend policy.Backend, in policy.Input) policy.EvaluationResult {
+	result, err := backend.EvaluateInput(context.Background(), in)
+	if err != nil {
+		// Fail-Closed: an unusable policy can never let execution proceed unattended.
+		return policy.ResultForDecision(policy.DecisionRequireHuman, fmt.Sprintf("Fail-Closed: Policy Error (%v)", err))
+	}
+	return result
+}
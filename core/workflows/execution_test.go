@@ -1,7 +1,10 @@
 package workflows
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/Rainminds/gantral/pkg/models"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
 )
 
@@ -46,13 +50,19 @@ func (s *UnitTestSuite) Test_HappyPath_AutoApprove() {
 		a.PersistInstance,
 		mock.Anything,
 		mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
-			return arg.WorkflowID == "wf-123" && arg.InitialState == engine.StateRunning
+			// The policy input and decision hashes must be recorded for v2 artifacts
+			_, hasInput := arg.PolicyResult[engine.PolicyInputHashKey].(string)
+			_, hasDecision := arg.PolicyResult[engine.PolicyDecisionHashKey].(string)
+			return arg.WorkflowID == "wf-123" && arg.InitialState == engine.StateRunning && hasInput && hasDecision
 		}),
 	).Return(&engine.Instance{
 		ID:    "inst-mock-1",
 		State: engine.StateRunning,
 	}, nil)
 
+	// Auto-run instances are completed through the Transition activity
+	s.expectTransitions("inst-mock-1", engine.StateCompleted)
+
 	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
 
 	s.True(s.env.IsWorkflowCompleted())
@@ -61,7 +71,7 @@ func (s *UnitTestSuite) Test_HappyPath_AutoApprove() {
 	var result WorkflowResult
 	s.NoError(s.env.GetWorkflowResult(&result))
 	s.Equal("inst-mock-1", result.InstanceID)
-	s.Equal(engine.StateRunning, result.FinalState)
+	s.Equal(engine.StateCompleted, result.FinalState)
 }
 
 func (s *UnitTestSuite) Test_HITL_Flow_Approved() {
@@ -99,6 +109,9 @@ func (s *UnitTestSuite) Test_HITL_Flow_Approved() {
 		AuthorityState: "APPROVED",
 	}, nil) // Success
 
+	// Approval resumes the instance and runs it to completion, one artifact per hop
+	s.expectTransitions("inst-hitl-1", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
 	// Register Delayed Signal
 	s.env.RegisterDelayedCallback(func() {
 		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
@@ -117,10 +130,7 @@ func (s *UnitTestSuite) Test_HITL_Flow_Approved() {
 	var result WorkflowResult
 	s.NoError(s.env.GetWorkflowResult(&result))
 	s.Equal("inst-hitl-1", result.InstanceID)
-	// Simplified workflow logic updates local struct but returns the "FinalState"
-	// based on the switch case if we implemented strict state tracking.
-	// In the implementation I assumed StateApproved.
-	s.Equal(engine.StateApproved, result.FinalState)
+	s.Equal(engine.StateCompleted, result.FinalState)
 }
 
 func (s *UnitTestSuite) Test_ActivityFailure_Retry() {
@@ -172,6 +182,9 @@ func (s *UnitTestSuite) Test_HITL_Timeout() {
 		AuthorityState: "REJECTED",
 	}, nil)
 
+	// 3. Rejection terminates the instance
+	s.expectTransitions("inst-timeout-1", engine.StateTerminated)
+
 	// Note: No signal sent. We rely on time skipping.
 	// Temporal TestEnv automatically skips time if workflow is blocked on timer.
 
@@ -183,7 +196,749 @@ func (s *UnitTestSuite) Test_HITL_Timeout() {
 	var result WorkflowResult
 	s.NoError(s.env.GetWorkflowResult(&result))
 	s.Equal("inst-timeout-1", result.InstanceID)
-	s.Equal(engine.StateRejected, result.FinalState)
+	s.Equal(engine.StateTerminated, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_Flow_Override_Resumes() {
+	input := WorkflowInput{
+		WorkflowID: "wf-override",
+		Policy: policy.Policy{
+			ID:          "pol-high",
+			Materiality: policy.MaterialityHigh,
+		},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(&engine.Instance{
+		ID:    "inst-override-1",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.Anything).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-mock-override",
+		AuthorityState: "OVERRIDDEN",
+	}, nil)
+
+	// OVERRIDDEN -> RESUMED is legal; the old code reported it as APPROVED and stopped.
+	s.expectTransitions("inst-override-1", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID:    "inst-override-1",
+			DecisionType:  engine.DecisionOverride,
+			ActorID:       "human-1",
+			Justification: "Emergency",
+			ContextDelta:  map[string]interface{}{"limit": 10},
+		})
+	}, 1*time.Second)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateCompleted, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_Override_RequiresBreakGlassRole() {
+	input := WorkflowInput{
+		WorkflowID: "wf-break-glass",
+		Policy: policy.Policy{
+			ID:            "pol-roles",
+			Materiality:   policy.MaterialityHigh,
+			ApproverRoles: []string{"approver"},
+			OverrideRoles: []string{"break-glass"},
+		},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+		_, pinned := arg.PolicyResult[engine.OverrideRolesKey]
+		return pinned
+	})).Return(&engine.Instance{
+		ID:    "inst-break-glass",
+		State: engine.StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{
+			engine.ApproverRolesKey: []string{"approver"},
+			engine.OverrideRolesKey: []string{"break-glass"},
+		},
+	}, nil)
+	s.env.OnActivity(a.RecordRejectedDecision, mock.Anything, mock.MatchedBy(func(arg activities.RejectedDecisionInput) bool {
+		return arg.Decision.ActorID == "alice"
+	})).Return(nil).Once()
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.MatchedBy(func(arg activities.RecordDecisionInput) bool {
+		return arg.ActorID == "bob" && arg.AuthorizedRole == "break-glass"
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-break-glass",
+		AuthorityState: "OVERRIDDEN",
+	}, nil).Once()
+	s.expectTransitions("inst-break-glass", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
+	// An approver cannot override; the break-glass holder can.
+	s.env.RegisterDelayedCallback(func() {
+		for _, signal := range []struct {
+			actor string
+			roles []string
+		}{{"alice", []string{"approver"}}, {"bob", []string{"user", "break-glass"}}} {
+			s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+				InstanceID:    "inst-break-glass",
+				DecisionType:  engine.DecisionOverride,
+				ActorID:       signal.actor,
+				Roles:         signal.roles,
+				Justification: "Emergency",
+				ContextDelta:  map[string]interface{}{"limit": 10},
+			})
+		}
+	}, 1*time.Second)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateCompleted, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_RefusedSignalDoesNotLeakIntoNext() {
+	input := WorkflowInput{
+		WorkflowID: "wf-leak",
+		Policy: policy.Policy{
+			ID:            "pol-roles",
+			Materiality:   policy.MaterialityHigh,
+			ApproverRoles: []string{"approver"},
+			OverrideRoles: []string{"break-glass"},
+		},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(&engine.Instance{
+		ID:    "inst-leak",
+		State: engine.StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{
+			engine.ApproverRolesKey: []string{"approver"},
+			engine.OverrideRolesKey: []string{"break-glass"},
+		},
+	}, nil)
+	s.env.OnActivity(a.RecordRejectedDecision, mock.Anything, mock.MatchedBy(func(arg activities.RejectedDecisionInput) bool {
+		return arg.Decision.ActorID == "dev-mode|alice"
+	})).Return(nil).Once()
+	// Bob's signal carries no identity or org: none of Alice's may be recorded with it.
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.MatchedBy(func(arg activities.RecordDecisionInput) bool {
+		return arg.ActorID == "bob" && arg.Identity == nil && arg.ActorOrgID == "" && arg.AuthorizedRole == "approver"
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-approved",
+		AuthorityState: "APPROVED",
+	}, nil).Once()
+	s.expectTransitions("inst-leak", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
+	s.env.RegisterDelayedCallback(func() {
+		// Refused: an approver cannot override.
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID:    "inst-leak",
+			DecisionType:  engine.DecisionOverride,
+			ActorID:       "dev-mode|alice",
+			Identity:      &engine.ActorIdentity{Subject: "alice", Issuer: "dev-mode", Roles: []string{"approver"}},
+			ActorOrgID:    "payments",
+			Justification: "Emergency",
+			ContextDelta:  map[string]interface{}{"limit": 10},
+		})
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID:    "inst-leak",
+			DecisionType:  engine.DecisionApprove,
+			ActorID:       "bob",
+			Roles:         []string{"approver"},
+			Justification: "Reviewed",
+		})
+	}, 1*time.Second)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_HITL_Quorum_DualApproval() {
+	input := WorkflowInput{
+		WorkflowID: "wf-quorum",
+		Policy: policy.Policy{
+			ID:          "pol-dual",
+			Materiality: policy.MaterialityHigh,
+			Approvers:   []string{"group:engineering", "group:compliance"},
+			Quorum:      2,
+		},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(
+		a.PersistInstance,
+		mock.Anything,
+		mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+			return fmt.Sprint(arg.PolicyResult["quorum"]) == "2"
+		}),
+	).Return(&engine.Instance{
+		ID:    "inst-quorum-1",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+	for _, voter := range []struct{ actor, group string }{{"alice", "group:engineering"}, {"carol", "group:compliance"}} {
+		voter := voter
+		s.env.OnActivity(a.RecordVote, mock.Anything, mock.MatchedBy(func(arg activities.RecordVoteInput) bool {
+			return arg.Decision.ActorID == voter.actor && arg.Group == voter.group
+		})).Return(&models.CommitmentArtifact{
+			ArtifactID:     "art-vote-" + voter.actor,
+			AuthorityState: "WAITING_FOR_HUMAN",
+		}, nil).Once()
+	}
+	s.env.OnActivity(a.RecordQuorumDecision, mock.Anything, mock.MatchedBy(func(arg activities.QuorumDecisionInput) bool {
+		return arg.DecisionType == engine.DecisionApprove && len(arg.Votes) == 2 &&
+			arg.Votes[0].ArtifactID == "art-vote-alice" && arg.Votes[1].ArtifactID == "art-vote-carol"
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-quorum",
+		AuthorityState: "APPROVED",
+	}, nil).Once()
+	s.expectTransitions("inst-quorum-1", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
+	vote := func(actor string, roles ...string) activities.RecordDecisionInput {
+		return activities.RecordDecisionInput{
+			InstanceID:   "inst-quorum-1",
+			DecisionType: engine.DecisionApprove,
+			ActorID:      actor,
+			Roles:        roles,
+		}
+	}
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalHumanDecision, vote("alice", "group:engineering", "group:compliance"))
+	}, 1*time.Second)
+	s.env.RegisterDelayedCallback(func() {
+		// Duplicate vote, and a second engineer: neither counts.
+		s.env.SignalWorkflow(SignalHumanDecision, vote("alice", "group:engineering", "group:compliance"))
+		s.env.SignalWorkflow(SignalHumanDecision, vote("bob", "group:engineering"))
+	}, 2*time.Second)
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(QueryApprovals)
+		s.NoError(err)
+		var status ApprovalStatus
+		s.NoError(value.Get(&status))
+		s.Equal(2, status.Quorum)
+		s.Equal(1, status.Outstanding)
+		s.Equal([]string{"group:compliance"}, status.PendingGroups)
+		s.Len(status.Votes, 1)
+		s.Empty(status.Outcome)
+	}, 3*time.Second)
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalHumanDecision, vote("carol", "group:compliance"))
+	}, 4*time.Second)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateCompleted, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_Quorum_RejectEndsVote() {
+	input := WorkflowInput{
+		WorkflowID: "wf-quorum-reject",
+		Policy:     policy.Policy{ID: "pol-two", Materiality: policy.MaterialityHigh, Quorum: 2},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(&engine.Instance{
+		ID:    "inst-quorum-2",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+	s.env.OnActivity(a.RecordVote, mock.Anything, mock.Anything).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-vote",
+		AuthorityState: "WAITING_FOR_HUMAN",
+	}, nil).Twice()
+	s.env.OnActivity(a.RecordQuorumDecision, mock.Anything, mock.MatchedBy(func(arg activities.QuorumDecisionInput) bool {
+		return arg.DecisionType == engine.DecisionReject && len(arg.Votes) == 2
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-quorum",
+		AuthorityState: "REJECTED",
+	}, nil).Once()
+	s.expectTransitions("inst-quorum-2", engine.StateTerminated)
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID: "inst-quorum-2", DecisionType: engine.DecisionApprove, ActorID: "alice",
+		})
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID: "inst-quorum-2", DecisionType: engine.DecisionReject, ActorID: "bob",
+		})
+	}, 1*time.Second)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateTerminated, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_SeparationOfDuties_RefusesCreator() {
+	rules := &policy.SeparationOfDuties{ExcludeCreator: true}
+	input := WorkflowInput{
+		WorkflowID: "wf-sod",
+		Policy:     policy.Policy{ID: "pol-sod", Materiality: policy.MaterialityHigh, SeparationOfDuties: rules},
+		CreatedBy:  "alice",
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+		_, pinned := arg.PolicyResult[engine.SeparationOfDutiesKey]
+		return arg.CreatedBy == "alice" && pinned
+	})).Return(&engine.Instance{
+		ID:            "inst-sod",
+		State:         engine.StateWaitingForHuman,
+		CreatedBy:     "alice",
+		PolicyContext: map[string]interface{}{engine.SeparationOfDutiesKey: rules},
+	}, nil)
+	s.env.OnActivity(a.RecordRejectedDecision, mock.Anything, mock.MatchedBy(func(arg activities.RejectedDecisionInput) bool {
+		return arg.Decision.ActorID == "alice" && arg.Reason != ""
+	})).Return(nil).Once()
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.MatchedBy(func(arg activities.RecordDecisionInput) bool {
+		return arg.ActorID == "bob"
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-sod",
+		AuthorityState: "APPROVED",
+	}, nil).Once()
+	s.expectTransitions("inst-sod", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
+	// The creator's own approval is refused and audit-logged; the workflow keeps waiting.
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID: "inst-sod", DecisionType: engine.DecisionApprove, ActorID: "alice", Justification: "Mine",
+		})
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID: "inst-sod", DecisionType: engine.DecisionApprove, ActorID: "bob", Justification: "Reviewed",
+		})
+	}, 1*time.Second)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateCompleted, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_Escalation_TierApproverDecides() {
+	escalation := &policy.Escalation{Tiers: []policy.EscalationTier{{TimeoutSeconds: 3600, ApproverRoles: []string{"director"}}}}
+	input := WorkflowInput{
+		WorkflowID: "wf-escalation",
+		Policy: policy.Policy{
+			ID:                     "pol-escalation",
+			Materiality:            policy.MaterialityHigh,
+			ApprovalTimeoutSeconds: 3600,
+			ApproverRoles:          []string{"approver"},
+			Escalation:             escalation,
+		},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+		_, pinned := arg.PolicyResult[engine.EscalationKey]
+		return pinned
+	})).Return(&engine.Instance{
+		ID:    "inst-escalation",
+		State: engine.StateWaitingForHuman,
+		PolicyContext: map[string]interface{}{
+			engine.ApproverRolesKey: []string{"approver"},
+			engine.EscalationKey:    escalation,
+		},
+	}, nil)
+	s.env.OnActivity(a.RecordEscalation, mock.Anything, mock.MatchedBy(func(arg activities.EscalationInput) bool {
+		return arg.InstanceID == "inst-escalation" && arg.Tier == 1 && arg.ApproverRoles[0] == "director"
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-escalated",
+		AuthorityState: "WAITING_FOR_HUMAN",
+	}, nil).Once()
+	s.env.OnActivity(a.RecordRejectedDecision, mock.Anything, mock.MatchedBy(func(arg activities.RejectedDecisionInput) bool {
+		return arg.Decision.ActorID == "alice"
+	})).Return(nil).Once()
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.MatchedBy(func(arg activities.RecordDecisionInput) bool {
+		return arg.ActorID == "bob" && arg.AuthorizedRole == "director"
+	})).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-approved",
+		AuthorityState: "APPROVED",
+	}, nil).Once()
+	s.expectTransitions("inst-escalation", engine.StateResumed, engine.StateRunning, engine.StateCompleted)
+
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(QueryEscalation)
+		s.NoError(err)
+		var status EscalationStatus
+		s.NoError(value.Get(&status))
+		s.Equal(0, status.Tier)
+		s.Equal(1, status.Tiers)
+		s.Equal([]string{"approver"}, status.ApproverRoles)
+		s.Equal(policy.TimeoutReject, status.FinalAction)
+	}, 30*time.Minute)
+
+	// After the first hour the approval has escalated: the original approver is refused,
+	// the tier's director decides.
+	s.env.RegisterDelayedCallback(func() {
+		value, err := s.env.QueryWorkflow(QueryEscalation)
+		s.NoError(err)
+		var status EscalationStatus
+		s.NoError(value.Get(&status))
+		s.Equal(1, status.Tier)
+		s.Equal([]string{"director"}, status.ApproverRoles)
+		s.WithinDuration(s.env.Now().Add(30*time.Minute), status.Deadline, time.Second)
+
+		for _, signal := range []struct {
+			actor string
+			roles []string
+		}{{"alice", []string{"approver"}}, {"bob", []string{"director"}}} {
+			s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+				InstanceID:    "inst-escalation",
+				DecisionType:  engine.DecisionApprove,
+				ActorID:       signal.actor,
+				Roles:         signal.roles,
+				Justification: "Reviewed",
+			})
+		}
+	}, 90*time.Minute)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateCompleted, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_HITL_Escalation_FinalActions() {
+	for _, tc := range []struct {
+		final    policy.TimeoutAction
+		decision engine.DecisionType
+		path     []engine.State
+		state    engine.State
+	}{
+		{policy.TimeoutTerminate, "", []engine.State{engine.StateTerminated}, engine.StateTerminated},
+		{policy.TimeoutApprove, engine.DecisionApprove, []engine.State{engine.StateResumed, engine.StateRunning, engine.StateCompleted}, engine.StateCompleted},
+	} {
+		s.Run(string(tc.final), func() {
+			s.SetupTest()
+			escalation := &policy.Escalation{
+				Tiers: []policy.EscalationTier{
+					{TimeoutSeconds: 600, ApproverRoles: []string{"lead"}},
+					{TimeoutSeconds: 600, ApproverRoles: []string{"director"}},
+				},
+				FinalAction: tc.final,
+			}
+			input := WorkflowInput{
+				WorkflowID: "wf-final",
+				Policy: policy.Policy{
+					ID:                    "pol-final",
+					Materiality:           policy.MaterialityMedium,
+					RequiresHumanApproval: true,
+					Escalation:            escalation,
+				},
+			}
+
+			var a *activities.ExecutionActivities
+			s.env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(&engine.Instance{
+				ID:            "inst-final",
+				State:         engine.StateWaitingForHuman,
+				PolicyContext: map[string]interface{}{engine.EscalationKey: escalation},
+			}, nil)
+			for tier := 1; tier <= 2; tier++ {
+				tier := tier
+				s.env.OnActivity(a.RecordEscalation, mock.Anything, mock.MatchedBy(func(arg activities.EscalationInput) bool {
+					return arg.Tier == tier
+				})).Return(&models.CommitmentArtifact{
+					ArtifactID:     fmt.Sprintf("art-tier-%d", tier),
+					AuthorityState: "WAITING_FOR_HUMAN",
+				}, nil).Once()
+			}
+			if tc.decision != "" {
+				s.env.OnActivity(a.RecordDecision, mock.Anything, mock.MatchedBy(func(arg activities.RecordDecisionInput) bool {
+					return arg.ActorID == engine.ActorSystem && arg.DecisionType == tc.decision
+				})).Return(&models.CommitmentArtifact{
+					ArtifactID:     "art-timeout",
+					AuthorityState: string(engine.StateApproved),
+				}, nil).Once()
+			}
+			s.expectTransitions("inst-final", tc.path...)
+
+			// No signal: the default timeout and both tiers expire.
+			s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+			s.True(s.env.IsWorkflowCompleted())
+			s.NoError(s.env.GetWorkflowError())
+
+			var result WorkflowResult
+			s.NoError(s.env.GetWorkflowResult(&result))
+			s.Equal(tc.state, result.FinalState)
+			s.env.AssertExpectations(s.T())
+		})
+	}
+}
+
+// expectTransitions registers one Transition activity call per target state, in order.
+func (s *UnitTestSuite) expectTransitions(instanceID string, targets ...engine.State) {
+	var a *activities.ExecutionActivities
+	for _, target := range targets {
+		target := target
+		s.env.OnActivity(
+			a.Transition,
+			mock.Anything,
+			mock.MatchedBy(func(arg activities.TransitionInput) bool {
+				return arg.InstanceID == instanceID && arg.TargetState == target
+			}),
+		).Return(&models.CommitmentArtifact{
+			ArtifactID:     "art-" + string(target),
+			AuthorityState: string(target),
+		}, nil).Once()
+	}
+}
+
+type stubBackend struct {
+	result policy.EvaluationResult
+	bundle string
+}
+
+func (b stubBackend) EvaluateInput(ctx context.Context, in policy.Input) (policy.EvaluationResult, error) {
+	return b.result, nil
+}
+
+func (b stubBackend) BundleHash() string {
+	return b.bundle
+}
+
+func (s *UnitTestSuite) Test_PolicyBackend_OverridesBuiltinRules() {
+	// Low materiality would auto-run under the built-in rules; the backend requires a human.
+	SetPolicyBackend(stubBackend{result: policy.EvaluationResult{
+		ShouldPause:    true,
+		NextState:      "WAITING_FOR_HUMAN",
+		Reason:         "rego says so",
+		Approvers:      []string{"group:compliance"},
+		TimeoutSeconds: 60,
+	}})
+	defer SetPolicyBackend(nil)
+
+	input := WorkflowInput{
+		WorkflowID: "wf-backend",
+		Policy:     policy.Policy{ID: "pol-low", Materiality: policy.MaterialityLow},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(
+		a.PersistInstance,
+		mock.Anything,
+		mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+			return arg.InitialState == engine.StateWaitingForHuman &&
+				arg.PolicyResult["reason"] == "rego says so"
+		}),
+	).Return(&engine.Instance{
+		ID:    "inst-backend-1",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.Anything).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-mock-backend",
+		AuthorityState: "REJECTED",
+	}, nil)
+	s.expectTransitions("inst-backend-1", engine.StateTerminated)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_PolicyBackend_BindsBundleHash() {
+	SetPolicyBackend(stubBackend{result: policy.ResultForDecision(policy.DecisionDeny, "forbidden"), bundle: "bundle-1"})
+	defer SetPolicyBackend(nil)
+
+	input := WorkflowInput{
+		WorkflowID: "wf-bundle",
+		Policy:     policy.Policy{ID: "pol-bundle"},
+	}
+	in := policy.NewInput(input.WorkflowID, input.Policy, nil)
+	in.InstanceID = "default-test-workflow-id"
+	in.CurrentState = string(engine.StateCreated)
+	in.BundleHash = "bundle-1"
+	inputHash, err := policy.HashInput(in)
+	s.NoError(err)
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(
+		a.PersistInstance,
+		mock.Anything,
+		mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+			return arg.PolicyResult["policy_bundle_hash"] == "bundle-1" &&
+				arg.PolicyResult[engine.PolicyInputHashKey] == inputHash
+		}),
+	).Return(&engine.Instance{ID: "inst-bundle-1", State: engine.StateTerminated}, nil)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_PolicyBackend_PinnedBundleMismatchFailsClosed() {
+	// The version was registered against bundle-1; the worker loaded bundle-2.
+	SetPolicyBackend(stubBackend{result: policy.ResultForDecision(policy.DecisionAllow, "rego allows"), bundle: "bundle-2"})
+	defer SetPolicyBackend(nil)
+
+	input := WorkflowInput{
+		WorkflowID: "wf-bundle",
+		Policy:     policy.Policy{ID: "pol-bundle", Materiality: policy.MaterialityLow, Bundle: "bundle-1"},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(
+		a.PersistInstance,
+		mock.Anything,
+		mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+			reason, _ := arg.PolicyResult["reason"].(string)
+			return arg.InitialState == engine.StateWaitingForHuman && strings.Contains(reason, "bundle-1")
+		}),
+	).Return(&engine.Instance{ID: "inst-bundle-2", State: engine.StateWaitingForHuman}, nil)
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.Anything).Return(&models.CommitmentArtifact{
+		ArtifactID:     "art-mock-bundle",
+		AuthorityState: "REJECTED",
+	}, nil)
+	s.expectTransitions("inst-bundle-2", engine.StateTerminated)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *UnitTestSuite) Test_PolicyDeny_Terminates() {
+	SetPolicyBackend(stubBackend{result: policy.ResultForDecision(policy.DecisionDeny, "forbidden")})
+	defer SetPolicyBackend(nil)
+
+	input := WorkflowInput{
+		WorkflowID: "wf-deny",
+		Policy:     policy.Policy{ID: "pol-deny"},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(
+		a.PersistInstance,
+		mock.Anything,
+		mock.MatchedBy(func(arg activities.PersistInstanceInput) bool {
+			return arg.InitialState == engine.StateTerminated &&
+				arg.PolicyResult["decision"] == "DENY"
+		}),
+	).Return(&engine.Instance{
+		ID:               "inst-deny-1",
+		State:            engine.StateTerminated,
+		LastArtifactHash: "art-denial",
+	}, nil)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateTerminated, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_Quarantine_ReleaseRetries() {
+	input := WorkflowInput{
+		WorkflowID: "wf-quarantine",
+		Policy:     policy.Policy{ID: "pol-low", Materiality: policy.MaterialityLow},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(&engine.Instance{
+		ID:    "inst-q-1",
+		State: engine.StateRunning,
+	}, nil)
+
+	// The first attempt finds a phantom head and quarantines the instance.
+	ambiguous := temporal.NewNonRetryableApplicationError("phantom head", activities.ErrTypeStateAmbiguous, nil)
+	s.env.OnActivity(a.Transition, mock.Anything, mock.Anything).Return(nil, ambiguous).Once()
+	s.env.OnActivity(a.ResolveQuarantine, mock.Anything, mock.MatchedBy(func(arg activities.ResolveQuarantineInput) bool {
+		return arg.InstanceID == "inst-q-1" && arg.Action == engine.QuarantineRelease && arg.RestoreState == engine.StateRunning
+	})).Return(&engine.Instance{ID: "inst-q-1", State: engine.StateRunning}, nil).Once()
+	s.expectTransitions("inst-q-1", engine.StateCompleted)
+
+	s.env.RegisterDelayedCallback(func() {
+		// Invalid resolutions are ignored; the workflow keeps waiting.
+		s.env.SignalWorkflow(SignalQuarantineResolution, activities.ResolveQuarantineInput{
+			InstanceID: "inst-q-1",
+			Action:     engine.QuarantineRelease,
+		})
+		s.env.SignalWorkflow(SignalQuarantineResolution, activities.ResolveQuarantineInput{
+			InstanceID:    "inst-q-1",
+			Action:        engine.QuarantineRelease,
+			ActorID:       "operator-1",
+			Justification: "head repaired from reconciliation report",
+		})
+	}, 48*time.Hour) // No timeout: the instance stays parked until an operator decides.
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateCompleted, result.FinalState)
+}
+
+func (s *UnitTestSuite) Test_Quarantine_Abandon() {
+	input := WorkflowInput{
+		WorkflowID: "wf-quarantine-abandon",
+		Policy:     policy.Policy{ID: "pol-high", Materiality: policy.MaterialityHigh},
+	}
+
+	var a *activities.ExecutionActivities
+	s.env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(&engine.Instance{
+		ID:    "inst-q-2",
+		State: engine.StateWaitingForHuman,
+	}, nil)
+	ambiguous := temporal.NewNonRetryableApplicationError("stale head", activities.ErrTypeStateAmbiguous, nil)
+	s.env.OnActivity(a.RecordDecision, mock.Anything, mock.Anything).Return(nil, ambiguous).Once()
+	s.env.OnActivity(a.ResolveQuarantine, mock.Anything, mock.MatchedBy(func(arg activities.ResolveQuarantineInput) bool {
+		return arg.Action == engine.QuarantineAbandon && arg.RestoreState == engine.StateWaitingForHuman
+	})).Return(&engine.Instance{ID: "inst-q-2", State: engine.StateQuarantined}, nil).Once()
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID:   "inst-q-2",
+			DecisionType: engine.DecisionApprove,
+			ActorID:      "human-1",
+		})
+	}, time.Second)
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow(SignalQuarantineResolution, activities.ResolveQuarantineInput{
+			InstanceID: "inst-q-2",
+			Action:     engine.QuarantineAbandon,
+			ActorID:    "operator-1",
+		})
+	}, time.Minute)
+
+	s.env.ExecuteWorkflow(GantralExecutionWorkflow, input)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	var result WorkflowResult
+	s.NoError(s.env.GetWorkflowResult(&result))
+	s.Equal(engine.StateQuarantined, result.FinalState)
 }
 
 func TestWorkflowTestSuite(t *testing.T) {
@@ -23,6 +23,17 @@ def generate_dev_token():
     encoded = jwt.encode(payload, AUTH_SECRET, algorithm="HS256")
     return encoded
 
+def register_policy(headers):
+    """Registers the demo policy. Idempotent: identical content returns the existing version."""
+    policy = {
+        "name": "demo-high",
+        "materiality": "HIGH",
+        "requires_human_approval": True
+    }
+    resp = requests.post(f"{GANTRAL_URL}/policies", json=policy, headers=headers, timeout=5)
+    resp.raise_for_status()
+    print(f"Policy version: {resp.json()['version_id']}")
+
 def trigger_workflow():
     token = generate_dev_token()
     headers = {
@@ -33,10 +44,11 @@ def trigger_workflow():
     payload = {
         "workflow_id": "demo-agent-flow", 
         "trigger_context": {"tier": "prod"}, 
-        "policy": {"materiality": "HIGH"}
+        "policy_name": "demo-high"
     }
     
     try:
+        register_policy(headers)
         print(f"Triggering workflow at {GANTRAL_URL}...")
         resp = requests.post(f"{GANTRAL_URL}/instances", json=payload, headers=headers, timeout=5)
         print(f"Status: {resp.status_code}")
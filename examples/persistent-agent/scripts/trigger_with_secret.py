@@ -40,9 +40,14 @@ def trigger_workflow():
                 "MOCK_VAL": "gantral+secret://vault/my-mock-secret"
             }
         }, 
-        "policy": {"materiality": "LOW"}, # LOW to avoid Human Approval for quick test
+        "policy_name": "secret-test-low", # LOW to avoid Human Approval for quick test
+    }
+    
+    # Policies are registered server-side; instances reference them by name.
+    policy = {"name": "secret-test-low", "materiality": "LOW"}
     
     try:
+        requests.post(f"{GANTRAL_URL}/policies", json=policy, headers=headers, timeout=5).raise_for_status()
         print(f"Triggering workflow with SECRETS at {GANTRAL_URL}...")
         resp = requests.post(f"{GANTRAL_URL}/instances", json=payload, headers=headers, timeout=5)
         print(f"Status: {resp.status_code}")
@@ -1,14 +1,14 @@
-
 package gantral.policies
 
-default allow = true
-default requires_human_approval = true # Global default safe
+default allow := true
+
+default requires_human_approval := true # Global default safe
 
 # Override: Auto-approve read-only actions
-requires_human_approval = false {
-    input.context.operation_type == "READ_ONLY"
+requires_human_approval := false if {
+	input.context.operation_type == "READ_ONLY"
 }
 
-reason = "Auto-approved for Read-Only operation" {
-    input.context.operation_type == "READ_ONLY"
+reason := "Auto-approved for Read-Only operation" if {
+	input.context.operation_type == "READ_ONLY"
 }
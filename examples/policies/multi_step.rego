@@ -1,19 +1,25 @@
-
 package gantral.policies
 
-default allow = true
-default requires_human_approval = false
-default approvers = []
+default allow := true
+
+default requires_human_approval := false
+
+default approvers := []
 
 # Critical Actions require dual approval
-requires_human_approval {
-    input.context.category == "CRITICAL"
+requires_human_approval if {
+	input.context.category == "CRITICAL"
+}
+
+approvers := ["group:engineering", "group:compliance"] if {
+	input.context.category == "CRITICAL"
 }
 
-approvers = ["group:engineering", "group:compliance"] {
-    input.context.category == "CRITICAL"
+reason := "Critical actions require Engineering and Compliance approval" if {
+	input.context.category == "CRITICAL"
 }
 
-reason = "Critical actions require Engineering and Compliance approval" {
-    input.context.category == "CRITICAL"
+# Both groups must approve: each vote counts once per group
+quorum := 2 if {
+	input.context.category == "CRITICAL"
 }
@@ -1,7 +1,13 @@
 
 #!/bin/bash
+echo "Registering policy (idempotent)..."
+curl -X POST http://localhost:8080/policies \
+  -H "Content-Type: application/json" \
+  -d '{"name": "split-high", "materiality": "HIGH", "requires_human_approval": true}'
+echo ""
+
 echo "Triggering new Split Agent execution..."
 curl -X POST http://localhost:8080/instances \
   -H "Content-Type: application/json" \
-  -d '{"workflow_id": "split-flow", "trigger_context": {"mode": "split"}, "policy": {"materiality": "HIGH"}}'
+  -d '{"workflow_id": "split-flow", "trigger_context": {"mode": "split"}, "policy_name": "split-high"}'
 echo ""
@@ -39,4 +39,16 @@ type Instance struct {
 	LastArtifactHash string
 	CreatedAt        pgtype.Timestamptz
 	UpdatedAt        pgtype.Timestamptz
+	CreatedBy        string
+	CreatorOrgID     string
+	EscalationTier   int32
+}
+
+type PolicyVersion struct {
+	VersionID    string
+	Name         string
+	Body         string
+	Deprecated   bool
+	CreatedAt    pgtype.Timestamptz
+	DeprecatedAt pgtype.Timestamptz
 }
@@ -6,9 +6,11 @@ INSERT INTO instances (
     trigger_context,
     policy_context,
     policy_version_id,
-    last_artifact_hash
+    last_artifact_hash,
+    created_by,
+    creator_org_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -21,6 +23,16 @@ UPDATE instances
 SET state = $2, last_artifact_hash = $3, updated_at = NOW()
 WHERE id = $1;
 
+-- name: TransitionInstanceState :execrows
+UPDATE instances
+SET state = $2, last_artifact_hash = $3, updated_at = NOW()
+WHERE id = $1 AND state = $4 AND last_artifact_hash = $5;
+
+-- name: EscalateInstance :execrows
+UPDATE instances
+SET escalation_tier = $2, last_artifact_hash = $3, updated_at = NOW()
+WHERE id = $1 AND state = 'WAITING_FOR_HUMAN' AND last_artifact_hash = $4;
+
 -- name: CreateDecision :one
 INSERT INTO decisions (
     id, instance_id, type, actor_id, justification, role, context_snapshot, context_delta, policy_version_id
@@ -49,3 +61,37 @@ RETURNING *;
 SELECT * FROM audit_events
 WHERE instance_id = $1
 ORDER BY timestamp ASC;
+
+-- name: CreatePolicyVersion :one
+INSERT INTO policy_versions (
+    version_id, name, body
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (version_id) DO NOTHING
+RETURNING *;
+
+-- name: GetPolicyVersion :one
+SELECT * FROM policy_versions
+WHERE version_id = $1 LIMIT 1;
+
+-- name: GetLatestPolicyVersion :one
+SELECT * FROM policy_versions
+WHERE name = $1 AND deprecated = FALSE
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: ListPolicyVersions :many
+SELECT * FROM policy_versions
+ORDER BY name ASC, created_at DESC;
+
+-- name: ListPolicyVersionsByName :many
+SELECT * FROM policy_versions
+WHERE name = $1
+ORDER BY created_at DESC;
+
+-- name: DeprecatePolicyVersion :one
+UPDATE policy_versions
+SET deprecated = TRUE, deprecated_at = COALESCE(deprecated_at, NOW())
+WHERE version_id = $1
+RETURNING *;
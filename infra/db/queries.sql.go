@@ -100,11 +100,13 @@ INSERT INTO instances (
     trigger_context,
     policy_context,
     policy_version_id,
-    last_artifact_hash
+    last_artifact_hash,
+    created_by,
+    creator_org_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, workflow_id, state, trigger_context, policy_context, policy_version_id, last_artifact_hash, created_at, updated_at
+RETURNING id, workflow_id, state, trigger_context, policy_context, policy_version_id, last_artifact_hash, created_at, updated_at, created_by, creator_org_id, escalation_tier
 `
 
 type CreateInstanceParams struct {
@@ -115,6 +117,8 @@ type CreateInstanceParams struct {
 	PolicyContext    []byte
 	PolicyVersionID  string
 	LastArtifactHash string
+	CreatedBy        string
+	CreatorOrgID     string
 }
 
 func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams) (Instance, error) {
@@ -126,6 +130,8 @@ func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams)
 		arg.PolicyContext,
 		arg.PolicyVersionID,
 		arg.LastArtifactHash,
+		arg.CreatedBy,
+		arg.CreatorOrgID,
 	)
 	var i Instance
 	err := row.Scan(
@@ -138,10 +144,90 @@ func (q *Queries) CreateInstance(ctx context.Context, arg CreateInstanceParams)
 		&i.LastArtifactHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.CreatorOrgID,
+		&i.EscalationTier,
+	)
+	return i, err
+}
+
+const createPolicyVersion = `-- name: CreatePolicyVersion :one
+INSERT INTO policy_versions (
+    version_id, name, body
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (version_id) DO NOTHING
+RETURNING version_id, name, body, deprecated, created_at, deprecated_at
+`
+
+type CreatePolicyVersionParams struct {
+	VersionID string
+	Name      string
+	Body      string
+}
+
+func (q *Queries) CreatePolicyVersion(ctx context.Context, arg CreatePolicyVersionParams) (PolicyVersion, error) {
+	row := q.db.QueryRow(ctx, createPolicyVersion, arg.VersionID, arg.Name, arg.Body)
+	var i PolicyVersion
+	err := row.Scan(
+		&i.VersionID,
+		&i.Name,
+		&i.Body,
+		&i.Deprecated,
+		&i.CreatedAt,
+		&i.DeprecatedAt,
 	)
 	return i, err
 }
 
+const deprecatePolicyVersion = `-- name: DeprecatePolicyVersion :one
+UPDATE policy_versions
+SET deprecated = TRUE, deprecated_at = COALESCE(deprecated_at, NOW())
+WHERE version_id = $1
+RETURNING version_id, name, body, deprecated, created_at, deprecated_at
+`
+
+func (q *Queries) DeprecatePolicyVersion(ctx context.Context, versionID string) (PolicyVersion, error) {
+	row := q.db.QueryRow(ctx, deprecatePolicyVersion, versionID)
+	var i PolicyVersion
+	err := row.Scan(
+		&i.VersionID,
+		&i.Name,
+		&i.Body,
+		&i.Deprecated,
+		&i.CreatedAt,
+		&i.DeprecatedAt,
+	)
+	return i, err
+}
+
+const escalateInstance = `-- name: EscalateInstance :execrows
+UPDATE instances
+SET escalation_tier = $2, last_artifact_hash = $3, updated_at = NOW()
+WHERE id = $1 AND state = 'WAITING_FOR_HUMAN' AND last_artifact_hash = $4
+`
+
+type EscalateInstanceParams struct {
+	ID                 string
+	EscalationTier     int32
+	LastArtifactHash   string
+	LastArtifactHash_2 string
+}
+
+func (q *Queries) EscalateInstance(ctx context.Context, arg EscalateInstanceParams) (int64, error) {
+	result, err := q.db.Exec(ctx, escalateInstance,
+		arg.ID,
+		arg.EscalationTier,
+		arg.LastArtifactHash,
+		arg.LastArtifactHash_2,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getAuditEvents = `-- name: GetAuditEvents :many
 SELECT id, instance_id, event_type, payload, timestamp FROM audit_events
 WHERE instance_id = $1
@@ -211,7 +297,7 @@ func (q *Queries) GetDecisionsByInstance(ctx context.Context, instanceID string)
 }
 
 const getInstance = `-- name: GetInstance :one
-SELECT id, workflow_id, state, trigger_context, policy_context, policy_version_id, last_artifact_hash, created_at, updated_at FROM instances
+SELECT id, workflow_id, state, trigger_context, policy_context, policy_version_id, last_artifact_hash, created_at, updated_at, created_by, creator_org_id, escalation_tier FROM instances
 WHERE id = $1 LIMIT 1
 `
 
@@ -228,12 +314,55 @@ func (q *Queries) GetInstance(ctx context.Context, id string) (Instance, error)
 		&i.LastArtifactHash,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CreatedBy,
+		&i.CreatorOrgID,
+		&i.EscalationTier,
+	)
+	return i, err
+}
+
+const getLatestPolicyVersion = `-- name: GetLatestPolicyVersion :one
+SELECT version_id, name, body, deprecated, created_at, deprecated_at FROM policy_versions
+WHERE name = $1 AND deprecated = FALSE
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestPolicyVersion(ctx context.Context, name string) (PolicyVersion, error) {
+	row := q.db.QueryRow(ctx, getLatestPolicyVersion, name)
+	var i PolicyVersion
+	err := row.Scan(
+		&i.VersionID,
+		&i.Name,
+		&i.Body,
+		&i.Deprecated,
+		&i.CreatedAt,
+		&i.DeprecatedAt,
+	)
+	return i, err
+}
+
+const getPolicyVersion = `-- name: GetPolicyVersion :one
+SELECT version_id, name, body, deprecated, created_at, deprecated_at FROM policy_versions
+WHERE version_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPolicyVersion(ctx context.Context, versionID string) (PolicyVersion, error) {
+	row := q.db.QueryRow(ctx, getPolicyVersion, versionID)
+	var i PolicyVersion
+	err := row.Scan(
+		&i.VersionID,
+		&i.Name,
+		&i.Body,
+		&i.Deprecated,
+		&i.CreatedAt,
+		&i.DeprecatedAt,
 	)
 	return i, err
 }
 
 const listInstances = `-- name: ListInstances :many
-SELECT id, workflow_id, state, trigger_context, policy_context, policy_version_id, last_artifact_hash, created_at, updated_at FROM instances
+SELECT id, workflow_id, state, trigger_context, policy_context, policy_version_id, last_artifact_hash, created_at, updated_at, created_by, creator_org_id, escalation_tier FROM instances
 ORDER BY created_at DESC
 `
 
@@ -256,6 +385,9 @@ func (q *Queries) ListInstances(ctx context.Context) ([]Instance, error) {
 			&i.LastArtifactHash,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.CreatedBy,
+			&i.CreatorOrgID,
+			&i.EscalationTier,
 		); err != nil {
 			return nil, err
 		}
@@ -267,6 +399,99 @@ func (q *Queries) ListInstances(ctx context.Context) ([]Instance, error) {
 	return items, nil
 }
 
+const listPolicyVersions = `-- name: ListPolicyVersions :many
+SELECT version_id, name, body, deprecated, created_at, deprecated_at FROM policy_versions
+ORDER BY name ASC, created_at DESC
+`
+
+func (q *Queries) ListPolicyVersions(ctx context.Context) ([]PolicyVersion, error) {
+	rows, err := q.db.Query(ctx, listPolicyVersions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PolicyVersion
+	for rows.Next() {
+		var i PolicyVersion
+		if err := rows.Scan(
+			&i.VersionID,
+			&i.Name,
+			&i.Body,
+			&i.Deprecated,
+			&i.CreatedAt,
+			&i.DeprecatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPolicyVersionsByName = `-- name: ListPolicyVersionsByName :many
+SELECT version_id, name, body, deprecated, created_at, deprecated_at FROM policy_versions
+WHERE name = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPolicyVersionsByName(ctx context.Context, name string) ([]PolicyVersion, error) {
+	rows, err := q.db.Query(ctx, listPolicyVersionsByName, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PolicyVersion
+	for rows.Next() {
+		var i PolicyVersion
+		if err := rows.Scan(
+			&i.VersionID,
+			&i.Name,
+			&i.Body,
+			&i.Deprecated,
+			&i.CreatedAt,
+			&i.DeprecatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const transitionInstanceState = `-- name: TransitionInstanceState :execrows
+UPDATE instances
+SET state = $2, last_artifact_hash = $3, updated_at = NOW()
+WHERE id = $1 AND state = $4 AND last_artifact_hash = $5
+`
+
+type TransitionInstanceStateParams struct {
+	ID                 string
+	State              string
+	LastArtifactHash   string
+	State_2            string
+	LastArtifactHash_2 string
+}
+
+func (q *Queries) TransitionInstanceState(ctx context.Context, arg TransitionInstanceStateParams) (int64, error) {
+	result, err := q.db.Exec(ctx, transitionInstanceState,
+		arg.ID,
+		arg.State,
+		arg.LastArtifactHash,
+		arg.State_2,
+		arg.LastArtifactHash_2,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateInstanceState = `-- name: UpdateInstanceState :exec
 UPDATE instances
 SET state = $2, last_artifact_hash = $3, updated_at = NOW()
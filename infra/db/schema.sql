@@ -7,7 +7,10 @@ CREATE TABLE instances (
     policy_version_id TEXT NOT NULL DEFAULT '',
     last_artifact_hash TEXT NOT NULL DEFAULT '',
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by TEXT NOT NULL DEFAULT '',
+    creator_org_id TEXT NOT NULL DEFAULT '',
+    escalation_tier INTEGER NOT NULL DEFAULT 0
 );
 
 CREATE TABLE decisions (
@@ -30,3 +33,12 @@ CREATE TABLE audit_events (
     payload JSONB NOT NULL,
     timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW()
 );
+
+CREATE TABLE policy_versions (
+    version_id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    body TEXT NOT NULL,
+    deprecated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deprecated_at TIMESTAMPTZ
+);
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trg_policy_versions_immutable ON policy_versions;
+DROP FUNCTION IF EXISTS policy_versions_immutable();
+DROP TABLE IF EXISTS policy_versions;
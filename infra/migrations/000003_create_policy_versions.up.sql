@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS policy_versions (
+    version_id TEXT PRIMARY KEY, -- SHA-256 of body (content address)
+    name TEXT NOT NULL,
+    body TEXT NOT NULL, -- Canonical bytes, re-hashed on every read
+    deprecated BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deprecated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_policy_versions_name ON policy_versions (name, created_at DESC);
+
+-- Immutability: only the deprecation flag may change, and only from FALSE to TRUE.
+CREATE OR REPLACE FUNCTION policy_versions_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        RAISE EXCEPTION 'policy versions are immutable';
+    END IF;
+    IF NEW.version_id <> OLD.version_id OR NEW.name <> OLD.name OR NEW.body <> OLD.body
+        OR NEW.created_at <> OLD.created_at OR (OLD.deprecated AND NOT NEW.deprecated) THEN
+        RAISE EXCEPTION 'policy versions are immutable';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_policy_versions_immutable ON policy_versions;
+CREATE TRIGGER trg_policy_versions_immutable
+    BEFORE UPDATE OR DELETE ON policy_versions
+    FOR EACH ROW EXECUTE FUNCTION policy_versions_immutable();
@@ -0,0 +1,2 @@
+ALTER TABLE instances DROP COLUMN IF EXISTS creator_org_id;
+ALTER TABLE instances DROP COLUMN IF EXISTS created_by;
@@ -0,0 +1,3 @@
+-- The authenticated identity that created an instance, for separation-of-duties checks.
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS created_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS creator_org_id TEXT NOT NULL DEFAULT '';
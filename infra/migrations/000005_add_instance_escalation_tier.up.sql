@@ -0,0 +1,2 @@
+-- The escalation tier a waiting instance has reached (0 before the first escalation).
+ALTER TABLE instances ADD COLUMN IF NOT EXISTS escalation_tier INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,241 @@
+// Package anchor periodically publishes the latest signed checkpoint of the global
+// artifact log to external witnesses and stores their receipts in the log bucket.
+//
+// The artifact store and the log are operated by the same party as the database,
+// which the adversary model does not trust (specs/06). A witness outside that
+// party's control pins each checkpoint, so a later rewrite of the log is detectable
+// with a consistency proof against an anchored checkpoint.
+package anchor
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+	"github.com/Rainminds/gantral/pkg/translog"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/gcerrors"
+)
+
+// ErrForked indicates that a witness already holds a different checkpoint of the
+// same log and size: the log was rewritten or forked.
+var ErrForked = errors.New("witness holds a different checkpoint for this tree size")
+
+// Anchor publishes a checkpoint to one external witness.
+type Anchor interface {
+	// Name identifies the witness; it names the stored receipt.
+	Name() string
+	// Anchor publishes cp and returns the receipt. Anchoring the same checkpoint
+	// again must succeed.
+	Anchor(ctx context.Context, cp *translog.Checkpoint) (*translog.Receipt, error)
+}
+
+// ReceiptLog is the part of artifactlog.Log the anchorer needs.
+type ReceiptLog interface {
+	LatestCheckpoint(ctx context.Context) (*translog.Checkpoint, error)
+	Receipts(ctx context.Context, size uint64) ([]*translog.Receipt, error)
+	PutReceipt(ctx context.Context, r *translog.Receipt) error
+}
+
+// BucketWitness keeps a write-once copy of every checkpoint in a bucket the log
+// operator cannot write to (a second bucket under different credentials, or a
+// directory synced elsewhere, e.g. a git repository).
+type BucketWitness struct {
+	name   string
+	kind   string
+	bucket *blob.Bucket
+	now    func() time.Time
+}
+
+// NewBucketWitness wraps an open bucket. The caller keeps ownership of the bucket.
+func NewBucketWitness(name string, bucket *blob.Bucket) *BucketWitness {
+	return &BucketWitness{name: name, kind: translog.ReceiptKindBucket, bucket: bucket, now: time.Now}
+}
+
+// OpenBucketWitness opens the bucket at a gocloud URL. file:// URLs yield file receipts.
+func OpenBucketWitness(ctx context.Context, name, bucketURL string) (*BucketWitness, error) {
+	bucket, err := blob.OpenBucket(ctx, bucketURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open witness bucket: %w", err)
+	}
+	w := NewBucketWitness(name, bucket)
+	if u, err := url.Parse(bucketURL); err == nil && u.Scheme == "file" {
+		w.kind = translog.ReceiptKindFile
+	}
+	return w, nil
+}
+
+// NewFileWitness keeps checkpoint copies under dir, creating it if needed.
+func NewFileWitness(name, dir string) (*BucketWitness, error) {
+	bucket, err := fileblob.OpenBucket(dir, &fileblob.Options{CreateDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open witness directory: %w", err)
+	}
+	w := NewBucketWitness(name, bucket)
+	w.kind = translog.ReceiptKindFile
+	return w, nil
+}
+
+// Close releases the underlying bucket.
+func (w *BucketWitness) Close() error {
+	return w.bucket.Close()
+}
+
+// Name implements Anchor.
+func (w *BucketWitness) Name() string {
+	return w.name
+}
+
+// Anchor writes the canonical checkpoint to checkpoints/<origin>/<tree_size>.json.
+// An existing identical copy is accepted; a different one is ErrForked.
+func (w *BucketWitness) Anchor(ctx context.Context, cp *translog.Checkpoint) (*translog.Receipt, error) {
+	data, err := translog.CanonicalCheckpoint(cp)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("checkpoints/%s/%020d.json", url.PathEscape(cp.Origin), cp.TreeSize)
+
+	err = w.bucket.WriteAll(ctx, key, data, &blob.WriterOptions{ContentType: "application/json", IfNotExist: true})
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+		existing, readErr := w.bucket.ReadAll(ctx, key)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read witness copy: %w", readErr)
+		}
+		if string(existing) != string(data) {
+			return nil, fmt.Errorf("%w: %s@%d at %s", ErrForked, cp.Origin, cp.TreeSize, key)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to write witness copy: %w", err)
+	}
+
+	r, err := newReceipt(w.name, w.kind, cp, w.now())
+	if err != nil {
+		return nil, err
+	}
+	r.Location = key
+	return r, nil
+}
+
+// TSAWitness time-stamps the checkpoint digest with an RFC 3161 authority.
+type TSAWitness struct {
+	name   string
+	client *rfc3161.Client
+}
+
+// NewTSAWitness wraps a TSA client.
+func NewTSAWitness(name string, client *rfc3161.Client) *TSAWitness {
+	return &TSAWitness{name: name, client: client}
+}
+
+// Name implements Anchor.
+func (w *TSAWitness) Name() string {
+	return w.name
+}
+
+// Anchor requests a time-stamp token over the checkpoint digest.
+func (w *TSAWitness) Anchor(ctx context.Context, cp *translog.Checkpoint) (*translog.Receipt, error) {
+	digest, err := translog.CheckpointDigest(cp)
+	if err != nil {
+		return nil, err
+	}
+	token, err := w.client.Timestamp(ctx, digest)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newReceipt(w.name, translog.ReceiptKindRFC3161, cp, token.GenTime)
+	if err != nil {
+		return nil, err
+	}
+	r.Token = token.DER
+	return r, nil
+}
+
+func newReceipt(witness, kind string, cp *translog.Checkpoint, at time.Time) (*translog.Receipt, error) {
+	digest, err := translog.CheckpointDigest(cp)
+	if err != nil {
+		return nil, err
+	}
+	return &translog.Receipt{
+		Witness:          witness,
+		Kind:             kind,
+		Origin:           cp.Origin,
+		TreeSize:         cp.TreeSize,
+		RootHash:         cp.RootHash,
+		CheckpointSHA256: hex.EncodeToString(digest),
+		AnchoredAt:       at.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// AnchorLatest anchors the latest checkpoint with every witness that has no receipt
+// for it yet and stores the new receipts. It returns the receipts it stored; failing
+// witnesses do not stop the others and are reported in the joined error.
+func AnchorLatest(ctx context.Context, log ReceiptLog, anchors []Anchor) ([]*translog.Receipt, error) {
+	cp, err := log.LatestCheckpoint(ctx)
+	if errors.Is(err, artifactlog.ErrCheckpointNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	existing, err := log.Receipts(ctx, cp.TreeSize)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[string]bool, len(existing))
+	for _, r := range existing {
+		done[r.Witness] = true
+	}
+
+	var stored []*translog.Receipt
+	var errs []error
+	for _, a := range anchors {
+		if done[a.Name()] {
+			continue
+		}
+		r, err := a.Anchor(ctx, cp)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("witness %s: %w", a.Name(), err))
+			continue
+		}
+		switch err := log.PutReceipt(ctx, r); {
+		case err == nil:
+			stored = append(stored, r)
+		case errors.Is(err, artifactlog.ErrReceiptExists):
+			// Another worker anchored the same checkpoint meanwhile; its receipt stands.
+		default:
+			errs = append(errs, fmt.Errorf("witness %s: %w", a.Name(), err))
+		}
+	}
+	return stored, errors.Join(errs...)
+}
+
+// Run anchors the latest checkpoint every interval until ctx is done.
+func Run(ctx context.Context, log ReceiptLog, anchors []Anchor, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stored, err := AnchorLatest(ctx, log, anchors)
+		for _, r := range stored {
+			logger.Info("Checkpoint anchored", "witness", r.Witness, "kind", r.Kind, "tree_size", r.TreeSize, "root_hash", r.RootHash)
+		}
+		if errors.Is(err, ErrForked) {
+			logger.Error("SECURITY ALERT: artifact log diverges from an anchored checkpoint", "error", err)
+		} else if err != nil {
+			logger.Error("Anchoring failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package anchor
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+	"github.com/Rainminds/gantral/pkg/rfc3161/rfc3161test"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/translog"
+	"gocloud.dev/blob/memblob"
+)
+
+// newLog returns a log with one signed checkpoint over n artifacts.
+func newLog(t *testing.T, n int) (*artifactlog.Log, *signing.Signer) {
+	t.Helper()
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("log-key", priv)
+	bucket := memblob.OpenBucket(nil)
+	t.Cleanup(func() { bucket.Close() })
+
+	log := artifactlog.New(bucket, "gantral.test")
+	for i := 0; i < n; i++ {
+		if _, err := log.Append(context.Background(), fmt.Sprintf("art-%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := log.Checkpoint(context.Background(), signer); err != nil {
+		t.Fatal(err)
+	}
+	return log, signer
+}
+
+func TestAnchorLatest(t *testing.T) {
+	ctx := context.Background()
+	log, signer := newLog(t, 3)
+
+	tsa, err := rfc3161test.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(tsa)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	file, err := NewFileWitness("file", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	anchors := []Anchor{file, NewTSAWitness("tsa", &rfc3161.Client{URL: srv.URL, Roots: tsa.Roots()})}
+
+	stored, err := AnchorLatest(ctx, log, anchors)
+	if err != nil || len(stored) != 2 {
+		t.Fatalf("Expected 2 receipts, got %d, err=%v", len(stored), err)
+	}
+
+	cp, _ := log.LatestCheckpoint(ctx)
+	receipts, err := log.Receipts(ctx, cp.TreeSize)
+	if err != nil || len(receipts) != 2 {
+		t.Fatalf("Expected 2 stored receipts, got %d, err=%v", len(receipts), err)
+	}
+	for _, r := range receipts {
+		if err := translog.VerifyReceipt(r, cp, tsa.Roots()); err != nil {
+			t.Errorf("Receipt of %s does not verify: %v", r.Witness, err)
+		}
+	}
+
+	// The file witness copy is the canonical checkpoint: its digest is in the receipt.
+	copyPath := filepath.Join(dir, filepath.FromSlash(stored[0].Location))
+	data, err := os.ReadFile(copyPath)
+	if err != nil {
+		t.Fatalf("Witness copy missing: %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != stored[0].CheckpointSHA256 {
+		t.Errorf("Witness copy does not match the receipt digest")
+	}
+
+	// Nothing new to anchor.
+	if stored, err := AnchorLatest(ctx, log, anchors); err != nil || len(stored) != 0 {
+		t.Errorf("Expected no new receipts, got %d, err=%v", len(stored), err)
+	}
+
+	t.Run("Rewritten Log Is Detected", func(t *testing.T) {
+		// Same origin, size and key, different root: the witness copy disagrees.
+		fcp := *cp
+		fcp.RootHash = hex.EncodeToString(make([]byte, sha256.Size))
+		_ = fcp.Sign(signer)
+		if _, err := file.Anchor(ctx, &fcp); !errors.Is(err, ErrForked) {
+			t.Errorf("Expected ErrForked, got %v", err)
+		}
+	})
+
+	t.Run("Failing Witness Does Not Block Others", func(t *testing.T) {
+		_, _ = log.Append(ctx, "art-3")
+		_, _ = log.Checkpoint(ctx, signer)
+		tsa.Reject = true
+		stored, err := AnchorLatest(ctx, log, anchors)
+		if !errors.Is(err, rfc3161.ErrRejected) || len(stored) != 1 || stored[0].Witness != "file" {
+			t.Errorf("Expected the file receipt and a TSA rejection, got %d, err=%v", len(stored), err)
+		}
+	})
+}
+
+func TestVerifyReceipt_RejectsOtherCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	log, signer := newLog(t, 2)
+	file, _ := NewFileWitness("file", t.TempDir())
+	defer file.Close()
+
+	cp, _ := log.LatestCheckpoint(ctx)
+	r, err := file.Anchor(ctx, cp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resigned := *cp
+	resigned.Timestamp = "2030-01-01T00:00:00Z"
+	_ = resigned.Sign(signer)
+	if err := translog.VerifyReceipt(r, &resigned, nil); !errors.Is(err, translog.ErrInvalidReceipt) {
+		t.Errorf("Expected ErrInvalidReceipt, got %v", err)
+	}
+}
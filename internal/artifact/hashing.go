@@ -3,11 +3,12 @@ package artifact
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
 )
 
 // HashContext computes a deterministic SHA-256 hash of a JSON-compatible map.
-// It ensures keys are sorted to guarantee the same hash for the same content.
+// The map is canonicalized with RFC 8785 (JCS), so any JCS implementation reproduces the hash.
 func HashContext(data map[string]interface{}) (string, error) {
 	// Handle nil or empty map consistently as empty JSON object "{}"
 	if len(data) == 0 {
@@ -15,13 +16,12 @@ func HashContext(data map[string]interface{}) (string, error) {
 		return hex.EncodeToString(hash[:]), nil
 	}
 
-	// 1. Canonicalize by marshalling keys in sorted order.
-	// We rely on standard encoding/json which sorts map keys (RFC 8785 behavior).
+	// 1. Canonicalize (sorted keys, ES6 number form, no HTML escaping).
 	// WARNING: Arrays/Slices are ORDERED sequences. We DO NOT sort them.
 	// If the context contains Sets (unordered lists), the CALLER must sort them
 	// to ensure deterministic hashing. Auto-sorting here would corrupt valid sequences (e.g. history logs).
 
-	bytes, err := json.Marshal(data)
+	bytes, err := jcs.Marshal(data)
 	if err != nil {
 		return "", err
 	}
@@ -32,4 +32,18 @@ type ArtifactEmitter interface {
 		contextHash string,
 		actorID string,
 	) (*models.CommitmentArtifact, error)
+
+	// EmitBoundArtifact is EmitArtifact for schema v2: it additionally binds the
+	// policy input and decision hashes that justify the transition.
+	// Both hashes are required; an incomplete binding is refused (Fail-Closed).
+	EmitBoundArtifact(
+		ctx context.Context,
+		instanceID string,
+		prevHash string,
+		state string,
+		policyVer string,
+		contextHash string,
+		actorID string,
+		binding models.PolicyBinding,
+	) (*models.CommitmentArtifact, error)
 }
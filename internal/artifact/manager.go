@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
 )
 
 var (
@@ -22,14 +23,21 @@ var (
 // Manager implements the ArtifactEmitter interface.
 // It manages the lifecycle of commitment artifacts.
 type Manager struct {
-	store Store
+	store  Store
+	signer *signing.Signer
 }
 
 // NewManager creates a new artifact manager with the given persistence store.
+// Artifacts emitted by this manager are unsigned; see NewSigningManager.
 func NewManager(store Store) *Manager {
 	return &Manager{store: store}
 }
 
+// NewSigningManager creates a manager that signs every artifact before persisting it.
+func NewSigningManager(store Store, signer *signing.Signer) *Manager {
+	return &Manager{store: store, signer: signer}
+}
+
 // EmitArtifact generates, seals, and calculates the ID for a new commitment artifact.
 // It implements strict validation and fail-closed logic.
 //
@@ -48,34 +56,96 @@ func (m *Manager) EmitArtifact(
 	actorID string,
 ) (*models.CommitmentArtifact, error) {
 	// 1. Fail-Closed Input Validation
-	if instanceID == "" {
-		return nil, fmt.Errorf("%w: instanceID required", ErrInvalidInput)
+	if err := validateInput(instanceID, state, contextHash); err != nil {
+		return nil, err
 	}
-	if state == "" {
-		return nil, fmt.Errorf("%w: authority state required", ErrInvalidInput)
+
+	// 2. Instantiate Model (v3, no policy binding)
+	art := models.NewCommitmentArtifactV3(
+		instanceID,
+		prevHash,
+		state,
+		policyVer,
+		contextHash,
+		actorID,
+		models.PolicyBinding{},
+	)
+
+	return m.seal(ctx, art)
+}
+
+// EmitBoundArtifact generates, seals, and persists a v3 artifact bound to a policy evaluation.
+func (m *Manager) EmitBoundArtifact(
+	ctx context.Context,
+	instanceID string,
+	prevHash string,
+	state string,
+	policyVer string,
+	contextHash string,
+	actorID string,
+	binding models.PolicyBinding,
+) (*models.CommitmentArtifact, error) {
+	if err := validateInput(instanceID, state, contextHash); err != nil {
+		return nil, err
 	}
-	if contextHash == "" {
-		return nil, fmt.Errorf("%w: context hash required", ErrInvalidInput)
+	if binding.InputHash == "" || binding.DecisionHash == "" {
+		return nil, fmt.Errorf("%w: policy input and decision hashes required", ErrInvalidInput)
 	}
-	// prevHash can be empty for genesis, so we don't strictly block it,
-	// but we might want to enforce "0000..." for genesis in future iterations.
 
-	// 2. Instantiate Model
-	art := models.NewCommitmentArtifact(
+	art := models.NewCommitmentArtifactV3(
 		instanceID,
 		prevHash,
 		state,
 		policyVer,
 		contextHash,
 		actorID,
+		binding,
 	)
 
+	return m.seal(ctx, art)
+}
+
+// Adopt prepares an artifact persisted by an earlier, failed emission for adoption: it is
+// passed to the store when the store is an Adopter, and is a no-op otherwise.
+func (m *Manager) Adopt(ctx context.Context, art *models.CommitmentArtifact) error {
+	if adopter, ok := m.store.(Adopter); ok {
+		return adopter.Adopt(ctx, art)
+	}
+	return nil
+}
+
+// validateInput enforces the fields every artifact version requires (Fail-Closed).
+func validateInput(instanceID, state, contextHash string) error {
+	if instanceID == "" {
+		return fmt.Errorf("%w: instanceID required", ErrInvalidInput)
+	}
+	if state == "" {
+		return fmt.Errorf("%w: authority state required", ErrInvalidInput)
+	}
+	if contextHash == "" {
+		return fmt.Errorf("%w: context hash required", ErrInvalidInput)
+	}
+	// prevHash can be empty for genesis, so we don't strictly block it,
+	// but we might want to enforce "0000..." for genesis in future iterations.
+	return nil
+}
+
+// seal hashes and persists an artifact. Nothing is returned unless both succeed.
+func (m *Manager) seal(ctx context.Context, art *models.CommitmentArtifact) (*models.CommitmentArtifact, error) {
 	// 3. Calculate Canonical Hash (The "Seal")
 	// If this fails, strict fail-closed: we simply return error and NO artifact.
 	if err := art.CalculateHashAndSetID(); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrArtifactSerialization, err)
 	}
 
+	// 3b. Sign the canonical payload (Non-Repudiation).
+	// An artifact that should be signed but is not MUST NOT be persisted.
+	if m.signer != nil {
+		if err := m.signer.Sign(art); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrArtifactSerialization, err)
+		}
+	}
+
 	// 4. Persistence (Phase 6.2)
 	// We persist to the WORM storage before returning.
 	// If persistence fails, we MUST fail the operation (Atomicity).
@@ -2,10 +2,13 @@ package artifact
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"testing"
 
 	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
 )
 
 // MockStore is a no-op store for testing Manager logic.
@@ -19,6 +22,14 @@ func (s *MockStore) Get(ctx context.Context, id string) (*models.CommitmentArtif
 	return nil, nil
 }
 
+func (s *MockStore) ListByInstance(ctx context.Context, instanceID string) ([]*models.CommitmentArtifact, error) {
+	return nil, nil
+}
+
+func (s *MockStore) Walk(ctx context.Context, fn WalkFunc) error {
+	return nil
+}
+
 func TestEmitArtifact_Success(t *testing.T) {
 	m := NewManager(&MockStore{})
 
@@ -43,8 +54,8 @@ func TestEmitArtifact_Success(t *testing.T) {
 		t.Error("ArtifactID should be populated")
 	}
 	// ArtifactHash removed in v1model
-	if art.ArtifactVersion != "v1" {
-		t.Errorf("Expected version v1, got %s", art.ArtifactVersion)
+	if art.ArtifactVersion != models.SchemaVersionV3 {
+		t.Errorf("Expected version %s, got %s", models.SchemaVersionV3, art.ArtifactVersion)
 	}
 }
 
@@ -133,3 +144,51 @@ func TestArtifact_JSONStructure(t *testing.T) {
 		}
 	}
 }
+
+func TestEmitBoundArtifact(t *testing.T) {
+	m := NewManager(&MockStore{})
+	binding := models.PolicyBinding{InputHash: "in-hash", DecisionHash: "dec-hash"}
+
+	art, err := m.EmitBoundArtifact(context.Background(), "inst-123", "prev", "APPROVED", "policy-v1", "ctx", "actor-bob", binding)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	if art.ArtifactVersion != models.SchemaVersionV3 {
+		t.Errorf("Expected version %s, got %s", models.SchemaVersionV3, art.ArtifactVersion)
+	}
+	if art.PolicyInputHash != "in-hash" || art.PolicyDecisionHash != "dec-hash" {
+		t.Errorf("Policy binding not carried into artifact: %+v", art)
+	}
+
+	// Fail-Closed: a partial binding must not silently downgrade to v1.
+	art, err = m.EmitBoundArtifact(context.Background(), "inst-123", "prev", "APPROVED", "policy-v1", "ctx", "actor-bob", models.PolicyBinding{InputHash: "in-hash"})
+	if err == nil || art != nil {
+		t.Fatal("Expected error for incomplete policy binding")
+	}
+}
+
+func TestSigningManager_SignsEveryArtifact(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := signing.NewSigner("key-1", priv)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	m := NewSigningManager(&MockStore{}, signer)
+	keys := signing.KeySet{"key-1": signer.PublicKey()}
+
+	v1, err := m.EmitArtifact(context.Background(), "inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "actor")
+	if err != nil {
+		t.Fatalf("EmitArtifact: %v", err)
+	}
+	v2, err := m.EmitBoundArtifact(context.Background(), "inst-1", v1.ArtifactID, "COMPLETED", "pv", "ctx", "actor",
+		models.PolicyBinding{InputHash: "in", DecisionHash: "dec"})
+	if err != nil {
+		t.Fatalf("EmitBoundArtifact: %v", err)
+	}
+
+	for _, art := range []*models.CommitmentArtifact{v1, v2} {
+		if err := keys.Verify(art); err != nil {
+			t.Errorf("artifact %s (%s) not validly signed: %v", art.ArtifactID, art.ArtifactVersion, err)
+		}
+	}
+}
@@ -14,8 +14,15 @@ var (
 
 	// ErrArtifactNotFound indicates the requested artifact does not exist on the medium.
 	ErrArtifactNotFound = errors.New("artifact not found")
+
+	// ErrInvalidID indicates an artifact or instance ID that is unsafe to use as a storage key.
+	ErrInvalidID = errors.New("invalid artifact or instance ID")
 )
 
+// WalkFunc is called for each stored artifact. Returning a non-nil error stops
+// the walk and Walk returns that error.
+type WalkFunc func(artifact *models.CommitmentArtifact) error
+
 // Store defines the interface for the immutable persistence layer.
 // Implementations MUST ensure that writes are atomic and immutable.
 type Store interface {
@@ -26,4 +33,20 @@ type Store interface {
 
 	// Get retrieves an artifact by its ID.
 	Get(ctx context.Context, artifactID string) (*models.CommitmentArtifact, error)
+
+	// ListByInstance returns every artifact of an instance in the order it was written,
+	// without scanning the whole store. An unknown instance yields an empty list.
+	// Write order is not proof of chain order; verifiers rebuild the chain from hash linkage.
+	ListByInstance(ctx context.Context, instanceID string) ([]*models.CommitmentArtifact, error)
+
+	// Walk streams every stored artifact to fn, one at a time, in no particular order.
+	Walk(ctx context.Context, fn WalkFunc) error
+}
+
+// Adopter is implemented by stores that do more on Write than persist the artifact (see
+// internal/artifactlog). Adopt finishes that work for an artifact that an earlier, failed
+// Write already persisted, before a retry adopts it instead of emitting another. It is
+// idempotent.
+type Adopter interface {
+	Adopt(ctx context.Context, art *models.CommitmentArtifact) error
 }
@@ -0,0 +1,357 @@
+// Package artifactlog maintains the global append-only log of emitted artifact IDs
+// and its signed checkpoints (see pkg/translog) in a gocloud.dev/blob bucket.
+//
+// Key layout:
+//
+//	log/leaves/<index>              artifact ID of leaf <index> (zero-padded, dense from 0)
+//	log/checkpoints/<tree_size>.json  signed checkpoint (zero-padded size)
+//	log/receipts/<tree_size>/<witness>.json  anchor receipt of that checkpoint (see internal/anchor)
+//
+// Leaves are sequenced by the bucket itself: an appender claims the next index with a
+// conditional create (If-None-Match: * on S3) and moves on to the following index if
+// another writer got there first. Several workers can therefore share one log without
+// coordination, and no index is ever written twice.
+package artifactlog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rainminds/gantral/pkg/merkle"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/translog"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+const (
+	leafPrefix       = "log/leaves/"
+	checkpointPrefix = "log/checkpoints/"
+	receiptPrefix    = "log/receipts/"
+
+	// maxAppendAttempts bounds the retries of a contended append.
+	maxAppendAttempts = 64
+)
+
+var (
+	// ErrNotInLog indicates an artifact ID that is not among the requested leaves.
+	ErrNotInLog = errors.New("artifact not in log")
+	// ErrInvalidTreeSize indicates a tree size beyond the current log or out of order.
+	ErrInvalidTreeSize = errors.New("invalid tree size")
+	// ErrCheckpointNotFound indicates that no checkpoint exists for the requested size.
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	// ErrReceiptExists indicates that the witness already has a receipt for the checkpoint.
+	ErrReceiptExists = errors.New("anchor receipt already stored")
+)
+
+// Log is a handle on the shared log. It caches the leaves it has read; the bucket is
+// the source of truth and is re-read for leaves appended by other processes.
+type Log struct {
+	bucket *blob.Bucket
+	origin string
+	now    func() time.Time
+
+	mu     sync.Mutex
+	ids    []string
+	hashes [][]byte
+	index  map[string]uint64
+}
+
+// New wraps an open bucket. origin names the log in every checkpoint.
+// The caller keeps ownership of the bucket.
+func New(bucket *blob.Bucket, origin string) *Log {
+	return &Log{bucket: bucket, origin: origin, now: time.Now, index: make(map[string]uint64)}
+}
+
+// Open opens the bucket at url and wraps it.
+func Open(ctx context.Context, url, origin string) (*Log, error) {
+	bucket, err := blob.OpenBucket(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log bucket: %w", err)
+	}
+	return New(bucket, origin), nil
+}
+
+// Close releases the underlying bucket.
+func (l *Log) Close() error {
+	return l.bucket.Close()
+}
+
+// Origin returns the log's name.
+func (l *Log) Origin() string {
+	return l.origin
+}
+
+// Append adds an artifact ID to the log and returns its leaf index.
+// Appending an ID that is already in the log returns its existing index.
+func (l *Log) Append(ctx context.Context, artifactID string) (uint64, error) {
+	if artifactID == "" {
+		return 0, fmt.Errorf("artifact ID required")
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for attempt := 0; attempt < maxAppendAttempts; attempt++ {
+		if err := l.syncLocked(ctx); err != nil {
+			return 0, err
+		}
+		if idx, ok := l.index[artifactID]; ok {
+			return idx, nil
+		}
+
+		idx := uint64(len(l.ids))
+		err := l.bucket.WriteAll(ctx, leafKey(idx), []byte(artifactID), &blob.WriterOptions{
+			ContentType: "text/plain",
+			IfNotExist:  true,
+		})
+		if err == nil {
+			l.addLocked(artifactID)
+			return idx, nil
+		}
+		if gcerrors.Code(err) != gcerrors.FailedPrecondition {
+			return 0, fmt.Errorf("failed to append to log: %w", err)
+		}
+		// Another writer claimed idx; read it and try the next one.
+	}
+	return 0, fmt.Errorf("failed to append to log: index still contended after %d attempts", maxAppendAttempts)
+}
+
+// Size returns the current number of leaves.
+func (l *Log) Size(ctx context.Context) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.syncLocked(ctx); err != nil {
+		return 0, err
+	}
+	return uint64(len(l.ids)), nil
+}
+
+// Contains reports whether an artifact ID is in the log.
+func (l *Log) Contains(ctx context.Context, artifactID string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.syncLocked(ctx); err != nil {
+		return false, err
+	}
+	_, ok := l.index[artifactID]
+	return ok, nil
+}
+
+// Checkpoint signs and stores a checkpoint over the current log. If a checkpoint for
+// this size already exists (e.g. written by another worker), that one is returned.
+func (l *Log) Checkpoint(ctx context.Context, signer *signing.Signer) (*translog.Checkpoint, error) {
+	l.mu.Lock()
+	if err := l.syncLocked(ctx); err != nil {
+		l.mu.Unlock()
+		return nil, err
+	}
+	size := uint64(len(l.hashes))
+	root := merkle.Root(l.hashes)
+	l.mu.Unlock()
+
+	cp := &translog.Checkpoint{
+		Origin:    l.origin,
+		TreeSize:  size,
+		RootHash:  fmt.Sprintf("%x", root),
+		Timestamp: l.now().UTC().Format(time.RFC3339),
+	}
+	if err := cp.Sign(signer); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.bucket.WriteAll(ctx, checkpointKey(size), data, &blob.WriterOptions{
+		ContentType: "application/json",
+		IfNotExist:  true,
+	})
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+		return l.GetCheckpoint(ctx, size)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to store checkpoint: %w", err)
+	}
+	return cp, nil
+}
+
+// GetCheckpoint returns the stored checkpoint for a tree size.
+func (l *Log) GetCheckpoint(ctx context.Context, size uint64) (*translog.Checkpoint, error) {
+	data, err := l.bucket.ReadAll(ctx, checkpointKey(size))
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		return nil, ErrCheckpointNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp translog.Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to deserialize checkpoint: %w", err)
+	}
+	return &cp, nil
+}
+
+// LatestCheckpoint returns the checkpoint with the largest tree size.
+func (l *Log) LatestCheckpoint(ctx context.Context) (*translog.Checkpoint, error) {
+	var latest string
+	it := l.bucket.List(&blob.ListOptions{Prefix: checkpointPrefix})
+	for {
+		obj, err := it.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+		}
+		if obj.Key > latest { // Zero-padded: lexical order is numeric order
+			latest = obj.Key
+		}
+	}
+	if latest == "" {
+		return nil, ErrCheckpointNotFound
+	}
+	size, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(latest, checkpointPrefix), ".json"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected checkpoint key %s", latest)
+	}
+	return l.GetCheckpoint(ctx, size)
+}
+
+// PutReceipt stores an anchor receipt next to its checkpoint. Receipts are write-once.
+func (l *Log) PutReceipt(ctx context.Context, r *translog.Receipt) error {
+	if r.Witness == "" || strings.ContainsAny(r.Witness, "/\\") {
+		return fmt.Errorf("invalid witness name %q", r.Witness)
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	err = l.bucket.WriteAll(ctx, receiptKey(r.TreeSize, r.Witness), data, &blob.WriterOptions{
+		ContentType: "application/json",
+		IfNotExist:  true,
+	})
+	if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+		return ErrReceiptExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store receipt: %w", err)
+	}
+	return nil
+}
+
+// Receipts returns the anchor receipts of the checkpoint of a tree size, ordered by witness.
+func (l *Log) Receipts(ctx context.Context, size uint64) ([]*translog.Receipt, error) {
+	receipts := []*translog.Receipt{}
+	it := l.bucket.List(&blob.ListOptions{Prefix: fmt.Sprintf("%s%020d/", receiptPrefix, size)})
+	for {
+		obj, err := it.Next(ctx)
+		if err == io.EOF {
+			return receipts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list receipts: %w", err)
+		}
+		data, err := l.bucket.ReadAll(ctx, obj.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read receipt %s: %w", obj.Key, err)
+		}
+		var r translog.Receipt
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to deserialize receipt %s: %w", obj.Key, err)
+		}
+		receipts = append(receipts, &r)
+	}
+}
+
+// InclusionProof proves that artifactID is among the first treeSize leaves.
+func (l *Log) InclusionProof(ctx context.Context, artifactID string, treeSize uint64) (*translog.InclusionProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.syncLocked(ctx); err != nil {
+		return nil, err
+	}
+	if treeSize > uint64(len(l.hashes)) {
+		return nil, fmt.Errorf("%w: %d exceeds log size %d", ErrInvalidTreeSize, treeSize, len(l.hashes))
+	}
+	idx, ok := l.index[artifactID]
+	if !ok || idx >= treeSize {
+		return nil, fmt.Errorf("%w: %s in tree of size %d", ErrNotInLog, artifactID, treeSize)
+	}
+
+	hashes, err := merkle.InclusionProof(l.hashes[:treeSize], idx)
+	if err != nil {
+		return nil, err
+	}
+	return &translog.InclusionProof{
+		ArtifactID: artifactID,
+		LeafIndex:  idx,
+		TreeSize:   treeSize,
+		Hashes:     translog.EncodeHashes(hashes),
+	}, nil
+}
+
+// ConsistencyProof proves that the tree of size first is a prefix of the tree of size second.
+func (l *Log) ConsistencyProof(ctx context.Context, first, second uint64) (*translog.ConsistencyProof, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.syncLocked(ctx); err != nil {
+		return nil, err
+	}
+	if first > second || second > uint64(len(l.hashes)) {
+		return nil, fmt.Errorf("%w: need first <= second <= %d, got %d and %d", ErrInvalidTreeSize, len(l.hashes), first, second)
+	}
+
+	hashes, err := merkle.ConsistencyProof(l.hashes[:second], first)
+	if err != nil {
+		return nil, err
+	}
+	return &translog.ConsistencyProof{
+		FirstSize:  first,
+		SecondSize: second,
+		Hashes:     translog.EncodeHashes(hashes),
+	}, nil
+}
+
+// syncLocked reads leaves appended since the last sync. Leaves are dense, so the
+// first missing index is the end of the log.
+func (l *Log) syncLocked(ctx context.Context) error {
+	for {
+		data, err := l.bucket.ReadAll(ctx, leafKey(uint64(len(l.ids))))
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read log leaf %d: %w", len(l.ids), err)
+		}
+		l.addLocked(string(data))
+	}
+}
+
+func (l *Log) addLocked(artifactID string) {
+	if _, dup := l.index[artifactID]; !dup {
+		l.index[artifactID] = uint64(len(l.ids))
+	}
+	l.ids = append(l.ids, artifactID)
+	l.hashes = append(l.hashes, translog.LeafHash(artifactID))
+}
+
+func leafKey(idx uint64) string {
+	return fmt.Sprintf("%s%020d", leafPrefix, idx)
+}
+
+func receiptKey(size uint64, witness string) string {
+	return fmt.Sprintf("%s%020d/%s.json", receiptPrefix, size, witness)
+}
+
+func checkpointKey(size uint64) string {
+	return fmt.Sprintf("%s%020d.json", checkpointPrefix, size)
+}
@@ -0,0 +1,229 @@
+package artifactlog
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/translog"
+	"gocloud.dev/blob/memblob"
+)
+
+func newSigner(t *testing.T) (*signing.Signer, signing.KeySet) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := signing.NewSigner("log-key", priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, signing.KeySet{"log-key": pub}
+}
+
+func TestLog_AppendIsDenseAndIdempotent(t *testing.T) {
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	ctx := context.Background()
+
+	// Two handles on one bucket behave like two workers.
+	a, b := New(bucket, "test"), New(bucket, "test")
+	for i := 0; i < 5; i++ {
+		log := a
+		if i%2 == 1 {
+			log = b
+		}
+		idx, err := log.Append(ctx, fmt.Sprintf("art-%d", i))
+		if err != nil {
+			t.Fatalf("Append failed: %v", err)
+		}
+		if idx != uint64(i) {
+			t.Errorf("art-%d: expected index %d, got %d", i, i, idx)
+		}
+	}
+
+	if idx, err := b.Append(ctx, "art-0"); err != nil || idx != 0 {
+		t.Errorf("Re-append should return the existing index 0, got %d, err=%v", idx, err)
+	}
+	if size, _ := a.Size(ctx); size != 5 {
+		t.Errorf("Expected size 5, got %d", size)
+	}
+}
+
+func TestLog_ConcurrentAppends(t *testing.T) {
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	ctx := context.Background()
+
+	const writers, perWriter = 4, 10
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			log := New(bucket, "test") // Separate handle per writer
+			for i := 0; i < perWriter; i++ {
+				if _, err := log.Append(ctx, fmt.Sprintf("w%d-%d", w, i)); err != nil {
+					t.Errorf("Append failed: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	log := New(bucket, "test")
+	if size, _ := log.Size(ctx); size != writers*perWriter {
+		t.Fatalf("Expected %d leaves, got %d", writers*perWriter, size)
+	}
+	if len(log.index) != writers*perWriter {
+		t.Errorf("Expected every artifact exactly once, got %d distinct IDs", len(log.index))
+	}
+}
+
+func TestLog_CheckpointsAndProofs(t *testing.T) {
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	ctx := context.Background()
+	signer, keys := newSigner(t)
+	log := New(bucket, "gantral.test/log")
+
+	for i := 0; i < 3; i++ {
+		_, _ = log.Append(ctx, fmt.Sprintf("art-%d", i))
+	}
+	cp1, err := log.Checkpoint(ctx, signer)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	for i := 3; i < 7; i++ {
+		_, _ = log.Append(ctx, fmt.Sprintf("art-%d", i))
+	}
+	cp2, err := log.Checkpoint(ctx, signer)
+	if err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+
+	for _, cp := range []*translog.Checkpoint{cp1, cp2} {
+		if err := translog.VerifyCheckpoint(cp, keys); err != nil {
+			t.Errorf("Checkpoint %d does not verify: %v", cp.TreeSize, err)
+		}
+	}
+	if latest, err := log.LatestCheckpoint(ctx); err != nil || latest.TreeSize != 7 {
+		t.Errorf("Expected latest checkpoint of size 7, got %+v, err=%v", latest, err)
+	}
+
+	// Inclusion of an early artifact in both trees.
+	for _, cp := range []*translog.Checkpoint{cp1, cp2} {
+		proof, err := log.InclusionProof(ctx, "art-1", cp.TreeSize)
+		if err != nil {
+			t.Fatalf("InclusionProof failed: %v", err)
+		}
+		if err := translog.VerifyInclusion(proof, cp); err != nil {
+			t.Errorf("Inclusion in tree %d does not verify: %v", cp.TreeSize, err)
+		}
+	}
+	if _, err := log.InclusionProof(ctx, "art-5", cp1.TreeSize); !errors.Is(err, ErrNotInLog) {
+		t.Errorf("Expected ErrNotInLog for a later artifact, got %v", err)
+	}
+
+	proof, err := log.ConsistencyProof(ctx, cp1.TreeSize, cp2.TreeSize)
+	if err != nil {
+		t.Fatalf("ConsistencyProof failed: %v", err)
+	}
+	if err := translog.VerifyConsistency(proof, cp1, cp2); err != nil {
+		t.Errorf("Consistency does not verify: %v", err)
+	}
+	if _, err := log.ConsistencyProof(ctx, 2, 99); !errors.Is(err, ErrInvalidTreeSize) {
+		t.Errorf("Expected ErrInvalidTreeSize, got %v", err)
+	}
+
+	// A second checkpoint at the same size returns the stored one.
+	again, err := log.Checkpoint(ctx, signer)
+	if err != nil || again.Signature != cp2.Signature {
+		t.Errorf("Expected the stored checkpoint to be returned, got %+v, err=%v", again, err)
+	}
+}
+
+func TestStore_LogsEveryWrite(t *testing.T) {
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	ctx := context.Background()
+
+	inner, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	log := New(bucket, "test")
+	store := WrapStore(inner, log)
+
+	art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := store.Get(ctx, art.ArtifactID); err != nil {
+		t.Errorf("Artifact not persisted: %v", err)
+	}
+	if _, err := log.InclusionProof(ctx, art.ArtifactID, 1); err != nil {
+		t.Errorf("Artifact not logged: %v", err)
+	}
+
+	// A rejected write (duplicate) must not be logged again.
+	_ = store.Write(ctx, art)
+	if size, _ := log.Size(ctx); size != 1 {
+		t.Errorf("Expected 1 leaf, got %d", size)
+	}
+}
+
+func TestStore_LogsArtifactWhoseAppendFailed(t *testing.T) {
+	ctx := context.Background()
+	inner, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The first emission persists the artifact, then fails to reach the log.
+	down := memblob.OpenBucket(nil)
+	_ = down.Close()
+	art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := WrapStore(inner, New(down, "test")).Write(ctx, art); err == nil {
+		t.Fatal("Expected the write to fail when the log is unavailable")
+	}
+
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	log := New(bucket, "test")
+	store := WrapStore(inner, log)
+
+	// A retry that adopts the stored artifact logs it, once.
+	for i := 0; i < 2; i++ {
+		if err := store.Adopt(ctx, art); err != nil {
+			t.Fatalf("Adopt failed: %v", err)
+		}
+	}
+	if size, _ := log.Size(ctx); size != 1 {
+		t.Errorf("Expected 1 leaf, got %d", size)
+	}
+
+	// So does a retry that writes it again, which still reports the duplicate.
+	other := New(memblob.OpenBucket(nil), "test")
+	if err := WrapStore(inner, other).Write(ctx, art); !errors.Is(err, artifact.ErrArtifactAlreadyExists) {
+		t.Errorf("Expected ErrArtifactAlreadyExists, got %v", err)
+	}
+	if ok, err := other.Contains(ctx, art.ArtifactID); err != nil || !ok {
+		t.Errorf("Rewritten artifact not logged: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package artifactlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+)
+
+// Store is an artifact.Store that appends every written artifact to the log.
+//
+// The artifact is persisted first and logged second, matching the emit-then-record
+// order used everywhere else: a failure in between leaves an unlogged artifact, never a
+// logged ID without an artifact. The retry that adopts the artifact (Adopt), or writes
+// it again, logs it; reconciliation reports any that stay unlogged.
+type Store struct {
+	artifact.Store
+	log *Log
+}
+
+// WrapStore returns store with log appends on Write.
+func WrapStore(store artifact.Store, log *Log) *Store {
+	return &Store{Store: store, log: log}
+}
+
+// Write persists the artifact and appends its ID to the log. The emission fails if
+// the append fails, so no artifact is acknowledged without being logged. An artifact
+// that is already stored is still appended (appends are idempotent per ID): an earlier
+// Write may have persisted it and then failed to log it.
+func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error {
+	err := s.Store.Write(ctx, art)
+	if err != nil && !errors.Is(err, artifact.ErrArtifactAlreadyExists) {
+		return err
+	}
+	if _, lerr := s.log.Append(ctx, art.ArtifactID); lerr != nil {
+		return fmt.Errorf("artifact %s written but not logged: %w", art.ArtifactID, lerr)
+	}
+	return err
+}
+
+// Adopt appends an artifact persisted by an earlier Write to the log, in case that Write
+// failed to. It implements artifact.Adopter.
+func (s *Store) Adopt(ctx context.Context, art *models.CommitmentArtifact) error {
+	if _, err := s.log.Append(ctx, art.ArtifactID); err != nil {
+		return fmt.Errorf("artifact %s adopted but not logged: %w", art.ArtifactID, err)
+	}
+	return nil
+}
+
+// RunCheckpointer signs a checkpoint every interval while the log grows, until ctx is done.
+func (l *Log) RunCheckpointer(ctx context.Context, signer *signing.Signer, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		size, err := l.Size(ctx)
+		if err != nil {
+			logger.Error("Checkpoint skipped: log unreadable", "error", err)
+			continue
+		}
+		if size == 0 || size == last {
+			continue
+		}
+		cp, err := l.Checkpoint(ctx, signer)
+		if err != nil {
+			logger.Error("Checkpoint failed", "tree_size", size, "error", err)
+			continue
+		}
+		last = cp.TreeSize
+		logger.Info("Log checkpoint signed", "origin", cp.Origin, "tree_size", cp.TreeSize, "root_hash", cp.RootHash)
+	}
+}
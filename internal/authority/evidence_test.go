@@ -23,6 +23,11 @@ func (m *MockEmitter) EmitArtifact(ctx context.Context, instanceID, prevHash, st
 	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
 }
 
+func (m *MockEmitter) EmitBoundArtifact(ctx context.Context, instanceID, prevHash, state, policyVer, contextHash, actorID string, binding models.PolicyBinding) (*models.CommitmentArtifact, error) {
+	args := m.Called(ctx, instanceID, prevHash, state, policyVer, contextHash, actorID, binding)
+	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
+}
+
 type MockDB struct {
 	mock.Mock
 }
@@ -36,6 +41,24 @@ func (m *MockDB) RecordDecision(ctx context.Context, cmd engine.RecordDecisionCm
 	args := m.Called(ctx, cmd, nextState)
 	return args.Get(0).(*engine.Instance), args.Error(1)
 }
+func (m *MockDB) TransitionInstance(ctx context.Context, cmd engine.TransitionCmd) (*engine.Instance, error) {
+	return nil, nil
+}
+func (m *MockDB) RecordVote(ctx context.Context, cmd engine.RecordVoteCmd) (*engine.Instance, error) {
+	return nil, nil
+}
+func (m *MockDB) RecordEscalation(ctx context.Context, cmd engine.RecordEscalationCmd) (*engine.Instance, error) {
+	return nil, nil
+}
+func (m *MockDB) RecordRejectedDecision(ctx context.Context, cmd engine.RejectedDecisionCmd) error {
+	return nil
+}
+func (m *MockDB) QuarantineInstance(ctx context.Context, cmd engine.QuarantineCmd) (*engine.Instance, error) {
+	return nil, nil
+}
+func (m *MockDB) ResolveQuarantine(ctx context.Context, cmd engine.ResolveQuarantineCmd) (*engine.Instance, error) {
+	return nil, nil
+}
 func (m *MockDB) CreateInstance(ctx context.Context, inst *engine.Instance) error { return nil }
 func (m *MockDB) ListInstances(ctx context.Context) ([]*engine.Instance, error)   { return nil, nil }
 func (m *MockDB) GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error) {
@@ -66,6 +89,7 @@ func Test_Opaque_Handling(t *testing.T) {
 	// 1. Setup DB to return instance (for chaining)
 	mockDB.On("GetInstance", mock.Anything, instanceID).Return(&engine.Instance{
 		ID:               instanceID,
+		State:            engine.StateWaitingForHuman,
 		LastArtifactHash: "prev-hash",
 	}, nil)
 
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/Rainminds/gantral/core/engine"
 	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
 )
 
 var (
 	// ErrStateAmbiguous indicates a critical disconnect between execution state and evidence.
 	// This usually implies a phantom write or a replays attack.
-	ErrStateAmbiguous = errors.New("CRITICAL: state ambiguity detected (phantom artifact)")
+	// It is engine.ErrStateAmbiguous, so that activities can fail closed on it.
+	ErrStateAmbiguous = engine.ErrStateAmbiguous
 )
 
 // ConsistencyGuard safeguards transitions by verifying evidence existence.
@@ -34,7 +37,12 @@ func (g *ConsistencyGuard) EnsureStateConsistency(ctx context.Context, instanceI
 		// But if they claim an ID, it MUST exist.
 		return nil
 	}
+	_, err := g.claimedArtifact(ctx, instanceID, artifactID)
+	return err
+}
 
+// claimedArtifact fetches the artifact a database record claims and checks its binding.
+func (g *ConsistencyGuard) claimedArtifact(ctx context.Context, instanceID string, artifactID string) (*models.CommitmentArtifact, error) {
 	// 1. Check Store
 	art, err := g.store.Get(ctx, artifactID)
 
@@ -44,10 +52,10 @@ func (g *ConsistencyGuard) EnsureStateConsistency(ctx context.Context, instanceI
 			slog.Error("SECURITY ALERT: Phantom Artifact Detected",
 				"instance_id", instanceID,
 				"claimed_artifact_id", artifactID)
-			return fmt.Errorf("%w: artifact %s not found", ErrStateAmbiguous, artifactID)
+			return nil, fmt.Errorf("%w: artifact %s not found", ErrStateAmbiguous, artifactID)
 		}
 		// Other errors (e.g. connection) -> Fail Closed
-		return fmt.Errorf("consistency check failed: %w", err)
+		return nil, fmt.Errorf("consistency check failed: %w", err)
 	}
 
 	// 3. Verify Binding (Defense against ID reuse or collision)
@@ -55,8 +63,85 @@ func (g *ConsistencyGuard) EnsureStateConsistency(ctx context.Context, instanceI
 		slog.Error("SECURITY ALERT: Cross-Instance Contamination",
 			"expected_instance", instanceID,
 			"artifact_instance", art.InstanceID)
-		return fmt.Errorf("%w: instance mismatch", ErrStateAmbiguous)
+		return nil, fmt.Errorf("%w: instance mismatch", ErrStateAmbiguous)
+	}
+
+	return art, nil
+}
+
+// EnsureChainHead verifies that artifactID, the chain head the database claims for an
+// instance, is safe to chain a new artifact from: it exists, belongs to the instance
+// (EnsureStateConsistency) and has no successor in the store. A successor means the
+// database is behind the evidence; chaining from the stale head would fork the chain.
+// The one exception is a single successor matching attempt, which is the caller's own
+// artifact from an earlier try whose database write failed: it is returned for adoption.
+// Any other successor (a rolled-back database, a concurrent writer) is ambiguous.
+// An empty head is only accepted for an instance without any artifact (pre-genesis records).
+func (g *ConsistencyGuard) EnsureChainHead(ctx context.Context, instanceID string, artifactID string, attempt engine.ChainAttempt) (*models.CommitmentArtifact, error) {
+	if err := g.EnsureStateConsistency(ctx, instanceID, artifactID); err != nil {
+		return nil, err
+	}
+
+	arts, err := g.store.ListByInstance(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("consistency check failed: %w", err)
+	}
+
+	prev := artifactID
+	if prev == "" {
+		prev = models.GenesisHash
+	}
+	var successors []*models.CommitmentArtifact
+	for _, art := range arts {
+		if art.PrevArtifactHash == prev {
+			successors = append(successors, art)
+		}
+	}
+
+	if artifactID == "" && len(arts) > len(successors) {
+		slog.Error("SECURITY ALERT: Missing Chain Head",
+			"instance_id", instanceID,
+			"stored_artifacts", len(arts))
+		return nil, fmt.Errorf("%w: no chain head recorded but %d artifact(s) stored", ErrStateAmbiguous, len(arts))
+	}
+	if len(successors) == 0 {
+		return nil, nil
+	}
+
+	orphan := successors[0]
+	if len(successors) == 1 && attempt.Matches(instanceID, orphan) && !hasSuccessor(arts, orphan.ArtifactID) {
+		slog.Warn("Orphaned artifact from an earlier attempt",
+			"instance_id", instanceID,
+			"claimed_artifact_id", artifactID,
+			"orphan_artifact_id", orphan.ArtifactID)
+		return orphan, nil
 	}
 
-	return nil
+	slog.Error("SECURITY ALERT: Stale Chain Head",
+		"instance_id", instanceID,
+		"claimed_artifact_id", artifactID,
+		"successor_artifact_id", orphan.ArtifactID,
+		"successors", len(successors))
+	return nil, fmt.Errorf("%w: artifact %s is not the chain head (successor %s)", ErrStateAmbiguous, prev, orphan.ArtifactID)
+}
+
+// HeadArtifact returns artifactID once EnsureChainHead verifies it as the instance's chain
+// head (no successor). An empty head has no artifact and returns nil.
+func (g *ConsistencyGuard) HeadArtifact(ctx context.Context, instanceID string, artifactID string) (*models.CommitmentArtifact, error) {
+	if _, err := g.EnsureChainHead(ctx, instanceID, artifactID, engine.ChainAttempt{}); err != nil {
+		return nil, err
+	}
+	if artifactID == "" {
+		return nil, nil
+	}
+	return g.claimedArtifact(ctx, instanceID, artifactID)
+}
+
+func hasSuccessor(arts []*models.CommitmentArtifact, artifactID string) bool {
+	for _, art := range arts {
+		if art.PrevArtifactHash == artifactID {
+			return true
+		}
+	}
+	return false
 }
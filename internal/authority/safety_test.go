@@ -5,6 +5,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/Rainminds/gantral/core/engine"
 	"github.com/Rainminds/gantral/internal/artifact"
 	"github.com/Rainminds/gantral/pkg/models"
 	"github.com/stretchr/testify/assert"
@@ -30,6 +31,19 @@ func (m *MockArtifactStore) Get(ctx context.Context, id string) (*models.Commitm
 	return args.Get(0).(*models.CommitmentArtifact), args.Error(1)
 }
 
+func (m *MockArtifactStore) ListByInstance(ctx context.Context, instanceID string) ([]*models.CommitmentArtifact, error) {
+	args := m.Called(ctx, instanceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CommitmentArtifact), args.Error(1)
+}
+
+func (m *MockArtifactStore) Walk(ctx context.Context, fn artifact.WalkFunc) error {
+	args := m.Called(ctx, fn)
+	return args.Error(0)
+}
+
 // Since Engine is a struct, we can't mock it directly easily unless we interface it.
 // Phase 6.6 requirement was to wrap *Engine.
 // But for testing the wrapper, we need to inject errors.
@@ -98,6 +112,111 @@ func Test_Valid_Consistency(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func Test_Chain_Head(t *testing.T) {
+	ctx := context.Background()
+	head := &models.CommitmentArtifact{ArtifactID: "head-1", InstanceID: "inst-1", PrevArtifactHash: models.GenesisHash}
+	successor := &models.CommitmentArtifact{
+		ArtifactID:       "next-2",
+		InstanceID:       "inst-1",
+		PrevArtifactHash: "head-1",
+		AuthorityState:   "APPROVED",
+		PolicyVersionID:  "pol-1",
+		ContextHash:      "ctx-1",
+		HumanActorID:     "user-1",
+	}
+	attempt := engine.ChainAttempt{State: engine.StateApproved, PolicyVersionID: "pol-1", ContextHash: "ctx-1", ActorID: "user-1"}
+
+	t.Run("Head", func(t *testing.T) {
+		mockStore := new(MockArtifactStore)
+		mockStore.On("Get", ctx, "head-1").Return(head, nil)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{head}, nil)
+
+		orphan, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "head-1", attempt)
+		assert.NoError(t, err)
+		assert.Nil(t, orphan)
+	})
+
+	t.Run("Self Orphan", func(t *testing.T) {
+		// An earlier attempt emitted next-2, then failed to commit the database.
+		mockStore := new(MockArtifactStore)
+		mockStore.On("Get", ctx, "head-1").Return(head, nil)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{head, successor}, nil)
+
+		orphan, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "head-1", attempt)
+		assert.NoError(t, err)
+		assert.Equal(t, successor, orphan)
+	})
+
+	t.Run("Self Orphan At Genesis", func(t *testing.T) {
+		first := *successor
+		first.PrevArtifactHash = models.GenesisHash
+		mockStore := new(MockArtifactStore)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{&first}, nil)
+
+		orphan, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "", attempt)
+		assert.NoError(t, err)
+		assert.Equal(t, &first, orphan)
+	})
+
+	t.Run("Stale Head", func(t *testing.T) {
+		// The database was rolled back, or another writer chained next-2 for a different decision.
+		mockStore := new(MockArtifactStore)
+		mockStore.On("Get", ctx, "head-1").Return(head, nil)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{head, successor}, nil)
+
+		other := attempt
+		other.State = engine.StateRejected
+		orphan, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "head-1", other)
+		assert.True(t, errors.Is(err, ErrStateAmbiguous))
+		assert.Contains(t, err.Error(), "successor next-2")
+		assert.Nil(t, orphan)
+	})
+
+	t.Run("Matching Successor With Its Own Successor", func(t *testing.T) {
+		// The database is more than one artifact behind: not an orphan of this attempt.
+		third := &models.CommitmentArtifact{ArtifactID: "next-3", InstanceID: "inst-1", PrevArtifactHash: "next-2"}
+		mockStore := new(MockArtifactStore)
+		mockStore.On("Get", ctx, "head-1").Return(head, nil)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{head, successor, third}, nil)
+
+		_, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "head-1", attempt)
+		assert.True(t, errors.Is(err, ErrStateAmbiguous))
+	})
+
+	t.Run("Missing Head With Evidence", func(t *testing.T) {
+		mockStore := new(MockArtifactStore)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{head}, nil)
+
+		_, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "", attempt)
+		assert.True(t, errors.Is(err, ErrStateAmbiguous))
+	})
+
+	t.Run("Store Unavailable", func(t *testing.T) {
+		mockStore := new(MockArtifactStore)
+		mockStore.On("Get", ctx, "head-1").Return(head, nil)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return(nil, errors.New("bucket offline"))
+
+		_, err := NewConsistencyGuard(mockStore).EnsureChainHead(ctx, "inst-1", "head-1", attempt)
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, ErrStateAmbiguous), "an outage is retried, not quarantined")
+	})
+
+	t.Run("Head Artifact", func(t *testing.T) {
+		mockStore := new(MockArtifactStore)
+		mockStore.On("Get", ctx, "next-2").Return(successor, nil)
+		mockStore.On("ListByInstance", ctx, "inst-1").Return([]*models.CommitmentArtifact{head, successor}, nil)
+
+		got, err := NewConsistencyGuard(mockStore).HeadArtifact(ctx, "inst-1", "next-2")
+		assert.NoError(t, err)
+		assert.Equal(t, successor, got)
+
+		// head-1 has a successor, so it is not the head.
+		mockStore.On("Get", ctx, "head-1").Return(head, nil)
+		_, err = NewConsistencyGuard(mockStore).HeadArtifact(ctx, "inst-1", "head-1")
+		assert.True(t, errors.Is(err, ErrStateAmbiguous))
+	})
+}
+
 // -- Policy Safety Tests --
 // To test Policy fail-closed, we need an interface. I'll define one locally for the wrapper to use/test,
 // or check if I can modify the wrapper to use an interface.
@@ -0,0 +1,314 @@
+// Package opa provides an embedded Open Policy Agent (Rego) policy backend.
+//
+// Policies are loaded from disk once, compiled, and then evaluated in-process.
+// Non-deterministic builtins (time.now_ns, http.send, rand.intn, ...) are removed
+// from the compiler capabilities, so a policy that uses them is rejected at load time.
+// This keeps evaluation a pure function of its input and therefore safe to call
+// from inside GantralExecutionWorkflow.
+package opa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	corepolicy "github.com/Rainminds/gantral/core/policy"
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// DefaultQuery is the Rego document Gantral policies are expected to define.
+const DefaultQuery = "data.gantral.policies"
+
+var (
+	// ErrNoPolicies indicates that no policy path was supplied.
+	ErrNoPolicies = errors.New("no policy paths configured")
+	// ErrUndefinedResult indicates that the query produced no result (e.g. package mismatch).
+	ErrUndefinedResult = errors.New("policy query returned an undefined result")
+	// ErrInvalidResult indicates that the policy document has an unexpected shape.
+	ErrInvalidResult = errors.New("policy returned an invalid result")
+)
+
+// evalTime is pinned so that any time-dependent builtin that slips through is still reproducible.
+var evalTime = time.Unix(0, 0).UTC()
+
+// Evaluator evaluates Rego policies loaded from disk.
+// It implements internal/policy.Evaluator, core/policy.Backend and core/policy.Bundled.
+type Evaluator struct {
+	query      rego.PreparedEvalQuery
+	bundleHash string
+}
+
+// NewEvaluator loads and compiles the policies found at paths.
+// Each path may be a .rego file, a directory, or a bundle tarball (.tar.gz).
+func NewEvaluator(ctx context.Context, paths ...string) (*Evaluator, error) {
+	if len(paths) == 0 {
+		return nil, ErrNoPolicies
+	}
+
+	opts := []func(*rego.Rego){
+		rego.Query(DefaultQuery),
+		rego.Capabilities(deterministicCapabilities()),
+		rego.StrictBuiltinErrors(true),
+	}
+	for _, p := range paths {
+		if strings.HasSuffix(p, ".tar.gz") {
+			opts = append(opts, rego.LoadBundle(p))
+		} else {
+			opts = append(opts, rego.Load([]string{p}, nil))
+		}
+	}
+
+	bundleHash, err := hashPaths(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash policies: %w", err)
+	}
+
+	query, err := rego.New(opts...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile policies: %w", err)
+	}
+	return &Evaluator{query: query, bundleHash: bundleHash}, nil
+}
+
+// BundleHash implements core/policy.Bundled: the SHA-256 over every file loaded, by path
+// relative to the configured path. It is fixed when the evaluator is created.
+func (e *Evaluator) BundleHash() string {
+	return e.bundleHash
+}
+
+// hashPaths hashes the content of every regular file under paths, in a stable order.
+// Files are keyed by their path relative to the configured path, so the hash does not
+// depend on where the policies are deployed.
+func hashPaths(paths []string) (string, error) {
+	h := sha256.New()
+	for i, root := range paths {
+		var files []string
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return "", err
+		}
+		sort.Strings(files)
+
+		for _, path := range files {
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return "", err
+			}
+			if rel == "." {
+				rel = filepath.Base(path)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return "", err
+			}
+			sum := sha256.Sum256(content)
+			fmt.Fprintf(h, "%d %s %s\n", i, filepath.ToSlash(rel), hex.EncodeToString(sum[:]))
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Evaluate implements internal/policy.Evaluator. The policy is evaluated with an empty context.
+func (e *Evaluator) Evaluate(ctx context.Context, p corepolicy.Policy) (corepolicy.EvaluationResult, error) {
+	return e.EvaluateInput(ctx, corepolicy.NewInput("", p, nil))
+}
+
+// EvaluateInput implements core/policy.Backend.
+func (e *Evaluator) EvaluateInput(ctx context.Context, in corepolicy.Input) (corepolicy.EvaluationResult, error) {
+	rs, err := e.query.Eval(ctx, rego.EvalInput(in), rego.EvalTime(evalTime))
+	if err != nil {
+		return corepolicy.EvaluationResult{}, fmt.Errorf("policy evaluation failed: %w", err)
+	}
+	if len(rs) == 0 || len(rs[0].Expressions) == 0 {
+		return corepolicy.EvaluationResult{}, ErrUndefinedResult
+	}
+
+	doc, ok := rs[0].Expressions[0].Value.(map[string]interface{})
+	if !ok {
+		return corepolicy.EvaluationResult{}, fmt.Errorf("%w: expected object, got %T", ErrInvalidResult, rs[0].Expressions[0].Value)
+	}
+	return decode(doc)
+}
+
+// decode maps the policy document onto an EvaluationResult.
+//
+// Recognised rules:
+//   - decision (string): explicit ALLOW / REQUIRE_HUMAN / DENY. Takes precedence.
+//   - deny (bool), allow (bool): DENY if deny is true or allow is false.
+//   - requires_human_approval (bool): REQUIRE_HUMAN if true.
+//   - approvers ([]string), quorum (number), reason (string), timeout_seconds (number).
+//   - approver_roles ([]string), override_roles ([]string): no role may be in both.
+func decode(doc map[string]interface{}) (corepolicy.EvaluationResult, error) {
+	reason, err := optString(doc, "reason")
+	if err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+
+	decision, err := decodeDecision(doc)
+	if err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+	if reason == "" {
+		reason = fmt.Sprintf("Policy decision: %s", decision)
+	}
+
+	result := corepolicy.ResultForDecision(decision, reason)
+
+	if result.Approvers, err = optStrings(doc, "approvers"); err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+	quorum, err := optInt(doc, "quorum")
+	if err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+	result.Quorum = int(quorum)
+	if result.TimeoutSeconds, err = optInt(doc, "timeout_seconds"); err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+	if result.ApproverRoles, err = optStrings(doc, "approver_roles"); err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+	if result.OverrideRoles, err = optStrings(doc, "override_roles"); err != nil {
+		return corepolicy.EvaluationResult{}, err
+	}
+	if err := corepolicy.ValidateRoles(result.ApproverRoles, result.OverrideRoles); err != nil {
+		return corepolicy.EvaluationResult{}, fmt.Errorf("%w: %v", ErrInvalidResult, err)
+	}
+	return result, nil
+}
+
+func decodeDecision(doc map[string]interface{}) (corepolicy.Decision, error) {
+	explicit, err := optString(doc, "decision")
+	if err != nil {
+		return "", err
+	}
+	if explicit != "" {
+		d := corepolicy.Decision(strings.ToUpper(explicit))
+		switch d {
+		case corepolicy.DecisionAllow, corepolicy.DecisionRequireHuman, corepolicy.DecisionDeny:
+			return d, nil
+		default:
+			return "", fmt.Errorf("%w: unknown decision %q", ErrInvalidResult, explicit)
+		}
+	}
+
+	deny, err := optBool(doc, "deny", false)
+	if err != nil {
+		return "", err
+	}
+	allow, err := optBool(doc, "allow", true)
+	if err != nil {
+		return "", err
+	}
+	if deny || !allow {
+		return corepolicy.DecisionDeny, nil
+	}
+
+	requiresHuman, err := optBool(doc, "requires_human_approval", false)
+	if err != nil {
+		return "", err
+	}
+	if requiresHuman {
+		return corepolicy.DecisionRequireHuman, nil
+	}
+	return corepolicy.DecisionAllow, nil
+}
+
+func optString(doc map[string]interface{}, key string) (string, error) {
+	v, ok := doc[key]
+	if !ok {
+		return "", nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s must be a string, got %T", ErrInvalidResult, key, v)
+	}
+	return s, nil
+}
+
+func optBool(doc map[string]interface{}, key string, fallback bool) (bool, error) {
+	v, ok := doc[key]
+	if !ok {
+		return fallback, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("%w: %s must be a boolean, got %T", ErrInvalidResult, key, v)
+	}
+	return b, nil
+}
+
+func optInt(doc map[string]interface{}, key string) (int64, error) {
+	v, ok := doc[key]
+	if !ok {
+		return 0, nil
+	}
+	n, ok := v.(interface{ Int64() (int64, error) }) // json.Number
+	if !ok {
+		return 0, fmt.Errorf("%w: %s must be a number, got %T", ErrInvalidResult, key, v)
+	}
+	i, err := n.Int64()
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("%w: %s must be a non-negative integer", ErrInvalidResult, key)
+	}
+	return i, nil
+}
+
+func optStrings(doc map[string]interface{}, key string) ([]string, error) {
+	v, ok := doc[key]
+	if !ok {
+		return nil, nil
+	}
+	// Rego sets and arrays both decode to []interface{}.
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s must be an array of strings, got %T", ErrInvalidResult, key, v)
+	}
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s must contain only strings", ErrInvalidResult, key)
+		}
+		out = append(out, s)
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	// Sorted so that set ordering never leaks into the result.
+	sort.Strings(out)
+	return out, nil
+}
+
+// deterministicCapabilities returns the current OPA capabilities minus every
+// builtin flagged as non-deterministic.
+func deterministicCapabilities() *ast.Capabilities {
+	caps := ast.CapabilitiesForThisVersion()
+	builtins := make([]*ast.Builtin, 0, len(caps.Builtins))
+	for _, b := range caps.Builtins {
+		if b.Nondeterministic {
+			continue
+		}
+		builtins = append(builtins, b)
+	}
+	caps.Builtins = builtins
+	caps.AllowNet = []string{}
+	return caps
+}
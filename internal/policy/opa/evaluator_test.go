@@ -0,0 +1,194 @@
+package opa
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	corepolicy "github.com/Rainminds/gantral/core/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func examplePolicy(name string) string {
+	return filepath.Join("..", "..", "..", "examples", "policies", name)
+}
+
+func writePolicy(t *testing.T, src string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.rego")
+	if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEvaluator_BasicApproval(t *testing.T) {
+	ctx := context.Background()
+	ev, err := NewEvaluator(ctx, examplePolicy("basic_approval.rego"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	high := corepolicy.Policy{ID: "pol-high", Materiality: corepolicy.MaterialityHigh}
+	res, err := ev.EvaluateInput(ctx, corepolicy.NewInput("wf-1", high, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, res.ShouldPause)
+	assert.Equal(t, "WAITING_FOR_HUMAN", res.NextState)
+	assert.Equal(t, "High Materiality workflow requires human approval", res.Reason)
+
+	low := corepolicy.Policy{ID: "pol-low", Materiality: corepolicy.MaterialityLow}
+	res, err = ev.Evaluate(ctx, low)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.False(t, res.ShouldPause)
+	assert.Equal(t, "RUNNING", res.NextState)
+}
+
+func TestEvaluator_TriggerContextAndApprovers(t *testing.T) {
+	ctx := context.Background()
+	ev, err := NewEvaluator(ctx, examplePolicy("multi_step.rego"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in := corepolicy.NewInput("wf-1", corepolicy.Policy{ID: "pol"}, map[string]interface{}{"category": "CRITICAL"})
+	res, err := ev.EvaluateInput(ctx, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.True(t, res.ShouldPause)
+	assert.Equal(t, []string{"group:compliance", "group:engineering"}, res.Approvers)
+	assert.Equal(t, 2, res.Quorum)
+}
+
+func TestEvaluator_Deny(t *testing.T) {
+	ctx := context.Background()
+	ev, err := NewEvaluator(ctx, examplePolicy("timeout.rego"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in := corepolicy.NewInput("wf-1", corepolicy.Policy{ID: "pol"}, nil)
+	in.CurrentState = "WAITING_FOR_HUMAN"
+	res, err := ev.EvaluateInput(ctx, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Time values are absent from input, so the rule is undefined and execution is allowed.
+	assert.Equal(t, "RUNNING", res.NextState)
+
+	path := writePolicy(t, `package gantral.policies
+decision := "DENY"
+reason := "blocked"
+timeout_seconds := 60
+`)
+	ev, err = NewEvaluator(ctx, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, err = ev.Evaluate(ctx, corepolicy.Policy{ID: "pol"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.False(t, res.ShouldPause)
+	assert.Equal(t, "TERMINATED", res.NextState)
+	assert.Equal(t, "blocked", res.Reason)
+	assert.Equal(t, int64(60), res.TimeoutSeconds)
+}
+
+func TestEvaluator_RejectsNondeterministicBuiltins(t *testing.T) {
+	path := writePolicy(t, `package gantral.policies
+requires_human_approval if time.now_ns() > 0
+`)
+	_, err := NewEvaluator(context.Background(), path)
+	assert.Error(t, err)
+}
+
+func TestEvaluator_InvalidResult(t *testing.T) {
+	ctx := context.Background()
+
+	ev, err := NewEvaluator(ctx, writePolicy(t, `package gantral.policies
+decision := "MAYBE"
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = ev.Evaluate(ctx, corepolicy.Policy{ID: "pol"})
+	assert.ErrorIs(t, err, ErrInvalidResult)
+
+	ev, err = NewEvaluator(ctx, writePolicy(t, `package other
+allow := true
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = ev.Evaluate(ctx, corepolicy.Policy{ID: "pol"})
+	assert.Error(t, err)
+}
+
+func TestEvaluator_DecisionRoles(t *testing.T) {
+	ctx := context.Background()
+
+	ev, err := NewEvaluator(ctx, writePolicy(t, `package gantral.policies
+requires_human_approval := true
+approver_roles := ["approver"]
+override_roles := ["break-glass"]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	res, err := ev.Evaluate(ctx, corepolicy.Policy{ID: "pol"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"approver"}, res.ApproverRoles)
+	assert.Equal(t, []string{"break-glass"}, res.OverrideRoles)
+
+	// Override is break-glass: a role that approves cannot also override.
+	ev, err = NewEvaluator(ctx, writePolicy(t, `package gantral.policies
+requires_human_approval := true
+approver_roles := ["approver"]
+override_roles := ["approver"]
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = ev.Evaluate(ctx, corepolicy.Policy{ID: "pol"})
+	assert.ErrorIs(t, err, ErrInvalidResult)
+}
+
+func TestNewEvaluator_NoPaths(t *testing.T) {
+	_, err := NewEvaluator(context.Background())
+	assert.ErrorIs(t, err, ErrNoPolicies)
+}
+
+func TestEvaluator_BundleHash(t *testing.T) {
+	ctx := context.Background()
+	src := `package gantral.policies
+requires_human_approval := true
+`
+	a, err := NewEvaluator(ctx, writePolicy(t, src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := NewEvaluator(ctx, writePolicy(t, src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Len(t, a.BundleHash(), 64)
+	assert.Equal(t, a.BundleHash(), b.BundleHash(), "the same policies deployed elsewhere hash the same")
+
+	changed, err := NewEvaluator(ctx, writePolicy(t, src+"reason := \"changed\"\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.NotEqual(t, a.BundleHash(), changed.BundleHash())
+
+	dir, err := NewEvaluator(ctx, filepath.Dir(writePolicy(t, src)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	assert.Equal(t, a.BundleHash(), dir.BundleHash(), "a directory hashes its files by relative path")
+}
@@ -23,6 +23,7 @@ type Evaluator interface {
 // FailClosedEngine wraps a Policy Engine to enforce strict fail-closed behavior.
 // If the underlying engine fails or returns an ambiguous result, this wrapper
 // ensures the decision is "WAITING_FOR_HUMAN" (safe state), never "RUNNING".
+// A DENY is always passed through as "TERMINATED".
 type FailClosedEngine struct {
 	inner Evaluator
 }
@@ -37,31 +38,90 @@ func (e *FailClosedEngine) Evaluate(ctx context.Context, p corepolicy.Policy) (c
 	// 1. Delegated Evaluation
 	result, err := e.inner.Evaluate(ctx, p)
 
+	return guard(p.ID, result, err), nil
+}
+
+// EvaluateInput implements core/policy.Backend with the same fail-closed guarantees.
+// If the inner evaluator is not a Backend, the trigger context is dropped and
+// evaluation falls back to Evaluate.
+func (e *FailClosedEngine) EvaluateInput(ctx context.Context, in corepolicy.Input) (corepolicy.EvaluationResult, error) {
+	var result corepolicy.EvaluationResult
+	var err error
+	if backend, ok := e.inner.(corepolicy.Backend); ok {
+		result, err = backend.EvaluateInput(ctx, in)
+	} else {
+		result, err = e.inner.Evaluate(ctx, in.Policy)
+	}
+
+	return guard(in.Policy.ID, result, err), nil
+}
+
+// BundleHash implements core/policy.Bundled for inner evaluators that load a bundle.
+func (e *FailClosedEngine) BundleHash() string {
+	if b, ok := e.inner.(corepolicy.Bundled); ok {
+		return b.BundleHash()
+	}
+	return ""
+}
+
+// guard converts evaluation failures and ambiguous results into the safe state.
+func guard(policyID string, result corepolicy.EvaluationResult, err error) corepolicy.EvaluationResult {
 	// 2. Fail-Closed Guard (Error Case)
 	if err != nil {
 		slog.Error("SECURITY ALERT: Policy evaluation failed. Enforcing fail-closed.",
 			"error", err,
-			"policy_id", p.ID)
+			"policy_id", policyID)
 
+		// Swallowing error allows the workflow to "park" in a SAFE state
+		// rather than crash-looping. This is intentional Fail-Safe design.
 		return corepolicy.EvaluationResult{
+			Decision:    corepolicy.DecisionRequireHuman,
 			ShouldPause: true,
 			NextState:   constants.StateWaitingForHuman, // Fail Safe
 			Reason:      fmt.Sprintf("Fail-Closed: Policy Error (%v)", err),
-		}, nil
-		// Swallowing error allows the workflow to "park" in a SAFE state
-		// rather than crash-looping. This is intentional Fail-Safe design.
+		}
 	}
 
 	// 3. Fail-Closed Guard (Invalid/Ambiguous Result)
 	if result.NextState == "" {
-		slog.Error("SECURITY ALERT: Policy returned empty state. Enforcing fail-closed.", "policy_id", p.ID)
-		return corepolicy.EvaluationResult{
-			ShouldPause: true,
-			NextState:   constants.StateWaitingForHuman,
-			Reason:      "Fail-Closed: Ambiguous Result",
-		}, nil
+		slog.Error("SECURITY ALERT: Policy returned empty state. Enforcing fail-closed.", "policy_id", policyID)
+		return ambiguous()
+	}
+
+	// Legacy evaluators only set NextState; derive the decision from it.
+	decision := result.Decision
+	if decision == "" {
+		inferred, ok := corepolicy.DecisionForState(result.NextState)
+		if !ok {
+			slog.Error("SECURITY ALERT: Policy returned unknown state. Enforcing fail-closed.",
+				"policy_id", policyID, "next_state", result.NextState)
+			return ambiguous()
+		}
+		decision = inferred
 	}
 
+	// The decision and the state it diverts to MUST agree (ADR-007).
+	// A DENY is never softened; any other disagreement parks the execution.
+	canonical := corepolicy.ResultForDecision(decision, result.Reason)
+	if decision != corepolicy.DecisionDeny &&
+		(canonical.Decision != decision || canonical.NextState != result.NextState || canonical.ShouldPause != result.ShouldPause) {
+		slog.Error("SECURITY ALERT: Policy decision contradicts next state. Enforcing fail-closed.",
+			"policy_id", policyID, "decision", decision, "next_state", result.NextState)
+		return ambiguous()
+	}
+	canonical.Approvers = result.Approvers
+	canonical.Quorum = result.Quorum
+	canonical.TimeoutSeconds = result.TimeoutSeconds
+
 	// 4. Pass-through valid result
-	return result, nil
+	return canonical
+}
+
+func ambiguous() corepolicy.EvaluationResult {
+	return corepolicy.EvaluationResult{
+		Decision:    corepolicy.DecisionRequireHuman,
+		ShouldPause: true,
+		NextState:   constants.StateWaitingForHuman,
+		Reason:      "Fail-Closed: Ambiguous Result",
+	}
 }
@@ -83,3 +83,77 @@ func Test_Valid_PassThrough(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "RUNNING", result.NextState)
 }
+
+type errBackend struct{ MockEvaluator }
+
+func (b *errBackend) EvaluateInput(ctx context.Context, in corepolicy.Input) (corepolicy.EvaluationResult, error) {
+	return corepolicy.EvaluationResult{}, errors.New("rego compile error")
+}
+
+func Test_EvaluateInput_Fails_Closed(t *testing.T) {
+	safeEngine := NewFailClosedEngine(&errBackend{})
+	in := corepolicy.NewInput("wf-1", corepolicy.Policy{ID: "test-policy"}, map[string]interface{}{"k": "v"})
+
+	result, err := safeEngine.EvaluateInput(context.Background(), in)
+
+	assert.NoError(t, err)
+	assert.True(t, result.ShouldPause)
+	assert.Equal(t, "WAITING_FOR_HUMAN", result.NextState)
+	assert.Contains(t, result.Reason, "rego compile error")
+}
+
+func Test_EvaluateInput_Falls_Back_To_Evaluate(t *testing.T) {
+	mockEval := new(MockEvaluator)
+	safeEngine := NewFailClosedEngine(mockEval)
+	ctx := context.Background()
+	p := corepolicy.Policy{ID: "test-policy"}
+
+	mockEval.On("Evaluate", ctx, p).Return(corepolicy.EvaluationResult{NextState: "RUNNING"}, nil)
+
+	result, err := safeEngine.EvaluateInput(ctx, corepolicy.NewInput("wf-1", p, nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "RUNNING", result.NextState)
+	mockEval.AssertExpectations(t)
+}
+
+func Test_Deny_PassThrough(t *testing.T) {
+	mockEval := new(MockEvaluator)
+	safeEngine := NewFailClosedEngine(mockEval)
+	ctx := context.Background()
+	p := corepolicy.Policy{ID: "test-policy"}
+
+	// Case: DENY with an inconsistent state is still a DENY (never softened)
+	mockEval.On("Evaluate", ctx, p).Return(corepolicy.EvaluationResult{
+		Decision:  corepolicy.DecisionDeny,
+		NextState: "RUNNING",
+		Reason:    "blocked",
+	}, nil)
+
+	result, err := safeEngine.Evaluate(ctx, p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, corepolicy.DecisionDeny, result.Decision)
+	assert.Equal(t, "TERMINATED", result.NextState)
+	assert.False(t, result.ShouldPause)
+}
+
+func Test_Contradictory_Result_Fails_Closed(t *testing.T) {
+	mockEval := new(MockEvaluator)
+	safeEngine := NewFailClosedEngine(mockEval)
+	ctx := context.Background()
+	p := corepolicy.Policy{ID: "test-policy"}
+
+	// Case: ALLOW that claims to pause
+	mockEval.On("Evaluate", ctx, p).Return(corepolicy.EvaluationResult{
+		Decision:    corepolicy.DecisionAllow,
+		ShouldPause: true,
+		NextState:   "WAITING_FOR_HUMAN",
+	}, nil)
+
+	result, err := safeEngine.Evaluate(ctx, p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, corepolicy.DecisionRequireHuman, result.Decision)
+	assert.Contains(t, result.Reason, "Ambiguous Result")
+}
@@ -0,0 +1,319 @@
+// Package reconcile compares the operational database with the artifact store.
+//
+// Decisions and transitions emit their artifact first and write the database second
+// (see core/activities), so the two stores can diverge: an artifact whose database
+// write failed is an orphan, which is acceptable but must be visible. The reverse, a
+// database record without its artifact, must never happen. The reconciler walks every
+// instance and classifies each divergence:
+//
+//   - ORPHAN: evidence the database does not know about.
+//   - MISSING: the database (or the global log) references evidence that does not exist.
+//   - MISMATCH: both exist but disagree.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/verifier"
+)
+
+// Class groups findings by which side is ahead.
+type Class string
+
+const (
+	ClassOrphan   Class = "ORPHAN"
+	ClassMissing  Class = "MISSING"
+	ClassMismatch Class = "MISMATCH"
+)
+
+// Code is a stable, machine-readable reason for a finding.
+type Code string
+
+const (
+	// CodeOrphanInstance: artifacts of an instance the database has no row for
+	// (genesis emitted, instance insert failed).
+	CodeOrphanInstance Code = "ORPHAN_INSTANCE"
+	// CodeOrphanArtifact: the chain extends beyond instances.last_artifact_hash
+	// (artifact emitted, state update failed).
+	CodeOrphanArtifact Code = "ORPHAN_ARTIFACT"
+	// CodeMissingHead: instances.last_artifact_hash is empty or not in the store.
+	CodeMissingHead Code = "MISSING_HEAD"
+	// CodeMissingArtifact: an audit event references an artifact that is not in the store.
+	CodeMissingArtifact Code = "MISSING_ARTIFACT"
+	// CodeNotLogged: a stored artifact is not in the global artifact log.
+	CodeNotLogged Code = "NOT_LOGGED"
+	// CodeStateMismatch: instances.state differs from the head artifact's authority state.
+	CodeStateMismatch Code = "STATE_MISMATCH"
+	// CodeHeadOffChain: the head artifact exists but is not on the instance's main chain.
+	CodeHeadOffChain Code = "HEAD_OFF_CHAIN"
+	// CodeDecisionMismatch: the decisions table disagrees with the decision artifacts.
+	CodeDecisionMismatch Code = "DECISION_MISMATCH"
+	// CodeAuditMismatch: audit_events disagree with the decisions table or the chain.
+	CodeAuditMismatch Code = "AUDIT_MISMATCH"
+	// CodeChainInvalid: the chain itself fails verification (fork, cycle, broken link...).
+	CodeChainInvalid Code = "CHAIN_INVALID"
+)
+
+// Finding is a single divergence between the database and the evidence.
+type Finding struct {
+	Class      Class  `json:"class"`
+	Code       Code   `json:"code"`
+	InstanceID string `json:"instance_id"`
+	ArtifactID string `json:"artifact_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Database is the operational state being reconciled (adapters/secondary/postgres.Store).
+type Database interface {
+	ListInstances(ctx context.Context) ([]*engine.Instance, error)
+	GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error)
+	ListDecisions(ctx context.Context, instanceID string) ([]engine.DecisionRecord, error)
+}
+
+// LogIndex answers whether an artifact is in the global log (artifactlog.Log).
+type LogIndex interface {
+	Contains(ctx context.Context, artifactID string) (bool, error)
+}
+
+// Reconciler compares a Database with an artifact.Store and, optionally, the global log.
+type Reconciler struct {
+	db    Database
+	store artifact.Store
+	log   LogIndex
+
+	// Grace skips orphans younger than this: between emission and the database
+	// write every in-flight artifact is briefly an orphan.
+	Grace time.Duration
+	now   func() time.Time
+}
+
+// New creates a reconciler. log may be nil when no global log is configured.
+func New(db Database, store artifact.Store, log LogIndex) *Reconciler {
+	return &Reconciler{db: db, store: store, log: log, Grace: time.Minute, now: time.Now}
+}
+
+// Run reconciles every instance and returns an unsigned report.
+// It fails only when a store cannot be read; divergences are findings.
+func (r *Reconciler) Run(ctx context.Context) (*Report, error) {
+	instances, err := r.db.ListInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	// One pass over the store groups every artifact by instance, so that chains of
+	// instances unknown to the database are found too.
+	chains := make(map[string][]models.CommitmentArtifact)
+	total := 0
+	err = r.store.Walk(ctx, func(art *models.CommitmentArtifact) error {
+		chains[art.InstanceID] = append(chains[art.InstanceID], *art)
+		total++
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk artifact store: %w", err)
+	}
+
+	report := newReport(r.now(), len(instances), total)
+	known := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		known[inst.ID] = true
+		findings, err := r.reconcileInstance(ctx, inst, chains[inst.ID])
+		if err != nil {
+			return nil, fmt.Errorf("instance %s: %w", inst.ID, err)
+		}
+		report.add(findings...)
+	}
+
+	for id, arts := range chains {
+		if !known[id] && !r.recent(arts) {
+			report.add(Finding{
+				Class:      ClassOrphan,
+				Code:       CodeOrphanInstance,
+				InstanceID: id,
+				Message:    fmt.Sprintf("%d artifact(s) for an instance the database does not know", len(arts)),
+			})
+		}
+	}
+
+	if r.log != nil {
+		for _, arts := range chains {
+			for _, art := range arts {
+				ok, err := r.log.Contains(ctx, art.ArtifactID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read artifact log: %w", err)
+				}
+				if !ok && !r.recent([]models.CommitmentArtifact{art}) {
+					report.add(Finding{
+						Class:      ClassMissing,
+						Code:       CodeNotLogged,
+						InstanceID: art.InstanceID,
+						ArtifactID: art.ArtifactID,
+						Message:    "artifact is stored but not in the global artifact log",
+					})
+				}
+			}
+		}
+	}
+
+	report.sort()
+	return report, nil
+}
+
+func (r *Reconciler) reconcileInstance(ctx context.Context, inst *engine.Instance, arts []models.CommitmentArtifact) ([]Finding, error) {
+	var findings []Finding
+	add := func(class Class, code Code, artifactID, format string, args ...interface{}) {
+		findings = append(findings, Finding{Class: class, Code: code, InstanceID: inst.ID, ArtifactID: artifactID, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// An instance without any artifact is reported once, as a missing head, below.
+	var chain []models.CommitmentArtifact
+	if len(arts) > 0 {
+		var chainFindings []verifier.Finding
+		chain, chainFindings = verifier.OrderChain(arts)
+		chainFindings = append(chainFindings, verifier.VerifyTransitions(chain)...)
+		for _, f := range chainFindings {
+			add(ClassMismatch, CodeChainInvalid, f.ArtifactID, "%s: %s", f.Code, f.Message)
+		}
+	}
+
+	// 1. The database head must be on the main chain; everything after it is orphaned.
+	head := -1
+	for i, art := range chain {
+		if art.ArtifactID == inst.LastArtifactHash {
+			head = i
+		}
+	}
+	switch {
+	case head >= 0:
+		if string(inst.State) != chain[head].AuthorityState {
+			add(ClassMismatch, CodeStateMismatch, inst.LastArtifactHash,
+				"database state %s, head artifact state %s", inst.State, chain[head].AuthorityState)
+		}
+		for _, art := range chain[head+1:] {
+			if !r.recent([]models.CommitmentArtifact{art}) {
+				add(ClassOrphan, CodeOrphanArtifact, art.ArtifactID,
+					"artifact (%s) extends the chain beyond the database head", art.AuthorityState)
+			}
+		}
+	case inst.LastArtifactHash == "":
+		add(ClassMissing, CodeMissingHead, "", "instance has no last_artifact_hash")
+	case contains(arts, inst.LastArtifactHash):
+		add(ClassMismatch, CodeHeadOffChain, inst.LastArtifactHash, "head artifact is not on the instance's main chain")
+	default:
+		if _, err := r.store.Get(ctx, inst.LastArtifactHash); err == nil {
+			add(ClassMismatch, CodeHeadOffChain, inst.LastArtifactHash, "head artifact belongs to another instance")
+		} else {
+			add(ClassMissing, CodeMissingHead, inst.LastArtifactHash, "head artifact not found in the store")
+		}
+	}
+	acknowledged := chain
+	if head >= 0 {
+		acknowledged = chain[:head+1]
+	}
+
+	// 2. Every decision row has a decision artifact, in the same order.
+	decisions, err := r.db.ListDecisions(ctx, inst.ID)
+	if err != nil {
+		return nil, err
+	}
+	var decisionStates []string
+	for _, art := range acknowledged {
+		if isDecisionState(art.AuthorityState) {
+			decisionStates = append(decisionStates, art.AuthorityState)
+		}
+	}
+	if len(decisions) != len(decisionStates) {
+		add(ClassMismatch, CodeDecisionMismatch, "", "%d decision row(s), %d decision artifact(s)", len(decisions), len(decisionStates))
+	} else {
+		for i, d := range decisions {
+			want, err := engine.CalculateNextState(d.Type)
+			if err != nil || string(want) != decisionStates[i] {
+				add(ClassMismatch, CodeDecisionMismatch, "", "decision %s (%s) does not match decision artifact %d (%s)", d.ID, d.Type, i, decisionStates[i])
+			}
+		}
+	}
+
+	// 3. Audit events agree with the decisions and reference existing chain artifacts.
+	events, err := r.db.GetAuditEvents(ctx, inst.ID)
+	if err != nil {
+		return nil, err
+	}
+	onChain := make(map[string]bool, len(acknowledged))
+	for _, art := range acknowledged {
+		onChain[art.ArtifactID] = true
+	}
+	recorded := 0
+	for _, evt := range events {
+		switch evt.EventType {
+		case "DECISION_RECORDED":
+			recorded++
+		case "STATE_TRANSITIONED":
+			id, _ := evt.Payload["artifact_id"].(string)
+			switch {
+			case id == "" || onChain[id]:
+			case contains(arts, id):
+				add(ClassMismatch, CodeAuditMismatch, id, "audit event %s references an artifact off the acknowledged chain", evt.ID)
+			default:
+				add(ClassMissing, CodeMissingArtifact, id, "audit event %s references an artifact not in the store", evt.ID)
+			}
+		}
+	}
+	if recorded != len(decisions) {
+		add(ClassMismatch, CodeAuditMismatch, "", "%d DECISION_RECORDED event(s), %d decision row(s)", recorded, len(decisions))
+	}
+
+	return findings, nil
+}
+
+// recent reports whether every artifact is younger than the grace period.
+// Unparseable timestamps are never recent.
+func (r *Reconciler) recent(arts []models.CommitmentArtifact) bool {
+	cutoff := r.now().Add(-r.Grace)
+	for _, art := range arts {
+		ts, err := time.Parse(time.RFC3339Nano, art.Timestamp)
+		if err != nil || !ts.After(cutoff) {
+			return false
+		}
+	}
+	return len(arts) > 0
+}
+
+func isDecisionState(state string) bool {
+	switch engine.State(state) {
+	case engine.StateApproved, engine.StateRejected, engine.StateOverridden:
+		return true
+	}
+	return false
+}
+
+func contains(arts []models.CommitmentArtifact, id string) bool {
+	for _, art := range arts {
+		if art.ArtifactID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sortFindings orders findings by instance, class, code and artifact for stable reports.
+func sortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.InstanceID != b.InstanceID {
+			return a.InstanceID < b.InstanceID
+		}
+		if a.Class != b.Class {
+			return a.Class < b.Class
+		}
+		if a.Code != b.Code {
+			return a.Code < b.Code
+		}
+		return a.ArtifactID < b.ArtifactID
+	})
+}
@@ -0,0 +1,218 @@
+package reconcile
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"gocloud.dev/blob/memblob"
+)
+
+// fakeDB is an in-memory Database.
+type fakeDB struct {
+	instances []*engine.Instance
+	decisions map[string][]engine.DecisionRecord
+	events    map[string][]engine.AuditEvent
+}
+
+func (f *fakeDB) ListInstances(ctx context.Context) ([]*engine.Instance, error) {
+	return f.instances, nil
+}
+
+func (f *fakeDB) GetAuditEvents(ctx context.Context, instanceID string) ([]engine.AuditEvent, error) {
+	return f.events[instanceID], nil
+}
+
+func (f *fakeDB) ListDecisions(ctx context.Context, instanceID string) ([]engine.DecisionRecord, error) {
+	return f.decisions[instanceID], nil
+}
+
+type fixture struct {
+	t     *testing.T
+	db    *fakeDB
+	store *local.Store
+}
+
+func newFixture(t *testing.T) *fixture {
+	store, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fixture{t: t, store: store, db: &fakeDB{
+		decisions: map[string][]engine.DecisionRecord{},
+		events:    map[string][]engine.AuditEvent{},
+	}}
+}
+
+// chain writes artifacts with the given states, each linked to the previous one.
+func (f *fixture) chain(instanceID string, states ...string) []*models.CommitmentArtifact {
+	prev := models.GenesisHash
+	var arts []*models.CommitmentArtifact
+	for i, state := range states {
+		art := models.NewCommitmentArtifact(instanceID, prev, state, "pv", fmt.Sprintf("ctx-%d", i), "sys")
+		if err := art.CalculateHashAndSetID(); err != nil {
+			f.t.Fatal(err)
+		}
+		if err := f.store.Write(context.Background(), art); err != nil {
+			f.t.Fatal(err)
+		}
+		arts = append(arts, art)
+		prev = art.ArtifactID
+	}
+	return arts
+}
+
+// approved registers an instance whose database rows match WAITING_FOR_HUMAN -> APPROVED.
+func (f *fixture) approved(instanceID string) []*models.CommitmentArtifact {
+	arts := f.chain(instanceID, "WAITING_FOR_HUMAN", "APPROVED")
+	f.db.instances = append(f.db.instances, &engine.Instance{ID: instanceID, State: engine.StateApproved, LastArtifactHash: arts[1].ArtifactID})
+	f.db.decisions[instanceID] = []engine.DecisionRecord{{ID: "dec-" + instanceID, InstanceID: instanceID, Type: engine.DecisionApprove}}
+	f.db.events[instanceID] = []engine.AuditEvent{
+		{ID: "evt-1", EventType: "INSTANCE_CREATED"},
+		{ID: "evt-2", EventType: "DECISION_RECORDED"},
+	}
+	return arts
+}
+
+func (f *fixture) run(log LogIndex) *Report {
+	rec := New(f.db, f.store, log)
+	rec.now = func() time.Time { return time.Now().Add(time.Hour) } // Everything is past the grace period
+	report, err := rec.Run(context.Background())
+	if err != nil {
+		f.t.Fatalf("Run failed: %v", err)
+	}
+	return report
+}
+
+func codes(r *Report) map[Code]int {
+	out := make(map[Code]int)
+	for _, f := range r.Findings {
+		out[f.Code]++
+	}
+	return out
+}
+
+func TestReconcile_Clean(t *testing.T) {
+	f := newFixture(t)
+	f.approved("inst-1")
+
+	report := f.run(nil)
+	if !report.Clean() {
+		t.Fatalf("Expected no findings, got %+v", report.Findings)
+	}
+	if report.Instances != 1 || report.Artifacts != 2 {
+		t.Errorf("Expected 1 instance and 2 artifacts, got %d and %d", report.Instances, report.Artifacts)
+	}
+}
+
+func TestReconcile_Classification(t *testing.T) {
+	f := newFixture(t)
+
+	// Artifact written, DB update failed: the chain runs ahead of the database.
+	ahead := f.approved("inst-ahead")
+	resumed := models.NewCommitmentArtifact("inst-ahead", ahead[1].ArtifactID, "RESUMED", "pv", "ctx-r", "sys")
+	_ = resumed.CalculateHashAndSetID()
+	_ = f.store.Write(context.Background(), resumed)
+
+	// Genesis emitted, instance insert failed.
+	f.chain("inst-unknown", "RUNNING")
+
+	// The database claims an artifact that was never written.
+	f.db.instances = append(f.db.instances, &engine.Instance{ID: "inst-phantom", State: engine.StateRunning, LastArtifactHash: "deadbeef"})
+
+	// The database state disagrees with the head artifact.
+	drift := f.approved("inst-drift")
+	f.db.instances[len(f.db.instances)-1].State = engine.StateRejected
+	_ = drift
+
+	// A decision row without a decision artifact, and a transition event for a missing artifact.
+	f.approved("inst-extra")
+	f.db.decisions["inst-extra"] = append(f.db.decisions["inst-extra"], engine.DecisionRecord{ID: "dec-x", Type: engine.DecisionReject})
+	f.db.events["inst-extra"] = append(f.db.events["inst-extra"], engine.AuditEvent{
+		ID: "evt-3", EventType: "STATE_TRANSITIONED", Payload: map[string]interface{}{"artifact_id": "cafebabe"},
+	})
+
+	report := f.run(nil)
+	got := codes(report)
+	want := map[Code]int{
+		CodeOrphanArtifact:   1,
+		CodeOrphanInstance:   1,
+		CodeMissingHead:      1,
+		CodeStateMismatch:    1,
+		CodeDecisionMismatch: 1,
+		CodeAuditMismatch:    1, // 1 DECISION_RECORDED event vs 2 decision rows
+		CodeMissingArtifact:  1,
+	}
+	for code, n := range want {
+		if got[code] != n {
+			t.Errorf("%s: expected %d finding(s), got %d", code, n, got[code])
+		}
+	}
+	if len(report.Findings) != 7 {
+		t.Errorf("Expected 7 findings, got %+v", report.Findings)
+	}
+	if report.Summary[ClassOrphan] != 2 || report.Summary[ClassMissing] != 2 || report.Summary[ClassMismatch] != 3 {
+		t.Errorf("Unexpected summary %v", report.Summary)
+	}
+}
+
+func TestReconcile_GracePeriod(t *testing.T) {
+	f := newFixture(t)
+	f.chain("inst-in-flight", "RUNNING")
+
+	rec := New(f.db, f.store, nil) // Real clock: the artifact was emitted just now
+	report, err := rec.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Clean() {
+		t.Errorf("In-flight artifacts must not be reported, got %+v", report.Findings)
+	}
+}
+
+func TestReconcile_NotLogged(t *testing.T) {
+	f := newFixture(t)
+	arts := f.approved("inst-1")
+
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	log := artifactlog.New(bucket, "test")
+	_, _ = log.Append(context.Background(), arts[0].ArtifactID)
+
+	report := f.run(log)
+	if len(report.Findings) != 1 || report.Findings[0].Code != CodeNotLogged || report.Findings[0].ArtifactID != arts[1].ArtifactID {
+		t.Errorf("Expected one NOT_LOGGED finding for the decision artifact, got %+v", report.Findings)
+	}
+}
+
+func TestReport_Signature(t *testing.T) {
+	f := newFixture(t)
+	f.approved("inst-1")
+	f.chain("inst-unknown", "RUNNING")
+	report := f.run(nil)
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("ops-key", priv)
+	keys := signing.KeySet{"ops-key": pub}
+	if err := report.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyReport(report, keys); err != nil {
+		t.Fatalf("Expected a valid signature, got %v", err)
+	}
+
+	// Hiding a finding breaks the signature.
+	report.Findings = report.Findings[:0]
+	if err := VerifyReport(report, keys); !errors.Is(err, signing.ErrBadSignature) {
+		t.Errorf("Expected ErrBadSignature, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// ReportVersion is the version of the report format.
+const ReportVersion = "1"
+
+// ErrInvalidReport indicates a report whose signature does not verify.
+var ErrInvalidReport = errors.New("invalid reconciliation report")
+
+// Report is the result of one reconciliation run. It is signed like log checkpoints:
+// Ed25519 over the RFC 8785 form of every field except the signature.
+type Report struct {
+	ReportVersion string        `json:"report_version"`
+	GeneratedAt   string        `json:"generated_at"` // RFC 3339, UTC
+	Instances     int           `json:"instances"`    // Instances in the database
+	Artifacts     int           `json:"artifacts"`    // Artifacts in the store
+	Summary       map[Class]int `json:"summary"`
+	Findings      []Finding     `json:"findings"`
+	KeyID         string        `json:"key_id,omitempty"`
+	Signature     string        `json:"signature,omitempty"`
+}
+
+func newReport(now time.Time, instances, artifacts int) *Report {
+	return &Report{
+		ReportVersion: ReportVersion,
+		GeneratedAt:   now.UTC().Format(time.RFC3339),
+		Instances:     instances,
+		Artifacts:     artifacts,
+		Summary:       map[Class]int{ClassOrphan: 0, ClassMissing: 0, ClassMismatch: 0},
+		Findings:      []Finding{},
+	}
+}
+
+func (r *Report) add(findings ...Finding) {
+	for _, f := range findings {
+		r.Summary[f.Class]++
+		r.Findings = append(r.Findings, f)
+	}
+}
+
+func (r *Report) sort() {
+	sortFindings(r.Findings)
+}
+
+// Clean reports whether the run found no divergence.
+func (r *Report) Clean() bool {
+	return len(r.Findings) == 0
+}
+
+// SignedPayload returns the canonical bytes the signature covers.
+func (r *Report) SignedPayload() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = ""
+	return jcs.Marshal(unsigned)
+}
+
+// Sign sets KeyID and Signature.
+func (r *Report) Sign(signer *signing.Signer) error {
+	r.KeyID = signer.KeyID()
+	payload, err := r.SignedPayload()
+	if err != nil {
+		return fmt.Errorf("failed to sign report: %w", err)
+	}
+	r.Signature = signer.SignPayload(signing.ContextReconcileReport, payload)
+	return nil
+}
+
+// VerifyReport checks the report's signature against a trusted key set.
+func VerifyReport(r *Report, keys signing.KeySet) error {
+	payload, err := r.SignedPayload()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReport, err)
+	}
+	if err := keys.VerifyPayload(r.KeyID, signing.ContextReconcileReport, payload, r.Signature); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidReport, err)
+	}
+	return nil
+}
+
+var (
+	findingsGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gantral_reconcile_findings",
+		Help: "Divergences between the database and the artifact store found by the last reconciliation, by class",
+	}, []string{"class"})
+	codesGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gantral_reconcile_findings_by_code",
+		Help: "Divergences found by the last reconciliation, by class and reason code",
+	}, []string{"class", "code"})
+	instancesGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gantral_reconcile_instances",
+		Help: "Instances checked by the last reconciliation",
+	})
+	artifactsGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gantral_reconcile_artifacts",
+		Help: "Artifacts checked by the last reconciliation",
+	})
+	lastRunGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gantral_reconcile_last_success_timestamp_seconds",
+		Help: "Unix time of the last completed reconciliation",
+	})
+)
+
+// RecordMetrics publishes a completed report as Prometheus gauges.
+// Every class is always exported so that alerts can match on zero.
+func RecordMetrics(r *Report) {
+	for class, n := range r.Summary {
+		findingsGauge.WithLabelValues(string(class)).Set(float64(n))
+	}
+	codesGauge.Reset()
+	for _, f := range r.Findings {
+		codesGauge.WithLabelValues(string(f.Class), string(f.Code)).Inc()
+	}
+	instancesGauge.Set(float64(r.Instances))
+	artifactsGauge.Set(float64(r.Artifacts))
+	lastRunGauge.SetToCurrentTime()
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 
 	"github.com/Rainminds/gantral/internal/artifact"
 	"github.com/Rainminds/gantral/pkg/models"
@@ -27,15 +26,16 @@ func NewReplayGuard(store artifact.Store) *ReplayGuard {
 }
 
 // ValidateReplay checks a "claimed" artifact from the workflow history against the store.
-// In a real interceptor, we would extract the ArtifactID from the activity result or signal payload.
 //
 // Parameters:
-//   - claimedArtifact: The artifact struct (or ID) observed in the workflow history re-execution.
+//   - claimedArtifact: The artifact observed in the workflow history re-execution
+//     (the result of an evidence-emitting activity).
 //
 // Behavior:
-//   - Fetches the authoritative artifact from the Store using claimedArtifact.ID.
-//   - Compares critical fields (State, ContextHash, ActorID).
-//   - Returns ErrReplayTampered if missing or mismatched.
+//   - Recomputes the claimed artifact's hash (history integrity).
+//   - Fetches the authoritative artifact from the Store using claimedArtifact.ArtifactID.
+//   - Compares every canonical field (CanonicalFields).
+//   - Returns a *Violation (wrapping ErrReplayTampered) if missing or mismatched.
 func (g *ReplayGuard) ValidateReplay(ctx context.Context, claimedArtifact *models.CommitmentArtifact) error {
 	if claimedArtifact == nil {
 		return nil // No artifact claimed, nothing to validate (e.g. non-authority event)
@@ -49,32 +49,46 @@ func (g *ReplayGuard) ValidateReplay(ctx context.Context, claimedArtifact *model
 		return fmt.Errorf("failed to calculate hash: %w", err)
 	}
 	if check.ArtifactID != claimedArtifact.ArtifactID {
-		slog.Error("SECURITY ALERT: Replay history tampered (hash mismatch)",
-			"claimed_id", claimedArtifact.ArtifactID,
-			"calculated_id", check.ArtifactID)
-		return fmt.Errorf("%w: history integrity compromised", ErrReplayTampered)
+		v := &Violation{
+			Kind:       ViolationIntegrity,
+			InstanceID: claimedArtifact.InstanceID,
+			ArtifactID: claimedArtifact.ArtifactID,
+			Detail:     "history integrity compromised",
+			Mismatches: []FieldMismatch{{Field: "artifact_id", Claimed: claimedArtifact.ArtifactID, Stored: check.ArtifactID}},
+		}
+		v.log()
+		return v
 	}
 
 	// 2. Fetch Authoritative Artifact (Existence Proof)
 	authoritative, err := g.store.Get(ctx, claimedArtifact.ArtifactID)
 	if err != nil {
 		if errors.Is(err, artifact.ErrArtifactNotFound) {
-			slog.Error("SECURITY ALERT: Replay claimed artifact that does not exist",
-				"claimed_id", claimedArtifact.ArtifactID)
-			return fmt.Errorf("%w: artifact %s missing from store", ErrReplayTampered, claimedArtifact.ArtifactID)
+			v := &Violation{
+				Kind:       ViolationMissing,
+				InstanceID: claimedArtifact.InstanceID,
+				ArtifactID: claimedArtifact.ArtifactID,
+				Detail:     fmt.Sprintf("artifact %s missing from store", claimedArtifact.ArtifactID),
+			}
+			v.log()
+			return v
 		}
 		return fmt.Errorf("failed to fetch artifact during replay: %w", err)
 	}
 
-	// 3. Consistency Check (State Sanity)
-	// Even if ID matches (Integrity), ensure the specific fields match
+	// 3. Consistency Check (Every Canonical Field)
+	// Even if ID matches (Integrity), ensure every field matches
 	// (Defense against theoretical hash collisions or store inconsistencies).
-
-	if authoritative.AuthorityState != claimedArtifact.AuthorityState {
-		slog.Error("SECURITY ALERT: Replay claimed different state",
-			"claimed_state", claimedArtifact.AuthorityState,
-			"stored_state", authoritative.AuthorityState)
-		return fmt.Errorf("%w: state mismatch", ErrReplayTampered)
+	if mismatches := CompareArtifacts(claimedArtifact, authoritative); len(mismatches) > 0 {
+		v := &Violation{
+			Kind:       ViolationFieldMismatch,
+			InstanceID: claimedArtifact.InstanceID,
+			ArtifactID: claimedArtifact.ArtifactID,
+			Detail:     "history and store disagree",
+			Mismatches: mismatches,
+		}
+		v.log()
+		return v
 	}
 
 	return nil
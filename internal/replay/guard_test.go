@@ -2,9 +2,12 @@ package replay_test
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/internal/artifact"
 	"github.com/Rainminds/gantral/internal/replay"
 	"github.com/Rainminds/gantral/internal/storage/local"
 	"github.com/Rainminds/gantral/pkg/models"
@@ -66,3 +69,61 @@ func Test_Replay_Gaslight(t *testing.T) {
 		t.Errorf("Expected 'history integrity compromised' error, got: %v", err)
 	}
 }
+
+// divergentStore serves a different artifact than the one written under an ID,
+// as a corrupted or substituted store would.
+type divergentStore struct {
+	artifact.Store
+	served *models.CommitmentArtifact
+}
+
+func (s divergentStore) Get(ctx context.Context, id string) (*models.CommitmentArtifact, error) {
+	return s.served, nil
+}
+
+func Test_Replay_FieldMismatch_IsStructured(t *testing.T) {
+	claimed := models.NewCommitmentArtifact("inst-1", "prev-a", "APPROVED", "v1", "ctx-hash", "admin")
+	if err := claimed.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	served := *claimed
+	served.PrevArtifactHash = "prev-b"
+	served.HumanActorID = "other-admin"
+
+	err := replay.NewReplayGuard(divergentStore{served: &served}).ValidateReplay(context.Background(), claimed)
+
+	var v *replay.Violation
+	if !errors.As(err, &v) || !errors.Is(err, replay.ErrReplayTampered) {
+		t.Fatalf("Expected a *Violation wrapping ErrReplayTampered, got %v", err)
+	}
+	if v.Kind != replay.ViolationFieldMismatch || v.ArtifactID != claimed.ArtifactID {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+	fields := map[string]bool{}
+	for _, m := range v.Mismatches {
+		fields[m.Field] = true
+	}
+	if len(v.Mismatches) != 2 || !fields["prev_artifact_hash"] || !fields["human_actor_id"] {
+		t.Errorf("Expected prev_artifact_hash and human_actor_id mismatches, got %+v", v.Mismatches)
+	}
+}
+
+func Test_ValidateDecision(t *testing.T) {
+	art := models.NewCommitmentArtifact("inst-1", "prev", "APPROVED", "v1", "ctx-hash", "alice")
+	_ = art.CalculateHashAndSetID()
+	claim := replay.DecisionClaim{InstanceID: "inst-1", DecisionType: engine.DecisionApprove, ActorID: "alice", ContextHash: "ctx-hash"}
+
+	if err := replay.ValidateDecision("HumanDecision", claim, art); err != nil {
+		t.Errorf("Bound decision rejected: %v", err)
+	}
+
+	// A history whose signal was rewritten to a rejection by someone else.
+	tampered := claim
+	tampered.DecisionType = engine.DecisionReject
+	tampered.ActorID = "mallory"
+	err := replay.ValidateDecision("HumanDecision", tampered, art)
+	var v *replay.Violation
+	if !errors.As(err, &v) || v.Kind != replay.ViolationSignalMismatch || v.Signal != "HumanDecision" || len(v.Mismatches) != 2 {
+		t.Errorf("Expected a SIGNAL_MISMATCH on authority_state and human_actor_id, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package replay
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/pkg/models"
+)
+
+// ViolationKind classifies why replayed history was rejected.
+type ViolationKind string
+
+const (
+	// ViolationIntegrity: an artifact in history does not hash to its own ID.
+	ViolationIntegrity ViolationKind = "HISTORY_INTEGRITY"
+	// ViolationMissing: history claims an artifact the store does not hold.
+	ViolationMissing ViolationKind = "ARTIFACT_MISSING"
+	// ViolationFieldMismatch: history and store hold different content for one artifact ID.
+	ViolationFieldMismatch ViolationKind = "FIELD_MISMATCH"
+	// ViolationSignalMismatch: a decision is not the one carried by the signal, or the
+	// artifact it produced does not record that decision.
+	ViolationSignalMismatch ViolationKind = "SIGNAL_MISMATCH"
+)
+
+// FieldMismatch is one canonical field on which the claim and the evidence disagree.
+type FieldMismatch struct {
+	Field   string `json:"field"`
+	Claimed string `json:"claimed"`
+	Stored  string `json:"stored"`
+}
+
+// Violation is a structured replay guard failure. It wraps ErrReplayTampered.
+type Violation struct {
+	Kind       ViolationKind   `json:"kind"`
+	InstanceID string          `json:"instance_id"`
+	ArtifactID string          `json:"artifact_id,omitempty"`
+	Signal     string          `json:"signal,omitempty"` // Signal name, for SIGNAL_MISMATCH
+	Detail     string          `json:"detail"`
+	Mismatches []FieldMismatch `json:"mismatches,omitempty"`
+}
+
+func (v *Violation) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s: %s", ErrReplayTampered, v.Kind, v.Detail)
+	for _, m := range v.Mismatches {
+		fmt.Fprintf(&b, "; %s claimed=%q stored=%q", m.Field, m.Claimed, m.Stored)
+	}
+	return b.String()
+}
+
+// Unwrap lets callers match any violation with errors.Is(err, ErrReplayTampered).
+func (v *Violation) Unwrap() error {
+	return ErrReplayTampered
+}
+
+func (v *Violation) log() {
+	fields := make([]string, len(v.Mismatches))
+	for i, m := range v.Mismatches {
+		fields[i] = m.Field
+	}
+	slog.Error("SECURITY ALERT: Replay guard violation",
+		"kind", v.Kind,
+		"instance_id", v.InstanceID,
+		"artifact_id", v.ArtifactID,
+		"signal", v.Signal,
+		"detail", v.Detail,
+		"fields", fields)
+}
+
+// CanonicalFields lists the hashed fields of an artifact by their JSON names.
+func CanonicalFields(a *models.CommitmentArtifact) [][2]string {
+	return [][2]string{
+		{"artifact_version", a.ArtifactVersion},
+		{"artifact_id", a.ArtifactID},
+		{"instance_id", a.InstanceID},
+		{"prev_artifact_hash", a.PrevArtifactHash},
+		{"authority_state", a.AuthorityState},
+		{"policy_version_id", a.PolicyVersionID},
+		{"context_hash", a.ContextHash},
+		{"human_actor_id", a.HumanActorID},
+		{"timestamp", a.Timestamp},
+		{"policy_input_hash", a.PolicyInputHash},
+		{"policy_decision_hash", a.PolicyDecisionHash},
+	}
+}
+
+// CompareArtifacts returns every canonical field on which claimed and stored differ.
+func CompareArtifacts(claimed, stored *models.CommitmentArtifact) []FieldMismatch {
+	c, s := CanonicalFields(claimed), CanonicalFields(stored)
+	var mismatches []FieldMismatch
+	for i := range c {
+		if c[i][1] != s[i][1] {
+			mismatches = append(mismatches, FieldMismatch{Field: c[i][0], Claimed: c[i][1], Stored: s[i][1]})
+		}
+	}
+	return mismatches
+}
+
+// DecisionClaim is what a decision asked for: the HumanDecision signal payload, the
+// SYSTEM rejection the workflow builds on approval timeout, one vote of a multi-party
+// decision, or the aggregate outcome of the votes.
+type DecisionClaim struct {
+	InstanceID     string
+	DecisionType   engine.DecisionType
+	ActorID        string
+	ContextHash    string // Evidence hash, or the hash of the context snapshot
+	AuthorityState string // Expected artifact state; empty derives it from DecisionType
+}
+
+// ValidateDecision binds a decision to the artifact it produced: the artifact must record
+// the decision's instance, resulting state, actor and context. It returns a *Violation
+// of kind SIGNAL_MISMATCH listing every disagreeing field.
+func ValidateDecision(signal string, claim DecisionClaim, art *models.CommitmentArtifact) error {
+	expectedState := claim.AuthorityState
+	if expectedState == "" {
+		if next, err := engine.CalculateNextState(claim.DecisionType); err == nil {
+			expectedState = string(next)
+		}
+	}
+
+	var mismatches []FieldMismatch
+	for _, f := range []FieldMismatch{
+		{Field: "instance_id", Claimed: claim.InstanceID, Stored: art.InstanceID},
+		{Field: "authority_state", Claimed: expectedState, Stored: art.AuthorityState},
+		{Field: "human_actor_id", Claimed: claim.ActorID, Stored: art.HumanActorID},
+		{Field: "context_hash", Claimed: claim.ContextHash, Stored: art.ContextHash},
+	} {
+		if f.Claimed != f.Stored {
+			mismatches = append(mismatches, f)
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	v := &Violation{
+		Kind:       ViolationSignalMismatch,
+		InstanceID: claim.InstanceID,
+		ArtifactID: art.ArtifactID,
+		Signal:     signal,
+		Detail:     "decision is not bound to the artifact it produced",
+		Mismatches: mismatches,
+	}
+	v.log()
+	return v
+}
@@ -0,0 +1,74 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"gocloud.dev/blob"
+)
+
+// evidencePrefix is where the worker's evidence interceptor writes
+// evidence/<evidence_id>.json (see cmd/worker/evidence).
+const evidencePrefix = "evidence/"
+
+// EvidenceStore reads ExecutionEvidence from the evidence bucket.
+type EvidenceStore struct {
+	bucket *blob.Bucket
+}
+
+// NewEvidenceStore wraps an open bucket. The caller keeps ownership of the bucket.
+func NewEvidenceStore(bucket *blob.Bucket) *EvidenceStore {
+	return &EvidenceStore{bucket: bucket}
+}
+
+// OpenEvidenceStore opens the evidence bucket at url (EVIDENCE_BUCKET_URL).
+func OpenEvidenceStore(ctx context.Context, url string) (*EvidenceStore, error) {
+	bucket, err := blob.OpenBucket(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open evidence bucket: %w", err)
+	}
+	return &EvidenceStore{bucket: bucket}, nil
+}
+
+// Close releases the underlying bucket.
+func (s *EvidenceStore) Close() error {
+	return s.bucket.Close()
+}
+
+// ListByInstance returns the evidence captured for an instance, ordered by evidence ID.
+//
+// Evidence is keyed by content hash only, so this reads every evidence object in the
+// bucket. It is meant for audit exports, not for request-path lookups.
+func (s *EvidenceStore) ListByInstance(ctx context.Context, instanceID string) ([]*models.ExecutionEvidence, error) {
+	evidence := []*models.ExecutionEvidence{}
+	it := s.bucket.List(&blob.ListOptions{Prefix: evidencePrefix})
+	for {
+		obj, err := it.Next(ctx)
+		if err == io.EOF {
+			return evidence, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list evidence: %w", err)
+		}
+
+		id, ok := strings.CutSuffix(strings.TrimPrefix(obj.Key, evidencePrefix), ".json")
+		if !ok || !safeIDRegex.MatchString(id) {
+			continue
+		}
+		data, err := s.bucket.ReadAll(ctx, obj.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read evidence %s: %w", id, err)
+		}
+		var ev models.ExecutionEvidence
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return nil, fmt.Errorf("failed to deserialize evidence %s: %w", id, err)
+		}
+		if ev.InstanceID == instanceID {
+			evidence = append(evidence, &ev)
+		}
+	}
+}
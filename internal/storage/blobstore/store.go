@@ -0,0 +1,265 @@
+// Package blobstore implements artifact.Store on top of gocloud.dev/blob, so that
+// commitment artifacts can live in S3-compatible object storage (or GCS, Azure,
+// the local filesystem and memory for development and tests).
+package blobstore
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+)
+
+// safeIDRegex ensures artifact IDs contain only safe characters to prevent key injection.
+var safeIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// Key layout inside the bucket (evidence uses "evidence/"):
+//
+//	artifacts/<artifact_id>.json                              the immutable artifact
+//	index/<instance_id>/<unix_nanos>-<artifact_id>            empty marker, lists the instance in write order
+//
+// The marker is written before the artifact, so every stored artifact is indexed. A marker
+// whose artifact was never written (the write failed) is skipped, as is a repeated marker
+// left by retrying the write.
+const (
+	keyPrefix   = "artifacts/"
+	indexPrefix = "index/"
+)
+
+// ErrRetentionUnsupported indicates that object-lock retention was requested on a
+// bucket whose driver cannot apply it. Writes fail rather than silently skip the lock.
+var ErrRetentionUnsupported = errors.New("object lock retention not supported by bucket driver")
+
+// LockMode is the S3 Object Lock retention mode.
+type LockMode string
+
+const (
+	// LockModeCompliance: nobody, including the root account, can shorten or remove retention.
+	LockModeCompliance LockMode = "COMPLIANCE"
+	// LockModeGovernance: users with s3:BypassGovernanceRetention can remove retention.
+	LockModeGovernance LockMode = "GOVERNANCE"
+)
+
+// Options configures optional object-lock retention.
+// The zero value writes with conditional create only (no retention).
+type Options struct {
+	// Retention is how long each artifact is locked after it is written. Zero disables object lock.
+	Retention time.Duration
+	// Mode is the object-lock mode. Defaults to LockModeCompliance when Retention is set.
+	Mode LockMode
+}
+
+// Store implements artifact.Store over a blob.Bucket.
+//
+// Write-once is enforced by the bucket itself through a conditional create
+// (If-None-Match: * on S3), not by a check-then-write, so two concurrent writers
+// cannot both succeed. With Options.Retention set, every object is additionally
+// placed under object lock so that it cannot be deleted or overwritten in place
+// (the bucket must have Object Lock enabled).
+type Store struct {
+	bucket *blob.Bucket
+	opts   Options
+	now    func() time.Time
+}
+
+// NewStore wraps an open bucket. The caller keeps ownership of the bucket.
+func NewStore(bucket *blob.Bucket, opts Options) (*Store, error) {
+	if opts.Retention < 0 {
+		return nil, fmt.Errorf("invalid retention: %s", opts.Retention)
+	}
+	if opts.Retention > 0 && opts.Mode == "" {
+		opts.Mode = LockModeCompliance
+	}
+	switch opts.Mode {
+	case "", LockModeCompliance, LockModeGovernance:
+	default:
+		return nil, fmt.Errorf("invalid object lock mode: %q", opts.Mode)
+	}
+	return &Store{bucket: bucket, opts: opts, now: time.Now}, nil
+}
+
+// OpenStore opens the bucket at url (e.g. "s3://bucket?region=eu-west-1",
+// "file:///var/lib/gantral/artifacts", "mem://") and wraps it.
+// The driver for the URL scheme must be linked in by the caller.
+func OpenStore(ctx context.Context, url string, opts Options) (*Store, error) {
+	bucket, err := blob.OpenBucket(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open artifact bucket: %w", err)
+	}
+	s, err := NewStore(bucket, opts)
+	if err != nil {
+		_ = bucket.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the underlying bucket.
+func (s *Store) Close() error {
+	return s.bucket.Close()
+}
+
+// Write persists the artifact exactly once.
+// A second write of the same ID returns artifact.ErrArtifactAlreadyExists and
+// leaves the stored object untouched.
+func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error {
+	if !safeIDRegex.MatchString(art.ArtifactID) {
+		return fmt.Errorf("%w: artifact ID %q", artifact.ErrInvalidID, art.ArtifactID)
+	}
+	if !safeIDRegex.MatchString(art.InstanceID) {
+		return fmt.Errorf("%w: instance ID %q", artifact.ErrInvalidID, art.InstanceID)
+	}
+
+	data, err := json.Marshal(art)
+	if err != nil {
+		return fmt.Errorf("serialization failed: %w", err)
+	}
+
+	// Content-MD5 lets the backend reject corrupted uploads; S3 also requires it for object-lock writes.
+	sum := md5.Sum(data)
+	wopts := &blob.WriterOptions{
+		ContentType: "application/json",
+		ContentMD5:  sum[:],
+		IfNotExist:  true,
+		Metadata: map[string]string{
+			"instance_id":     art.InstanceID,
+			"authority_state": art.AuthorityState,
+		},
+	}
+	if s.opts.Retention > 0 {
+		wopts.BeforeWrite = s.applyRetention
+	}
+
+	// The index marker is derived data and carries no retention. It goes first: an artifact
+	// written without its marker would be invisible to ListByInstance (and the chain-head guard).
+	marker := fmt.Sprintf("%s%s/%020d-%s", indexPrefix, art.InstanceID, s.now().UnixNano(), art.ArtifactID)
+	if err := s.bucket.WriteAll(ctx, marker, nil, &blob.WriterOptions{IfNotExist: true}); err != nil {
+		return fmt.Errorf("failed to index artifact %s: %w", art.ArtifactID, err)
+	}
+
+	// WriteAll only commits the object if every byte was written (no partial writes).
+	if err := s.bucket.WriteAll(ctx, key(art.ArtifactID), data, wopts); err != nil {
+		if errors.Is(err, ErrRetentionUnsupported) {
+			return err
+		}
+		if gcerrors.Code(err) == gcerrors.FailedPrecondition {
+			return artifact.ErrArtifactAlreadyExists
+		}
+		return fmt.Errorf("failed to write artifact object: %w", err)
+	}
+	return nil
+}
+
+// applyRetention sets object-lock retention on the driver's put request.
+func (s *Store) applyRetention(asFunc func(any) bool) error {
+	var req *s3.PutObjectInput
+	if !asFunc(&req) {
+		return ErrRetentionUnsupported
+	}
+	until := s.now().Add(s.opts.Retention).UTC()
+	req.ObjectLockMode = s3types.ObjectLockMode(s.opts.Mode)
+	req.ObjectLockRetainUntilDate = &until
+	return nil
+}
+
+// Get retrieves an artifact by ID.
+func (s *Store) Get(ctx context.Context, artifactID string) (*models.CommitmentArtifact, error) {
+	if !safeIDRegex.MatchString(artifactID) {
+		return nil, fmt.Errorf("%w: artifact ID %q", artifact.ErrInvalidID, artifactID)
+	}
+
+	data, err := s.bucket.ReadAll(ctx, key(artifactID))
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, artifact.ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("failed to read artifact object: %w", err)
+	}
+
+	var art models.CommitmentArtifact
+	if err := json.Unmarshal(data, &art); err != nil {
+		return nil, fmt.Errorf("failed to deserialize artifact: %w", err)
+	}
+	return &art, nil
+}
+
+// ListByInstance returns the instance's artifacts in write order, using the index markers.
+func (s *Store) ListByInstance(ctx context.Context, instanceID string) ([]*models.CommitmentArtifact, error) {
+	if !safeIDRegex.MatchString(instanceID) {
+		return nil, fmt.Errorf("%w: instance ID %q", artifact.ErrInvalidID, instanceID)
+	}
+
+	prefix := indexPrefix + instanceID + "/"
+	arts := []*models.CommitmentArtifact{}
+	seen := map[string]bool{}
+	it := s.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := it.Next(ctx)
+		if err == io.EOF {
+			return arts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list index: %w", err)
+		}
+
+		_, id, ok := strings.Cut(strings.TrimPrefix(obj.Key, prefix), "-")
+		if !ok || seen[id] {
+			continue // The first marker of a retried write is its write order
+		}
+		art, err := s.Get(ctx, id)
+		if errors.Is(err, artifact.ErrArtifactNotFound) {
+			continue // The write failed after indexing; nothing was stored
+		}
+		if err != nil {
+			return nil, fmt.Errorf("index entry %s: %w", obj.Key, err)
+		}
+		// The index is not authoritative: never let it attribute an artifact to another instance.
+		if art.InstanceID != instanceID {
+			return nil, fmt.Errorf("index entry %s belongs to instance %s, not %s", obj.Key, art.InstanceID, instanceID)
+		}
+		seen[id] = true
+		arts = append(arts, art)
+	}
+}
+
+// Walk streams every artifact object in the bucket, ordered by key.
+func (s *Store) Walk(ctx context.Context, fn artifact.WalkFunc) error {
+	it := s.bucket.List(&blob.ListOptions{Prefix: keyPrefix})
+	for {
+		obj, err := it.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list artifacts: %w", err)
+		}
+
+		id, ok := strings.CutSuffix(strings.TrimPrefix(obj.Key, keyPrefix), ".json")
+		if !ok || !safeIDRegex.MatchString(id) {
+			continue
+		}
+		art, err := s.Get(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := fn(art); err != nil {
+			return err
+		}
+	}
+}
+
+func key(artifactID string) string {
+	return keyPrefix + artifactID + ".json"
+}
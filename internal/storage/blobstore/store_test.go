@@ -0,0 +1,378 @@
+package blobstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"gocloud.dev/blob"
+	_ "gocloud.dev/blob/fileblob"
+	"gocloud.dev/blob/memblob"
+)
+
+func newArtifact(t *testing.T, ctxHash string) *models.CommitmentArtifact {
+	t.Helper()
+	art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "v1", ctxHash, "user")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("Failed to seal artifact: %v", err)
+	}
+	return art
+}
+
+func newMemStore(t *testing.T, opts Options) (*Store, *blob.Bucket) {
+	t.Helper()
+	bucket := memblob.OpenBucket(nil)
+	t.Cleanup(func() { _ = bucket.Close() })
+	store, err := NewStore(bucket, opts)
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	return store, bucket
+}
+
+func TestStore_RoundTrip(t *testing.T) {
+	store, bucket := newMemStore(t, Options{})
+	ctx := context.Background()
+	art := newArtifact(t, "ctx-A")
+
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	got, err := store.Get(ctx, art.ArtifactID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if *got != *art {
+		t.Errorf("Round trip mismatch:\nwant %+v\ngot  %+v", art, got)
+	}
+
+	attrs, err := bucket.Attributes(ctx, "artifacts/"+art.ArtifactID+".json")
+	if err != nil {
+		t.Fatalf("Object not stored under the artifacts/ prefix: %v", err)
+	}
+	if attrs.Metadata["instance_id"] != "inst-1" {
+		t.Errorf("Expected instance_id metadata, got %v", attrs.Metadata)
+	}
+}
+
+func TestStore_WriteOnce(t *testing.T) {
+	store, _ := newMemStore(t, Options{})
+	ctx := context.Background()
+
+	artA := newArtifact(t, "ctx-A")
+	if err := store.Write(ctx, artA); err != nil {
+		t.Fatalf("Failed first write: %v", err)
+	}
+
+	// Same ID, different content: must be refused and must not replace the original.
+	artB := newArtifact(t, "ctx-B")
+	artB.ArtifactID = artA.ArtifactID
+	if err := store.Write(ctx, artB); !errors.Is(err, artifact.ErrArtifactAlreadyExists) {
+		t.Fatalf("Expected ErrArtifactAlreadyExists, got %v", err)
+	}
+
+	got, err := store.Get(ctx, artA.ArtifactID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.ContextHash != "ctx-A" {
+		t.Errorf("Original artifact was overwritten: context_hash=%s", got.ContextHash)
+	}
+}
+
+func TestStore_ConcurrentWritesSingleWinner(t *testing.T) {
+	store, _ := newMemStore(t, Options{})
+	ctx := context.Background()
+	art := newArtifact(t, "ctx-A")
+
+	const writers = 16
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a := *art
+			errs <- store.Write(ctx, &a)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	wins := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			wins++
+		case !errors.Is(err, artifact.ErrArtifactAlreadyExists):
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if wins != 1 {
+		t.Errorf("Expected exactly one successful write, got %d", wins)
+	}
+}
+
+func TestStore_GetErrors(t *testing.T) {
+	store, _ := newMemStore(t, Options{})
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, "missing"); !errors.Is(err, artifact.ErrArtifactNotFound) {
+		t.Errorf("Expected ErrArtifactNotFound, got %v", err)
+	}
+	if _, err := store.Get(ctx, "../etc/passwd"); err == nil {
+		t.Error("Expected invalid ID to be rejected")
+	}
+
+	art := newArtifact(t, "ctx-A")
+	art.ArtifactID = "../escape"
+	if err := store.Write(ctx, art); err == nil {
+		t.Error("Expected invalid ID to be rejected on write")
+	}
+}
+
+func TestStore_FileBlob(t *testing.T) {
+	ctx := context.Background()
+	store, err := OpenStore(ctx, "file://"+t.TempDir(), Options{})
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	defer store.Close()
+
+	art := newArtifact(t, "ctx-A")
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := store.Write(ctx, art); !errors.Is(err, artifact.ErrArtifactAlreadyExists) {
+		t.Errorf("Expected ErrArtifactAlreadyExists, got %v", err)
+	}
+	if _, err := store.Get(ctx, art.ArtifactID); err != nil {
+		t.Errorf("Get failed: %v", err)
+	}
+}
+
+func TestStore_IndexFailureLeavesNothingUnindexed(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := OpenStore(ctx, "file://"+dir, Options{})
+	if err != nil {
+		t.Fatalf("OpenStore failed: %v", err)
+	}
+	defer store.Close()
+
+	// A file where the instance's index directory belongs makes every marker write fail.
+	if err := os.MkdirAll(filepath.Join(dir, "index"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	blocker := filepath.Join(dir, "index", "inst-1")
+	if err := os.WriteFile(blocker, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	art := newArtifact(t, "ctx-A")
+	if err := store.Write(ctx, art); err == nil {
+		t.Fatal("Expected Write to fail when the index marker cannot be written")
+	}
+	if _, err := store.Get(ctx, art.ArtifactID); !errors.Is(err, artifact.ErrArtifactNotFound) {
+		t.Fatalf("An unindexed artifact must not be stored, got %v", err)
+	}
+
+	// The retry succeeds and the artifact is listed.
+	if err := os.Remove(blocker); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	arts, err := store.ListByInstance(ctx, "inst-1")
+	if err != nil {
+		t.Fatalf("ListByInstance failed: %v", err)
+	}
+	if len(arts) != 1 || arts[0].ArtifactID != art.ArtifactID {
+		t.Errorf("Expected the retried artifact to be listed, got %d artifacts", len(arts))
+	}
+}
+
+func TestStore_ListByInstanceSkipsFailedAndRepeatedWrites(t *testing.T) {
+	store, bucket := newMemStore(t, Options{})
+	ctx := context.Background()
+	tick := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { tick = tick.Add(time.Millisecond); return tick }
+
+	// A write that indexed its artifact, then failed to store it.
+	if err := bucket.WriteAll(ctx, "index/inst-1/00000000000000000001-missing", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	art := newArtifact(t, "ctx-A")
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	// A retried write of the same artifact leaves a second marker.
+	if err := store.Write(ctx, art); !errors.Is(err, artifact.ErrArtifactAlreadyExists) {
+		t.Fatalf("Expected ErrArtifactAlreadyExists, got %v", err)
+	}
+
+	arts, err := store.ListByInstance(ctx, "inst-1")
+	if err != nil {
+		t.Fatalf("ListByInstance failed: %v", err)
+	}
+	if len(arts) != 1 || arts[0].ArtifactID != art.ArtifactID {
+		t.Errorf("Expected exactly the stored artifact, got %d artifacts", len(arts))
+	}
+}
+
+func TestStore_RetentionFailsClosedWithoutObjectLock(t *testing.T) {
+	// memblob has no object lock; the write must fail rather than store an unlocked object.
+	store, bucket := newMemStore(t, Options{Retention: time.Hour})
+	ctx := context.Background()
+	art := newArtifact(t, "ctx-A")
+
+	if err := store.Write(ctx, art); !errors.Is(err, ErrRetentionUnsupported) {
+		t.Fatalf("Expected ErrRetentionUnsupported, got %v", err)
+	}
+	if exists, _ := bucket.Exists(ctx, "artifacts/"+art.ArtifactID+".json"); exists {
+		t.Error("Artifact was stored without retention")
+	}
+}
+
+func TestStore_ApplyRetention(t *testing.T) {
+	store, _ := newMemStore(t, Options{Retention: 24 * time.Hour})
+	fixed := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return fixed }
+
+	req := &s3.PutObjectInput{}
+	asFunc := func(i any) bool {
+		p, ok := i.(**s3.PutObjectInput)
+		if ok {
+			*p = req
+		}
+		return ok
+	}
+	if err := store.applyRetention(asFunc); err != nil {
+		t.Fatalf("applyRetention failed: %v", err)
+	}
+	if req.ObjectLockMode != s3types.ObjectLockModeCompliance {
+		t.Errorf("Expected COMPLIANCE mode by default, got %q", req.ObjectLockMode)
+	}
+	if req.ObjectLockRetainUntilDate == nil || !req.ObjectLockRetainUntilDate.Equal(fixed.Add(24*time.Hour)) {
+		t.Errorf("Unexpected retain-until date: %v", req.ObjectLockRetainUntilDate)
+	}
+}
+
+func TestNewStore_InvalidOptions(t *testing.T) {
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+
+	if _, err := NewStore(bucket, Options{Retention: -time.Second}); err == nil {
+		t.Error("Expected negative retention to be rejected")
+	}
+	if _, err := NewStore(bucket, Options{Retention: time.Hour, Mode: "FOREVER"}); err == nil {
+		t.Error("Expected unknown lock mode to be rejected")
+	}
+}
+
+func TestStore_ListByInstanceAndWalk(t *testing.T) {
+	store, _ := newMemStore(t, Options{})
+	ctx := context.Background()
+
+	// Strictly increasing clock so index markers sort in write order.
+	tick := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { tick = tick.Add(time.Millisecond); return tick }
+
+	var want []string
+	prev := models.GenesisHash
+	for _, state := range []string{"WAITING_FOR_HUMAN", "APPROVED", "RESUMED"} {
+		art := models.NewCommitmentArtifact("inst-a", prev, state, "v1", "ctx", "user")
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(ctx, art); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		want = append(want, art.ArtifactID)
+		prev = art.ArtifactID
+	}
+	other := newArtifact(t, "ctx-other")
+	other.InstanceID = "inst-b"
+	if err := other.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(ctx, other); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	arts, err := store.ListByInstance(ctx, "inst-a")
+	if err != nil {
+		t.Fatalf("ListByInstance failed: %v", err)
+	}
+	if len(arts) != len(want) {
+		t.Fatalf("Expected %d artifacts, got %d", len(want), len(arts))
+	}
+	for i, art := range arts {
+		if art.ArtifactID != want[i] {
+			t.Errorf("artifact[%d]: expected %s, got %s", i, want[i], art.ArtifactID)
+		}
+	}
+
+	if arts, err := store.ListByInstance(ctx, "inst-none"); err != nil || len(arts) != 0 {
+		t.Errorf("Expected empty list for unknown instance, got %d, err=%v", len(arts), err)
+	}
+	if _, err := store.ListByInstance(ctx, "a/b"); !errors.Is(err, artifact.ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+
+	seen := 0
+	if err := store.Walk(ctx, func(*models.CommitmentArtifact) error { seen++; return nil }); err != nil {
+		t.Fatalf("Walk failed: %v", err)
+	}
+	if seen != len(want)+1 {
+		t.Errorf("Walk saw %d artifacts, expected %d (index markers must not be walked)", seen, len(want)+1)
+	}
+}
+
+func TestEvidenceStore_ListByInstance(t *testing.T) {
+	bucket := memblob.OpenBucket(nil)
+	defer bucket.Close()
+	ctx := context.Background()
+
+	// Same layout as the worker's evidence interceptor.
+	put := func(instanceID, tool string) *models.ExecutionEvidence {
+		ev := models.NewExecutionEvidence(instanceID, tool, []byte(`{}`), []byte(`{}`))
+		if err := ev.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := json.Marshal(ev)
+		if err := bucket.WriteAll(ctx, "evidence/"+ev.EvidenceID+".json", data, nil); err != nil {
+			t.Fatal(err)
+		}
+		return ev
+	}
+	a1 := put("inst-a", "refund")
+	a2 := put("inst-a", "notify")
+	put("inst-b", "refund")
+	if err := bucket.WriteAll(ctx, "evidence/readme.txt", []byte("ignored"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := NewEvidenceStore(bucket).ListByInstance(ctx, "inst-a")
+	if err != nil {
+		t.Fatalf("ListByInstance failed: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 evidence objects, got %d", len(got))
+	}
+	ids := map[string]bool{got[0].EvidenceID: true, got[1].EvidenceID: true}
+	if !ids[a1.EvidenceID] || !ids[a2.EvidenceID] {
+		t.Errorf("Unexpected evidence: %v", ids)
+	}
+}
@@ -2,6 +2,7 @@ package local
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -144,3 +145,174 @@ func Test_Atomic_Failure(t *testing.T) {
 		t.Error("Atomicity Failure: Partial or empty file exists after failed write")
 	}
 }
+
+func Test_ListByInstance_WriteOrder(t *testing.T) {
+	store, tmpDir := setupTestStore(t)
+	ctx := context.Background()
+
+	// Interleave two instances; each index must only hold its own artifacts, in write order.
+	var want []string
+	prev := models.GenesisHash
+	for i, state := range []string{"WAITING_FOR_HUMAN", "APPROVED", "RESUMED"} {
+		art := models.NewCommitmentArtifact("inst-a", prev, state, "v1", "ctx", "user")
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(ctx, art); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+		other := models.NewCommitmentArtifact("inst-b", models.GenesisHash, state, "v1", "ctx", "user")
+		if err := other.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(ctx, other); err != nil {
+			t.Fatalf("write other %d: %v", i, err)
+		}
+		want = append(want, art.ArtifactID)
+		prev = art.ArtifactID
+	}
+
+	arts, err := store.ListByInstance(ctx, "inst-a")
+	if err != nil {
+		t.Fatalf("ListByInstance failed: %v", err)
+	}
+	if len(arts) != len(want) {
+		t.Fatalf("Expected %d artifacts, got %d", len(want), len(arts))
+	}
+	for i, art := range arts {
+		if art.ArtifactID != want[i] {
+			t.Errorf("artifact[%d]: expected %s, got %s", i, want[i], art.ArtifactID)
+		}
+	}
+
+	// A torn final line (crash mid-append) is not an entry.
+	f, err := os.OpenFile(filepath.Join(tmpDir, "index", "inst-a"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("deadbeef")
+	_ = f.Close()
+	if arts, err := store.ListByInstance(ctx, "inst-a"); err != nil || len(arts) != len(want) {
+		t.Errorf("Torn index line not ignored: %d artifacts, err=%v", len(arts), err)
+	}
+
+	// Unknown instance: empty, not an error.
+	if arts, err := store.ListByInstance(ctx, "inst-none"); err != nil || len(arts) != 0 {
+		t.Errorf("Expected empty list for unknown instance, got %d, err=%v", len(arts), err)
+	}
+	if _, err := store.ListByInstance(ctx, "../inst-a"); !errors.Is(err, artifact.ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+}
+
+func Test_ListByInstance_RejectsForeignIndexEntry(t *testing.T) {
+	store, tmpDir := setupTestStore(t)
+	ctx := context.Background()
+
+	art := models.NewCommitmentArtifact("inst-b", models.GenesisHash, "WAITING_FOR_HUMAN", "v1", "ctx", "user")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatal(err)
+	}
+
+	// A tampered index must not attribute inst-b's artifact to inst-a.
+	if err := os.WriteFile(filepath.Join(tmpDir, "index", "inst-a"), []byte(art.ArtifactID+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.ListByInstance(ctx, "inst-a"); err == nil {
+		t.Error("Expected foreign index entry to be rejected")
+	}
+}
+
+func Test_Index_Failure_Leaves_Nothing_Unindexed(t *testing.T) {
+	store, tmpDir := setupTestStore(t)
+	ctx := context.Background()
+
+	// A file where the index directory belongs makes indexing fail.
+	if err := os.WriteFile(filepath.Join(tmpDir, "index"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	art := models.NewCommitmentArtifact("inst-a", models.GenesisHash, "WAITING_FOR_HUMAN", "v1", "ctx", "user")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(ctx, art); err == nil {
+		t.Fatal("Expected the write to fail when the index cannot be written")
+	}
+	if _, err := store.Get(ctx, art.ArtifactID); !errors.Is(err, artifact.ErrArtifactNotFound) {
+		t.Errorf("Unindexed artifact was stored: %v", err)
+	}
+}
+
+func Test_ListByInstance_Skips_Failed_And_Repeated_Writes(t *testing.T) {
+	store, tmpDir := setupTestStore(t)
+	ctx := context.Background()
+
+	art := models.NewCommitmentArtifact("inst-a", models.GenesisHash, "WAITING_FOR_HUMAN", "v1", "ctx", "user")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Write(ctx, art); err != nil {
+		t.Fatal(err)
+	}
+
+	// Entries left by writes that failed after indexing: one never stored, one retried.
+	f, err := os.OpenFile(filepath.Join(tmpDir, "index", "inst-a"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("missing\n" + art.ArtifactID + "\n")
+	_ = f.Close()
+
+	arts, err := store.ListByInstance(ctx, "inst-a")
+	if err != nil {
+		t.Fatalf("ListByInstance failed: %v", err)
+	}
+	if len(arts) != 1 || arts[0].ArtifactID != art.ArtifactID {
+		t.Errorf("Expected only %s, got %d artifacts", art.ArtifactID, len(arts))
+	}
+}
+
+func Test_Walk(t *testing.T) {
+	store, tmpDir := setupTestStore(t)
+	ctx := context.Background()
+
+	ids := map[string]bool{}
+	for _, inst := range []string{"inst-a", "inst-b", "inst-c"} {
+		art := models.NewCommitmentArtifact(inst, models.GenesisHash, "WAITING_FOR_HUMAN", "v1", "ctx", "user")
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(ctx, art); err != nil {
+			t.Fatal(err)
+		}
+		ids[art.ArtifactID] = true
+	}
+	// Leftover temp files are not artifacts.
+	_ = os.WriteFile(filepath.Join(tmpDir, "artifact-123.tmp"), []byte("{"), 0644)
+
+	seen := 0
+	err := store.Walk(ctx, func(art *models.CommitmentArtifact) error {
+		if !ids[art.ArtifactID] {
+			t.Errorf("Unexpected artifact %s", art.ArtifactID)
+		}
+		seen++
+		return nil
+	})
+	if err != nil || seen != len(ids) {
+		t.Errorf("Expected %d artifacts, saw %d (err=%v)", len(ids), seen, err)
+	}
+
+	// Returning an error stops the walk.
+	stop := errors.New("stop")
+	calls := 0
+	err = store.Walk(ctx, func(*models.CommitmentArtifact) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("Expected walk to stop after first callback, calls=%d err=%v", calls, err)
+	}
+}
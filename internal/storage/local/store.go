@@ -3,10 +3,13 @@ package local
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/Rainminds/gantral/internal/artifact"
@@ -16,7 +19,19 @@ import (
 // safeIDRegex ensures artifact IDs contain only safe characters to prevent path traversal.
 var safeIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
 
+// indexDir holds one append-only file per instance listing its artifact IDs in write order.
+const indexDir = "index"
+
 // Store implements artifact.Store using the local filesystem.
+//
+// Layout:
+//
+//	<base>/<artifact_id>.json     one immutable file per artifact
+//	<base>/index/<instance_id>    artifact IDs of the instance, one per line, append-only
+//
+// The ID is indexed before the artifact is written, so every stored artifact is indexed.
+// An entry whose artifact was never written (the write failed) is skipped, as is an entry
+// repeated by retrying the write.
 type Store struct {
 	basePath string
 	mu       sync.RWMutex // Protects concurrent access if needed (though FS handles locking mostly)
@@ -34,15 +49,19 @@ func NewStore(basePath string) (*Store, error) {
 // Write persists the artifact atomically and consistently (WORM).
 // Steps:
 // 1. Check if target exists (Fail if yes).
-// 2. Write to temp file.
-// 3. Sync to disk.
-// 4. Atomic Rename.
+// 2. Append the ID to the instance index.
+// 3. Write to temp file.
+// 4. Sync to disk.
+// 5. Atomic Rename.
 func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if !safeIDRegex.MatchString(art.ArtifactID) {
-		return fmt.Errorf("invalid artifact ID format: %s", art.ArtifactID)
+		return fmt.Errorf("%w: artifact ID %q", artifact.ErrInvalidID, art.ArtifactID)
+	}
+	if !safeIDRegex.MatchString(art.InstanceID) {
+		return fmt.Errorf("%w: instance ID %q", artifact.ErrInvalidID, art.InstanceID)
 	}
 
 	targetPath := filepath.Join(s.basePath, art.ArtifactID+".json")
@@ -60,7 +79,13 @@ func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error
 		return fmt.Errorf("serialization failed: %w", err)
 	}
 
-	// 2. Write to Temp File (Atomicity)
+	// 2. Index. It goes first: an artifact written without its entry would be invisible
+	// to ListByInstance (and the chain-head guard).
+	if err := s.appendIndex(art.InstanceID, art.ArtifactID); err != nil {
+		return fmt.Errorf("failed to index artifact %s: %w", art.ArtifactID, err)
+	}
+
+	// 3. Write to Temp File (Atomicity)
 	// We create the temp file in the same directory to ensure atomic rename works (same partition).
 	tmpFile, err := os.CreateTemp(s.basePath, "artifact-*.tmp")
 	if err != nil {
@@ -80,7 +105,7 @@ func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error
 		return fmt.Errorf("failed to write to temp file: %w", err)
 	}
 
-	// 3. Fsync (Durability)
+	// 4. Fsync (Durability)
 	if err := tmpFile.Sync(); err != nil {
 		return fmt.Errorf("failed to sync temp file: %w", err)
 	}
@@ -90,7 +115,7 @@ func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error
 	}
 	tmpFile = nil // Prevent double close in defer
 
-	// 4. Atomic Rename
+	// 5. Atomic Rename
 	if err := os.Rename(tmpName, targetPath); err != nil {
 		return fmt.Errorf("failed to rename artifact file: %w", err)
 	}
@@ -98,13 +123,43 @@ func (s *Store) Write(ctx context.Context, art *models.CommitmentArtifact) error
 	return nil
 }
 
+// appendIndex appends one line to the instance index and syncs it.
+// Lines are only ever appended; a torn final line (crash mid-write) is ignored on read.
+func (s *Store) appendIndex(instanceID, artifactID string) error {
+	if err := os.MkdirAll(filepath.Join(s.basePath, indexDir), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+	f, err := os.OpenFile(s.indexPath(instanceID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open index: %w", err)
+	}
+	if _, err := f.WriteString(artifactID + "\n"); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to append to index: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to sync index: %w", err)
+	}
+	return f.Close()
+}
+
+func (s *Store) indexPath(instanceID string) string {
+	return filepath.Join(s.basePath, indexDir, instanceID)
+}
+
 // Get retrieves an artifact from disk.
 func (s *Store) Get(ctx context.Context, artifactID string) (*models.CommitmentArtifact, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	return s.get(artifactID)
+}
+
+// get reads an artifact; the caller holds s.mu.
+func (s *Store) get(artifactID string) (*models.CommitmentArtifact, error) {
 	if !safeIDRegex.MatchString(artifactID) {
-		return nil, fmt.Errorf("invalid artifact ID format: %s", artifactID)
+		return nil, fmt.Errorf("%w: artifact ID %q", artifact.ErrInvalidID, artifactID)
 	}
 
 	targetPath := filepath.Join(s.basePath, artifactID+".json")
@@ -124,3 +179,90 @@ func (s *Store) Get(ctx context.Context, artifactID string) (*models.CommitmentA
 
 	return &art, nil
 }
+
+// ListByInstance returns the instance's artifacts in write order, using the instance index.
+func (s *Store) ListByInstance(ctx context.Context, instanceID string) ([]*models.CommitmentArtifact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if !safeIDRegex.MatchString(instanceID) {
+		return nil, fmt.Errorf("%w: instance ID %q", artifact.ErrInvalidID, instanceID)
+	}
+
+	data, err := os.ReadFile(s.indexPath(instanceID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*models.CommitmentArtifact{}, nil
+		}
+		return nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	// Only newline-terminated lines are complete entries.
+	lines := strings.Split(string(data), "\n")
+	lines = lines[:len(lines)-1]
+
+	arts := make([]*models.CommitmentArtifact, 0, len(lines))
+	seen := map[string]bool{}
+	for _, id := range lines {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if seen[id] {
+			continue
+		}
+		art, err := s.get(id)
+		if errors.Is(err, artifact.ErrArtifactNotFound) {
+			continue // The write failed after indexing; nothing was stored
+		}
+		if err != nil {
+			return nil, fmt.Errorf("index entry %s: %w", id, err)
+		}
+		// The index is not authoritative: never let it attribute an artifact to another instance.
+		if art.InstanceID != instanceID {
+			return nil, fmt.Errorf("index entry %s belongs to instance %s, not %s", id, art.InstanceID, instanceID)
+		}
+		seen[id] = true
+		arts = append(arts, art)
+	}
+	return arts, nil
+}
+
+// Walk streams every artifact file in the store, ordered by artifact ID.
+// fn runs under the store read lock and must not write to the store.
+func (s *Store) Walk(ctx context.Context, fn artifact.WalkFunc) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir, err := os.Open(s.basePath)
+	if err != nil {
+		return fmt.Errorf("failed to open artifact directory: %w", err)
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return fmt.Errorf("failed to list artifact directory: %w", err)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		id, ok := strings.CutSuffix(name, ".json")
+		if !ok || !safeIDRegex.MatchString(id) {
+			continue // Temp files, the index directory, foreign files
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		art, err := s.get(id)
+		if err != nil {
+			if errors.Is(err, artifact.ErrArtifactNotFound) {
+				continue
+			}
+			return err
+		}
+		if err := fn(art); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+// Package storage selects the artifact.Store implementation from the environment.
+package storage
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/Rainminds/gantral/internal/anchor"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/artifactlog"
+	"github.com/Rainminds/gantral/internal/storage/blobstore"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/config"
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+
+	// Bucket drivers for ARTIFACT_STORE_URL
+	_ "gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/memblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+// OpenArtifactStore builds the artifact.Store from the environment.
+//
+//	ARTIFACT_STORE_URL         gocloud bucket URL, e.g. s3://gantral-artifacts?region=eu-west-1
+//	ARTIFACT_RETENTION         object-lock retention per artifact (Go duration, e.g. 61320h); requires S3 Object Lock
+//	ARTIFACT_OBJECT_LOCK_MODE  COMPLIANCE (default) or GOVERNANCE
+//	ARTIFACT_STORAGE_PATH      local directory used when ARTIFACT_STORE_URL is unset
+func OpenArtifactStore(ctx context.Context, logger *slog.Logger) (artifact.Store, error) {
+	storeURL := config.GetEnv("ARTIFACT_STORE_URL", "")
+	if storeURL == "" {
+		artifactDir := config.GetEnv("ARTIFACT_STORAGE_PATH", "./gantral_artifacts")
+		logger.Warn("ARTIFACT_STORE_URL not set; using local filesystem artifact store (not WORM)", "path", artifactDir)
+		return local.NewStore(artifactDir)
+	}
+
+	var opts blobstore.Options
+	if v := config.GetEnv("ARTIFACT_RETENTION", ""); v != "" {
+		retention, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ARTIFACT_RETENTION: %w", err)
+		}
+		opts.Retention = retention
+		opts.Mode = blobstore.LockMode(config.GetEnv("ARTIFACT_OBJECT_LOCK_MODE", string(blobstore.LockModeCompliance)))
+	}
+
+	store, err := blobstore.OpenStore(ctx, storeURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Artifact store: object storage", "url", redactURL(storeURL), "retention", opts.Retention, "lock_mode", opts.Mode)
+	return store, nil
+}
+
+// OpenEvidenceStore opens the evidence bucket the worker writes to.
+//
+//	EVIDENCE_BUCKET_URL  gocloud bucket URL; defaults to the OS temp directory, like the worker
+func OpenEvidenceStore(ctx context.Context, logger *slog.Logger) (*blobstore.EvidenceStore, error) {
+	bucketURL := config.GetEnv("EVIDENCE_BUCKET_URL", "")
+	if bucketURL == "" {
+		bucketURL = "file://" + os.TempDir()
+	}
+	store, err := blobstore.OpenEvidenceStore(ctx, bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Evidence store", "url", redactURL(bucketURL))
+	return store, nil
+}
+
+// OpenArtifactLog opens the global artifact log, or returns nil when it is not configured.
+//
+//	ARTIFACT_LOG_URL     gocloud bucket URL of the log (may be the artifact bucket)
+//	ARTIFACT_LOG_ORIGIN  name of the log in every checkpoint (default "gantral")
+func OpenArtifactLog(ctx context.Context, logger *slog.Logger) (*artifactlog.Log, error) {
+	logURL := config.GetEnv("ARTIFACT_LOG_URL", "")
+	if logURL == "" {
+		logger.Warn("ARTIFACT_LOG_URL not set; artifacts are not appended to a global log")
+		return nil, nil
+	}
+	l, err := artifactlog.Open(ctx, logURL, config.GetEnv("ARTIFACT_LOG_ORIGIN", "gantral"))
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Artifact log", "url", redactURL(logURL), "origin", l.Origin())
+	return l, nil
+}
+
+// OpenAnchors builds the external witnesses checkpoints are anchored to.
+//
+//	ANCHOR_WITNESS_URL  gocloud bucket URL of a witness under different credentials (file:// for a directory)
+//	ANCHOR_TSA_URL      RFC 3161 time-stamp authority
+//	ANCHOR_TSA_ROOTS    PEM file of trusted TSA root certificates
+func OpenAnchors(ctx context.Context, logger *slog.Logger) ([]anchor.Anchor, error) {
+	var anchors []anchor.Anchor
+	if witnessURL := config.GetEnv("ANCHOR_WITNESS_URL", ""); witnessURL != "" {
+		w, err := anchor.OpenBucketWitness(ctx, "witness", witnessURL)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("Anchor witness", "url", redactURL(witnessURL))
+		anchors = append(anchors, w)
+	}
+	if tsaURL := config.GetEnv("ANCHOR_TSA_URL", ""); tsaURL != "" {
+		client := &rfc3161.Client{URL: tsaURL}
+		if rootsPath := config.GetEnv("ANCHOR_TSA_ROOTS", ""); rootsPath != "" {
+			pem, err := os.ReadFile(rootsPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read ANCHOR_TSA_ROOTS: %w", err)
+			}
+			client.Roots = x509.NewCertPool()
+			if !client.Roots.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("ANCHOR_TSA_ROOTS contains no certificates")
+			}
+		} else {
+			logger.Warn("ANCHOR_TSA_ROOTS not set; TSA certificates are not checked against trusted roots")
+		}
+		logger.Info("Anchor time-stamp authority", "url", redactURL(tsaURL))
+		anchors = append(anchors, anchor.NewTSAWitness("tsa", client))
+	}
+	return anchors, nil
+}
+
+// redactURL strips credentials from a bucket URL before logging it.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "<unparseable>"
+	}
+	return u.Redacted()
+}
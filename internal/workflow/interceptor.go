@@ -2,16 +2,30 @@ package workflow
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 
+	"github.com/Rainminds/gantral/core/activities"
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/workflows"
 	"github.com/Rainminds/gantral/internal/replay"
 	"github.com/Rainminds/gantral/pkg/models"
+	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/workflow"
 )
 
-// ReplayInterceptor enforces that replayed activities have valid artifacts.
+// Registered names of the activities.ExecutionActivities methods that record decisions.
+const (
+	recordDecisionActivity       = "RecordDecision"
+	recordVoteActivity           = "RecordVote"
+	recordQuorumDecisionActivity = "RecordQuorumDecision"
+)
+
+// ReplayInterceptor enforces that replayed activities have valid artifacts, and that every
+// decision signal in history is bound to the artifact it produced.
 // It matches the specific requirement: "If the runtime proposes a history... execution must Fail-Closed."
 type ReplayInterceptor struct {
 	interceptor.WorkerInterceptorBase
@@ -31,27 +45,51 @@ func (r *ReplayInterceptor) InterceptWorkflow(
 	return &replayWorkflowInbound{
 		WorkflowInboundInterceptorBase: interceptor.WorkflowInboundInterceptorBase{Next: next},
 		guard:                          r.Guard,
+		signals:                        &decisionSignals{},
 	}
 }
 
-// replayWorkflowInbound intercepts inbound calls to init outbound interceptors.
+// decisionSignals holds the HumanDecision signals delivered to one workflow execution,
+// in delivery order. On replay they are the signal payloads recorded in history.
+type decisionSignals struct {
+	received []activities.RecordDecisionInput
+}
+
+// replayWorkflowInbound intercepts inbound calls to init outbound interceptors
+// and to observe decision signals.
 type replayWorkflowInbound struct {
 	interceptor.WorkflowInboundInterceptorBase
-	guard *replay.ReplayGuard
+	guard   *replay.ReplayGuard
+	signals *decisionSignals
 }
 
 func (w *replayWorkflowInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
 	i := &replayWorkflowOutbound{
 		WorkflowOutboundInterceptorBase: interceptor.WorkflowOutboundInterceptorBase{Next: outbound},
 		guard:                           w.guard,
+		signals:                         w.signals,
 	}
 	return w.Next.Init(i)
 }
 
+// HandleSignal records every decision signal before it reaches the workflow's channel.
+// Channels cannot be wrapped (selectors require the SDK's own type), so signals are
+// observed here, where both live delivery and history replay pass.
+func (w *replayWorkflowInbound) HandleSignal(ctx workflow.Context, in *interceptor.HandleSignalInput) error {
+	if in.SignalName == workflows.SignalHumanDecision {
+		var decision activities.RecordDecisionInput
+		if err := converter.GetDefaultDataConverter().FromPayloads(in.Arg, &decision); err == nil {
+			w.signals.received = append(w.signals.received, decision)
+		}
+	}
+	return w.Next.HandleSignal(ctx, in)
+}
+
 // replayWorkflowOutbound intercepts outbound calls (ExecuteActivity).
 type replayWorkflowOutbound struct {
 	interceptor.WorkflowOutboundInterceptorBase
-	guard *replay.ReplayGuard
+	guard   *replay.ReplayGuard
+	signals *decisionSignals
 }
 
 // ExecuteActivity intercepts activity calls to valid results on replay.
@@ -60,6 +98,11 @@ func (w *replayWorkflowOutbound) ExecuteActivity(
 	activityType string,
 	args ...interface{},
 ) workflow.Future {
+	var claim *replay.DecisionClaim
+	if len(args) > 0 {
+		claim = w.bindDecision(activityType, args[0])
+	}
+
 	// Call the next interceptor (or SDK core)
 	f := w.Next.ExecuteActivity(ctx, activityType, args...)
 
@@ -69,7 +112,142 @@ func (w *replayWorkflowOutbound) ExecuteActivity(
 		ctx:    ctx,
 		guard:  w.guard,
 		name:   activityType,
+		claim:  claim,
+	}
+}
+
+// bindDecision checks a decision-recording activity against the signals delivered to this
+// execution and returns the claim its artifact must record (nil for other activities).
+func (w *replayWorkflowOutbound) bindDecision(activityType string, arg interface{}) *replay.DecisionClaim {
+	switch activityType {
+	case recordDecisionActivity:
+		// A human decision must be exactly one of the signals delivered to this execution.
+		// The timeout path (SYSTEM) is built by the workflow itself.
+		input, ok := inputArg[activities.RecordDecisionInput](arg)
+		if !ok {
+			return nil
+		}
+		c := decisionClaim(input)
+		if input.ActorID != engine.ActorSystem && !w.signals.contains(input) {
+			signalMismatch(input.InstanceID, fmt.Sprintf("decision by %q was not received as a signal", input.ActorID))
+		}
+		return &c
+
+	case recordVoteActivity:
+		// A vote must be one of the signals; its artifact keeps the instance waiting.
+		input, ok := inputArg[activities.RecordVoteInput](arg)
+		if !ok {
+			return nil
+		}
+		d := input.Decision
+		if !w.signals.contains(d) {
+			signalMismatch(d.InstanceID, fmt.Sprintf("vote by %q was not received as a signal", d.ActorID))
+		}
+		contextHash, _ := activities.VoteContextHash(input)
+		return &replay.DecisionClaim{
+			InstanceID:     d.InstanceID,
+			DecisionType:   d.DecisionType,
+			ActorID:        d.ActorID,
+			ContextHash:    contextHash,
+			AuthorityState: string(engine.StateWaitingForHuman),
+		}
+
+	case recordQuorumDecisionActivity:
+		// Every vote the outcome counts must have been received as a signal.
+		input, ok := inputArg[activities.QuorumDecisionInput](arg)
+		if !ok {
+			return nil
+		}
+		for _, v := range input.Votes {
+			if !w.signals.containsVote(input.InstanceID, v) {
+				signalMismatch(input.InstanceID, fmt.Sprintf("vote by %q was not received as a signal", v.ActorID))
+			}
+		}
+		contextHash, _ := activities.QuorumContextHash(input)
+		return &replay.DecisionClaim{
+			InstanceID:   input.InstanceID,
+			DecisionType: input.DecisionType,
+			ActorID:      engine.QuorumActorID(input.Votes, input.DecisionType),
+			ContextHash:  contextHash,
+		}
 	}
+	return nil
+}
+
+func signalMismatch(instanceID, detail string) {
+	fail(&replay.Violation{
+		Kind:       replay.ViolationSignalMismatch,
+		InstanceID: instanceID,
+		Signal:     workflows.SignalHumanDecision,
+		Detail:     detail,
+	})
+}
+
+func (s *decisionSignals) containsVote(instanceID string, v engine.Vote) bool {
+	for _, received := range s.received {
+		if received.InstanceID == instanceID && received.ActorID == v.ActorID && received.DecisionType == v.Decision {
+			return true
+		}
+	}
+	return false
+}
+
+// contains reports whether a decision is one of the received signals. The workflow adds
+// only the role it admitted the decision under, which must be a role the signal carried.
+func (s *decisionSignals) contains(decision activities.RecordDecisionInput) bool {
+	claim := decisionClaim(decision)
+	for _, received := range s.received {
+		if decision.AuthorizedRole != "" {
+			if !slices.Contains(engine.HeldRoles(received.Identity, received.Roles), decision.AuthorizedRole) {
+				continue
+			}
+			received.AuthorizedRole = decision.AuthorizedRole
+		}
+		if decisionClaim(received) == claim {
+			return true
+		}
+	}
+	return false
+}
+
+// decisionClaim derives what a decision binds into its artifact, exactly as
+// activities.RecordDecision does.
+func decisionClaim(input activities.RecordDecisionInput) replay.DecisionClaim {
+	contextHash, _ := activities.DecisionContextHash(input)
+	// An authenticated decision claims its identity's issuer|subject binding, whatever
+	// ActorID says, so a rewritten actor can never match the signal that was received.
+	actorID := input.ActorID
+	if input.Identity != nil {
+		actorID = input.Identity.ActorID()
+	}
+	return replay.DecisionClaim{
+		InstanceID:   input.InstanceID,
+		DecisionType: input.DecisionType,
+		ActorID:      actorID,
+		ContextHash:  contextHash,
+	}
+}
+
+// inputArg returns an activity argument passed by value or by pointer.
+func inputArg[T any](arg interface{}) (T, bool) {
+	switch v := arg.(type) {
+	case T:
+		return v, true
+	case *T:
+		if v != nil {
+			return *v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// fail rejects the history. Panicking inside a workflow is the standard way to reject a
+// deterministic violation: the workflow task fails and is retried, so the worker never
+// proceeds on bad history, and the workflow is not completed with a forged outcome.
+// The panic value is the structured error (a *replay.Violation for tampering).
+func fail(err error) {
+	panic(fmt.Errorf("REPLAY GUARD VIOLATION: %w", err))
 }
 
 // replayFuture wraps workflow.Future to inspect results.
@@ -78,6 +256,7 @@ type replayFuture struct {
 	ctx   workflow.Context
 	guard *replay.ReplayGuard
 	name  string
+	claim *replay.DecisionClaim // Set for decision-recording activities: what the artifact must record
 }
 
 // Get intercepts the result retrieval.
@@ -99,14 +278,17 @@ func (f *replayFuture) Get(ctx workflow.Context, valuePtr interface{}) error {
 	// Since we know the specific activity types or can check type, we try type assertion.
 
 	// Use a helper to extract the artifact safely.
-	if artifact, ok := extractArtifact(valuePtr); ok {
-		slog.Debug("Validating replayed artifact", "activity", f.name, "id", artifact.ArtifactID)
+	if art, ok := extractArtifact(valuePtr); ok {
+		slog.Debug("Validating replayed artifact", "activity", f.name, "id", art.ArtifactID)
 		// Use a detached context for the store lookup, as workflow.Context is not compatible.
-		if vErr := f.guard.ValidateReplay(context.Background(), artifact); vErr != nil {
-			// FAIL-CLOSED: Panic or Token Cancellation.
-			// Panicking inside a workflow on replay is the standard way to reject deterministic violation.
-			// It crashes the replay, preventing the worker from proceeding on this bad history.
-			panic(fmt.Sprintf("REPLAY GUARD VIOLATION: %v", vErr))
+		if vErr := f.guard.ValidateReplay(context.Background(), art); vErr != nil {
+			fail(vErr)
+		}
+		// Bind the decision signal to the artifact it produced.
+		if f.claim != nil {
+			if vErr := replay.ValidateDecision(workflows.SignalHumanDecision, *f.claim, art); vErr != nil {
+				fail(vErr)
+			}
 		}
 	}
 
@@ -119,10 +301,13 @@ func extractArtifact(valuePtr interface{}) (*models.CommitmentArtifact, bool) {
 		return nil, false
 	}
 
-	// 1. Direct Typed Pointer: **CommitmentArtifact
+	// 1. Direct Typed Pointer: **CommitmentArtifact or *CommitmentArtifact
 	if ptr, ok := valuePtr.(**models.CommitmentArtifact); ok && ptr != nil && *ptr != nil {
 		return *ptr, true
 	}
+	if ptr, ok := valuePtr.(*models.CommitmentArtifact); ok && ptr != nil && ptr.ArtifactID != "" {
+		return ptr, true
+	}
 
 	// 2. Interface Wrapper: *interface{} -> *CommitmentArtifact OR map[string]interface{}
 	if ptr, ok := valuePtr.(*interface{}); ok && ptr != nil {
@@ -139,33 +324,22 @@ func extractArtifact(valuePtr interface{}) (*models.CommitmentArtifact, bool) {
 		// 2b. Map (JSON unmarshalled)
 		if m, ok := val.(map[string]interface{}); ok {
 			// Check for required fields to identify it as an Artifact
-			id, hasID := m["artifact_id"].(string)
-			state, hasState := m["authority_state"].(string)
+			_, hasID := m["artifact_id"].(string)
+			_, hasState := m["authority_state"].(string)
 			// hash field is removed in v1, ID is the hash.
 
 			if hasID && hasState {
-				// Reconstruct for validation
-				// extracting other fields for integrity hash check
-				// extracting other fields for integrity hash check
-				instID, _ := m["instance_id"].(string)
-				prevHash, _ := m["prev_artifact_hash"].(string)
-				policyVer, _ := m["policy_version_id"].(string)
-				ctxHash, _ := m["context_hash"].(string)
-				actorID, _ := m["human_actor_id"].(string)
-				timestamp, _ := m["timestamp"].(string)
-				version, _ := m["artifact_version"].(string)
-
-				return &models.CommitmentArtifact{
-					ArtifactID:       id,
-					AuthorityState:   state,
-					InstanceID:       instID,
-					PrevArtifactHash: prevHash,
-					PolicyVersionID:  policyVer,
-					ContextHash:      ctxHash,
-					HumanActorID:     actorID,
-					Timestamp:        timestamp,
-					ArtifactVersion:  version,
-				}, true
+				// Reconstruct for validation via the artifact's own JSON schema,
+				// so every versioned field (v1 and v2) takes part in the integrity hash check.
+				raw, err := json.Marshal(m)
+				if err != nil {
+					return nil, false
+				}
+				var art models.CommitmentArtifact
+				if err := json.Unmarshal(raw, &art); err != nil {
+					return nil, false
+				}
+				return &art, true
 			}
 		}
 	}
@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Rainminds/gantral/core/activities"
+	"github.com/Rainminds/gantral/core/engine"
+	"github.com/Rainminds/gantral/core/policy"
+	"github.com/Rainminds/gantral/core/workflows"
+	"github.com/Rainminds/gantral/internal/artifact"
+	"github.com/Rainminds/gantral/internal/replay"
+	"github.com/Rainminds/gantral/internal/storage/local"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+// guardedEnv is a workflow test environment running under the replay interceptor.
+// Activities emit real, chained artifacts into a local store through emit.
+type guardedEnv struct {
+	*testsuite.TestWorkflowEnvironment
+	emit func(instanceID, state, contextHash, actor string) *models.CommitmentArtifact
+}
+
+func newGuardedEnv(t *testing.T) guardedEnv {
+	store, err := local.NewStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	head := models.GenesisHash
+	emit := func(instanceID, state, contextHash, actor string) *models.CommitmentArtifact {
+		art := models.NewCommitmentArtifact(instanceID, head, state, "pol-high", contextHash, actor)
+		if err := art.CalculateHashAndSetID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Write(ctx, art); err != nil {
+			t.Fatal(err)
+		}
+		head = art.ArtifactID
+		return art
+	}
+
+	var s testsuite.WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{NewReplayInterceptor(replay.NewReplayGuard(store))},
+	})
+
+	var a *activities.ExecutionActivities
+	env.OnActivity(a.PersistInstance, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, in activities.PersistInstanceInput) (*engine.Instance, error) {
+			genesis := emit("inst-1", string(engine.StateWaitingForHuman), "genesis", engine.ActorSystem)
+			return &engine.Instance{ID: "inst-1", State: engine.StateWaitingForHuman, LastArtifactHash: genesis.ArtifactID, PolicyContext: in.PolicyResult}, nil
+		})
+	env.OnActivity(a.Transition, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, in activities.TransitionInput) (*models.CommitmentArtifact, error) {
+			return emit(in.InstanceID, string(in.TargetState), "transition", engine.ActorSystem), nil
+		})
+	return guardedEnv{TestWorkflowEnvironment: env, emit: emit}
+}
+
+// runGuarded runs an HITL workflow under the replay interceptor. actorOverride, when set,
+// is recorded instead of the signal's actor.
+func runGuarded(t *testing.T, actorOverride string) error {
+	env := newGuardedEnv(t)
+	emit := env.emit
+
+	var a *activities.ExecutionActivities
+	env.OnActivity(a.RecordDecision, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, in activities.RecordDecisionInput) (*models.CommitmentArtifact, error) {
+			next, _ := engine.CalculateNextState(in.DecisionType)
+			contextHash, _ := artifact.HashContext(in.ContextSnapshot)
+			actor := in.ActorID
+			if actorOverride != "" {
+				actor = actorOverride
+			}
+			return emit(in.InstanceID, string(next), contextHash, actor), nil
+		})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(workflows.SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID:      "inst-1",
+			DecisionType:    engine.DecisionApprove,
+			ActorID:         engine.BindActorID("dev-mode", "alice"),
+			Identity:        &engine.ActorIdentity{Subject: "alice", Issuer: "dev-mode", Type: "human"},
+			ContextSnapshot: map[string]interface{}{"ticket": "CHG-1"},
+		})
+	}, time.Second)
+
+	env.ExecuteWorkflow(workflows.GantralExecutionWorkflow, workflows.WorkflowInput{
+		WorkflowID: "wf-guarded",
+		Policy:     policy.Policy{ID: "pol-high", Materiality: policy.MaterialityHigh},
+	})
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("workflow did not complete")
+	}
+	return env.GetWorkflowError()
+}
+
+func TestReplayInterceptor_BoundDecision(t *testing.T) {
+	if err := runGuarded(t, ""); err != nil {
+		t.Errorf("Honest execution rejected: %v", err)
+	}
+}
+
+func TestReplayInterceptor_DecisionNotBoundToSignal(t *testing.T) {
+	// The artifact is intact and stored, but it records a different approver than the signal.
+	err := runGuarded(t, "mallory")
+	if err == nil {
+		t.Fatal("Expected a replay guard violation")
+	}
+	for _, want := range []string{string(replay.ViolationSignalMismatch), "human_actor_id", `claimed="dev-mode|alice"`, `stored="mallory"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in violation, got: %v", want, err)
+		}
+	}
+}
+
+func TestReplayInterceptor_DecisionUnderCheckedRole(t *testing.T) {
+	// The workflow adds the role it admitted the decision under; the signal carried it.
+	env := newGuardedEnv(t)
+
+	var a *activities.ExecutionActivities
+	env.OnActivity(a.RecordDecision, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, in activities.RecordDecisionInput) (*models.CommitmentArtifact, error) {
+			if in.AuthorizedRole != "approver" {
+				t.Errorf("AuthorizedRole = %q, want approver", in.AuthorizedRole)
+			}
+			next, _ := engine.CalculateNextState(in.DecisionType)
+			contextHash, _ := activities.DecisionContextHash(in)
+			return env.emit(in.InstanceID, string(next), contextHash, in.ActorID), nil
+		})
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(workflows.SignalHumanDecision, activities.RecordDecisionInput{
+			InstanceID:   "inst-1",
+			DecisionType: engine.DecisionApprove,
+			ActorID:      "alice",
+			Roles:        []string{"user", "approver"},
+		})
+	}, time.Second)
+
+	env.ExecuteWorkflow(workflows.GantralExecutionWorkflow, workflows.WorkflowInput{
+		WorkflowID: "wf-roles",
+		Policy:     policy.Policy{ID: "pol-high", Materiality: policy.MaterialityHigh, ApproverRoles: []string{"approver"}},
+	})
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("workflow did not complete")
+	}
+	if err := env.GetWorkflowError(); err != nil {
+		t.Errorf("Honest execution rejected: %v", err)
+	}
+}
+
+// runQuorumGuarded runs a 2-of-2 vote under the replay interceptor. aggregateActor, when
+// set, is recorded on the aggregate artifact instead of the voters.
+func runQuorumGuarded(t *testing.T, aggregateActor string) error {
+	env := newGuardedEnv(t)
+
+	var a *activities.ExecutionActivities
+	env.OnActivity(a.RecordVote, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, in activities.RecordVoteInput) (*models.CommitmentArtifact, error) {
+			contextHash, _ := activities.VoteContextHash(in)
+			return env.emit(in.Decision.InstanceID, string(engine.StateWaitingForHuman), contextHash, in.Decision.ActorID), nil
+		})
+	env.OnActivity(a.RecordQuorumDecision, mock.Anything, mock.Anything).Return(
+		func(ctx context.Context, in activities.QuorumDecisionInput) (*models.CommitmentArtifact, error) {
+			contextHash, _ := activities.QuorumContextHash(in)
+			actor := engine.QuorumActorID(in.Votes, in.DecisionType)
+			if aggregateActor != "" {
+				actor = aggregateActor
+			}
+			return env.emit(in.InstanceID, string(engine.StateApproved), contextHash, actor), nil
+		})
+
+	env.RegisterDelayedCallback(func() {
+		for _, actor := range []string{"alice", "bob"} {
+			env.SignalWorkflow(workflows.SignalHumanDecision, activities.RecordDecisionInput{
+				InstanceID:   "inst-1",
+				DecisionType: engine.DecisionApprove,
+				ActorID:      actor,
+			})
+		}
+	}, time.Second)
+
+	env.ExecuteWorkflow(workflows.GantralExecutionWorkflow, workflows.WorkflowInput{
+		WorkflowID: "wf-quorum",
+		Policy:     policy.Policy{ID: "pol-high", Materiality: policy.MaterialityHigh, Quorum: 2},
+	})
+	if !env.IsWorkflowCompleted() {
+		t.Fatal("workflow did not complete")
+	}
+	return env.GetWorkflowError()
+}
+
+func TestReplayInterceptor_QuorumBoundToVotes(t *testing.T) {
+	if err := runQuorumGuarded(t, ""); err != nil {
+		t.Errorf("Honest vote rejected: %v", err)
+	}
+
+	// The aggregate must name the voters that decided it.
+	err := runQuorumGuarded(t, "quorum:alice,mallory")
+	if err == nil {
+		t.Fatal("Expected a replay guard violation")
+	}
+	for _, want := range []string{string(replay.ViolationSignalMismatch), "human_actor_id", `stored="quorum:alice,mallory"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in violation, got: %v", want, err)
+		}
+	}
+}
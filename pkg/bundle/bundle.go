@@ -0,0 +1,243 @@
+// Package bundle writes and verifies self-contained audit bundles.
+//
+// A bundle is a tar archive that an auditor can verify offline in one step:
+//
+//	manifest.json                 bundle version, instances, SHA-256 and size of every other member
+//	artifacts/<artifact_id>.json  commitment artifacts of the listed instances
+//	evidence/<evidence_id>.json   ExecutionEvidence captured for those instances
+//	policies/<version_id>.json    canonical policy bodies referenced by the artifacts
+//	keys.json                     optional public signing keys (signing.KeySetFile)
+//
+// Every member is content-addressed by its file name, and the manifest binds the
+// exact bytes of every member. Publishing the SHA-256 of manifest.json therefore pins
+// the whole bundle.
+package bundle
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+)
+
+// Version is the bundle format version written by this package.
+const Version = "1"
+
+// Member paths.
+const (
+	ManifestPath = "manifest.json"
+	KeysPath     = "keys.json"
+
+	artifactsDir = "artifacts/"
+	evidenceDir  = "evidence/"
+	policiesDir  = "policies/"
+)
+
+// maxMemberSize bounds a single member so that a hostile bundle cannot exhaust memory.
+const maxMemberSize = 64 << 20
+
+// safeIDRegex restricts IDs used as member names (no path separators or traversal).
+var safeIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
+
+// ErrInvalidContents indicates contents that cannot be written as a bundle.
+var ErrInvalidContents = errors.New("invalid bundle contents")
+
+// Manifest describes a bundle. It is the only member not listed in itself.
+type Manifest struct {
+	BundleVersion string   `json:"bundle_version"`
+	CreatedAt     string   `json:"created_at"`
+	Instances     []string `json:"instances"`
+	Members       []Member `json:"members"`
+}
+
+// Member is one archived file and the digest of its exact bytes.
+type Member struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Contents is what goes into a bundle.
+type Contents struct {
+	Instances []string
+	Artifacts []*models.CommitmentArtifact
+	Evidence  []*models.ExecutionEvidence
+	// Policies maps a policy version ID to the canonical body it is the SHA-256 of.
+	Policies map[string][]byte
+	// Keys are the public signing keys to ship with the bundle (optional).
+	Keys *signing.KeySetFile
+}
+
+// Write archives the contents to w and returns the manifest.
+// Members are written in sorted order with fixed metadata, so the same contents
+// and createdAt always produce the same bytes.
+func Write(w io.Writer, c Contents, createdAt time.Time) (*Manifest, error) {
+	files := make(map[string][]byte)
+	add := func(dir, id string, data []byte) error {
+		if !safeIDRegex.MatchString(id) {
+			return fmt.Errorf("%w: unsafe ID %q", ErrInvalidContents, id)
+		}
+		path := dir + id + ".json"
+		if _, dup := files[path]; dup {
+			return nil // Same ID, same content
+		}
+		files[path] = data
+		return nil
+	}
+
+	for _, art := range c.Artifacts {
+		data, err := json.Marshal(art)
+		if err != nil {
+			return nil, fmt.Errorf("%w: artifact %s: %v", ErrInvalidContents, art.ArtifactID, err)
+		}
+		if err := add(artifactsDir, art.ArtifactID, data); err != nil {
+			return nil, err
+		}
+	}
+	for _, ev := range c.Evidence {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return nil, fmt.Errorf("%w: evidence %s: %v", ErrInvalidContents, ev.EvidenceID, err)
+		}
+		if err := add(evidenceDir, ev.EvidenceID, data); err != nil {
+			return nil, err
+		}
+	}
+	for id, body := range c.Policies {
+		if err := add(policiesDir, id, body); err != nil {
+			return nil, err
+		}
+	}
+	if c.Keys != nil {
+		data, err := json.MarshalIndent(c.Keys, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("%w: keys: %v", ErrInvalidContents, err)
+		}
+		files[KeysPath] = data
+	}
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	instances := append([]string(nil), c.Instances...)
+	sort.Strings(instances)
+
+	manifest := &Manifest{
+		BundleVersion: Version,
+		CreatedAt:     createdAt.UTC().Format(time.RFC3339),
+		Instances:     instances,
+		Members:       make([]Member, 0, len(paths)),
+	}
+	for _, p := range paths {
+		sum := sha256.Sum256(files[p])
+		manifest.Members = append(manifest.Members, Member{
+			Path:   p,
+			SHA256: hex.EncodeToString(sum[:]),
+			Size:   int64(len(files[p])),
+		})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("%w: manifest: %v", ErrInvalidContents, err)
+	}
+
+	tw := tar.NewWriter(w)
+	writeFile := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(data)),
+			ModTime:  createdAt.UTC().Truncate(time.Second),
+			Typeflag: tar.TypeReg,
+			Format:   tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	// The manifest goes first so that streaming readers see it before the members.
+	if err := writeFile(ManifestPath, manifestData); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	for _, p := range paths {
+		if err := writeFile(p, files[p]); err != nil {
+			return nil, fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return manifest, nil
+}
+
+// readMembers loads every regular file of the archive.
+// Anything that is not a plain, uniquely named, relative file is rejected.
+func readMembers(r io.Reader) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unreadable archive: %v", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("member %q is not a regular file", hdr.Name)
+		}
+		if !validMemberPath(hdr.Name) {
+			return nil, fmt.Errorf("member %q has an unexpected path", hdr.Name)
+		}
+		if _, dup := files[hdr.Name]; dup {
+			return nil, fmt.Errorf("member %q appears twice", hdr.Name)
+		}
+		if hdr.Size > maxMemberSize {
+			return nil, fmt.Errorf("member %q exceeds %d bytes", hdr.Name, maxMemberSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxMemberSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("unreadable member %q: %v", hdr.Name, err)
+		}
+		files[hdr.Name] = data
+	}
+}
+
+// validMemberPath accepts only the paths this package writes.
+func validMemberPath(path string) bool {
+	if path == ManifestPath || path == KeysPath {
+		return true
+	}
+	_, ok := memberID(path)
+	return ok
+}
+
+// memberID returns the ID encoded in an artifacts/, evidence/ or policies/ member path.
+func memberID(path string) (string, bool) {
+	for _, dir := range []string{artifactsDir, evidenceDir, policiesDir} {
+		if rest, ok := strings.CutPrefix(path, dir); ok {
+			id, ok := strings.CutSuffix(rest, ".json")
+			if ok && safeIDRegex.MatchString(id) {
+				return id, true
+			}
+			return "", false
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,286 @@
+package bundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Rainminds/gantral/pkg/bundle"
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+)
+
+var createdAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fixture is a signed two-instance bundle with its policy and evidence.
+type fixture struct {
+	contents bundle.Contents
+	keys     signing.KeySet
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	signer, err := signing.NewSigner("key-1", priv)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+
+	body := []byte(`{"name":"refunds","requires_human_approval":true}`)
+	sum := sha256.Sum256(body)
+	pv := hex.EncodeToString(sum[:])
+
+	var arts []*models.CommitmentArtifact
+	chain := func(instanceID string, states ...string) {
+		prev := models.GenesisHash
+		for _, state := range states {
+			art := models.NewCommitmentArtifact(instanceID, prev, state, pv, "ctx-"+state, "user-1")
+			if err := art.CalculateHashAndSetID(); err != nil {
+				t.Fatalf("hash: %v", err)
+			}
+			if err := signer.Sign(art); err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			arts = append(arts, art)
+			prev = art.ArtifactID
+		}
+	}
+	chain("inst-a", "WAITING_FOR_HUMAN", "APPROVED", "RESUMED")
+	chain("inst-b", "WAITING_FOR_HUMAN", "REJECTED")
+
+	ev := models.NewExecutionEvidence("inst-a", "refund", []byte(`{"amount":10}`), []byte(`{"ok":true}`))
+	if err := ev.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("evidence hash: %v", err)
+	}
+
+	return fixture{
+		contents: bundle.Contents{
+			Instances: []string{"inst-b", "inst-a"},
+			Artifacts: arts,
+			Evidence:  []*models.ExecutionEvidence{ev},
+			Policies:  map[string][]byte{pv: body},
+			Keys:      &signing.KeySetFile{Keys: []signing.PublicKeyEntry{signing.Entry("key-1", pub)}},
+		},
+		keys: signing.KeySet{"key-1": pub},
+	}
+}
+
+func write(t *testing.T, c bundle.Contents) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := bundle.Write(&buf, c, createdAt); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// rewrite copies a bundle through edit, which may change a member's bytes or drop it (nil).
+// Extra members are appended at the end.
+func rewrite(t *testing.T, data []byte, edit func(name string, body []byte) []byte, extra map[string][]byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	tw := tar.NewWriter(&out)
+	put := func(name string, body []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(tr)
+		if body = edit(hdr.Name, body); body != nil {
+			put(hdr.Name, body)
+		}
+	}
+	for name, body := range extra {
+		put(name, body)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return out.Bytes()
+}
+
+func findingCodes(findings []verifier.Finding) []verifier.ReasonCode {
+	codes := make([]verifier.ReasonCode, 0, len(findings))
+	for _, f := range findings {
+		codes = append(codes, f.Code)
+	}
+	return codes
+}
+
+func TestBundle_RoundTripTrustedKeys(t *testing.T) {
+	f := newFixture(t)
+	res := bundle.Verify(bytes.NewReader(write(t, f.contents)), f.keys)
+
+	assert.True(t, res.Valid, res.Findings)
+	assert.Equal(t, verifier.OutcomeValid, res.Outcome)
+	assert.Equal(t, bundle.KeysTrusted, res.Keys)
+	assert.Equal(t, []string{"inst-a", "inst-b"}, res.Manifest.Instances)
+	assert.Len(t, res.Instances, 2)
+	assert.Equal(t, 5, res.Artifacts)
+	assert.Equal(t, 1, res.Evidence)
+	assert.Equal(t, 1, res.Policies)
+	assert.Len(t, res.ManifestSHA256, 64)
+}
+
+func TestBundle_FallsBackToBundledKeys(t *testing.T) {
+	f := newFixture(t)
+	res := bundle.Verify(bytes.NewReader(write(t, f.contents)), nil)
+	assert.True(t, res.Valid, res.Findings)
+	assert.Equal(t, bundle.KeysBundle, res.Keys)
+
+	// Without any keys the signatures are not checked, but integrity still is.
+	f.contents.Keys = nil
+	res = bundle.Verify(bytes.NewReader(write(t, f.contents)), nil)
+	assert.True(t, res.Valid, res.Findings)
+	assert.Equal(t, bundle.KeysNone, res.Keys)
+}
+
+func TestBundle_Deterministic(t *testing.T) {
+	f := newFixture(t)
+	first := write(t, f.contents)
+
+	// Input order must not change the output bytes.
+	arts := f.contents.Artifacts
+	for i, j := 0, len(arts)-1; i < j; i, j = i+1, j-1 {
+		arts[i], arts[j] = arts[j], arts[i]
+	}
+	assert.Equal(t, first, write(t, f.contents))
+}
+
+func TestBundle_TamperedMember(t *testing.T) {
+	f := newFixture(t)
+	target := "artifacts/" + f.contents.Artifacts[1].ArtifactID + ".json"
+	data := rewrite(t, write(t, f.contents), func(name string, body []byte) []byte {
+		if name == target {
+			return bytes.Replace(body, []byte("APPROVED"), []byte("REJECTED"), 1)
+		}
+		return body
+	}, nil)
+
+	res := bundle.Verify(bytes.NewReader(data), f.keys)
+	assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+	assert.Contains(t, findingCodes(res.Findings), verifier.ReasonManifestMismatch)
+}
+
+func TestBundle_MissingAndUnlistedMembers(t *testing.T) {
+	f := newFixture(t)
+	ev := "evidence/" + f.contents.Evidence[0].EvidenceID + ".json"
+	data := rewrite(t, write(t, f.contents), func(name string, body []byte) []byte {
+		if name == ev {
+			return nil
+		}
+		return body
+	}, map[string][]byte{"policies/extra.json": []byte(`{}`)})
+
+	res := bundle.Verify(bytes.NewReader(data), f.keys)
+	assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+	codes := findingCodes(res.Findings)
+	assert.Contains(t, codes, verifier.ReasonMissingMember)
+	assert.Contains(t, codes, verifier.ReasonUnlistedMember)
+}
+
+func TestBundle_MissingPolicyIsInconclusive(t *testing.T) {
+	f := newFixture(t)
+	f.contents.Policies = nil
+
+	res := bundle.Verify(bytes.NewReader(write(t, f.contents)), f.keys)
+	assert.Equal(t, verifier.OutcomeInconclusive, res.Outcome)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonMissingPolicy}, findingCodes(res.Findings))
+}
+
+func TestBundle_ContentChecksBeyondManifest(t *testing.T) {
+	// A consistent manifest proves only that the bundle is intact, not that its members are genuine.
+	t.Run("policy body does not hash to its ID", func(t *testing.T) {
+		f := newFixture(t)
+		for id := range f.contents.Policies {
+			f.contents.Policies[id] = []byte(`{"name":"forged"}`)
+		}
+		res := bundle.Verify(bytes.NewReader(write(t, f.contents)), f.keys)
+		assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+		assert.Contains(t, findingCodes(res.Findings), verifier.ReasonHashMismatch)
+	})
+
+	t.Run("evidence does not match its ID", func(t *testing.T) {
+		f := newFixture(t)
+		f.contents.Evidence[0].OutputPayload = []byte(`{"ok":false}`)
+		res := bundle.Verify(bytes.NewReader(write(t, f.contents)), f.keys)
+		assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+		assert.Contains(t, findingCodes(res.Findings), verifier.ReasonHashMismatch)
+	})
+
+	t.Run("signature from an untrusted key", func(t *testing.T) {
+		f := newFixture(t)
+		other, _, _ := ed25519.GenerateKey(rand.Reader)
+		res := bundle.Verify(bytes.NewReader(write(t, f.contents)), signing.KeySet{"key-1": other})
+		assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+		assert.Contains(t, findingCodes(res.Findings), verifier.ReasonBadSignature)
+	})
+
+	t.Run("artifact of an unlisted instance", func(t *testing.T) {
+		f := newFixture(t)
+		f.contents.Instances = []string{"inst-a"}
+		res := bundle.Verify(bytes.NewReader(write(t, f.contents)), f.keys)
+		assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+		assert.Contains(t, findingCodes(res.Findings), verifier.ReasonManifestMismatch)
+	})
+
+	t.Run("listed instance without artifacts", func(t *testing.T) {
+		f := newFixture(t)
+		f.contents.Instances = append(f.contents.Instances, "inst-c")
+		res := bundle.Verify(bytes.NewReader(write(t, f.contents)), f.keys)
+		assert.Equal(t, verifier.OutcomeInconclusive, res.Outcome)
+		assert.Equal(t, []verifier.ReasonCode{verifier.ReasonMissingGenesis}, findingCodes(res.Findings))
+	})
+}
+
+func TestBundle_Malformed(t *testing.T) {
+	res := bundle.Verify(bytes.NewReader([]byte("not a tar archive")), nil)
+	assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonMalformedBundle}, findingCodes(res.Findings))
+
+	f := newFixture(t)
+	data := rewrite(t, write(t, f.contents), func(name string, body []byte) []byte { return body },
+		map[string][]byte{"../escape.json": []byte(`{}`)})
+	res = bundle.Verify(bytes.NewReader(data), nil)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonMalformedBundle}, findingCodes(res.Findings))
+
+	data = rewrite(t, write(t, f.contents), func(name string, body []byte) []byte {
+		if name == bundle.ManifestPath {
+			return bytes.Replace(body, []byte(`"bundle_version": "1"`), []byte(`"bundle_version": "9"`), 1)
+		}
+		return body
+	}, nil)
+	res = bundle.Verify(bytes.NewReader(data), nil)
+	assert.Equal(t, verifier.OutcomeInconclusive, res.Outcome)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonUnsupportedVersion}, findingCodes(res.Findings))
+}
+
+func TestWrite_RejectsUnsafeIDs(t *testing.T) {
+	f := newFixture(t)
+	f.contents.Policies = map[string][]byte{"../../etc/passwd": []byte("x")}
+	_, err := bundle.Write(io.Discard, f.contents, createdAt)
+	assert.ErrorIs(t, err, bundle.ErrInvalidContents)
+}
@@ -0,0 +1,249 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
+	"github.com/Rainminds/gantral/pkg/verifier"
+)
+
+// KeySource records where the keys used for signature checks came from.
+type KeySource string
+
+const (
+	// KeysNone: no keys were available; signatures were not checked.
+	KeysNone KeySource = "none"
+	// KeysTrusted: keys supplied by the auditor, independent of the bundle.
+	KeysTrusted KeySource = "trusted"
+	// KeysBundle: keys shipped inside the bundle. They prove consistency with the
+	// exporter's claimed keys, not origin; pin them out of band for non-repudiation.
+	KeysBundle KeySource = "bundle"
+)
+
+// Result is the outcome of verifying a bundle.
+type Result struct {
+	Valid          bool             `json:"valid"`
+	Outcome        verifier.Outcome `json:"outcome"`
+	ManifestSHA256 string           `json:"manifest_sha256,omitempty"`
+	Manifest       *Manifest        `json:"manifest,omitempty"`
+	Keys           KeySource        `json:"keys"`
+
+	// Findings are bundle-level problems (manifest, members, policies, evidence, artifact files).
+	Findings []verifier.Finding `json:"findings,omitempty"`
+	// Instances are the per-instance chain results for the artifacts that passed.
+	Instances []verifier.InstanceResult `json:"instances"`
+
+	Artifacts int `json:"artifacts"`
+	Evidence  int `json:"evidence"`
+	Policies  int `json:"policies"`
+}
+
+// bundleFinding builds a finding that is not tied to a chain position.
+func bundleFinding(outcome verifier.Outcome, code verifier.ReasonCode, id, format string, args ...interface{}) verifier.Finding {
+	return verifier.Finding{Index: -1, ArtifactID: id, Outcome: outcome, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Verify checks a bundle end to end:
+//  1. The archive is well formed and manifest.json lists exactly its members, byte for byte.
+//  2. Every artifact verifies (hash, schema, and signature when keys are available) and
+//     is named after its artifact_id; the chain of every instance is rebuilt and checked.
+//  3. Every policy body hashes to its version ID, and every policy an artifact references is present.
+//  4. Every evidence blob hashes to its evidence ID and belongs to a listed instance.
+//
+// trusted may be nil, in which case keys.json from the bundle (if any) is used.
+// Verification never stops at the first problem; the Outcome is the worst of all findings.
+func Verify(r io.Reader, trusted signing.KeySet) *Result {
+	res := &Result{Keys: KeysNone, Instances: []verifier.InstanceResult{}}
+
+	files, err := readMembers(r)
+	if err != nil {
+		return res.finish(bundleFinding(verifier.OutcomeInvalid, verifier.ReasonMalformedBundle, "", "%v", err))
+	}
+
+	// 1. Manifest
+	manifestData, ok := files[ManifestPath]
+	if !ok {
+		return res.finish(bundleFinding(verifier.OutcomeInvalid, verifier.ReasonMalformedBundle, "", "manifest.json missing"))
+	}
+	sum := sha256.Sum256(manifestData)
+	res.ManifestSHA256 = hex.EncodeToString(sum[:])
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return res.finish(bundleFinding(verifier.OutcomeInvalid, verifier.ReasonMalformedBundle, "", "invalid manifest.json: %v", err))
+	}
+	res.Manifest = &manifest
+	if manifest.BundleVersion != Version {
+		return res.finish(bundleFinding(verifier.OutcomeInconclusive, verifier.ReasonUnsupportedVersion, "", "unsupported bundle_version %q (supported: %s)", manifest.BundleVersion, Version))
+	}
+
+	var findings []verifier.Finding
+	verified := make(map[string][]byte) // Members whose bytes match the manifest
+	listed := make(map[string]bool, len(manifest.Members))
+	for _, m := range manifest.Members {
+		if listed[m.Path] {
+			findings = append(findings, bundleFinding(verifier.OutcomeInvalid, verifier.ReasonManifestMismatch, "", "manifest lists %s twice", m.Path))
+			continue
+		}
+		listed[m.Path] = true
+
+		data, ok := files[m.Path]
+		if !ok {
+			findings = append(findings, bundleFinding(verifier.OutcomeInvalid, verifier.ReasonMissingMember, "", "%s is listed in the manifest but missing", m.Path))
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, m.SHA256) || int64(len(data)) != m.Size {
+			findings = append(findings, bundleFinding(verifier.OutcomeInvalid, verifier.ReasonManifestMismatch, "", "%s does not match the manifest (sha256 %s, size %d)", m.Path, got, len(data)))
+			continue
+		}
+		verified[m.Path] = data
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if p != ManifestPath && !listed[p] {
+			findings = append(findings, bundleFinding(verifier.OutcomeInvalid, verifier.ReasonUnlistedMember, "", "%s is not listed in the manifest", p))
+		}
+	}
+
+	// 2. Keys
+	keys := trusted
+	if keys != nil {
+		res.Keys = KeysTrusted
+	} else if data, ok := verified[KeysPath]; ok {
+		bundled, err := signing.ParseKeySet(data)
+		if err != nil {
+			findings = append(findings, bundleFinding(verifier.OutcomeInvalid, verifier.ReasonMalformedBundle, "", "keys.json: %v", err))
+		} else {
+			keys, res.Keys = bundled, KeysBundle
+		}
+	}
+
+	instances := make(map[string]bool, len(manifest.Instances))
+	for _, id := range manifest.Instances {
+		instances[id] = true
+	}
+
+	// 3. Artifacts, policies and evidence, in path order for stable output.
+	var artifacts []models.CommitmentArtifact
+	policies := make(map[string]bool)
+	for _, p := range paths {
+		data, ok := verified[p]
+		if !ok {
+			continue
+		}
+		id, _ := memberID(p)
+		switch {
+		case strings.HasPrefix(p, artifactsDir):
+			res.Artifacts++
+			art, f := checkArtifact(id, data, keys, instances)
+			findings = append(findings, f...)
+			if art != nil {
+				artifacts = append(artifacts, *art)
+			}
+		case strings.HasPrefix(p, policiesDir):
+			res.Policies++
+			sum := sha256.Sum256(data)
+			if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, id) {
+				findings = append(findings, bundleFinding(verifier.OutcomeInvalid, verifier.ReasonHashMismatch, "", "policy %s: body hashes to %s", id, got))
+				continue
+			}
+			policies[strings.ToLower(id)] = true
+		case strings.HasPrefix(p, evidenceDir):
+			res.Evidence++
+			findings = append(findings, checkEvidence(id, data, instances)...)
+		}
+	}
+
+	// Every policy an artifact was evaluated under must be in the bundle.
+	missing := make(map[string]bool)
+	for _, art := range artifacts {
+		pv := art.PolicyVersionID
+		if pv == "" || policies[strings.ToLower(pv)] || missing[pv] {
+			continue
+		}
+		missing[pv] = true
+		findings = append(findings, bundleFinding(verifier.OutcomeInconclusive, verifier.ReasonMissingPolicy, art.ArtifactID, "policy version %s referenced by artifacts is not in the bundle", pv))
+	}
+
+	// 4. Chains
+	res.Instances = verifier.VerifyInstances(artifacts)
+	seen := make(map[string]bool, len(res.Instances))
+	for _, inst := range res.Instances {
+		seen[inst.InstanceID] = true
+	}
+	for _, id := range manifest.Instances {
+		if !seen[id] {
+			findings = append(findings, bundleFinding(verifier.OutcomeInconclusive, verifier.ReasonMissingGenesis, "", "instance %s is listed in the manifest but has no valid artifacts", id))
+		}
+	}
+
+	return res.finish(findings...)
+}
+
+// checkArtifact verifies one artifact member. It returns the artifact only if it is fit
+// for chain reconstruction.
+func checkArtifact(id string, data []byte, keys signing.KeySet, instances map[string]bool) (*models.CommitmentArtifact, []verifier.Finding) {
+	vr, _ := verifier.VerifyArtifactWithKeys(data, keys)
+	if !vr.Valid {
+		return nil, []verifier.Finding{bundleFinding(vr.Outcome, vr.Code, id, "artifact %s: %s", id, vr.Error)}
+	}
+	if vr.ArtifactID != id {
+		return nil, []verifier.Finding{bundleFinding(verifier.OutcomeInvalid, verifier.ReasonManifestMismatch, id, "artifact file %s.json holds artifact %s", id, vr.ArtifactID)}
+	}
+
+	var art models.CommitmentArtifact
+	_ = json.Unmarshal(data, &art) // Already parsed successfully by the verifier
+	if !instances[art.InstanceID] {
+		return nil, []verifier.Finding{bundleFinding(verifier.OutcomeInvalid, verifier.ReasonManifestMismatch, id, "artifact %s belongs to instance %s, which the manifest does not list", id, art.InstanceID)}
+	}
+	return &art, nil
+}
+
+// checkEvidence verifies one evidence member against its content address.
+func checkEvidence(id string, data []byte, instances map[string]bool) []verifier.Finding {
+	var ev models.ExecutionEvidence
+	if err := json.Unmarshal(data, &ev); err != nil {
+		return []verifier.Finding{bundleFinding(verifier.OutcomeInvalid, verifier.ReasonInvalidJSON, "", "evidence %s: %v", id, err)}
+	}
+	hash, err := ev.CalculateHash()
+	if err != nil {
+		return []verifier.Finding{bundleFinding(verifier.OutcomeInvalid, verifier.ReasonSchemaViolation, "", "evidence %s: %v", id, err)}
+	}
+	if hash != id || ev.EvidenceID != id {
+		return []verifier.Finding{bundleFinding(verifier.OutcomeInvalid, verifier.ReasonHashMismatch, "", "evidence %s: content hashes to %s (claimed %s)", id, hash, ev.EvidenceID)}
+	}
+	if !instances[ev.InstanceID] {
+		return []verifier.Finding{bundleFinding(verifier.OutcomeInvalid, verifier.ReasonManifestMismatch, "", "evidence %s belongs to instance %s, which the manifest does not list", id, ev.InstanceID)}
+	}
+	return nil
+}
+
+// finish records the findings and aggregates the outcome over findings and instances.
+func (res *Result) finish(findings ...verifier.Finding) *Result {
+	res.Findings = findings
+	outcomes := make([]verifier.Outcome, 0, len(findings)+len(res.Instances))
+	for _, f := range findings {
+		outcomes = append(outcomes, f.Outcome)
+	}
+	for _, inst := range res.Instances {
+		outcomes = append(outcomes, inst.Outcome)
+	}
+	if len(res.Instances) == 0 && len(findings) == 0 {
+		outcomes = append(outcomes, verifier.OutcomeInconclusive) // Nothing to vouch for
+	}
+	res.Outcome = verifier.WorstOutcome(outcomes...)
+	res.Valid = res.Outcome == verifier.OutcomeValid
+	return res
+}
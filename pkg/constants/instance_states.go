@@ -12,3 +12,26 @@ const (
 	StateCompleted       = "COMPLETED"
 	StateTerminated      = "TERMINATED"
 )
+
+// States lists every canonical instance state.
+var States = []string{
+	StateCreated,
+	StateRunning,
+	StateWaitingForHuman,
+	StateApproved,
+	StateRejected,
+	StateOverridden,
+	StateResumed,
+	StateCompleted,
+	StateTerminated,
+}
+
+// IsValidState reports whether s is a canonical instance state.
+func IsValidState(s string) bool {
+	for _, state := range States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
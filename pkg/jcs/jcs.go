@@ -0,0 +1,291 @@
+// Package jcs implements the JSON Canonicalization Scheme (RFC 8785).
+//
+// Canonical output is what every Gantral hash is computed over, so it must be
+// reproducible by verifiers and SDKs in any language:
+//   - Object members sorted by the UTF-16 code units of their names.
+//   - No insignificant whitespace.
+//   - Strings escape only '"', '\\' and control characters (no HTML escaping).
+//   - Numbers serialized as IEEE-754 doubles in ECMAScript Number.prototype.toString form.
+//
+// Input must be I-JSON (RFC 7493): valid UTF-8, no duplicate member names, and numbers
+// representable as finite doubles. Anything else is rejected rather than normalized.
+// This package depends only on the standard library.
+package jcs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrNotIJSON indicates input that cannot be canonicalized (RFC 8785 section 3.1).
+var ErrNotIJSON = errors.New("jcs: input is not I-JSON")
+
+// Marshal encodes v with encoding/json and returns its canonical form.
+func Marshal(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(raw)
+}
+
+// Canonicalize returns the canonical form of a JSON document.
+func Canonicalize(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: invalid UTF-8", ErrNotIJSON)
+	}
+	// encoding/json silently replaces lone surrogate escapes with U+FFFD; I-JSON forbids them.
+	if err := checkSurrogates(data); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // Keep the number text; we format it ourselves.
+
+	var buf bytes.Buffer
+	if err := writeValue(&buf, dec); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data after top-level value", ErrNotIJSON)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeValue reads exactly one JSON value from dec and writes its canonical form.
+func writeValue(buf *bytes.Buffer, dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotIJSON, err)
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return writeObject(buf, dec)
+		case '[':
+			return writeArray(buf, dec)
+		default:
+			return fmt.Errorf("%w: unexpected %q", ErrNotIJSON, t)
+		}
+	case string:
+		writeString(buf, t)
+	case json.Number:
+		s, err := formatNumber(string(t))
+		if err != nil {
+			return err
+		}
+		buf.WriteString(s)
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case nil:
+		buf.WriteString("null")
+	default:
+		return fmt.Errorf("%w: unexpected token %v", ErrNotIJSON, tok)
+	}
+	return nil
+}
+
+type member struct {
+	name  string
+	key   []uint16 // Sort key: UTF-16 code units
+	value []byte
+}
+
+func writeObject(buf *bytes.Buffer, dec *json.Decoder) error {
+	var members []member
+	seen := make(map[string]bool)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrNotIJSON, err)
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("%w: object key is not a string", ErrNotIJSON)
+		}
+		if seen[name] {
+			return fmt.Errorf("%w: duplicate member name %q", ErrNotIJSON, name)
+		}
+		seen[name] = true
+
+		var value bytes.Buffer
+		if err := writeValue(&value, dec); err != nil {
+			return err
+		}
+		members = append(members, member{
+			name:  name,
+			key:   utf16.Encode([]rune(name)),
+			value: value.Bytes(),
+		})
+	}
+	if _, err := dec.Token(); err != nil { // Closing '}'
+		return fmt.Errorf("%w: %v", ErrNotIJSON, err)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return lessUTF16(members[i].key, members[j].key)
+	})
+
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeString(buf, m.name)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func writeArray(buf *bytes.Buffer, dec *json.Decoder) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeValue(buf, dec); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // Closing ']'
+		return fmt.Errorf("%w: %v", ErrNotIJSON, err)
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func lessUTF16(a, b []uint16) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// writeString serializes a string per RFC 8785 section 3.2.2.2.
+func writeString(buf *bytes.Buffer, s string) {
+	const hex = "0123456789abcdef"
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte(hex[r>>4])
+				buf.WriteByte(hex[r&0xF])
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// formatNumber serializes a JSON number per RFC 8785 section 3.2.2.3
+// (ECMAScript Number.prototype.toString of the nearest IEEE-754 double).
+func formatNumber(text string) (string, error) {
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", fmt.Errorf("%w: number %s is not a finite IEEE-754 double", ErrNotIJSON, text)
+	}
+	if f == 0 {
+		return "0", nil // Also covers -0
+	}
+
+	abs := math.Abs(f)
+	if abs < 1e21 && abs >= 1e-6 {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+
+	// Exponential form: Go yields "1e+21" / "1.5e-07"; ECMAScript drops exponent padding.
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exp, _ := strings.Cut(s, "e")
+	sign := exp[0]
+	digits := strings.TrimLeft(exp[1:], "0")
+	return mantissa + "e" + string(sign) + digits, nil
+}
+
+// checkSurrogates rejects \u escapes that encode an unpaired UTF-16 surrogate.
+func checkSurrogates(data []byte) error {
+	inString := false
+	pendingHigh := false // The previous escape was a high surrogate
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if !inString {
+			inString = c == '"'
+			continue
+		}
+		if c == '"' {
+			if pendingHigh {
+				return fmt.Errorf("%w: unpaired surrogate escape", ErrNotIJSON)
+			}
+			inString = false
+			continue
+		}
+		if c != '\\' || i+1 >= len(data) {
+			if pendingHigh {
+				return fmt.Errorf("%w: unpaired surrogate escape", ErrNotIJSON)
+			}
+			continue
+		}
+
+		// Escape sequence
+		i++
+		if data[i] != 'u' || i+4 >= len(data) {
+			if pendingHigh {
+				return fmt.Errorf("%w: unpaired surrogate escape", ErrNotIJSON)
+			}
+			continue
+		}
+		code, err := strconv.ParseUint(string(data[i+1:i+5]), 16, 16)
+		if err != nil {
+			return fmt.Errorf("%w: malformed \\u escape", ErrNotIJSON)
+		}
+		i += 4
+		switch {
+		case code >= 0xD800 && code <= 0xDBFF:
+			if pendingHigh {
+				return fmt.Errorf("%w: unpaired surrogate escape", ErrNotIJSON)
+			}
+			pendingHigh = true
+		case code >= 0xDC00 && code <= 0xDFFF:
+			if !pendingHigh {
+				return fmt.Errorf("%w: unpaired surrogate escape", ErrNotIJSON)
+			}
+			pendingHigh = false
+		default:
+			if pendingHigh {
+				return fmt.Errorf("%w: unpaired surrogate escape", ErrNotIJSON)
+			}
+		}
+	}
+	return nil
+}
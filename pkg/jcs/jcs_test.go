@@ -0,0 +1,135 @@
+package jcs
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "RFC 8785 section 3.2.2",
+			in:   `{"numbers":[333333333.33333329,1E30,4.50,2e-3,0.000000000000000000000000001],"string":"\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/","literals":[null,true,false]}`,
+			want: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			name: "Sort By UTF-16 Code Units",
+			in:   `{"\u20ac":1,"\r":2,"\ufb33":3,"1":4,"\ud83d\ude00":5,"\u0080":6,"\u00f6":7}`,
+			want: "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}",
+		},
+		{
+			name: "No HTML Escaping",
+			in:   `{"a":"<&>"}`,
+			want: `{"a":"<&>"}`,
+		},
+		{
+			name: "Line Separators Unescaped",
+			in:   `["\u2028"]`,
+			want: "[\"\u2028\"]",
+		},
+		{
+			name: "Whitespace And Nesting",
+			in:   ` { "b" : [ 1 , { "z" : 1 , "a" : 2 } ] , "a" : { } } `,
+			want: `{"a":{},"b":[1,{"a":2,"z":1}]}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Canonicalize([]byte(tc.in))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("got  %s\nwant %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[string]string{
+		"-0":                      "0",
+		"1.0":                     "1",
+		"1e21":                    "1e+21",
+		"999999999999999900000":   "999999999999999900000",
+		"1e-7":                    "1e-7",
+		"0.000001":                "0.000001",
+		"9007199254740993":        "9007199254740992",
+		"5e-324":                  "5e-324",
+		"-1.7976931348623157e308": "-1.7976931348623157e+308",
+		"1e23":                    "1e+23",
+	}
+	for in, want := range tests {
+		got, err := formatNumber(in)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got %s, want %s", in, got, want)
+		}
+	}
+
+	if _, err := formatNumber("1e400"); !errors.Is(err, ErrNotIJSON) {
+		t.Errorf("expected overflow to be rejected, got %v", err)
+	}
+}
+
+func TestCanonicalize_RejectsNonIJSON(t *testing.T) {
+	tests := map[string][]byte{
+		"Duplicate Member": []byte(`{"a":1,"a":2}`),
+		"Lone High":        []byte(`["\ud800"]`),
+		"Lone Low":         []byte(`["\udc00"]`),
+		"High Then Char":   []byte(`["\ud800x"]`),
+		"Invalid UTF-8":    {'"', 0xff, '"'},
+		"Trailing Data":    []byte(`{} {}`),
+		"Malformed":        []byte(`{"a":}`),
+		"Number Overflow":  []byte(`[-1e400]`),
+		"Truncated Escape": []byte(`["\u00"]`),
+		"Empty Document":   {},
+	}
+	for name, in := range tests {
+		if _, err := Canonicalize(in); !errors.Is(err, ErrNotIJSON) {
+			t.Errorf("%s: expected ErrNotIJSON, got %v", name, err)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	got, err := Marshal(map[string]interface{}{
+		"b":   []int{3, 1, 2}, // Arrays keep their order
+		"a":   1.0,
+		"url": "https://example.com/?a=1&b=2",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"a":1,"b":[3,1,2],"url":"https://example.com/?a=1&b=2"}`
+	if string(got) != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+
+	if _, err := Marshal(math.NaN()); err == nil {
+		t.Error("expected NaN to be rejected")
+	}
+}
+
+func TestFormatNumber_RoundTrips(t *testing.T) {
+	// The canonical form must parse back to the same double.
+	for _, f := range []float64{0.1, 1.0 / 3, 123456789012345680000, 4.35, 1e-10, -2.5e-8, math.MaxFloat64, math.SmallestNonzeroFloat64} {
+		s, err := formatNumber(strconv.FormatFloat(f, 'g', -1, 64))
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", f, err)
+		}
+		back, err := strconv.ParseFloat(s, 64)
+		if err != nil || back != f {
+			t.Errorf("%v: %s does not round-trip (%v, %v)", f, s, back, err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package merkle
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Node identifies the complete subtree over the 2^Level leaves starting at leaf
+// Index<<Level. Level 0 is a single leaf.
+type Node struct {
+	Level uint
+	Index uint64
+}
+
+// NodeFunc returns the hash of a complete subtree. It lets proofs be built from stored
+// subtree hashes instead of every leaf.
+type NodeFunc func(n Node) ([]byte, error)
+
+// NodeHash returns the hash of the interior node over two children.
+func NodeHash(left, right []byte) []byte {
+	return nodeHash(left, right)
+}
+
+// CompactRange is the right edge of a tree that only grows: the hashes of its maximal
+// complete subtrees, largest first. It gives the root after every append in O(log n)
+// space and time, without keeping the leaves. The zero value is the empty tree.
+type CompactRange struct {
+	size   uint64
+	hashes [][]byte
+}
+
+// Size returns the number of leaves appended.
+func (c *CompactRange) Size() uint64 {
+	return c.size
+}
+
+// Append adds the next leaf hash. completed, if not nil, is called with every complete
+// subtree above the leaf that the leaf completes, lowest first.
+func (c *CompactRange) Append(leafHash []byte, completed func(n Node, hash []byte)) {
+	h, n := leafHash, Node{Index: c.size}
+	for c.size>>n.Level&1 == 1 {
+		last := len(c.hashes) - 1
+		h = nodeHash(c.hashes[last], h)
+		c.hashes = c.hashes[:last]
+		n = Node{Level: n.Level + 1, Index: n.Index >> 1}
+		if completed != nil {
+			completed(n, h)
+		}
+	}
+	c.hashes = append(c.hashes, h)
+	c.size++
+}
+
+// Root returns the Merkle tree hash over the leaves appended so far.
+func (c *CompactRange) Root() []byte {
+	if c.size == 0 {
+		return EmptyRoot()
+	}
+	return foldRight(c.hashes)
+}
+
+// foldRight combines the hashes of consecutive subtrees, largest first, into the hash of
+// the tree over all of them.
+func foldRight(hashes [][]byte) []byte {
+	r := hashes[len(hashes)-1]
+	for i := len(hashes) - 2; i >= 0; i-- {
+		r = nodeHash(hashes[i], r)
+	}
+	return r
+}
+
+// rangeHash returns the Merkle tree hash over the n leaves starting at begin. begin must
+// be aligned to the smallest power of two not below n, which holds for every subtree
+// RFC 6962 recurses into: the range is then its complete subtrees, largest first.
+func rangeHash(node NodeFunc, begin, n uint64) ([]byte, error) {
+	var hashes [][]byte
+	for n > 0 {
+		level := uint(bits.Len64(n) - 1)
+		h, err := node(Node{Level: level, Index: begin >> level})
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, h)
+		begin += 1 << level
+		n -= 1 << level
+	}
+	return foldRight(hashes), nil
+}
+
+// split64 returns the largest power of two strictly smaller than n (n > 1).
+func split64(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// InclusionProofFrom returns the audit path of leaf index in the tree of the given size,
+// reading O(log² n) subtree hashes through node. It equals InclusionProof over the leaves.
+func InclusionProofFrom(node NodeFunc, size, index uint64) ([][]byte, error) {
+	if index >= size {
+		return nil, fmt.Errorf("merkle: leaf index %d out of range for tree size %d", index, size)
+	}
+	return pathFrom(node, index, 0, size)
+}
+
+func pathFrom(node NodeFunc, m, begin, n uint64) ([][]byte, error) {
+	if n <= 1 {
+		return nil, nil
+	}
+	k := split64(n)
+	var p [][]byte
+	var sibling []byte
+	var err error
+	if m < k {
+		if p, err = pathFrom(node, m, begin, k); err != nil {
+			return nil, err
+		}
+		sibling, err = rangeHash(node, begin+k, n-k)
+	} else {
+		if p, err = pathFrom(node, m-k, begin+k, n-k); err != nil {
+			return nil, err
+		}
+		sibling, err = rangeHash(node, begin, k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(p, sibling), nil
+}
+
+// ConsistencyProofFrom returns the proof that the tree of size1 is a prefix of the tree of
+// size2, reading subtree hashes through node. It equals ConsistencyProof over the leaves.
+func ConsistencyProofFrom(node NodeFunc, size1, size2 uint64) ([][]byte, error) {
+	if size1 > size2 {
+		return nil, fmt.Errorf("merkle: tree size %d exceeds %d", size1, size2)
+	}
+	if size1 == 0 || size1 == size2 {
+		return nil, nil
+	}
+	return subproofFrom(node, size1, 0, size2, true)
+}
+
+func subproofFrom(node NodeFunc, m, begin, n uint64, complete bool) ([][]byte, error) {
+	if m == n {
+		if complete {
+			return nil, nil
+		}
+		h, err := rangeHash(node, begin, n)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{h}, nil
+	}
+	k := split64(n)
+	var p [][]byte
+	var sibling []byte
+	var err error
+	if m <= k {
+		if p, err = subproofFrom(node, m, begin, k, complete); err != nil {
+			return nil, err
+		}
+		sibling, err = rangeHash(node, begin+k, n-k)
+	} else {
+		if p, err = subproofFrom(node, m-k, begin+k, n-k, false); err != nil {
+			return nil, err
+		}
+		sibling, err = rangeHash(node, begin, k)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(p, sibling), nil
+}
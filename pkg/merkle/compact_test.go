@@ -0,0 +1,119 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// leafNodes serves complete subtree hashes from the leaves, counting the reads.
+func leafNodes(leaves [][]byte, reads *int) NodeFunc {
+	return func(n Node) ([]byte, error) {
+		*reads++
+		begin, end := n.Index<<n.Level, (n.Index+1)<<n.Level
+		if end > uint64(len(leaves)) {
+			return nil, fmt.Errorf("node %+v beyond %d leaves", n, len(leaves))
+		}
+		return Root(leaves[begin:end]), nil
+	}
+}
+
+func TestCompactRange_MatchesRoot(t *testing.T) {
+	leaves := leafHashes(t, 33)
+	completed := map[Node][]byte{}
+
+	var c CompactRange
+	if !bytes.Equal(c.Root(), EmptyRoot()) {
+		t.Error("empty range: expected the empty root")
+	}
+	for i, leaf := range leaves {
+		c.Append(leaf, func(n Node, hash []byte) { completed[n] = hash })
+		if c.Size() != uint64(i+1) {
+			t.Fatalf("expected size %d, got %d", i+1, c.Size())
+		}
+		if !bytes.Equal(c.Root(), Root(leaves[:i+1])) {
+			t.Errorf("size %d: root differs from Root", i+1)
+		}
+	}
+
+	// Every complete subtree above the leaves is reported once, with its hash.
+	var reads int
+	node := leafNodes(leaves, &reads)
+	for level := uint(1); 1<<level <= len(leaves); level++ {
+		for index := uint64(0); (index+1)<<level <= uint64(len(leaves)); index++ {
+			n := Node{Level: level, Index: index}
+			want, _ := node(n)
+			if !bytes.Equal(completed[n], want) {
+				t.Errorf("node %+v: not reported or wrong hash", n)
+			}
+		}
+	}
+}
+
+func TestProofsFrom_MatchLeafProofs(t *testing.T) {
+	leaves := leafHashes(t, 21)
+	var reads int
+	node := leafNodes(leaves, &reads)
+
+	for n := 1; n <= len(leaves); n++ {
+		for i := 0; i < n; i++ {
+			want, _ := InclusionProof(leaves[:n], uint64(i))
+			got, err := InclusionProofFrom(node, uint64(n), uint64(i))
+			if err != nil {
+				t.Fatalf("InclusionProofFrom(%d, %d): %v", n, i, err)
+			}
+			if !equalProofs(got, want) {
+				t.Errorf("size %d index %d: inclusion proof differs", n, i)
+			}
+		}
+		for m := 0; m <= n; m++ {
+			want, _ := ConsistencyProof(leaves[:n], uint64(m))
+			got, err := ConsistencyProofFrom(node, uint64(m), uint64(n))
+			if err != nil {
+				t.Fatalf("ConsistencyProofFrom(%d, %d): %v", m, n, err)
+			}
+			if !equalProofs(got, want) {
+				t.Errorf("(%d, %d): consistency proof differs", m, n)
+			}
+		}
+	}
+
+	if _, err := InclusionProofFrom(node, 3, 3); err == nil {
+		t.Error("expected out-of-range index to be rejected")
+	}
+	if _, err := ConsistencyProofFrom(node, 4, 3); err == nil {
+		t.Error("expected shrinking tree to be rejected")
+	}
+}
+
+func TestProofsFrom_ReadLogarithmicNodes(t *testing.T) {
+	leaves := leafHashes(t, 1000)
+	var reads int
+	node := leafNodes(leaves, &reads)
+
+	if _, err := InclusionProofFrom(node, 1000, 617); err != nil {
+		t.Fatal(err)
+	}
+	if reads > 100 {
+		t.Errorf("inclusion proof read %d nodes", reads)
+	}
+	reads = 0
+	if _, err := ConsistencyProofFrom(node, 617, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if reads > 100 {
+		t.Errorf("consistency proof read %d nodes", reads)
+	}
+}
+
+func equalProofs(a, b [][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !bytes.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,206 @@
+// Package merkle implements the Merkle tree hashing, audit paths and consistency
+// proofs of RFC 6962 (section 2.1), with the proof verification algorithms of
+// RFC 9162 (sections 2.1.3.2 and 2.1.4.2).
+//
+// Leaves are hashed as SHA-256(0x00 || data) and interior nodes as
+// SHA-256(0x01 || left || right), so a leaf can never be confused with a node.
+// This package depends only on the standard library.
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// HashSize is the size of every hash in the tree.
+const HashSize = sha256.Size
+
+// ErrInvalidProof indicates a proof that does not verify against the given roots.
+var ErrInvalidProof = errors.New("merkle: invalid proof")
+
+// LeafHash returns the hash of a leaf entry.
+func LeafHash(data []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// nodeHash returns the hash of an interior node.
+func nodeHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// EmptyRoot is the root of the tree with no leaves: SHA-256 of the empty string.
+func EmptyRoot() []byte {
+	sum := sha256.Sum256(nil)
+	return sum[:]
+}
+
+// Root returns the Merkle tree hash (MTH) over the given leaf hashes.
+func Root(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return EmptyRoot()
+	}
+	return mth(leaves)
+}
+
+func mth(leaves [][]byte) []byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+	k := split(len(leaves))
+	return nodeHash(mth(leaves[:k]), mth(leaves[k:]))
+}
+
+// split returns the largest power of two strictly smaller than n (n > 1).
+func split(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
+
+// InclusionProof returns the audit path of leaf index in the tree over leaves.
+func InclusionProof(leaves [][]byte, index uint64) ([][]byte, error) {
+	if index >= uint64(len(leaves)) {
+		return nil, fmt.Errorf("merkle: leaf index %d out of range for tree size %d", index, len(leaves))
+	}
+	return path(int(index), leaves), nil
+}
+
+func path(m int, leaves [][]byte) [][]byte {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := split(len(leaves))
+	if m < k {
+		return append(path(m, leaves[:k]), mth(leaves[k:]))
+	}
+	return append(path(m-k, leaves[k:]), mth(leaves[:k]))
+}
+
+// ConsistencyProof returns the proof that the tree over the first size1 leaves is a
+// prefix of the tree over all leaves.
+func ConsistencyProof(leaves [][]byte, size1 uint64) ([][]byte, error) {
+	if size1 > uint64(len(leaves)) {
+		return nil, fmt.Errorf("merkle: tree size %d exceeds %d", size1, len(leaves))
+	}
+	if size1 == 0 || size1 == uint64(len(leaves)) {
+		return nil, nil
+	}
+	return subproof(int(size1), leaves, true), nil
+}
+
+func subproof(m int, leaves [][]byte, complete bool) [][]byte {
+	n := len(leaves)
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{mth(leaves)}
+	}
+	k := split(n)
+	if m <= k {
+		return append(subproof(m, leaves[:k], complete), mth(leaves[k:]))
+	}
+	return append(subproof(m-k, leaves[k:], false), mth(leaves[:k]))
+}
+
+// VerifyInclusion checks that leafHash is at index in the tree of the given size and root.
+func VerifyInclusion(leafHash []byte, index, size uint64, proof [][]byte, root []byte) error {
+	if index >= size {
+		return fmt.Errorf("%w: leaf index %d out of range for tree size %d", ErrInvalidProof, index, size)
+	}
+
+	fn, sn := index, size-1
+	r := leafHash
+	for _, p := range proof {
+		if sn == 0 {
+			return fmt.Errorf("%w: audit path too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			r = nodeHash(p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: audit path too short", ErrInvalidProof)
+	}
+	if !bytes.Equal(r, root) {
+		return fmt.Errorf("%w: calculated root does not match", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree (size1, root1) is a prefix of the tree (size2, root2).
+func VerifyConsistency(size1, size2 uint64, proof [][]byte, root1, root2 []byte) error {
+	switch {
+	case size1 > size2:
+		return fmt.Errorf("%w: tree size %d is larger than %d", ErrInvalidProof, size1, size2)
+	case size1 == size2:
+		if len(proof) != 0 || !bytes.Equal(root1, root2) {
+			return fmt.Errorf("%w: trees of equal size must have equal roots and an empty proof", ErrInvalidProof)
+		}
+		return nil
+	case size1 == 0:
+		// Every tree extends the empty tree.
+		if len(proof) != 0 || !bytes.Equal(root1, EmptyRoot()) {
+			return fmt.Errorf("%w: the empty tree has no consistency proof", ErrInvalidProof)
+		}
+		return nil
+	}
+
+	// A complete first tree is itself a node of the second: it opens the proof.
+	if size1&(size1-1) == 0 {
+		proof = append([][]byte{root1}, proof...)
+	}
+	if len(proof) == 0 {
+		return fmt.Errorf("%w: empty consistency proof", ErrInvalidProof)
+	}
+
+	fn, sn := size1-1, size2-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return fmt.Errorf("%w: consistency proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = nodeHash(c, fr)
+			sr = nodeHash(c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: consistency proof too short", ErrInvalidProof)
+	}
+	if !bytes.Equal(fr, root1) {
+		return fmt.Errorf("%w: first root does not match", ErrInvalidProof)
+	}
+	if !bytes.Equal(sr, root2) {
+		return fmt.Errorf("%w: second root does not match", ErrInvalidProof)
+	}
+	return nil
+}
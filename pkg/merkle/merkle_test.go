@@ -0,0 +1,119 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// Leaf inputs and roots from the RFC 6962 reference test data (certificate-transparency).
+var (
+	testLeaves = []string{"", "00", "10", "2021", "3031", "40414243", "5051525354555657", "606162636465666768696a6b6c6d6e6f"}
+	testRoots  = []string{
+		"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+		"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+		"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+		"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+		"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+		"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+		"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+	}
+)
+
+// leafHashes returns n leaf hashes: the reference inputs first, then distinct filler entries.
+func leafHashes(t *testing.T, n int) [][]byte {
+	t.Helper()
+	var leaves [][]byte
+	for i := 0; i < n; i++ {
+		data := []byte{0xFF, byte(i)}
+		if i < len(testLeaves) {
+			var err error
+			if data, err = hex.DecodeString(testLeaves[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		leaves = append(leaves, LeafHash(data))
+	}
+	return leaves
+}
+
+func TestRoot_ReferenceVectors(t *testing.T) {
+	if got := hex.EncodeToString(Root(nil)); got != "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Errorf("empty root: got %s", got)
+	}
+	leaves := leafHashes(t, len(testLeaves))
+	for n := 1; n <= len(leaves); n++ {
+		if got := hex.EncodeToString(Root(leaves[:n])); got != testRoots[n-1] {
+			t.Errorf("size %d: expected root %s, got %s", n, testRoots[n-1], got)
+		}
+	}
+}
+
+func TestInclusion_AllLeaves(t *testing.T) {
+	leaves := leafHashes(t, 21)
+	for n := 1; n <= len(leaves); n++ {
+		root := Root(leaves[:n])
+		for i := 0; i < n; i++ {
+			proof, err := InclusionProof(leaves[:n], uint64(i))
+			if err != nil {
+				t.Fatalf("InclusionProof(%d, %d): %v", i, n, err)
+			}
+			if err := VerifyInclusion(leaves[i], uint64(i), uint64(n), proof, root); err != nil {
+				t.Errorf("VerifyInclusion(%d, %d): %v", i, n, err)
+			}
+
+			// Wrong leaf, wrong index and truncated proofs must all fail.
+			other := leaves[(i+1)%len(leaves)]
+			if err := VerifyInclusion(other, uint64(i), uint64(n), proof, root); n > 1 && !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("size %d index %d: wrong leaf accepted", n, i)
+			}
+			if n > 1 {
+				if err := VerifyInclusion(leaves[i], uint64((i+1)%n), uint64(n), proof, root); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("size %d index %d: wrong index accepted", n, i)
+				}
+				if err := VerifyInclusion(leaves[i], uint64(i), uint64(n), proof[:len(proof)-1], root); !errors.Is(err, ErrInvalidProof) {
+					t.Errorf("size %d index %d: truncated proof accepted", n, i)
+				}
+			}
+		}
+	}
+
+	if _, err := InclusionProof(leaves[:3], 3); err == nil {
+		t.Error("expected out-of-range index to be rejected")
+	}
+}
+
+func TestConsistency_AllSizes(t *testing.T) {
+	leaves := leafHashes(t, 21)
+	for n := 1; n <= len(leaves); n++ {
+		root2 := Root(leaves[:n])
+		for m := 0; m <= n; m++ {
+			root1 := Root(leaves[:m])
+			proof, err := ConsistencyProof(leaves[:n], uint64(m))
+			if err != nil {
+				t.Fatalf("ConsistencyProof(%d, %d): %v", m, n, err)
+			}
+			if err := VerifyConsistency(uint64(m), uint64(n), proof, root1, root2); err != nil {
+				t.Errorf("VerifyConsistency(%d, %d): %v", m, n, err)
+			}
+
+			if m == 0 || m == n {
+				continue
+			}
+			// A rewritten history (different first root) must not verify.
+			forged := bytes.Repeat([]byte{0xAB}, HashSize)
+			if err := VerifyConsistency(uint64(m), uint64(n), proof, forged, root2); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("(%d, %d): forged first root accepted", m, n)
+			}
+			if err := VerifyConsistency(uint64(m), uint64(n), proof, root1, forged); !errors.Is(err, ErrInvalidProof) {
+				t.Errorf("(%d, %d): forged second root accepted", m, n)
+			}
+		}
+	}
+
+	if err := VerifyConsistency(5, 4, nil, nil, nil); !errors.Is(err, ErrInvalidProof) {
+		t.Error("expected shrinking tree to be rejected")
+	}
+}
@@ -6,12 +6,27 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
 )
 
-// SchemaVersionV1 defines the current schema version for commitment artifacts.
+// SchemaVersionV1 defines the original schema version for commitment artifacts.
 const SchemaVersionV1 = "v1"
 
+// SchemaVersionV2 adds the policy input and decision hashes (specs/04-policy-engine.md).
+const SchemaVersionV2 = "v2"
+
+// SchemaVersionV3 canonicalizes the payload with RFC 8785 (JCS) instead of encoding/json.
+// The policy hashes are optional in v3 (both or neither), so unbound artifacts use it too.
+const SchemaVersionV3 = "v3"
+
+// ErrUnsupportedVersion indicates an artifact_version whose major version is not understood.
+// Per specs/08 section 12, such artifacts MUST be rejected rather than guessed at.
+var ErrUnsupportedVersion = errors.New("unsupported artifact_version")
+
 // GenesisHash is the SHA-256 hash of comparable length (64 chars) consisting of zeros.
 // It is used as the PrevArtifactHash for the first artifact in a chain.
 const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
@@ -48,6 +63,54 @@ type CommitmentArtifact struct {
 
 	// Timestamp is the exact time of emission (RFC3339).
 	Timestamp string `json:"timestamp"`
+
+	// PolicyInputHash is the SHA256 hash of the exact input handed to the policy engine (v2+).
+	PolicyInputHash string `json:"policy_input_hash,omitempty"`
+
+	// PolicyDecisionHash is the SHA256 hash of the exact decision returned by the policy engine (v2+).
+	PolicyDecisionHash string `json:"policy_decision_hash,omitempty"`
+
+	// KeyID identifies the key that produced Signature (see pkg/signing).
+	KeyID string `json:"key_id,omitempty"`
+
+	// Signature is the base64 Ed25519 signature over the canonical payload.
+	// It is not part of the canonical payload itself (it signs it).
+	Signature string `json:"signature,omitempty"`
+}
+
+// PolicyBinding carries the policy evaluation hashes bound into a v2/v3 artifact.
+type PolicyBinding struct {
+	InputHash    string `json:"policy_input_hash"`
+	DecisionHash string `json:"policy_decision_hash"`
+}
+
+// LatestMajorVersion is the newest artifact schema major version this build understands.
+const LatestMajorVersion = 3
+
+// CheckVersion rejects artifact versions whose major version is not understood.
+// Minor versions are backward compatible and accepted. An empty version is legacy v1.
+func CheckVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	major, err := MajorVersion(version)
+	if err != nil {
+		return err
+	}
+	if major > LatestMajorVersion {
+		return fmt.Errorf("%w: %q (latest supported: v%d)", ErrUnsupportedVersion, version, LatestMajorVersion)
+	}
+	return nil
+}
+
+// MajorVersion parses the major component of an artifact_version ("v2", "v2.1", "2.1.0").
+func MajorVersion(version string) (int, error) {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	n, err := strconv.Atoi(major)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%w: %q", ErrUnsupportedVersion, version)
+	}
+	return n, nil
 }
 
 // NewCommitmentArtifact creates a new artifact with the given fields.
@@ -73,6 +136,39 @@ func NewCommitmentArtifact(
 	}
 }
 
+// NewCommitmentArtifactV2 creates a v2 artifact that additionally binds the policy evaluation hashes.
+func NewCommitmentArtifactV2(
+	instanceID string,
+	prevArtifactHash string,
+	authorityState string,
+	policyVersionID string,
+	contextHash string,
+	humanActorID string,
+	binding PolicyBinding,
+) *CommitmentArtifact {
+	a := NewCommitmentArtifact(instanceID, prevArtifactHash, authorityState, policyVersionID, contextHash, humanActorID)
+	a.ArtifactVersion = SchemaVersionV2
+	a.PolicyInputHash = binding.InputHash
+	a.PolicyDecisionHash = binding.DecisionHash
+	return a
+}
+
+// NewCommitmentArtifactV3 creates a v3 artifact. A zero binding produces an artifact
+// without policy hashes (genesis and lifecycle transitions).
+func NewCommitmentArtifactV3(
+	instanceID string,
+	prevArtifactHash string,
+	authorityState string,
+	policyVersionID string,
+	contextHash string,
+	humanActorID string,
+	binding PolicyBinding,
+) *CommitmentArtifact {
+	a := NewCommitmentArtifactV2(instanceID, prevArtifactHash, authorityState, policyVersionID, contextHash, humanActorID, binding)
+	a.ArtifactVersion = SchemaVersionV3
+	return a
+}
+
 // CalculateHashAndSetID computes the SHA256 hash of the canonical payload
 // and sets both ArtifactHash and ArtifactID.
 // It returns an error if serialization fails.
@@ -90,8 +186,8 @@ func (a *CommitmentArtifact) CalculateHashAndSetID() error {
 }
 
 // CanonicalPayload returns the strictly deterministic JSON bytes used for hashing.
-// It uses a map to ensure keys are sorted alphabetically by encoding/json,
-// guaranteeing a stable hash regardless of struct field order.
+// v1 and v2 marshal a map with encoding/json (sorted keys, HTML escaping); v3 and later
+// use RFC 8785 (JCS) so that verifiers in other languages can reproduce the bytes.
 func (a *CommitmentArtifact) CanonicalPayload() ([]byte, error) {
 	// 1. Validate required fields (Fail-Closed)
 	if a.InstanceID == "" {
@@ -111,7 +207,32 @@ func (a *CommitmentArtifact) CanonicalPayload() ([]byte, error) {
 	// We allow empty string or GenesisHash. Ideally, use models.GenesisHash.
 	// For now, we allow it but ensure it's included in the map.
 
-	// 2. Construct map for sorted keys
+	// 2. Construct map for sorted keys (field set depends on the schema version)
+	msg, major, err := a.canonicalFields()
+	if err != nil {
+		return nil, fmt.Errorf("canonical payload: %w", err)
+	}
+
+	// 3. Serialize. Pre-v3 hashes are frozen to the encoding/json bytes they were sealed with.
+	if major < 3 {
+		return json.Marshal(msg)
+	}
+	return jcs.Marshal(msg)
+}
+
+// canonicalFields returns the hashed field set for the artifact's schema version.
+// Minor versions share the field set of their major version.
+func (a *CommitmentArtifact) canonicalFields() (map[string]string, int, error) {
+	// Artifacts predating explicit versioning (empty artifact_version) hash as v1.
+	// The empty value is still part of the hashed payload, so it cannot be swapped later.
+	if err := CheckVersion(a.ArtifactVersion); err != nil {
+		return nil, 0, err
+	}
+	major := 1
+	if a.ArtifactVersion != "" {
+		major, _ = MajorVersion(a.ArtifactVersion)
+	}
+
 	msg := map[string]string{
 		"artifact_version":   a.ArtifactVersion,
 		"instance_id":        a.InstanceID,
@@ -123,8 +244,36 @@ func (a *CommitmentArtifact) CanonicalPayload() ([]byte, error) {
 		"timestamp":          a.Timestamp,
 	}
 
-	// 3. Marshal with standard library (sorts map keys)
-	return json.Marshal(msg)
+	switch major {
+	case 1:
+		// v1 does not hash these fields; carrying them would be unauthenticated content.
+		if a.PolicyInputHash != "" || a.PolicyDecisionHash != "" {
+			return nil, 0, errors.New("policy hashes are not part of artifact_version v1")
+		}
+		return msg, major, nil
+	case 2:
+		if a.PolicyInputHash == "" {
+			return nil, 0, errors.New("missing policy_input_hash")
+		}
+		if a.PolicyDecisionHash == "" {
+			return nil, 0, errors.New("missing policy_decision_hash")
+		}
+		msg["policy_input_hash"] = a.PolicyInputHash
+		msg["policy_decision_hash"] = a.PolicyDecisionHash
+		return msg, major, nil
+	case 3:
+		// Policy hashes are optional, but a half binding is never valid.
+		if (a.PolicyInputHash == "") != (a.PolicyDecisionHash == "") {
+			return nil, 0, errors.New("policy_input_hash and policy_decision_hash must be set together")
+		}
+		if a.PolicyInputHash != "" {
+			msg["policy_input_hash"] = a.PolicyInputHash
+			msg["policy_decision_hash"] = a.PolicyDecisionHash
+		}
+		return msg, major, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: %q", ErrUnsupportedVersion, a.ArtifactVersion)
+	}
 }
 
 // MarshalJSON implements the json.Marshaler interface to ensure
@@ -146,5 +295,14 @@ func (a *CommitmentArtifact) MarshalJSON() ([]byte, error) {
 		"human_actor_id":     a.HumanActorID,
 		"timestamp":          a.Timestamp,
 	}
+	// v2 fields are only serialized when present, so v1 artifacts keep their exact shape.
+	if a.PolicyInputHash != "" || a.PolicyDecisionHash != "" {
+		msg["policy_input_hash"] = a.PolicyInputHash
+		msg["policy_decision_hash"] = a.PolicyDecisionHash
+	}
+	if a.KeyID != "" || a.Signature != "" {
+		msg["key_id"] = a.KeyID
+		msg["signature"] = a.Signature
+	}
 	return json.Marshal(msg)
 }
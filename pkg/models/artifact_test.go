@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
 )
@@ -115,3 +116,106 @@ func TestArtifact_MarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestArtifact_V2_BindsPolicyHashes(t *testing.T) {
+	binding := PolicyBinding{InputHash: "in-1", DecisionHash: "dec-1"}
+	art := NewCommitmentArtifactV2("inst-1", GenesisHash, "APPROVED", "pv", "ctx", "user-1", binding)
+	art.Timestamp = "2024-01-01T00:00:00Z"
+
+	if art.ArtifactVersion != SchemaVersionV2 {
+		t.Fatalf("expected version %s, got %s", SchemaVersionV2, art.ArtifactVersion)
+	}
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	original := art.ArtifactID
+
+	// Every policy hash must be covered by the artifact hash.
+	tampered := *art
+	tampered.PolicyDecisionHash = "dec-2"
+	if err := tampered.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tampered.ArtifactID == original {
+		t.Error("changing policy_decision_hash did not change the artifact hash")
+	}
+
+	// v2 fails closed without a complete binding.
+	incomplete := *art
+	incomplete.PolicyInputHash = ""
+	if _, err := incomplete.CanonicalPayload(); err == nil || !strings.Contains(err.Error(), "policy_input_hash") {
+		t.Errorf("expected missing policy_input_hash error, got %v", err)
+	}
+}
+
+func TestArtifact_Versioning(t *testing.T) {
+	base := func(version string) *CommitmentArtifact {
+		return &CommitmentArtifact{
+			ArtifactVersion: version,
+			InstanceID:      "inst-1",
+			AuthorityState:  "APPROVED",
+			ContextHash:     "ctx",
+			Timestamp:       "2024-01-01T00:00:00Z",
+		}
+	}
+
+	t.Run("Minor Version Accepted", func(t *testing.T) {
+		if _, err := base("v1.1").CanonicalPayload(); err != nil {
+			t.Errorf("unexpected error for minor version: %v", err)
+		}
+	})
+
+	t.Run("Unknown Major Rejected", func(t *testing.T) {
+		for _, v := range []string{"v4", "v99.0", "banana"} {
+			if _, err := base(v).CanonicalPayload(); !errors.Is(err, ErrUnsupportedVersion) {
+				t.Errorf("%s: expected ErrUnsupportedVersion, got %v", v, err)
+			}
+		}
+	})
+
+	t.Run("V1 Rejects Unhashed V2 Fields", func(t *testing.T) {
+		art := base(SchemaVersionV1)
+		art.PolicyInputHash = "in-1"
+		if _, err := art.CanonicalPayload(); err == nil {
+			t.Error("expected v1 artifact carrying policy hashes to be rejected")
+		}
+	})
+
+	t.Run("V3 Policy Hashes Optional But Paired", func(t *testing.T) {
+		if _, err := base(SchemaVersionV3).CanonicalPayload(); err != nil {
+			t.Errorf("unexpected error for unbound v3 artifact: %v", err)
+		}
+		art := base(SchemaVersionV3)
+		art.PolicyDecisionHash = "dec-1"
+		if _, err := art.CanonicalPayload(); err == nil {
+			t.Error("expected half policy binding to be rejected")
+		}
+	})
+
+	t.Run("V3 Uses JCS", func(t *testing.T) {
+		// encoding/json escapes '&' as \u0026; RFC 8785 does not.
+		v2 := base(SchemaVersionV2)
+		v2.HumanActorID, v2.PolicyInputHash, v2.PolicyDecisionHash = "a&b", "in", "dec"
+		v3 := *v2
+		v3.ArtifactVersion = SchemaVersionV3
+
+		p2, _ := v2.CanonicalPayload()
+		p3, err := v3.CanonicalPayload()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(string(p2), `a\u0026b`) {
+			t.Errorf("v2 payload must keep encoding/json bytes: %s", p2)
+		}
+		if !strings.Contains(string(p3), `"a&b"`) {
+			t.Errorf("v3 payload must be JCS: %s", p3)
+		}
+	})
+
+	t.Run("V1 JSON Shape Unchanged", func(t *testing.T) {
+		b, _ := json.Marshal(base(SchemaVersionV1))
+		if strings.Contains(string(b), "policy_input_hash") {
+			t.Errorf("v1 artifact must not serialize v2 fields: %s", b)
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"time"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
 )
 
 // ExecutionEvidence represents the raw data of a tool execution.
@@ -42,7 +44,8 @@ func (e *ExecutionEvidence) CanonicalPayload() ([]byte, error) {
 		return nil, errors.New("missing tool_name")
 	}
 
-	// We create a map to ensure consistent key ordering (standard lib guarantees sorted keys)
+	// Canonicalized with RFC 8785 (JCS); the raw payloads are canonicalized too, so
+	// re-serializing the tool input/output does not change the hash.
 	// EvidenceID is EXCLUDED from the hash because it is derived FROM the hash.
 	payload := map[string]interface{}{
 		"instance_id":    e.InstanceID,
@@ -52,7 +55,7 @@ func (e *ExecutionEvidence) CanonicalPayload() ([]byte, error) {
 		"timestamp":      e.Timestamp,
 	}
 
-	return json.Marshal(payload)
+	return jcs.Marshal(payload)
 }
 
 // CalculateHashAndSetID computes the SHA-256 hash and sets it as the EvidenceID.
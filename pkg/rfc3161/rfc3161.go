@@ -0,0 +1,348 @@
+// Package rfc3161 is a minimal RFC 3161 time-stamp protocol client and token verifier.
+//
+// Only what anchoring needs is supported: SHA-256 message imprints, tokens signed
+// with a signer identified by issuer and serial number, and RSA, ECDSA or Ed25519
+// signatures over signed attributes. Tokens are kept as DER so that standard tools
+// (e.g. `openssl ts -verify`) can check them too.
+//
+// This package depends only on the standard library.
+package rfc3161
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+const (
+	// ContentTypeQuery and ContentTypeReply are the RFC 3161 HTTP media types.
+	ContentTypeQuery = "application/timestamp-query"
+	ContentTypeReply = "application/timestamp-reply"
+
+	maxReplySize = 1 << 20
+)
+
+var (
+	// ErrRejected indicates a response whose status is neither granted nor grantedWithMods.
+	ErrRejected = errors.New("time-stamp request rejected")
+	// ErrInvalidToken indicates a token that is malformed, does not cover the expected
+	// digest, or whose signature or certificate chain does not verify.
+	ErrInvalidToken = errors.New("invalid time-stamp token")
+)
+
+var (
+	OIDSHA256        = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	OIDSignedData    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	OIDTSTInfo       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	OIDContentType   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	OIDMessageDigest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// ASN.1 structures (RFC 3161, RFC 5652). Exported ones are shared with rfc3161test.
+
+type MessageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+type Request struct {
+	Version        int
+	MessageImprint MessageImprint
+	ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+	Nonce          *big.Int              `asn1:"optional"`
+	CertReq        bool                  `asn1:"optional,default:false"`
+}
+
+type StatusInfo struct {
+	Status       int
+	StatusString asn1.RawValue  `asn1:"optional"`
+	FailInfo     asn1.BitString `asn1:"optional"`
+}
+
+type Response struct {
+	Status         StatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+type ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,tag:0"`
+}
+
+type EncapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     []byte `asn1:"explicit,optional,tag:0"`
+}
+
+type SignedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo EncapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []SignerInfo  `asn1:"set"`
+}
+
+type IssuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type SignerInfo struct {
+	Version            int
+	SID                IssuerAndSerial
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type Attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+type Accuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"optional,tag:0"`
+	Micros  int `asn1:"optional,tag:1"`
+}
+
+type TSTInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint MessageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time     `asn1:"generalized"`
+	Accuracy       Accuracy      `asn1:"optional"`
+	Ordering       bool          `asn1:"optional,default:false"`
+	Nonce          *big.Int      `asn1:"optional"`
+	TSA            asn1.RawValue `asn1:"optional,tag:0"`
+	Extensions     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// Token is a verified time-stamp token.
+type Token struct {
+	DER     []byte    // The TimeStampToken (CMS ContentInfo)
+	GenTime time.Time // When the TSA saw the digest
+	Serial  *big.Int
+	Signer  *x509.Certificate
+	nonce   *big.Int
+}
+
+// Client requests time-stamp tokens from a TSA over HTTP.
+type Client struct {
+	URL   string
+	HTTP  *http.Client   // Defaults to http.DefaultClient
+	Roots *x509.CertPool // Trusted TSA roots; nil checks the signature against the embedded certificate only
+}
+
+// Timestamp asks the TSA to time-stamp a SHA-256 digest and verifies the returned token.
+func (c *Client) Timestamp(ctx context.Context, digest []byte) (*Token, error) {
+	if len(digest) != sha256.Size {
+		return nil, fmt.Errorf("digest must be %d bytes", sha256.Size)
+	}
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+	query, err := asn1.Marshal(Request{
+		Version:        1,
+		MessageImprint: MessageImprint{HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: OIDSHA256}, HashedMessage: digest},
+		Nonce:          nonce,
+		CertReq:        true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(query))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", ContentTypeQuery)
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("time-stamp request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("time-stamp request failed: TSA returned %s", resp.Status)
+	}
+	reply, err := io.ReadAll(io.LimitReader(resp.Body, maxReplySize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read time-stamp reply: %w", err)
+	}
+
+	der, err := ParseResponse(reply)
+	if err != nil {
+		return nil, err
+	}
+	token, err := Verify(der, digest, c.Roots)
+	if err != nil {
+		return nil, err
+	}
+	if token.nonce == nil || token.nonce.Cmp(nonce) != 0 {
+		return nil, fmt.Errorf("%w: nonce does not match the request", ErrInvalidToken)
+	}
+	return token, nil
+}
+
+// ParseResponse checks a TimeStampResp and returns its token.
+func ParseResponse(reply []byte) ([]byte, error) {
+	var resp Response
+	if rest, err := asn1.Unmarshal(reply, &resp); err != nil || len(rest) > 0 {
+		return nil, fmt.Errorf("%w: malformed time-stamp reply", ErrInvalidToken)
+	}
+	if resp.Status.Status > 1 { // 0 granted, 1 grantedWithMods
+		return nil, fmt.Errorf("%w: status %d", ErrRejected, resp.Status.Status)
+	}
+	if len(resp.TimeStampToken.FullBytes) == 0 {
+		return nil, fmt.Errorf("%w: granted reply without a token", ErrInvalidToken)
+	}
+	return resp.TimeStampToken.FullBytes, nil
+}
+
+// Verify checks that a DER TimeStampToken covers the SHA-256 digest and is signed by
+// the certificate it embeds. With non-nil roots that certificate must also chain to
+// them and be valid for time stamping at the token's generation time.
+func Verify(der, digest []byte, roots *x509.CertPool) (*Token, error) {
+	invalid := func(format string, args ...interface{}) (*Token, error) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidToken, fmt.Sprintf(format, args...))
+	}
+
+	var ci ContentInfo
+	if rest, err := asn1.Unmarshal(der, &ci); err != nil || len(rest) > 0 || !ci.ContentType.Equal(OIDSignedData) {
+		return invalid("not a CMS SignedData")
+	}
+	var sd SignedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &sd); err != nil {
+		return invalid("malformed SignedData: %v", err)
+	}
+	if !sd.EncapContentInfo.EContentType.Equal(OIDTSTInfo) || len(sd.EncapContentInfo.EContent) == 0 {
+		return invalid("content is not a TSTInfo")
+	}
+	var info TSTInfo
+	if _, err := asn1.Unmarshal(sd.EncapContentInfo.EContent, &info); err != nil {
+		return invalid("malformed TSTInfo: %v", err)
+	}
+	if !info.MessageImprint.HashAlgorithm.Algorithm.Equal(OIDSHA256) || !bytes.Equal(info.MessageImprint.HashedMessage, digest) {
+		return invalid("token does not cover the expected digest")
+	}
+	if len(sd.SignerInfos) != 1 {
+		return invalid("expected exactly one signer, got %d", len(sd.SignerInfos))
+	}
+
+	certs, err := x509.ParseCertificates(sd.Certificates.Bytes)
+	if err != nil || len(certs) == 0 {
+		return invalid("token carries no usable signer certificate")
+	}
+	si := sd.SignerInfos[0]
+	var signer *x509.Certificate
+	for _, cert := range certs {
+		if bytes.Equal(cert.RawIssuer, si.SID.Issuer.FullBytes) && cert.SerialNumber.Cmp(si.SID.Serial) == 0 {
+			signer = cert
+		}
+	}
+	if signer == nil {
+		return invalid("signer certificate not included")
+	}
+	if err := checkSignedAttrs(si, sd.EncapContentInfo.EContent); err != nil {
+		return invalid("%v", err)
+	}
+	alg, ok := signatureAlgorithm(si)
+	if !ok {
+		return invalid("unsupported signature algorithm %v", si.SignatureAlgorithm.Algorithm)
+	}
+	// The signature covers the DER SET OF the signed attributes, not the [0] IMPLICIT form.
+	signed := append([]byte{0x31}, si.SignedAttrs.FullBytes[1:]...)
+	if err := signer.CheckSignature(alg, signed, si.Signature); err != nil {
+		return invalid("bad signature: %v", err)
+	}
+
+	if roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs {
+			intermediates.AddCert(cert)
+		}
+		if _, err := signer.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   info.GenTime,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+		}); err != nil {
+			return invalid("untrusted TSA certificate: %v", err)
+		}
+	}
+
+	return &Token{DER: der, GenTime: info.GenTime, Serial: info.SerialNumber, Signer: signer, nonce: info.Nonce}, nil
+}
+
+// checkSignedAttrs requires a TSTInfo content type and a messageDigest over eContent.
+func checkSignedAttrs(si SignerInfo, eContent []byte) error {
+	if len(si.SignedAttrs.Bytes) == 0 {
+		return errors.New("signed attributes required")
+	}
+	if !si.DigestAlgorithm.Algorithm.Equal(OIDSHA256) {
+		return fmt.Errorf("unsupported digest algorithm %v", si.DigestAlgorithm.Algorithm)
+	}
+	var contentTypeOK, digestOK bool
+	rest := si.SignedAttrs.Bytes
+	for len(rest) > 0 {
+		var attr Attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			return fmt.Errorf("malformed signed attribute: %v", err)
+		}
+		if len(attr.Values) != 1 {
+			continue
+		}
+		switch {
+		case attr.Type.Equal(OIDContentType):
+			var ct asn1.ObjectIdentifier
+			_, err := asn1.Unmarshal(attr.Values[0].FullBytes, &ct)
+			contentTypeOK = err == nil && ct.Equal(OIDTSTInfo)
+		case attr.Type.Equal(OIDMessageDigest):
+			var md []byte
+			sum := sha256.Sum256(eContent)
+			_, err := asn1.Unmarshal(attr.Values[0].FullBytes, &md)
+			digestOK = err == nil && bytes.Equal(md, sum[:])
+		}
+	}
+	if !contentTypeOK || !digestOK {
+		return errors.New("signed attributes do not match the TSTInfo")
+	}
+	return nil
+}
+
+func signatureAlgorithm(si SignerInfo) (x509.SignatureAlgorithm, bool) {
+	switch alg := si.SignatureAlgorithm.Algorithm; {
+	case alg.Equal(oidSHA256WithRSA), alg.Equal(oidRSAEncryption):
+		return x509.SHA256WithRSA, true
+	case alg.Equal(oidECDSAWithSHA256):
+		return x509.ECDSAWithSHA256, true
+	case alg.Equal(oidEd25519):
+		return x509.PureEd25519, true
+	}
+	return x509.UnknownSignatureAlgorithm, false
+}
@@ -0,0 +1,67 @@
+package rfc3161_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+	"github.com/Rainminds/gantral/pkg/rfc3161/rfc3161test"
+)
+
+func TestTimestampAgainstStubTSA(t *testing.T) {
+	tsa, err := rfc3161test.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(tsa)
+	defer srv.Close()
+
+	digest := sha256.Sum256([]byte("checkpoint"))
+	client := &rfc3161.Client{URL: srv.URL, Roots: tsa.Roots()}
+	token, err := client.Timestamp(context.Background(), digest[:])
+	if err != nil {
+		t.Fatalf("Timestamp failed: %v", err)
+	}
+	if token.GenTime.IsZero() || token.Signer.Subject.CommonName != "Gantral Stub TSA" {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+
+	// The stored DER verifies offline, and only for the digest it covers.
+	if _, err := rfc3161.Verify(token.DER, digest[:], tsa.Roots()); err != nil {
+		t.Errorf("Offline verification failed: %v", err)
+	}
+	other := sha256.Sum256([]byte("another checkpoint"))
+	if _, err := rfc3161.Verify(token.DER, other[:], nil); !errors.Is(err, rfc3161.ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken for another digest, got %v", err)
+	}
+
+	t.Run("Untrusted TSA", func(t *testing.T) {
+		stranger, _ := rfc3161test.New()
+		if _, err := rfc3161.Verify(token.DER, digest[:], stranger.Roots()); !errors.Is(err, rfc3161.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken against foreign roots, got %v", err)
+		}
+		if _, err := rfc3161.Verify(token.DER, digest[:], x509.NewCertPool()); !errors.Is(err, rfc3161.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken against empty roots, got %v", err)
+		}
+	})
+
+	t.Run("Tampered Token", func(t *testing.T) {
+		tampered := append([]byte(nil), token.DER...)
+		tampered[len(tampered)-1] ^= 0x01 // Inside the signature
+		if _, err := rfc3161.Verify(tampered, digest[:], nil); !errors.Is(err, rfc3161.ErrInvalidToken) {
+			t.Errorf("Expected ErrInvalidToken, got %v", err)
+		}
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		tsa.Reject = true
+		defer func() { tsa.Reject = false }()
+		if _, err := client.Timestamp(context.Background(), digest[:]); !errors.Is(err, rfc3161.ErrRejected) {
+			t.Errorf("Expected ErrRejected, got %v", err)
+		}
+	})
+}
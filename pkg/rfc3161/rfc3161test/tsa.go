@@ -0,0 +1,219 @@
+// Package rfc3161test provides a local stub time-stamp authority for tests and
+// offline development. It answers RFC 3161 queries over HTTP with tokens signed by a
+// throwaway ECDSA certificate issued by a throwaway root.
+package rfc3161test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+)
+
+var (
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidStubPolicy      = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}
+	oidExtKeyUsage     = asn1.ObjectIdentifier{2, 5, 29, 37}
+	oidTimeStamping    = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 3, 8}
+	oidSigningCertV2   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+)
+
+// TSA is a stub time-stamp authority. It is an http.Handler.
+type TSA struct {
+	// Root is the self-signed CA; Cert is the time-stamping certificate it issued.
+	Root *x509.Certificate
+	Cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	serial int64
+	// Now is the clock stamped into tokens.
+	Now func() time.Time
+	// Reject makes the TSA answer every query with status rejection (2).
+	Reject bool
+}
+
+// New creates a TSA with a fresh root CA and time-stamping certificate.
+func New() (*TSA, error) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	notBefore, notAfter := time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour)
+
+	rootTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Gantral Stub TSA Root"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	root, err := createCertificate(rootTmpl, rootTmpl, &rootKey.PublicKey, rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// RFC 3161 requires the time-stamping extended key usage to be critical;
+	// crypto/x509 would mark it non-critical, which OpenSSL rejects.
+	eku, err := asn1.Marshal([]asn1.ObjectIdentifier{oidTimeStamping})
+	if err != nil {
+		return nil, err
+	}
+	cert, err := createCertificate(&x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		Subject:         pkix.Name{CommonName: "Gantral Stub TSA"},
+		NotBefore:       notBefore,
+		NotAfter:        notAfter,
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtraExtensions: []pkix.Extension{{Id: oidExtKeyUsage, Critical: true, Value: eku}},
+	}, root, &key.PublicKey, rootKey)
+	if err != nil {
+		return nil, err
+	}
+	return &TSA{Root: root, Cert: cert, key: key, Now: time.Now}, nil
+}
+
+func createCertificate(tmpl, parent *x509.Certificate, pub *ecdsa.PublicKey, signer *ecdsa.PrivateKey) (*x509.Certificate, error) {
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, signer)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// Roots returns a pool trusting only this TSA's root.
+func (t *TSA) Roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(t.Root)
+	return pool
+}
+
+// RootPEM returns the root certificate in PEM form (e.g. for a roots file).
+func (t *TSA) RootPEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: t.Root.Raw})
+}
+
+// ServeHTTP answers a single time-stamp query.
+func (t *TSA) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.Header.Get("Content-Type") != rfc3161.ContentTypeQuery {
+		http.Error(w, "expected a POST of "+rfc3161.ContentTypeQuery, http.StatusBadRequest)
+		return
+	}
+	query, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply, err := t.Respond(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", rfc3161.ContentTypeReply)
+	_, _ = w.Write(reply)
+}
+
+// Respond builds the DER TimeStampResp for a DER TimeStampReq.
+func (t *TSA) Respond(query []byte) ([]byte, error) {
+	var req rfc3161.Request
+	if _, err := asn1.Unmarshal(query, &req); err != nil {
+		return nil, err
+	}
+	if t.Reject {
+		return asn1.Marshal(rfc3161.Response{Status: rfc3161.StatusInfo{Status: 2}})
+	}
+
+	t.mu.Lock()
+	t.serial++
+	serial := t.serial
+	t.mu.Unlock()
+
+	eContent, err := asn1.Marshal(rfc3161.TSTInfo{
+		Version:        1,
+		Policy:         oidStubPolicy,
+		MessageImprint: req.MessageImprint,
+		SerialNumber:   big.NewInt(serial),
+		GenTime:        t.Now().UTC().Truncate(time.Second),
+		Nonce:          req.Nonce,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(eContent)
+	contentType, _ := asn1.Marshal(rfc3161.OIDTSTInfo)
+	messageDigest, _ := asn1.Marshal(sum[:])
+	// ESS signing-certificate-v2 (RFC 5816) with the default SHA-256 hash algorithm.
+	certHash := sha256.Sum256(t.Cert.Raw)
+	signingCert, _ := asn1.Marshal(struct{ Certs []struct{ Hash []byte } }{Certs: []struct{ Hash []byte }{{Hash: certHash[:]}}})
+	var attrs [][]byte
+	for _, a := range []rfc3161.Attribute{
+		{Type: rfc3161.OIDContentType, Values: []asn1.RawValue{{FullBytes: contentType}}},
+		{Type: rfc3161.OIDMessageDigest, Values: []asn1.RawValue{{FullBytes: messageDigest}}},
+		{Type: oidSigningCertV2, Values: []asn1.RawValue{{FullBytes: signingCert}}},
+	} {
+		der, err := asn1.Marshal(a)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, der)
+	}
+	sort.Slice(attrs, func(i, j int) bool { return bytes.Compare(attrs[i], attrs[j]) < 0 }) // DER SET OF order
+	attrBytes := bytes.Join(attrs, nil)
+
+	signedSet, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: attrBytes})
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(signedSet)
+	signature, err := ecdsa.SignASN1(rand.Reader, t.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	sha256Alg := pkix.AlgorithmIdentifier{Algorithm: rfc3161.OIDSHA256}
+	signedData, err := asn1.Marshal(rfc3161.SignedData{
+		Version:          3,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{sha256Alg},
+		EncapContentInfo: rfc3161.EncapsulatedContentInfo{EContentType: rfc3161.OIDTSTInfo, EContent: eContent},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: t.Cert.Raw},
+		SignerInfos: []rfc3161.SignerInfo{{
+			Version:            1,
+			SID:                rfc3161.IssuerAndSerial{Issuer: asn1.RawValue{FullBytes: t.Cert.RawIssuer}, Serial: t.Cert.SerialNumber},
+			DigestAlgorithm:    sha256Alg,
+			SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: attrBytes},
+			SignatureAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256},
+			Signature:          signature,
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	token, err := asn1.Marshal(rfc3161.ContentInfo{
+		ContentType: rfc3161.OIDSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(rfc3161.Response{Status: rfc3161.StatusInfo{Status: 0}, TimeStampToken: asn1.RawValue{FullBytes: token}})
+}
@@ -0,0 +1,247 @@
+// Package signing provides Ed25519 signatures over commitment artifacts.
+//
+// The signature covers the artifact's canonical payload (the exact bytes whose
+// SHA-256 is the ArtifactID), so a valid signature proves both integrity and origin.
+// One key also signs log checkpoints and reconciliation reports, so every signed message
+// is prefixed with the context of its payload kind (see Context).
+// This package has no dependencies beyond the standard library and pkg/models,
+// so it can be used by offline verifiers.
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Rainminds/gantral/pkg/models"
+)
+
+// AlgorithmEd25519 is the only supported signature algorithm.
+const AlgorithmEd25519 = "ed25519"
+
+// Context names the kind of payload a signature covers. The signed message is
+// context || 0x00 || payload, so a signature made for one kind never verifies as another.
+type Context string
+
+const (
+	// ContextArtifact is the context of commitment artifact signatures.
+	ContextArtifact Context = "gantral/artifact/v1"
+	// ContextCheckpoint is the context of global log checkpoint signatures (pkg/translog).
+	ContextCheckpoint Context = "gantral/checkpoint/v1"
+	// ContextReconcileReport is the context of reconciliation report signatures (internal/reconcile).
+	ContextReconcileReport Context = "gantral/reconcile-report/v1"
+)
+
+var (
+	// ErrUnsigned indicates an artifact without a key ID or signature.
+	ErrUnsigned = errors.New("artifact is not signed")
+	// ErrUnknownKey indicates a signature by a key outside the trusted key set.
+	ErrUnknownKey = errors.New("artifact signed by unknown key")
+	// ErrBadSignature indicates a signature that does not verify.
+	ErrBadSignature = errors.New("artifact signature invalid")
+	// ErrInvalidKey indicates malformed key material.
+	ErrInvalidKey = errors.New("invalid signing key")
+)
+
+// Signer signs artifacts with a single Ed25519 private key.
+type Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewSigner creates a signer. An empty keyID defaults to KeyIDFor(public key).
+func NewSigner(keyID string, key ed25519.PrivateKey) (*Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%w: expected %d-byte ed25519 private key", ErrInvalidKey, ed25519.PrivateKeySize)
+	}
+	if keyID == "" {
+		keyID = KeyIDFor(key.Public().(ed25519.PublicKey))
+	}
+	return &Signer{keyID: keyID, key: key}, nil
+}
+
+// KeyID returns the identifier embedded in every artifact this signer signs.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// PublicKey returns the verification key for this signer.
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// Sign sets KeyID and Signature on the artifact.
+// The artifact must be fully populated (its canonical payload must be computable).
+func (s *Signer) Sign(art *models.CommitmentArtifact) error {
+	payload, err := art.CanonicalPayload()
+	if err != nil {
+		return fmt.Errorf("failed to sign artifact: %w", err)
+	}
+	art.KeyID = s.keyID
+	art.Signature = s.SignPayload(ContextArtifact, payload)
+	return nil
+}
+
+// SignPayload returns the base64 signature over canonical bytes of the given context
+// (e.g. a transparency log checkpoint). Callers record KeyID() alongside it.
+func (s *Signer) SignPayload(context Context, payload []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, message(context, payload)))
+}
+
+func message(context Context, payload []byte) []byte {
+	msg := make([]byte, 0, len(context)+1+len(payload))
+	msg = append(msg, context...)
+	msg = append(msg, 0)
+	return append(msg, payload...)
+}
+
+// KeyIDFor derives a stable key ID from a public key: the first 16 hex chars of its SHA-256.
+func KeyIDFor(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadPrivateKey reads a PEM-encoded PKCS#8 Ed25519 private key
+// (as produced by `openssl genpkey -algorithm ed25519`).
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidKey)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: not an ed25519 key", ErrInvalidKey)
+	}
+	return key, nil
+}
+
+// PublicKeyEntry is one trusted key in a key set file.
+type PublicKeyEntry struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"` // Base64 (standard) of the raw 32-byte key
+}
+
+// KeySetFile is the on-disk format of a trusted key set (keys.json).
+type KeySetFile struct {
+	Keys []PublicKeyEntry `json:"keys"`
+}
+
+// KeySet maps key IDs to trusted Ed25519 public keys.
+type KeySet map[string]ed25519.PublicKey
+
+// LoadKeySet reads a trusted key set from a keys.json file.
+func LoadKeySet(path string) (KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key set: %w", err)
+	}
+	return ParseKeySet(data)
+}
+
+// LoadKeySetFile reads and validates a keys.json file, keeping its published form
+// (e.g. for shipping it inside an audit bundle).
+func LoadKeySetFile(path string) (*KeySetFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key set: %w", err)
+	}
+	if _, err := ParseKeySet(data); err != nil {
+		return nil, err
+	}
+	var file KeySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	return &file, nil
+}
+
+// ParseKeySet decodes a trusted key set. Duplicate IDs and unsupported algorithms are rejected.
+func ParseKeySet(data []byte) (KeySet, error) {
+	var file KeySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	keys := make(KeySet, len(file.Keys))
+	for _, entry := range file.Keys {
+		if entry.KeyID == "" {
+			return nil, fmt.Errorf("%w: key_id required", ErrInvalidKey)
+		}
+		if entry.Algorithm != "" && entry.Algorithm != AlgorithmEd25519 {
+			return nil, fmt.Errorf("%w: key %s: unsupported algorithm %q", ErrInvalidKey, entry.KeyID, entry.Algorithm)
+		}
+		raw, err := base64.StdEncoding.DecodeString(entry.PublicKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: key %s: expected base64 %d-byte ed25519 public key", ErrInvalidKey, entry.KeyID, ed25519.PublicKeySize)
+		}
+		if _, dup := keys[entry.KeyID]; dup {
+			return nil, fmt.Errorf("%w: duplicate key_id %s", ErrInvalidKey, entry.KeyID)
+		}
+		keys[entry.KeyID] = ed25519.PublicKey(raw)
+	}
+	return keys, nil
+}
+
+// Entry returns the key set entry for a public key, for publishing to verifiers.
+func Entry(keyID string, pub ed25519.PublicKey) PublicKeyEntry {
+	return PublicKeyEntry{
+		KeyID:     keyID,
+		Algorithm: AlgorithmEd25519,
+		PublicKey: base64.StdEncoding.EncodeToString(pub),
+	}
+}
+
+// Verify checks the artifact's signature against the trusted key set.
+func (ks KeySet) Verify(art *models.CommitmentArtifact) error {
+	if art.KeyID == "" || art.Signature == "" {
+		return ErrUnsigned
+	}
+	pub, ok := ks[art.KeyID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, art.KeyID)
+	}
+	payload, err := art.CanonicalPayload()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	return verify(pub, art.KeyID, message(ContextArtifact, payload), art.Signature)
+}
+
+// VerifyPayload checks a SignPayload signature of the given context against the trusted key set.
+func (ks KeySet) VerifyPayload(keyID string, context Context, payload []byte, signature string) error {
+	if keyID == "" || signature == "" {
+		return ErrUnsigned
+	}
+	pub, ok := ks[keyID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	return verify(pub, keyID, message(context, payload), signature)
+}
+
+func verify(pub ed25519.PublicKey, keyID string, msg []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed encoding", ErrBadSignature)
+	}
+	if !ed25519.Verify(pub, msg, sig) {
+		return fmt.Errorf("%w: key %s", ErrBadSignature, keyID)
+	}
+	return nil
+}
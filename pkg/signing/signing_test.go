@@ -0,0 +1,165 @@
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Rainminds/gantral/pkg/models"
+)
+
+func newTestSigner(t *testing.T, keyID string) *Signer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	s, err := NewSigner(keyID, priv)
+	if err != nil {
+		t.Fatalf("NewSigner: %v", err)
+	}
+	return s
+}
+
+func sealedArtifact(t *testing.T) *models.CommitmentArtifact {
+	t.Helper()
+	art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1")
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("CalculateHashAndSetID: %v", err)
+	}
+	return art
+}
+
+func TestSignAndVerify(t *testing.T) {
+	signer := newTestSigner(t, "key-1")
+	keys := KeySet{"key-1": signer.PublicKey()}
+
+	art := sealedArtifact(t)
+	if err := signer.Sign(art); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if art.KeyID != "key-1" || art.Signature == "" {
+		t.Fatalf("expected key ID and signature to be set, got %+v", art)
+	}
+	if err := keys.Verify(art); err != nil {
+		t.Fatalf("expected valid signature, got %v", err)
+	}
+
+	// Signature must survive the JSON round trip used by stores and verifiers.
+	data, _ := json.Marshal(art)
+	var loaded models.CommitmentArtifact
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if err := keys.Verify(&loaded); err != nil {
+		t.Errorf("signature lost in JSON round trip: %v", err)
+	}
+}
+
+func TestVerify_Failures(t *testing.T) {
+	signer := newTestSigner(t, "key-1")
+	other := newTestSigner(t, "key-1") // Same ID, different key material
+	keys := KeySet{"key-1": signer.PublicKey()}
+
+	t.Run("Unsigned", func(t *testing.T) {
+		if err := keys.Verify(sealedArtifact(t)); !errors.Is(err, ErrUnsigned) {
+			t.Errorf("expected ErrUnsigned, got %v", err)
+		}
+	})
+
+	t.Run("Unknown Key", func(t *testing.T) {
+		art := sealedArtifact(t)
+		_ = newTestSigner(t, "key-2").Sign(art)
+		if err := keys.Verify(art); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("expected ErrUnknownKey, got %v", err)
+		}
+	})
+
+	t.Run("Forged Key Material", func(t *testing.T) {
+		art := sealedArtifact(t)
+		_ = other.Sign(art)
+		if err := keys.Verify(art); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("expected ErrBadSignature, got %v", err)
+		}
+	})
+
+	t.Run("Content Tampered After Signing", func(t *testing.T) {
+		art := sealedArtifact(t)
+		_ = signer.Sign(art)
+		art.HumanActorID = "attacker"
+		if err := keys.Verify(art); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("expected ErrBadSignature, got %v", err)
+		}
+	})
+
+	t.Run("Signature From Another Context", func(t *testing.T) {
+		art := sealedArtifact(t)
+		payload, _ := art.CanonicalPayload()
+		art.KeyID = "key-1"
+		art.Signature = signer.SignPayload(ContextCheckpoint, payload)
+		if err := keys.Verify(art); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("expected ErrBadSignature, got %v", err)
+		}
+		if err := keys.VerifyPayload("key-1", ContextReconcileReport, payload, art.Signature); !errors.Is(err, ErrBadSignature) {
+			t.Errorf("expected ErrBadSignature across payload contexts, got %v", err)
+		}
+		if err := keys.VerifyPayload("key-1", ContextCheckpoint, payload, art.Signature); err != nil {
+			t.Errorf("expected the signature to verify in its own context, got %v", err)
+		}
+	})
+}
+
+func TestParseKeySet(t *testing.T) {
+	signer := newTestSigner(t, "")
+	if signer.KeyID() != KeyIDFor(signer.PublicKey()) {
+		t.Errorf("expected default key ID to be the key fingerprint, got %s", signer.KeyID())
+	}
+
+	file := KeySetFile{Keys: []PublicKeyEntry{Entry(signer.KeyID(), signer.PublicKey())}}
+	data, _ := json.Marshal(file)
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		t.Fatalf("ParseKeySet: %v", err)
+	}
+	if !keys[signer.KeyID()].Equal(signer.PublicKey()) {
+		t.Error("parsed key does not match")
+	}
+
+	cases := map[string]string{
+		"duplicate id":  `{"keys":[{"key_id":"a","public_key":"` + file.Keys[0].PublicKey + `"},{"key_id":"a","public_key":"` + file.Keys[0].PublicKey + `"}]}`,
+		"bad algorithm": `{"keys":[{"key_id":"a","algorithm":"rsa","public_key":"` + file.Keys[0].PublicKey + `"}]}`,
+		"short key":     `{"keys":[{"key_id":"a","public_key":"AAAA"}]}`,
+		"missing id":    `{"keys":[{"public_key":"` + file.Keys[0].PublicKey + `"}]}`,
+	}
+	for name, raw := range cases {
+		if _, err := ParseKeySet([]byte(raw)); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("%s: expected ErrInvalidKey, got %v", name, err)
+		}
+	}
+}
+
+func TestLoadPrivateKey(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "signing.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	loaded, err := LoadPrivateKey(path)
+	if err != nil {
+		t.Fatalf("LoadPrivateKey: %v", err)
+	}
+	if !loaded.Equal(priv) {
+		t.Error("loaded key does not match")
+	}
+}
@@ -0,0 +1,79 @@
+// Package statemachine holds the canonical execution state machine (specs/03-state-machine.md).
+//
+// It is shared by the operational engine (core/engine) and the offline verifier (pkg/verifier),
+// so both enforce exactly the same table. It depends only on pkg/constants and must stay
+// free of operational code.
+package statemachine
+
+import "github.com/Rainminds/gantral/pkg/constants"
+
+// Transitions defines the strict map of valid state transitions.
+// Terminal states map to an empty list.
+var Transitions = map[string][]string{
+	constants.StateCreated: {
+		constants.StateRunning,
+	},
+	constants.StateRunning: {
+		constants.StateWaitingForHuman,
+		constants.StateCompleted,
+		constants.StateTerminated,
+	},
+	constants.StateWaitingForHuman: {
+		constants.StateWaitingForHuman, // A recorded vote of a multi-party (quorum) decision, or an escalation
+		constants.StateApproved,
+		constants.StateRejected,
+		constants.StateOverridden,
+		constants.StateTerminated, // Approval timed out with the TERMINATE final action
+	},
+	constants.StateApproved: {
+		constants.StateResumed,
+	},
+	constants.StateRejected: {
+		constants.StateTerminated, // Or remediation path, but strictly TERMINATED for now based on simple model
+	},
+	constants.StateOverridden: {
+		constants.StateResumed,
+	},
+	constants.StateResumed: {
+		constants.StateRunning,
+	},
+	constants.StateCompleted:  {}, // Terminal state
+	constants.StateTerminated: {}, // Terminal state
+}
+
+// GenesisStates are the states an instance's first (genesis) artifact may record.
+// Policy evaluation happens at creation, so an instance is born RUNNING, WAITING_FOR_HUMAN
+// (REQUIRE_HUMAN) or TERMINATED (DENY, ADR-007). CREATED is accepted for chains that
+// record creation explicitly.
+var GenesisStates = []string{
+	constants.StateCreated,
+	constants.StateRunning,
+	constants.StateWaitingForHuman,
+	constants.StateTerminated,
+}
+
+// IsAllowed reports whether from -> to is a canonical transition.
+func IsAllowed(from, to string) bool {
+	for _, s := range Transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGenesisState reports whether a chain may start in state s.
+func IsGenesisState(s string) bool {
+	for _, g := range GenesisStates {
+		if s == g {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTerminal reports whether s is a known state with no outgoing transitions.
+func IsTerminal(s string) bool {
+	next, ok := Transitions[s]
+	return ok && len(next) == 0
+}
@@ -0,0 +1,82 @@
+package translog
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
+	"github.com/Rainminds/gantral/pkg/rfc3161"
+)
+
+// ErrInvalidReceipt indicates an anchor receipt that does not match its checkpoint,
+// or whose time-stamp token does not verify.
+var ErrInvalidReceipt = errors.New("invalid anchor receipt")
+
+// Receipt kinds.
+const (
+	ReceiptKindFile    = "file"    // Copy in a directory the log operator cannot rewrite
+	ReceiptKindBucket  = "bucket"  // Copy in a second bucket under different credentials
+	ReceiptKindRFC3161 = "rfc3161" // Time-stamp token from an RFC 3161 TSA
+)
+
+// Receipt records that an external witness saw a checkpoint. It is stored next to
+// the checkpoint it anchors; the witness keeps the evidence the operator cannot rewrite.
+type Receipt struct {
+	Witness          string `json:"witness"` // Configured name of the witness
+	Kind             string `json:"kind"`
+	Origin           string `json:"origin"`
+	TreeSize         uint64 `json:"tree_size"`
+	RootHash         string `json:"root_hash"`
+	CheckpointSHA256 string `json:"checkpoint_sha256"`  // Hex SHA-256 of the JCS-encoded signed checkpoint
+	AnchoredAt       string `json:"anchored_at"`        // RFC 3339; the TSA's genTime for rfc3161
+	Location         string `json:"location,omitempty"` // Where a file or bucket witness keeps its copy
+	Token            []byte `json:"token,omitempty"`    // DER TimeStampToken for rfc3161 (base64 in JSON)
+}
+
+// CanonicalCheckpoint returns the bytes a witness stores and anchors: the RFC 8785
+// form of the checkpoint including its signature.
+func CanonicalCheckpoint(c *Checkpoint) ([]byte, error) {
+	return jcs.Marshal(c)
+}
+
+// CheckpointDigest returns the SHA-256 of CanonicalCheckpoint.
+func CheckpointDigest(c *Checkpoint) ([]byte, error) {
+	data, err := CanonicalCheckpoint(c)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// VerifyReceipt checks that a receipt is about exactly this checkpoint. For rfc3161
+// receipts the token must also cover the checkpoint digest; with non-nil tsaRoots the
+// TSA certificate must chain to them. A file or bucket receipt only points at the
+// witness copy, which the auditor fetches and compares with CheckpointSHA256.
+func VerifyReceipt(r *Receipt, c *Checkpoint, tsaRoots *x509.CertPool) error {
+	if r.Origin != c.Origin || r.TreeSize != c.TreeSize || r.RootHash != c.RootHash {
+		return fmt.Errorf("%w: receipt is for %s@%d, checkpoint is %s@%d", ErrInvalidReceipt, r.Origin, r.TreeSize, c.Origin, c.TreeSize)
+	}
+	digest, err := CheckpointDigest(c)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+	}
+	if r.CheckpointSHA256 != hex.EncodeToString(digest) {
+		return fmt.Errorf("%w: checkpoint digest mismatch", ErrInvalidReceipt)
+	}
+
+	switch r.Kind {
+	case ReceiptKindFile, ReceiptKindBucket:
+		return nil
+	case ReceiptKindRFC3161:
+		if _, err := rfc3161.Verify(r.Token, digest, tsaRoots); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidReceipt, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidReceipt, r.Kind)
+	}
+}
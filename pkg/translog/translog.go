@@ -0,0 +1,166 @@
+// Package translog defines the offline-verifiable formats of the global artifact log:
+// signed checkpoints (Merkle tree heads), inclusion proofs and consistency proofs.
+//
+// Every emitted artifact ID is appended to a single append-only log. Leaf i is
+// merkle.LeafHash of the UTF-8 bytes of the i-th artifact ID. A checkpoint commits
+// to the first TreeSize leaves; an inclusion proof shows that an artifact is one of
+// them, and a consistency proof shows that a later checkpoint extends an earlier one
+// without rewriting it. Together they make deleting an instance's whole chain detectable.
+//
+// This package depends only on the standard library and pkg/{jcs,merkle,rfc3161,signing}.
+package translog
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
+	"github.com/Rainminds/gantral/pkg/merkle"
+	"github.com/Rainminds/gantral/pkg/signing"
+)
+
+// ErrInvalidCheckpoint indicates a checkpoint that is malformed or whose signature does not verify.
+var ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+
+// Checkpoint is a signed Merkle tree head over the first TreeSize leaves of the log.
+type Checkpoint struct {
+	Origin    string `json:"origin"` // Identifies the log; checkpoints of different logs are never comparable
+	TreeSize  uint64 `json:"tree_size"`
+	RootHash  string `json:"root_hash"` // Hex
+	Timestamp string `json:"timestamp"` // RFC 3339, UTC
+	KeyID     string `json:"key_id,omitempty"`
+	Signature string `json:"signature,omitempty"` // Base64 Ed25519 over SignedPayload
+}
+
+// SignedPayload returns the canonical (RFC 8785) bytes the signature covers:
+// every field except the signature itself.
+func (c *Checkpoint) SignedPayload() ([]byte, error) {
+	unsigned := *c
+	unsigned.Signature = ""
+	return jcs.Marshal(unsigned)
+}
+
+// Sign sets KeyID and Signature.
+func (c *Checkpoint) Sign(signer *signing.Signer) error {
+	c.KeyID = signer.KeyID()
+	payload, err := c.SignedPayload()
+	if err != nil {
+		return fmt.Errorf("failed to sign checkpoint: %w", err)
+	}
+	c.Signature = signer.SignPayload(signing.ContextCheckpoint, payload)
+	return nil
+}
+
+// Root decodes the root hash.
+func (c *Checkpoint) Root() ([]byte, error) {
+	root, err := hex.DecodeString(c.RootHash)
+	if err != nil || len(root) != merkle.HashSize {
+		return nil, fmt.Errorf("%w: root_hash must be %d hex-encoded bytes", ErrInvalidCheckpoint, merkle.HashSize)
+	}
+	return root, nil
+}
+
+// VerifyCheckpoint checks the checkpoint's form and, with a non-nil key set, its signature.
+// The returned error wraps signing.ErrUnsigned, ErrUnknownKey or ErrBadSignature for signature failures.
+func VerifyCheckpoint(c *Checkpoint, keys signing.KeySet) error {
+	if c.Origin == "" {
+		return fmt.Errorf("%w: origin required", ErrInvalidCheckpoint)
+	}
+	if _, err := c.Root(); err != nil {
+		return err
+	}
+	if keys == nil {
+		return nil
+	}
+	payload, err := c.SignedPayload()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidCheckpoint, err)
+	}
+	if err := keys.VerifyPayload(c.KeyID, signing.ContextCheckpoint, payload, c.Signature); err != nil {
+		return fmt.Errorf("checkpoint %s@%d: %w", c.Origin, c.TreeSize, err)
+	}
+	return nil
+}
+
+// InclusionProof shows that an artifact ID is leaf LeafIndex of the tree of size TreeSize.
+type InclusionProof struct {
+	ArtifactID string   `json:"artifact_id"`
+	LeafIndex  uint64   `json:"leaf_index"`
+	TreeSize   uint64   `json:"tree_size"`
+	Hashes     []string `json:"hashes"` // Hex audit path, leaf to root
+}
+
+// ConsistencyProof shows that the tree of size FirstSize is a prefix of the tree of size SecondSize.
+type ConsistencyProof struct {
+	FirstSize  uint64   `json:"first_size"`
+	SecondSize uint64   `json:"second_size"`
+	Hashes     []string `json:"hashes"` // Hex
+}
+
+// LeafHash returns the log leaf hash of an artifact ID.
+func LeafHash(artifactID string) []byte {
+	return merkle.LeafHash([]byte(artifactID))
+}
+
+// VerifyInclusion checks the proof against a checkpoint. The checkpoint itself must be
+// verified separately with VerifyCheckpoint.
+func VerifyInclusion(p *InclusionProof, c *Checkpoint) error {
+	if p.TreeSize != c.TreeSize {
+		return fmt.Errorf("%w: proof is for tree size %d, checkpoint has %d", merkle.ErrInvalidProof, p.TreeSize, c.TreeSize)
+	}
+	root, err := c.Root()
+	if err != nil {
+		return err
+	}
+	hashes, err := decodeHashes(p.Hashes)
+	if err != nil {
+		return err
+	}
+	return merkle.VerifyInclusion(LeafHash(p.ArtifactID), p.LeafIndex, p.TreeSize, hashes, root)
+}
+
+// VerifyConsistency checks that checkpoint second extends checkpoint first.
+// Both checkpoints must be verified separately with VerifyCheckpoint.
+func VerifyConsistency(p *ConsistencyProof, first, second *Checkpoint) error {
+	if first.Origin != second.Origin {
+		return fmt.Errorf("%w: checkpoints belong to different logs (%q, %q)", merkle.ErrInvalidProof, first.Origin, second.Origin)
+	}
+	if p.FirstSize != first.TreeSize || p.SecondSize != second.TreeSize {
+		return fmt.Errorf("%w: proof is for sizes %d→%d, checkpoints have %d→%d", merkle.ErrInvalidProof, p.FirstSize, p.SecondSize, first.TreeSize, second.TreeSize)
+	}
+	root1, err := first.Root()
+	if err != nil {
+		return err
+	}
+	root2, err := second.Root()
+	if err != nil {
+		return err
+	}
+	hashes, err := decodeHashes(p.Hashes)
+	if err != nil {
+		return err
+	}
+	return merkle.VerifyConsistency(p.FirstSize, p.SecondSize, hashes, root1, root2)
+}
+
+// EncodeHashes hex-encodes proof hashes.
+func EncodeHashes(hashes [][]byte) []string {
+	out := make([]string, len(hashes))
+	for i, h := range hashes {
+		out[i] = hex.EncodeToString(h)
+	}
+	return out
+}
+
+func decodeHashes(hashes []string) ([][]byte, error) {
+	out := make([][]byte, len(hashes))
+	for i, h := range hashes {
+		b, err := hex.DecodeString(h)
+		if err != nil || len(b) != merkle.HashSize {
+			return nil, fmt.Errorf("%w: hash %d is not %d hex-encoded bytes", merkle.ErrInvalidProof, i, merkle.HashSize)
+		}
+		out[i] = b
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package translog
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Rainminds/gantral/pkg/merkle"
+	"github.com/Rainminds/gantral/pkg/signing"
+)
+
+// tree builds a signed checkpoint over n artifact IDs.
+func tree(t *testing.T, signer *signing.Signer, n int) ([][]byte, *Checkpoint) {
+	t.Helper()
+	var leaves [][]byte
+	for i := 0; i < n; i++ {
+		leaves = append(leaves, LeafHash(fmt.Sprintf("art-%d", i)))
+	}
+	cp := &Checkpoint{
+		Origin:    "gantral.test/log",
+		TreeSize:  uint64(n),
+		RootHash:  hex.EncodeToString(merkle.Root(leaves)),
+		Timestamp: "2026-01-01T00:00:00Z",
+	}
+	if err := cp.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	return leaves, cp
+}
+
+func TestCheckpointSignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("log-key", priv)
+	keys := signing.KeySet{"log-key": pub}
+	_, cp := tree(t, signer, 5)
+
+	if err := VerifyCheckpoint(cp, keys); err != nil {
+		t.Fatalf("expected valid checkpoint, got %v", err)
+	}
+
+	forged := *cp
+	forged.TreeSize = 4 // Truncating the log invalidates the signature
+	if err := VerifyCheckpoint(&forged, keys); !errors.Is(err, signing.ErrBadSignature) {
+		t.Errorf("expected ErrBadSignature, got %v", err)
+	}
+
+	unsigned := *cp
+	unsigned.Signature = ""
+	if err := VerifyCheckpoint(&unsigned, keys); !errors.Is(err, signing.ErrUnsigned) {
+		t.Errorf("expected ErrUnsigned, got %v", err)
+	}
+	if err := VerifyCheckpoint(&unsigned, nil); err != nil {
+		t.Errorf("without keys only the form is checked, got %v", err)
+	}
+
+	malformed := *cp
+	malformed.RootHash = "zz"
+	if err := VerifyCheckpoint(&malformed, nil); !errors.Is(err, ErrInvalidCheckpoint) {
+		t.Errorf("expected ErrInvalidCheckpoint, got %v", err)
+	}
+}
+
+func TestProofs(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("log-key", priv)
+	leaves, cp2 := tree(t, signer, 11)
+	_, cp1 := tree(t, signer, 6)
+
+	hashes, _ := merkle.InclusionProof(leaves, 3)
+	inclusion := &InclusionProof{ArtifactID: "art-3", LeafIndex: 3, TreeSize: 11, Hashes: EncodeHashes(hashes)}
+	if err := VerifyInclusion(inclusion, cp2); err != nil {
+		t.Errorf("expected inclusion to verify, got %v", err)
+	}
+	inclusion.ArtifactID = "art-4"
+	if err := VerifyInclusion(inclusion, cp2); !errors.Is(err, merkle.ErrInvalidProof) {
+		t.Errorf("expected ErrInvalidProof for another artifact, got %v", err)
+	}
+	if err := VerifyInclusion(inclusion, cp1); !errors.Is(err, merkle.ErrInvalidProof) {
+		t.Errorf("expected ErrInvalidProof for a checkpoint of another size, got %v", err)
+	}
+
+	hashes, _ = merkle.ConsistencyProof(leaves, 6)
+	consistency := &ConsistencyProof{FirstSize: 6, SecondSize: 11, Hashes: EncodeHashes(hashes)}
+	if err := VerifyConsistency(consistency, cp1, cp2); err != nil {
+		t.Errorf("expected consistency to verify, got %v", err)
+	}
+
+	other := *cp1
+	other.Origin = "another/log"
+	if err := VerifyConsistency(consistency, &other, cp2); !errors.Is(err, merkle.ErrInvalidProof) {
+		t.Errorf("expected checkpoints of different logs to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package verifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Rainminds/gantral/pkg/models"
+)
+
+// InstanceResult is the verification outcome for the chain of a single instance.
+type InstanceResult struct {
+	InstanceID string    `json:"instance_id"`
+	Valid      bool      `json:"valid"`
+	Outcome    Outcome   `json:"outcome"`
+	Length     int       `json:"length"` // Artifacts on the main chain (genesis to head)
+	Findings   []Finding `json:"findings,omitempty"`
+
+	// Chain is the main chain in linkage order, genesis first.
+	Chain []models.CommitmentArtifact `json:"-"`
+}
+
+// VerifyInstances groups artifacts by InstanceID and rebuilds each instance's chain by
+// following PrevArtifactHash links from genesis. Timestamps are never used for ordering.
+//
+// Per instance it reports:
+//   - FORK: two artifacts claim the same parent (or two genesis artifacts). INVALID.
+//   - CYCLE: artifacts whose ancestry loops back on itself. INVALID.
+//   - ORPHAN: artifacts whose ancestry leads to an artifact not in the set. INCONCLUSIVE.
+//   - MISSING_GENESIS: no artifact links to genesis. INCONCLUSIVE.
+//   - ILLEGAL_TRANSITION: the main chain's states break the state machine. INVALID.
+//
+// Results are sorted by InstanceID.
+func VerifyInstances(artifacts []models.CommitmentArtifact) []InstanceResult {
+	groups := make(map[string][]models.CommitmentArtifact)
+	for _, art := range artifacts {
+		groups[art.InstanceID] = append(groups[art.InstanceID], art)
+	}
+
+	ids := make([]string, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	results := make([]InstanceResult, 0, len(ids))
+	for _, id := range ids {
+		chain, findings := OrderChain(groups[id])
+		findings = append(findings, VerifyTransitions(chain)...)
+		res := chainResult(findings)
+		results = append(results, InstanceResult{
+			InstanceID: id,
+			Valid:      res.Valid,
+			Outcome:    res.Outcome,
+			Length:     len(chain),
+			Findings:   findings,
+			Chain:      chain,
+		})
+	}
+	return results
+}
+
+// OrderChain rebuilds the main chain of a single instance from hash linkage.
+// On a fork the branch with the lowest artifact ID is followed so that the walk is
+// deterministic; every artifact off the main chain is reported as a finding.
+func OrderChain(artifacts []models.CommitmentArtifact) ([]models.CommitmentArtifact, []Finding) {
+	byID := make(map[string]models.CommitmentArtifact, len(artifacts))
+	children := make(map[string][]string)
+	for _, art := range artifacts {
+		if _, dup := byID[art.ArtifactID]; dup {
+			continue // Same ID means same content; duplicates are harmless copies.
+		}
+		byID[art.ArtifactID] = art
+		children[art.PrevArtifactHash] = append(children[art.PrevArtifactHash], art.ArtifactID)
+	}
+	for prev := range children {
+		sort.Strings(children[prev])
+	}
+
+	var chain []models.CommitmentArtifact
+	var findings []Finding
+	onChain := make(map[string]bool, len(byID))
+
+	// 1. Walk the main chain from genesis. Each artifact has exactly one parent,
+	// so a walk that starts at genesis cannot loop.
+	roots := children[models.GenesisHash]
+	switch {
+	case len(roots) == 0:
+		findings = append(findings, Finding{
+			Index:   0,
+			Outcome: OutcomeInconclusive,
+			Code:    ReasonMissingGenesis,
+			Message: "no artifact links to genesis: chain head missing",
+		})
+	case len(roots) > 1:
+		findings = append(findings, Finding{
+			Index:   0,
+			Outcome: OutcomeInvalid,
+			Code:    ReasonFork,
+			Message: fmt.Sprintf("fork at genesis: %d genesis artifacts (%s)", len(roots), strings.Join(roots, ", ")),
+		})
+	}
+	if len(roots) > 0 {
+		current := roots[0]
+		for {
+			chain = append(chain, byID[current])
+			onChain[current] = true
+
+			next := children[current]
+			if len(next) == 0 {
+				break
+			}
+			if len(next) > 1 {
+				findings = append(findings, Finding{
+					Index:      len(chain),
+					ArtifactID: current,
+					Outcome:    OutcomeInvalid,
+					Code:       ReasonFork,
+					Message:    fmt.Sprintf("fork: artifact[%d] (%s) has %d children (%s)", len(chain)-1, current, len(next), strings.Join(next, ", ")),
+				})
+			}
+			current = next[0]
+		}
+	}
+
+	// 2. Classify every artifact left off the main chain by walking its ancestry.
+	offChain := make([]string, 0, len(byID)-len(onChain))
+	for id := range byID {
+		if !onChain[id] {
+			offChain = append(offChain, id)
+		}
+	}
+	sort.Strings(offChain)
+
+	for _, id := range offChain {
+		findings = append(findings, classifyOffChain(id, byID, onChain))
+	}
+
+	return chain, findings
+}
+
+// classifyOffChain explains why an artifact is not on the main chain.
+func classifyOffChain(id string, byID map[string]models.CommitmentArtifact, onChain map[string]bool) Finding {
+	f := Finding{Index: -1, ArtifactID: id}
+	seen := map[string]bool{id: true}
+	prev := byID[id].PrevArtifactHash
+	for {
+		if prev == models.GenesisHash || onChain[prev] {
+			f.Outcome, f.Code = OutcomeInvalid, ReasonFork
+			f.Message = fmt.Sprintf("artifact %s is on a forked branch", id)
+			return f
+		}
+		if seen[prev] {
+			f.Outcome, f.Code = OutcomeInvalid, ReasonCycle
+			f.Message = fmt.Sprintf("artifact %s is part of a linkage cycle through %s", id, prev)
+			return f
+		}
+		parent, ok := byID[prev]
+		if !ok {
+			f.Outcome, f.Code = OutcomeInconclusive, ReasonOrphan
+			f.Message = fmt.Sprintf("artifact %s is orphaned: ancestor %s not found", id, prev)
+			return f
+		}
+		seen[prev] = true
+		prev = parent.PrevArtifactHash
+	}
+}
@@ -0,0 +1,116 @@
+package verifier_test
+
+import (
+	"testing"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+)
+
+// link builds a hashed artifact chained to prev.
+func link(t *testing.T, instanceID, prev, state string) models.CommitmentArtifact {
+	t.Helper()
+	art := models.NewCommitmentArtifact(instanceID, prev, state, "pv", "ctx-"+state, "SYSTEM")
+	art.Timestamp = "2026-01-01T00:00:00Z" // Same second for every artifact
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	return *art
+}
+
+func TestVerifyInstances_OrdersByLinkage(t *testing.T) {
+	a := link(t, "inst-1", models.GenesisHash, "WAITING_FOR_HUMAN")
+	b := link(t, "inst-1", a.ArtifactID, "APPROVED")
+	c := link(t, "inst-1", b.ArtifactID, "RESUMED")
+
+	// Input order and identical timestamps must not matter.
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{c, a, b})
+	assert.Len(t, results, 1)
+	assert.True(t, results[0].Valid, results[0].Findings)
+	assert.Equal(t, verifier.OutcomeValid, results[0].Outcome)
+	assert.Equal(t, 3, results[0].Length)
+	assert.Equal(t, []string{a.ArtifactID, b.ArtifactID, c.ArtifactID},
+		[]string{results[0].Chain[0].ArtifactID, results[0].Chain[1].ArtifactID, results[0].Chain[2].ArtifactID})
+}
+
+func TestVerifyInstances_GroupsByInstance(t *testing.T) {
+	a1 := link(t, "inst-a", models.GenesisHash, "RUNNING")
+	a2 := link(t, "inst-a", a1.ArtifactID, "COMPLETED")
+	b1 := link(t, "inst-b", models.GenesisHash, "TERMINATED")
+
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{b1, a2, a1})
+	assert.Len(t, results, 2)
+	assert.Equal(t, "inst-a", results[0].InstanceID)
+	assert.Equal(t, 2, results[0].Length)
+	assert.Equal(t, "inst-b", results[1].InstanceID)
+	assert.Equal(t, 1, results[1].Length)
+	assert.True(t, results[0].Valid)
+	assert.True(t, results[1].Valid)
+}
+
+func TestVerifyInstances_Fork(t *testing.T) {
+	a := link(t, "inst-1", models.GenesisHash, "WAITING_FOR_HUMAN")
+	approved := link(t, "inst-1", a.ArtifactID, "APPROVED")
+	rejected := link(t, "inst-1", a.ArtifactID, "REJECTED")
+
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{a, approved, rejected})
+	assert.Equal(t, verifier.OutcomeInvalid, results[0].Outcome)
+	assert.Equal(t, 2, results[0].Length)
+
+	codes := findingCodes(results[0].Findings)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonFork, verifier.ReasonFork}, codes)
+	assert.Equal(t, 1, results[0].Findings[0].Index)
+}
+
+func TestVerifyInstances_TwoGenesis(t *testing.T) {
+	a := link(t, "inst-1", models.GenesisHash, "RUNNING")
+	b := link(t, "inst-1", models.GenesisHash, "TERMINATED")
+
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{a, b})
+	assert.Equal(t, verifier.OutcomeInvalid, results[0].Outcome)
+	assert.Equal(t, verifier.ReasonFork, results[0].Findings[0].Code)
+}
+
+func TestVerifyInstances_Orphan(t *testing.T) {
+	a := link(t, "inst-1", models.GenesisHash, "WAITING_FOR_HUMAN")
+	b := link(t, "inst-1", a.ArtifactID, "APPROVED")
+	c := link(t, "inst-1", b.ArtifactID, "RESUMED")
+
+	// b is missing: c cannot be placed, but nothing proves tampering.
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{a, c})
+	assert.Equal(t, verifier.OutcomeInconclusive, results[0].Outcome)
+	assert.Equal(t, 1, results[0].Length)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonOrphan}, findingCodes(results[0].Findings))
+	assert.Equal(t, c.ArtifactID, results[0].Findings[0].ArtifactID)
+}
+
+func TestVerifyInstances_MissingGenesis(t *testing.T) {
+	a := link(t, "inst-1", models.GenesisHash, "WAITING_FOR_HUMAN")
+	b := link(t, "inst-1", a.ArtifactID, "APPROVED")
+
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{b})
+	assert.Equal(t, verifier.OutcomeInconclusive, results[0].Outcome)
+	assert.Equal(t, 0, results[0].Length)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonMissingGenesis, verifier.ReasonOrphan}, findingCodes(results[0].Findings))
+}
+
+func TestVerifyInstances_Cycle(t *testing.T) {
+	// Hashes make real cycles infeasible; the linkage graph is still checked
+	// so a hand-crafted set cannot hang or fool the verifier.
+	a := link(t, "inst-1", models.GenesisHash, "RUNNING")
+	x := models.CommitmentArtifact{ArtifactID: "x", InstanceID: "inst-1", PrevArtifactHash: "y"}
+	y := models.CommitmentArtifact{ArtifactID: "y", InstanceID: "inst-1", PrevArtifactHash: "x"}
+
+	results := verifier.VerifyInstances([]models.CommitmentArtifact{a, x, y})
+	assert.Equal(t, verifier.OutcomeInvalid, results[0].Outcome)
+	assert.Equal(t, []verifier.ReasonCode{verifier.ReasonCycle, verifier.ReasonCycle}, findingCodes(results[0].Findings))
+}
+
+func findingCodes(findings []verifier.Finding) []verifier.ReasonCode {
+	codes := make([]verifier.ReasonCode, len(findings))
+	for i, f := range findings {
+		codes[i] = f.Code
+	}
+	return codes
+}
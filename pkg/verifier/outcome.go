@@ -0,0 +1,79 @@
+package verifier
+
+// Outcome is the three-valued verification result defined in specs/08 section 9.
+type Outcome string
+
+const (
+	// OutcomeValid: all hashes match, chain intact, schema valid.
+	OutcomeValid Outcome = "VALID"
+	// OutcomeInvalid: the evidence is provably wrong (tampered, broken, illegal).
+	OutcomeInvalid Outcome = "INVALID"
+	// OutcomeInconclusive: the evidence cannot be judged (missing artifacts, partial
+	// chain, unsupported artifact_version). Never to be read as VALID.
+	OutcomeInconclusive Outcome = "INCONCLUSIVE"
+)
+
+// severity orders outcomes so that aggregation always keeps the worst one.
+func (o Outcome) severity() int {
+	switch o {
+	case OutcomeValid:
+		return 0
+	case OutcomeInconclusive:
+		return 1
+	default:
+		return 2 // Unknown outcomes are treated as INVALID (fail-closed)
+	}
+}
+
+// WorstOutcome aggregates outcomes: INVALID beats INCONCLUSIVE beats VALID.
+// No outcomes at all is INCONCLUSIVE: there is nothing to vouch for.
+func WorstOutcome(outcomes ...Outcome) Outcome {
+	if len(outcomes) == 0 {
+		return OutcomeInconclusive
+	}
+	worst := OutcomeValid
+	for _, o := range outcomes {
+		if o.severity() > worst.severity() {
+			worst = o
+		}
+	}
+	return worst
+}
+
+// ReasonCode is a stable, machine-readable reason for a non-VALID finding.
+type ReasonCode string
+
+const (
+	ReasonInvalidJSON        ReasonCode = "INVALID_JSON"
+	ReasonMissingField       ReasonCode = "MISSING_FIELD"
+	ReasonUnsupportedVersion ReasonCode = "UNSUPPORTED_VERSION"
+	ReasonSchemaViolation    ReasonCode = "SCHEMA_VIOLATION"
+	ReasonHashMismatch       ReasonCode = "HASH_MISMATCH"
+	ReasonIllegalState       ReasonCode = "ILLEGAL_AUTHORITY_STATE"
+	ReasonIllegalTransition  ReasonCode = "ILLEGAL_TRANSITION"
+	ReasonUnsigned           ReasonCode = "UNSIGNED"
+	ReasonUnknownKey         ReasonCode = "UNKNOWN_KEY"
+	ReasonBadSignature       ReasonCode = "BAD_SIGNATURE"
+	ReasonMissingGenesis     ReasonCode = "MISSING_GENESIS"
+	ReasonMissingLink        ReasonCode = "MISSING_LINK"
+	ReasonBrokenLink         ReasonCode = "BROKEN_LINK"
+	ReasonFork               ReasonCode = "FORK"
+	ReasonOrphan             ReasonCode = "ORPHAN"
+	ReasonCycle              ReasonCode = "CYCLE"
+
+	// Bundle-level codes (see pkg/bundle).
+	ReasonMalformedBundle  ReasonCode = "MALFORMED_BUNDLE"
+	ReasonManifestMismatch ReasonCode = "MANIFEST_MISMATCH"
+	ReasonMissingMember    ReasonCode = "MISSING_MEMBER"
+	ReasonUnlistedMember   ReasonCode = "UNLISTED_MEMBER"
+	ReasonMissingPolicy    ReasonCode = "MISSING_POLICY"
+)
+
+// Finding is a single problem found while verifying an artifact or chain.
+type Finding struct {
+	Index      int        `json:"index"` // Position in the chain, -1 when not applicable
+	ArtifactID string     `json:"artifact_id,omitempty"`
+	Outcome    Outcome    `json:"outcome"`
+	Code       ReasonCode `json:"code"`
+	Message    string     `json:"message"`
+}
@@ -0,0 +1,50 @@
+package verifier
+
+import (
+	"fmt"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/statemachine"
+)
+
+// VerifyTransitions replays the authority states of a linkage-ordered chain against the
+// canonical state machine (pkg/statemachine). The first artifact must record a genesis
+// state; every later artifact must be a legal successor of the one before it.
+// Each illegal step is an INVALID finding at the offending index.
+func VerifyTransitions(chain []models.CommitmentArtifact) []Finding {
+	var findings []Finding
+	for i := range chain {
+		if f := checkTransition(chain, i); f != nil {
+			findings = append(findings, *f)
+		}
+	}
+	return findings
+}
+
+// checkTransition validates the state of chain[i] given its predecessor (or genesis for i == 0).
+func checkTransition(chain []models.CommitmentArtifact, i int) *Finding {
+	curr := chain[i]
+	var msg string
+	if i == 0 {
+		if statemachine.IsGenesisState(curr.AuthorityState) {
+			return nil
+		}
+		msg = fmt.Sprintf("illegal genesis state: chain cannot start in %q", curr.AuthorityState)
+	} else {
+		from := chain[i-1].AuthorityState
+		if statemachine.IsAllowed(from, curr.AuthorityState) {
+			return nil
+		}
+		msg = fmt.Sprintf("illegal transition at artifact[%d]: %s -> %s", i, from, curr.AuthorityState)
+		if statemachine.IsTerminal(from) {
+			msg += " (after terminal state)"
+		}
+	}
+	return &Finding{
+		Index:      i,
+		ArtifactID: curr.ArtifactID,
+		Outcome:    OutcomeInvalid,
+		Code:       ReasonIllegalTransition,
+		Message:    msg,
+	}
+}
@@ -0,0 +1,67 @@
+package verifier_test
+
+import (
+	"testing"
+
+	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/verifier"
+	"github.com/stretchr/testify/assert"
+)
+
+// chainOf builds a linked chain with the given authority states.
+func chainOf(t *testing.T, states ...string) []models.CommitmentArtifact {
+	t.Helper()
+	chain := make([]models.CommitmentArtifact, 0, len(states))
+	prev := models.GenesisHash
+	for _, state := range states {
+		art := link(t, "inst-1", prev, state)
+		chain = append(chain, art)
+		prev = art.ArtifactID
+	}
+	return chain
+}
+
+func TestVerifyTransitions(t *testing.T) {
+	tests := []struct {
+		name      string
+		states    []string
+		badIndex  int // -1 when the chain is legal
+		wantInMsg string
+	}{
+		{"Full Approval Lifecycle", []string{"WAITING_FOR_HUMAN", "APPROVED", "RESUMED", "RUNNING", "COMPLETED"}, -1, ""},
+		{"Override Lifecycle", []string{"WAITING_FOR_HUMAN", "OVERRIDDEN", "RESUMED", "RUNNING", "COMPLETED"}, -1, ""},
+		{"Rejection", []string{"WAITING_FOR_HUMAN", "REJECTED", "TERMINATED"}, -1, ""},
+		{"Auto Run", []string{"RUNNING", "COMPLETED"}, -1, ""},
+		{"Policy Deny", []string{"TERMINATED"}, -1, ""},
+		{"Explicit Creation", []string{"CREATED", "RUNNING", "WAITING_FOR_HUMAN"}, -1, ""},
+		{"Approved After Completed", []string{"RUNNING", "COMPLETED", "APPROVED"}, 2, "after terminal state"},
+		{"Skipped Decision", []string{"WAITING_FOR_HUMAN", "RESUMED"}, 1, "WAITING_FOR_HUMAN -> RESUMED"},
+		{"Illegal Genesis", []string{"APPROVED", "RESUMED"}, 0, "illegal genesis state"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := chainOf(t, tt.states...)
+
+			findings := verifier.VerifyTransitions(chain)
+			report := verifier.VerifyChain(chain)
+			if tt.badIndex < 0 {
+				assert.Empty(t, findings)
+				assert.True(t, report.Valid, report.BrokenReason)
+				return
+			}
+
+			assert.Len(t, findings, 1)
+			assert.Equal(t, tt.badIndex, findings[0].Index)
+			assert.Equal(t, verifier.ReasonIllegalTransition, findings[0].Code)
+			assert.Equal(t, verifier.OutcomeInvalid, findings[0].Outcome)
+			assert.Contains(t, findings[0].Message, tt.wantInMsg)
+
+			// The same pass runs inside VerifyChain and VerifyInstances.
+			assert.Equal(t, verifier.OutcomeInvalid, report.Outcome)
+			assert.Equal(t, tt.badIndex, report.BrokenIndex)
+			results := verifier.VerifyInstances(chain)
+			assert.Equal(t, verifier.OutcomeInvalid, results[0].Outcome)
+		})
+	}
+}
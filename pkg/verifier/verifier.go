@@ -2,46 +2,85 @@ package verifier
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/Rainminds/gantral/pkg/constants"
 	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
 )
 
 // VerificationResult contains the outcome of an artifact check.
+// Valid is true only when Outcome is VALID; Code explains any other outcome.
 type VerificationResult struct {
-	Valid          bool   `json:"valid"`
-	ArtifactID     string `json:"artifact_id"`
-	CalculatedHash string `json:"calculated_hash"`
-	Error          string `json:"error,omitempty"`
+	Valid          bool       `json:"valid"`
+	Outcome        Outcome    `json:"outcome"`
+	Code           ReasonCode `json:"code,omitempty"`
+	ArtifactID     string     `json:"artifact_id"`
+	CalculatedHash string     `json:"calculated_hash"`
+	KeyID          string     `json:"key_id,omitempty"`
+	Error          string     `json:"error,omitempty"`
 }
 
 // ChainResult contains the outcome of a chain verification.
+// Verification does not stop at the first problem: every finding is reported,
+// and BrokenIndex/BrokenReason describe the first one.
 type ChainResult struct {
-	Valid        bool   `json:"valid"`
-	BrokenIndex  int    `json:"broken_index"`
-	BrokenReason string `json:"broken_reason,omitempty"`
+	Valid        bool      `json:"valid"`
+	Outcome      Outcome   `json:"outcome"`
+	BrokenIndex  int       `json:"broken_index"`
+	BrokenReason string    `json:"broken_reason,omitempty"`
+	Findings     []Finding `json:"findings,omitempty"`
+}
+
+// failed builds a non-VALID result.
+func failed(outcome Outcome, code ReasonCode, artifactID, msg string) *VerificationResult {
+	return &VerificationResult{
+		Outcome:    outcome,
+		Code:       code,
+		ArtifactID: artifactID,
+		Error:      msg,
+	}
 }
 
 // VerifyArtifact validates the integrity of a single artifact blob.
 // It checks:
 // 1. JSON structure validity.
-// 2. Hash consistency (Claimed ID == Calculated Hash).
+// 2. Schema version support (v1-v3; unknown major versions are INCONCLUSIVE).
+// 3. Authority state is a canonical state.
+// 4. Hash consistency (Claimed ID == Calculated Hash).
+//
+// Signatures are NOT checked; use VerifyArtifactWithKeys for non-repudiation.
 func VerifyArtifact(data []byte) (*VerificationResult, error) {
+	return VerifyArtifactWithKeys(data, nil)
+}
+
+// VerifyArtifactWithKeys is VerifyArtifact plus signature verification against a trusted key set.
+// With a non-nil key set, an unsigned artifact, an unknown key or a bad signature is INVALID.
+func VerifyArtifactWithKeys(data []byte, keys signing.KeySet) (*VerificationResult, error) {
 	// 1. Deserialize
 	var art models.CommitmentArtifact
 	if err := json.Unmarshal(data, &art); err != nil {
-		return &VerificationResult{
-			Valid: false,
-			Error: fmt.Sprintf("invalid json structure: %v", err),
-		}, nil
+		return failed(OutcomeInvalid, ReasonInvalidJSON, "", fmt.Sprintf("invalid json structure: %v", err)), nil
 	}
 
 	// 2. Check for missing critical fields (sanity check)
 	if art.ArtifactID == "" {
-		return &VerificationResult{Valid: false, Error: "missing artifact_id"}, nil
+		return failed(OutcomeInvalid, ReasonMissingField, "", "missing artifact_id"), nil
+	}
+
+	// 3. Unknown major schema versions cannot be judged (specs/08 sections 9 and 12).
+	// v1 to v3 are accepted; guessing at a newer layout would be fail-open.
+	if err := models.CheckVersion(art.ArtifactVersion); err != nil {
+		return failed(OutcomeInconclusive, ReasonUnsupportedVersion, art.ArtifactID, err.Error()), nil
 	}
 
-	// 3. Re-calculate Hash
+	// 4. Authority state must be canonical (specs/03).
+	if !constants.IsValidState(art.AuthorityState) {
+		return failed(OutcomeInvalid, ReasonIllegalState, art.ArtifactID, fmt.Sprintf("illegal authority state %q", art.AuthorityState)), nil
+	}
+
+	// 5. Re-calculate Hash
 	// We use the canonical payload logic from pkg/models to ensure strict adherence to the spec.
 	// NOTE: We work observing the object *as loaded*.
 	claimedID := art.ArtifactID
@@ -49,36 +88,87 @@ func VerifyArtifact(data []byte) (*VerificationResult, error) {
 	// Create a copy to recalculate to avoid mutating the source if it differed
 	checkArt := art
 	if err := checkArt.CalculateHashAndSetID(); err != nil {
-		return &VerificationResult{
-			Valid:      false,
-			ArtifactID: claimedID,
-			Error:      fmt.Sprintf("calculation failed: %v", err),
-		}, nil
+		return failed(OutcomeInvalid, ReasonSchemaViolation, claimedID, fmt.Sprintf("calculation failed: %v", err)), nil
 	}
 
-	// 4. Compare
+	// 6. Compare
 	if checkArt.ArtifactID != claimedID {
-		return &VerificationResult{
-			Valid:          false,
-			ArtifactID:     claimedID,
-			CalculatedHash: checkArt.ArtifactID,
-			Error:          "hash mismatch: integrity compromised",
-		}, nil
+		res := failed(OutcomeInvalid, ReasonHashMismatch, claimedID, "hash mismatch: integrity compromised")
+		res.CalculatedHash = checkArt.ArtifactID
+		return res, nil
+	}
+
+	// 7. Signature (Origin). Checked only after integrity, over the same canonical payload.
+	if keys != nil {
+		if err := keys.Verify(&art); err != nil {
+			res := failed(OutcomeInvalid, signatureReason(err), claimedID, err.Error())
+			res.CalculatedHash = checkArt.ArtifactID
+			res.KeyID = art.KeyID
+			return res, nil
+		}
 	}
 
 	return &VerificationResult{
 		Valid:          true,
+		Outcome:        OutcomeValid,
 		ArtifactID:     claimedID,
 		CalculatedHash: checkArt.ArtifactID,
+		KeyID:          art.KeyID,
 	}, nil
 }
 
+func signatureReason(err error) ReasonCode {
+	switch {
+	case errors.Is(err, signing.ErrUnsigned):
+		return ReasonUnsigned
+	case errors.Is(err, signing.ErrUnknownKey):
+		return ReasonUnknownKey
+	default:
+		return ReasonBadSignature
+	}
+}
+
 // VerifyChain validates a sequence of artifacts.
-// The artifacts MUST be sorted by the caller (usually by timestamp or linkage).
-// This function verifies strict cryptographic linkage: art[N].PrevHash == art[N-1].Hash.
+// The artifacts MUST be sorted by the caller; use VerifyInstances to rebuild the
+// order from linkage for an unordered set.
+// This function verifies strict cryptographic linkage: art[0].PrevHash == GenesisHash
+// and art[N].PrevHash == art[N-1].Hash.
+//
+// A link to an artifact that is not in the set is a missing artifact (INCONCLUSIVE);
+// a link to an artifact that is present but not the predecessor is a broken chain (INVALID).
+// Across intact links, the authority states must follow the canonical state machine
+// (see VerifyTransitions).
 func VerifyChain(chain []models.CommitmentArtifact) *ChainResult {
 	if len(chain) == 0 {
-		return &ChainResult{Valid: true}
+		return &ChainResult{Valid: true, Outcome: OutcomeValid}
+	}
+
+	present := make(map[string]bool, len(chain))
+	for _, art := range chain {
+		present[art.ArtifactID] = true
+	}
+
+	var findings []Finding
+	// State-machine legality is only meaningful across intact links;
+	// these findings are reported after the linkage findings.
+	var legality []Finding
+
+	// Anchor: the first artifact MUST be the genesis of its instance.
+	// Anything else means the head of the chain is missing or was replaced.
+	if chain[0].PrevArtifactHash != models.GenesisHash {
+		f := Finding{
+			Index:      0,
+			ArtifactID: chain[0].ArtifactID,
+			Outcome:    OutcomeInconclusive,
+			Code:       ReasonMissingGenesis,
+			Message:    fmt.Sprintf("chain does not start at genesis: artifact[0].prev (%s) != genesis hash", chain[0].PrevArtifactHash),
+		}
+		if present[chain[0].PrevArtifactHash] {
+			f.Outcome, f.Code = OutcomeInvalid, ReasonBrokenLink
+		}
+		findings = append(findings, f)
+	} else if f := checkTransition(chain, 0); f != nil {
+		legality = append(legality, *f)
 	}
 
 	// Verify links
@@ -87,14 +177,43 @@ func VerifyChain(chain []models.CommitmentArtifact) *ChainResult {
 		curr := chain[i]
 
 		// Strict Linkage Check
-		if curr.PrevArtifactHash != prev.ArtifactID {
-			return &ChainResult{
-				Valid:        false,
-				BrokenIndex:  i,
-				BrokenReason: fmt.Sprintf("linkage broken: artifact[%d].prev (%s) != artifact[%d].id (%s)", i, curr.PrevArtifactHash, i-1, prev.ArtifactID),
+		if curr.PrevArtifactHash == prev.ArtifactID {
+			if f := checkTransition(chain, i); f != nil {
+				legality = append(legality, *f)
 			}
+			continue
 		}
+		f := Finding{
+			Index:      i,
+			ArtifactID: curr.ArtifactID,
+			Outcome:    OutcomeInvalid,
+			Code:       ReasonBrokenLink,
+			Message:    fmt.Sprintf("linkage broken: artifact[%d].prev (%s) != artifact[%d].id (%s)", i, curr.PrevArtifactHash, i-1, prev.ArtifactID),
+		}
+		if curr.PrevArtifactHash != models.GenesisHash && !present[curr.PrevArtifactHash] {
+			f.Outcome, f.Code = OutcomeInconclusive, ReasonMissingLink
+			f.Message = fmt.Sprintf("missing artifact: artifact[%d].prev (%s) not found", i, curr.PrevArtifactHash)
+		}
+		findings = append(findings, f)
 	}
 
-	return &ChainResult{Valid: true}
+	return chainResult(append(findings, legality...))
+}
+
+// chainResult aggregates findings into a ChainResult.
+func chainResult(findings []Finding) *ChainResult {
+	if len(findings) == 0 {
+		return &ChainResult{Valid: true, Outcome: OutcomeValid}
+	}
+	outcomes := make([]Outcome, len(findings))
+	for i, f := range findings {
+		outcomes[i] = f.Outcome
+	}
+	return &ChainResult{
+		Valid:        false,
+		Outcome:      WorstOutcome(outcomes...),
+		BrokenIndex:  findings[0].Index,
+		BrokenReason: findings[0].Message,
+		Findings:     findings,
+	}
 }
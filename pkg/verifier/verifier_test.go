@@ -1,6 +1,8 @@
 package verifier_test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"os"
 	"path/filepath"
@@ -9,6 +11,7 @@ import (
 
 	"github.com/Rainminds/gantral/internal/artifact"
 	"github.com/Rainminds/gantral/pkg/models"
+	"github.com/Rainminds/gantral/pkg/signing"
 	"github.com/Rainminds/gantral/pkg/verifier"
 	"github.com/stretchr/testify/assert"
 )
@@ -89,7 +92,7 @@ func TestVerifyChain_Success(t *testing.T) {
 		ArtifactVersion:  models.SchemaVersionV1,
 		InstanceID:       "inst-chain",
 		Timestamp:        time.Now().UTC().Format(time.RFC3339),
-		AuthorityState:   "PENDING",
+		AuthorityState:   "WAITING_FOR_HUMAN",
 		ContextHash:      "hash-a",
 		PrevArtifactHash: models.GenesisHash,
 		HumanActorID:     "user-a",
@@ -118,7 +121,7 @@ func TestVerifyChain_BrokenLink(t *testing.T) {
 	// Chain: A -> B (but B points to wrong prev)
 	artA := models.CommitmentArtifact{
 		ArtifactID:       "hash-A",
-		PrevArtifactHash: "genesis",
+		PrevArtifactHash: models.GenesisHash,
 	}
 	artB := models.CommitmentArtifact{
 		ArtifactID:       "hash-B",
@@ -132,3 +135,177 @@ func TestVerifyChain_BrokenLink(t *testing.T) {
 	assert.False(t, report.Valid)
 	assert.Equal(t, 1, report.BrokenIndex)
 }
+
+func TestVerifyChain_RequiresGenesis(t *testing.T) {
+	// A chain whose head does not link to GenesisHash is truncated or re-rooted.
+	art := models.NewCommitmentArtifact("inst-1", "", "RUNNING", "pv", "ctx", "SYSTEM")
+	assert.NoError(t, art.CalculateHashAndSetID())
+
+	report := verifier.VerifyChain([]models.CommitmentArtifact{*art})
+	assert.False(t, report.Valid)
+	assert.Equal(t, 0, report.BrokenIndex)
+	assert.Contains(t, report.BrokenReason, "genesis")
+	assert.Equal(t, verifier.OutcomeInconclusive, report.Outcome)
+}
+
+func TestVerifyArtifact_Outcomes(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1")
+		assert.NoError(t, art.CalculateHashAndSetID())
+		data, _ := json.Marshal(art)
+
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.Equal(t, verifier.OutcomeValid, res.Outcome)
+		assert.Empty(t, res.Code)
+	})
+
+	t.Run("Illegal Authority State Is Invalid", func(t *testing.T) {
+		art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "MAYBE", "pv", "ctx", "user-1")
+		assert.NoError(t, art.CalculateHashAndSetID())
+		data, _ := json.Marshal(art)
+
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+		assert.Equal(t, verifier.ReasonIllegalState, res.Code)
+	})
+
+	t.Run("Hash Mismatch Is Invalid", func(t *testing.T) {
+		art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1")
+		assert.NoError(t, art.CalculateHashAndSetID())
+		art.HumanActorID = "mallory"
+		data, _ := json.Marshal(art)
+
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.Equal(t, verifier.OutcomeInvalid, res.Outcome)
+		assert.Equal(t, verifier.ReasonHashMismatch, res.Code)
+	})
+}
+
+func TestVerifyChain_Outcomes(t *testing.T) {
+	a := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "WAITING_FOR_HUMAN", "pv", "ctx", "SYSTEM")
+	assert.NoError(t, a.CalculateHashAndSetID())
+	b := models.NewCommitmentArtifact("inst-1", a.ArtifactID, "APPROVED", "pv", "ctx", "user-1")
+	assert.NoError(t, b.CalculateHashAndSetID())
+	c := models.NewCommitmentArtifact("inst-1", b.ArtifactID, "RESUMED", "pv", "ctx", "SYSTEM")
+	assert.NoError(t, c.CalculateHashAndSetID())
+	d := models.NewCommitmentArtifact("inst-1", c.ArtifactID, "RUNNING", "pv", "ctx", "SYSTEM")
+	assert.NoError(t, d.CalculateHashAndSetID())
+
+	t.Run("Missing Link Is Inconclusive", func(t *testing.T) {
+		// b was lost: c points at an artifact we do not have.
+		report := verifier.VerifyChain([]models.CommitmentArtifact{*a, *c, *d})
+		assert.False(t, report.Valid)
+		assert.Equal(t, verifier.OutcomeInconclusive, report.Outcome)
+		assert.Equal(t, 1, report.BrokenIndex)
+		assert.Equal(t, verifier.ReasonMissingLink, report.Findings[0].Code)
+	})
+
+	t.Run("Present But Wrong Predecessor Is Invalid", func(t *testing.T) {
+		report := verifier.VerifyChain([]models.CommitmentArtifact{*a, *c, *b, *d})
+		assert.Equal(t, verifier.OutcomeInvalid, report.Outcome)
+		assert.Equal(t, verifier.ReasonBrokenLink, report.Findings[0].Code)
+	})
+
+	t.Run("All Findings Reported", func(t *testing.T) {
+		// Verification does not stop at the first problem.
+		report := verifier.VerifyChain([]models.CommitmentArtifact{*c, *a, *d})
+		assert.Len(t, report.Findings, 3)
+		assert.Equal(t, []int{0, 1, 2}, []int{report.Findings[0].Index, report.Findings[1].Index, report.Findings[2].Index})
+		assert.Equal(t, verifier.OutcomeInvalid, report.Outcome)
+	})
+}
+
+func TestWorstOutcome(t *testing.T) {
+	assert.Equal(t, verifier.OutcomeValid, verifier.WorstOutcome(verifier.OutcomeValid, verifier.OutcomeValid))
+	assert.Equal(t, verifier.OutcomeInconclusive, verifier.WorstOutcome(verifier.OutcomeValid, verifier.OutcomeInconclusive))
+	assert.Equal(t, verifier.OutcomeInvalid, verifier.WorstOutcome(verifier.OutcomeInvalid, verifier.OutcomeInconclusive))
+	assert.Equal(t, verifier.OutcomeInconclusive, verifier.WorstOutcome())
+}
+
+func TestVerifyArtifact_SchemaVersions(t *testing.T) {
+	binding := models.PolicyBinding{InputHash: "in-hash", DecisionHash: "dec-hash"}
+
+	t.Run("V2 Valid", func(t *testing.T) {
+		art := models.NewCommitmentArtifactV2("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1", binding)
+		assert.NoError(t, art.CalculateHashAndSetID())
+
+		data, _ := json.Marshal(art)
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.True(t, res.Valid, res.Error)
+	})
+
+	t.Run("V2 Policy Hash Tampered", func(t *testing.T) {
+		art := models.NewCommitmentArtifactV2("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1", binding)
+		assert.NoError(t, art.CalculateHashAndSetID())
+		art.PolicyDecisionHash = "forged"
+
+		data, _ := json.Marshal(art)
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Contains(t, res.Error, "hash mismatch")
+	})
+
+	t.Run("Unknown Major Rejected", func(t *testing.T) {
+		art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1")
+		assert.NoError(t, art.CalculateHashAndSetID())
+		art.ArtifactVersion = "v4"
+
+		data, _ := json.Marshal(art)
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Equal(t, verifier.OutcomeInconclusive, res.Outcome)
+		assert.Equal(t, verifier.ReasonUnsupportedVersion, res.Code)
+		assert.Contains(t, res.Error, "unsupported artifact_version")
+	})
+}
+
+func TestVerifyArtifactWithKeys(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signer, _ := signing.NewSigner("key-1", priv)
+	keys := signing.KeySet{"key-1": signer.PublicKey()}
+
+	art := models.NewCommitmentArtifact("inst-1", models.GenesisHash, "APPROVED", "pv", "ctx", "user-1")
+	assert.NoError(t, art.CalculateHashAndSetID())
+
+	t.Run("Unsigned Is Invalid", func(t *testing.T) {
+		data, _ := json.Marshal(art)
+
+		// Without a key set, only integrity is checked.
+		res, err := verifier.VerifyArtifact(data)
+		assert.NoError(t, err)
+		assert.True(t, res.Valid)
+
+		res, err = verifier.VerifyArtifactWithKeys(data, keys)
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Contains(t, res.Error, "not signed")
+	})
+
+	t.Run("Signed Is Valid", func(t *testing.T) {
+		signed := *art
+		assert.NoError(t, signer.Sign(&signed))
+		data, _ := json.Marshal(&signed)
+
+		res, err := verifier.VerifyArtifactWithKeys(data, keys)
+		assert.NoError(t, err)
+		assert.True(t, res.Valid, res.Error)
+		assert.Equal(t, "key-1", res.KeyID)
+	})
+
+	t.Run("Unknown Key Is Invalid", func(t *testing.T) {
+		signed := *art
+		assert.NoError(t, signer.Sign(&signed))
+		data, _ := json.Marshal(&signed)
+
+		res, err := verifier.VerifyArtifactWithKeys(data, signing.KeySet{})
+		assert.NoError(t, err)
+		assert.False(t, res.Valid)
+		assert.Contains(t, res.Error, "unknown key")
+	})
+}
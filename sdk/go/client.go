@@ -8,7 +8,6 @@ import (
 	"net/http"
 
 	"github.com/Rainminds/gantral/core/engine"
-	"github.com/Rainminds/gantral/core/policy"
 )
 
 // Client is the Gantral SDK client.
@@ -26,15 +25,13 @@ func NewClient(baseURL string) *Client {
 }
 
 // CreateInstance creates a new execution instance.
-func (c *Client) CreateInstance(ctx context.Context, workflowID string, triggerContext map[string]interface{}, pol policy.Policy) (*engine.Instance, error) {
-	// Reconstruct the request body expected by the API
-	reqBody := triggerContext
-	// Note: The API currently expects a generic map and parses "materiality" from it if extracting logic is in handlers.
-	// The handler logic:
-	// if mat, ok := body["materiality"].(string); ok && mat == "HIGH" { pol.Materiality = ... }
-	// So we need to inject materiality into the map if we want to test that.
-	// For Typed SDK, we might want to expose overrides.
-	// For now, let's just pass the context map.
+// The policy is referenced by its registry name; the server pins the latest active version.
+func (c *Client) CreateInstance(ctx context.Context, workflowID string, triggerContext map[string]interface{}, policyName string) (*engine.Instance, error) {
+	reqBody := map[string]interface{}{
+		"workflow_id":     workflowID,
+		"trigger_context": triggerContext,
+		"policy_name":     policyName,
+	}
 
 	bodyBytes, err := json.Marshal(reqBody)
 	if err != nil {
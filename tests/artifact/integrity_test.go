@@ -161,3 +161,44 @@ func Test_Artifact_Tamper_Fails_SectionD(t *testing.T) {
 		})
 	}
 }
+
+// Test_Artifact_Integrity_Golden_V3 pins the v3 (RFC 8785) canonical bytes and hash.
+// The expected files are what a verifier in any language must reproduce.
+func Test_Artifact_Integrity_Golden_V3(t *testing.T) {
+	t.Parallel()
+
+	read := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("..", "..", "tests", "golden", name))
+		if err != nil {
+			t.Fatalf("Failed to read golden file %s: %v", name, err)
+		}
+		return data
+	}
+
+	data := read("canonical_artifact_v3.json")
+	var art models.CommitmentArtifact
+	if err := json.Unmarshal(data, &art); err != nil {
+		t.Fatalf("Failed to decode golden file: %v", err)
+	}
+
+	payload, err := art.CanonicalPayload()
+	if err != nil {
+		t.Fatalf("CanonicalPayload failed: %v", err)
+	}
+	if want := read("expected_canonical_v3.txt"); string(payload) != string(want) {
+		t.Errorf("Canonical payload mismatch!\nExpected: %s\nGot:      %s", want, payload)
+	}
+
+	claimed := art.ArtifactID
+	if err := art.CalculateHashAndSetID(); err != nil {
+		t.Fatalf("Hash calculation failed: %v", err)
+	}
+	if want := string(read("expected_sha256_v3.txt")); art.ArtifactID != want || claimed != want {
+		t.Errorf("Hash Mismatch!\nExpected: %s\nGot:      %s (claimed %s)", want, art.ArtifactID, claimed)
+	}
+
+	res, err := verifier.VerifyArtifact(data)
+	if err != nil || !res.Valid {
+		t.Errorf("Golden v3 artifact failed verification: %v %+v", err, res)
+	}
+}
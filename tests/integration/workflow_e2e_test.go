@@ -106,6 +106,18 @@ func Test_EndToEnd_HITL(t *testing.T) {
 	handler := &gantralhttp.Handler{
 		TemporalClient: c,
 		TaskQueue:      taskQueue,
+		Policies:       store,
+	}
+
+	// Register the policy version the instance will be pinned to
+	version, err := policy.NewVersion("e2e-high", policy.Policy{
+		Materiality: policy.MaterialityHigh, // Forces HITL
+	})
+	if err != nil {
+		t.Fatalf("Failed to build policy version: %v", err)
+	}
+	if _, _, err := store.CreatePolicyVersion(ctx, version); err != nil {
+		t.Fatalf("Failed to register policy: %v", err)
 	}
 
 	// =========================================================================
@@ -117,10 +129,7 @@ func Test_EndToEnd_HITL(t *testing.T) {
 	createReq := gantralhttp.CreateInstanceRequest{
 		WorkflowID:     workflowID,
 		TriggerContext: map[string]interface{}{"amount": 1000},
-		Policy: policy.Policy{
-			ID:          "pol-e2e-high",
-			Materiality: policy.MaterialityHigh, // Forces HITL
-		},
+		PolicyName:     "e2e-high",
 	}
 	body, _ := json.Marshal(createReq)
 	req := httptest.NewRequest("POST", "/instances", bytes.NewReader(body))
@@ -191,9 +200,8 @@ func Test_EndToEnd_HITL(t *testing.T) {
 		if err != nil {
 			return false
 		}
-		if inst.State != engine.StateApproved {
-			return false // It might be Approved locally, workflow keeps running or completes?
-			// In our workflow logic: State -> Approved, then returns.
+		if inst.State != engine.StateCompleted {
+			return false // APPROVED -> RESUMED -> RUNNING -> COMPLETED, one artifact per hop
 		}
 
 		// 2. Check Temporal
@@ -202,7 +210,7 @@ func Test_EndToEnd_HITL(t *testing.T) {
 			return false
 		}
 		return desc.WorkflowExecutionInfo.Status == enums.WORKFLOW_EXECUTION_STATUS_COMPLETED
-	}, 10*time.Second, 500*time.Millisecond, "Instance should be APPROVED and Workflow COMPLETED")
+	}, 10*time.Second, 500*time.Millisecond, "Instance should be COMPLETED and Workflow COMPLETED")
 
 	// E. Audit Check (Optional but requested)
 	// We didn't fully implement "GetAuditLogs" in Store or API yet as per recent steps?
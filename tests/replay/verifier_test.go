@@ -100,7 +100,7 @@ func Test_Replay_Chain_Linking_SectionE(t *testing.T) {
 	}
 
 	// 3. Waiting
-	art3 := models.NewCommitmentArtifact("inst-1", art2.ArtifactID, "WAITING", "v1", "ctx3", "user1")
+	art3 := models.NewCommitmentArtifact("inst-1", art2.ArtifactID, "WAITING_FOR_HUMAN", "v1", "ctx3", "user1")
 	if err := art3.CalculateHashAndSetID(); err != nil {
 		t.Fatalf("Failed to calculate hash: %v", err)
 	}
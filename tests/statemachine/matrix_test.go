@@ -36,9 +36,11 @@ func Test_StateMachine_Matrix(t *testing.T) {
 			engine.StateTerminated:      true,
 		},
 		engine.StateWaitingForHuman: {
-			engine.StateApproved:   true,
-			engine.StateRejected:   true,
-			engine.StateOverridden: true,
+			engine.StateWaitingForHuman: true, // A vote of a multi-party (quorum) decision, or an escalation
+			engine.StateApproved:        true,
+			engine.StateRejected:        true,
+			engine.StateOverridden:      true,
+			engine.StateTerminated:      true, // Approval timed out with the TERMINATE final action
 		},
 		engine.StateApproved: {
 			engine.StateResumed: true,
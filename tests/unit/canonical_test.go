@@ -129,3 +129,29 @@ func Test_Crypto_SectionM(t *testing.T) {
 		t.Errorf("Hash mismatch against direct SHA256.\nFunc: %s\nExp:  %s", hash, expected)
 	}
 }
+
+// Test_Canonicalization_JCS_SectionK verifies that context hashes are computed over
+// RFC 8785 bytes, not Go-specific encoding/json output.
+func Test_Canonicalization_JCS_SectionK(t *testing.T) {
+	t.Parallel()
+
+	input := map[string]interface{}{
+		"query": "a<b && c>d",        // encoding/json would emit <, &, >
+		"ratio": json.Number("2.50"), // Number text is normalized, not passed through
+		"big":   json.Number("1E21"),
+	}
+	hash, err := artifact.HashContext(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte(`{"big":1e+21,"query":"a<b && c>d","ratio":2.5}`))
+	if expected := hex.EncodeToString(sum[:]); hash != expected {
+		t.Errorf("Context hash is not over JCS bytes.\nFunc: %s\nExp:  %s", hash, expected)
+	}
+
+	// Non-finite or non-I-JSON input must fail closed rather than hash something.
+	if _, err := artifact.HashContext(map[string]interface{}{"n": json.Number("1e400")}); err == nil {
+		t.Error("Expected out-of-range number to be rejected")
+	}
+}
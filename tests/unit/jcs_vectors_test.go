@@ -0,0 +1,65 @@
+package unit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Rainminds/gantral/pkg/jcs"
+)
+
+// jcsVectors mirrors tests/golden/jcs_vectors.json, which is shared with non-Go verifiers.
+type jcsVectors struct {
+	Vectors []struct {
+		Name      string `json:"name"`
+		Input     string `json:"input"`
+		Canonical string `json:"canonical"`
+		SHA256    string `json:"sha256"`
+	} `json:"vectors"`
+	Invalid []struct {
+		Name  string `json:"name"`
+		Input string `json:"input"`
+	} `json:"invalid"`
+}
+
+// Test_Canonicalization_JCSVectors checks the canonicalizer against the published
+// cross-language RFC 8785 vectors.
+func Test_Canonicalization_JCSVectors(t *testing.T) {
+	t.Parallel()
+
+	data, err := os.ReadFile(filepath.Join("..", "golden", "jcs_vectors.json"))
+	if err != nil {
+		t.Fatalf("Failed to read vectors: %v", err)
+	}
+	var vectors jcsVectors
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatalf("Failed to decode vectors: %v", err)
+	}
+	if len(vectors.Vectors) == 0 || len(vectors.Invalid) == 0 {
+		t.Fatal("Vector file is empty")
+	}
+
+	for _, v := range vectors.Vectors {
+		got, err := jcs.Canonicalize([]byte(v.Input))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", v.Name, err)
+			continue
+		}
+		if string(got) != v.Canonical {
+			t.Errorf("%s: canonical mismatch\nGot:  %s\nWant: %s", v.Name, got, v.Canonical)
+		}
+		sum := sha256.Sum256(got)
+		if hex.EncodeToString(sum[:]) != v.SHA256 {
+			t.Errorf("%s: sha256 mismatch", v.Name)
+		}
+	}
+
+	for _, v := range vectors.Invalid {
+		if _, err := jcs.Canonicalize([]byte(v.Input)); err == nil {
+			t.Errorf("%s: expected input to be rejected", v.Name)
+		}
+	}
+}